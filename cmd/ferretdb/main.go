@@ -36,16 +36,22 @@ import (
 
 	"github.com/FerretDB/FerretDB/v2/build/version"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/compression"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/v2/internal/dataapi"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/lockout"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
 	"github.com/FerretDB/FerretDB/v2/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/v2/internal/util/debug"
 	"github.com/FerretDB/FerretDB/v2/internal/util/devbuild"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/observability"
+	"github.com/FerretDB/FerretDB/v2/internal/util/oidc"
+	"github.com/FerretDB/FerretDB/v2/internal/util/plain"
 	"github.com/FerretDB/FerretDB/v2/internal/util/state"
 	"github.com/FerretDB/FerretDB/v2/internal/util/telemetry"
 )
@@ -63,18 +69,22 @@ var cli struct {
 	Mode        string `default:"${default_mode}" help:"${help_mode}"                           enum:"${enum_mode}"`
 	Auth        bool   `default:"true"            help:"Enable authentication (on by default)." negatable:""`
 	StateDir    string `default:"."               help:"Process state directory."`
-	ReplSetName string `default:""                help:"Replica set name."`
+	ReplSetName string `default:""                help:"Replica set name (disables mongos-like hello responses)."`
 
 	Listen struct {
-		Addr        string `default:"127.0.0.1:27017" help:"Listen TCP address for MongoDB protocol."`
-		Unix        string `default:""                help:"Listen Unix domain socket path for MongoDB protocol."`
-		TLS         string `default:""                help:"Listen TLS address for MongoDB protocol."`
-		TLSCertFile string `default:""                help:"TLS cert file path."`
-		TLSKeyFile  string `default:""                help:"TLS key file path."`
-		TLSCaFile   string `default:""                help:"TLS CA file path."`
-		DataAPIAddr string `default:""                help:"Listen TCP address for HTTP Data API."`
+		Addr                 string `default:"127.0.0.1:27017" help:"Listen TCP address for MongoDB protocol."`
+		Unix                 string `default:""                help:"Listen Unix domain socket path for MongoDB protocol."`
+		TLS                  string `default:""                help:"Listen TLS address for MongoDB protocol."`
+		TLSCertFile          string `default:""                help:"TLS cert file path."`
+		TLSKeyFile           string `default:""                help:"TLS key file path."`
+		TLSCaFile            string `default:""                help:"TLS CA file path."`
+		TLSRequireClientCert bool   `default:"true"            help:"Require TLS client certificates if CA file is set." negatable:""`
+		DataAPIAddr          string `default:""                help:"Listen TCP address for HTTP Data API."`
 	} `embed:"" prefix:"listen-"`
 
+	Compressors          []string `default:"${default_compressors}" help:"${help_compressors}"`
+	ZlibCompressionLevel int      `default:"6"                      help:"zlib compression level, from -1 to 9."`
+
 	Proxy struct {
 		Addr        string `default:"" help:"Proxy address."`
 		TLSCertFile string `default:"" help:"Proxy TLS cert file path."`
@@ -82,18 +92,74 @@ var cli struct {
 		TLSCaFile   string `default:"" help:"Proxy TLS CA file path."`
 	} `embed:"" prefix:"proxy-"`
 
+	AuthLockout struct {
+		Threshold   int           `default:"0"  help:"Failed SCRAM attempts before lockout; 0 disables brute-force protection."`
+		Backoff     time.Duration `default:"1s" help:"Blocking time after the first failed attempt, doubled for each next one."`
+		Duration    time.Duration `default:"1m" help:"First lockout duration, doubled for each next lockout."`
+		MaxDuration time.Duration `default:"1h" help:"Maximal backoff and lockout duration."`
+	} `embed:"" prefix:"auth-lockout-"`
+
+	PLAINAuth struct {
+		File               string            `default:""                 help:"htpasswd-style users file for PLAIN authentication."`
+		LDAPURL            string            `default:"" name:"ldap-url" help:"LDAP server URL for PLAIN authentication."`
+		LDAPUserDNTemplate string            `default:""                 help:"LDAP user DN template with {user} placeholder."`
+		LDAPGroupBaseDN    string            `default:""                 help:"LDAP base DN for user groups search."`
+		LDAPGroupRoles     map[string]string `default:""                 help:"LDAP groups mapped to roles in the admin database."`
+	} `embed:"" prefix:"plain-auth-"`
+
+	OIDC struct {
+		Issuer              string            `default:""       help:"OIDC token issuer; enables MONGODB-OIDC authentication."`
+		Audience            string            `default:""       help:"OIDC token audience."`
+		JWKS                string            `default:""       help:"OIDC JWKS file path or URL." name:"jwks"`
+		JWKSRefreshInterval time.Duration     `default:"1h"     help:"OIDC JWKS refresh interval."`
+		UsernameClaim       string            `default:"sub"    help:"OIDC token claim used as a username."`
+		GroupsClaim         string            `default:"groups" help:"OIDC token claim with groups mapped to roles."`
+		GroupRoles          map[string]string `default:""       help:"OIDC groups mapped to roles in the admin database."`
+		ClientID            string            `default:""       help:"OIDC client ID returned to clients."`
+	} `embed:"" prefix:"oidc-"`
+
+	DataAPI struct {
+		TokenKeyFile string        `default:""   help:"Data API bearer token signing key file path; random key if empty; required with --cursor-shared."`
+		TokenTTL     time.Duration `default:"1h" help:"Data API bearer token lifetime."`
+		AuthCacheTTL time.Duration `default:"1m" help:"Data API successful authentication cache lifetime; 0 disables caching."`
+	} `embed:"" prefix:"data-api-"`
+
+	Audit struct {
+		Destination string `default:"" help:"${help_audit_destination}" enum:"${enum_audit_destination}"`
+		Path        string `default:"" help:"Audit log file path."`
+		Filter      string `default:"" help:"Audit filter expression in JSON."`
+	} `embed:"" prefix:"audit-"`
+
+	MaxTime struct {
+		Default time.Duration `default:"0" help:"Time limit for read commands without maxTimeMS; 0 means no limit."`
+		Limit   time.Duration `default:"0" help:"Maximal time limit for commands, capping maxTimeMS; 0 means no limit."`
+	} `embed:"" prefix:"max-time-"`
+
+	Cursor struct {
+		Timeout             time.Duration `default:"10m"   help:"Idle cursor timeout, unless noCursorTimeout is set."`
+		Max                 int           `default:"0"     help:"Maximal number of open cursors; 0 means no limit."`
+		MaxPerUser          int           `default:"0"     help:"Maximal number of open cursors per user; 0 means no limit."`
+		MaxPersisted        int           `default:"0"     help:"Maximal number of cursors with persisted PostgreSQL connections; 0 means no limit."`
+		MaxPersistedPerUser int           `default:"0"     help:"Maximal number of cursors with persisted PostgreSQL connections per user; 0 means no limit."`
+		Shared              bool          `default:"false" help:"Store cursors and their sessions in PostgreSQL to share them between FerretDB instances."    negatable:""`
+	} `embed:"" prefix:"cursor-"`
+
 	DebugAddr string `default:"127.0.0.1:8088" help:"Listen address for HTTP handlers for metrics, pprof, etc."`
 
 	Log struct {
-		Level  string `default:"${default_log_level}" help:"${help_log_level}"`
-		Format string `default:"console"              help:"${help_log_format}"                     enum:"${enum_log_format}"`
-		UUID   bool   `default:"false"                help:"Add instance UUID to all log messages." negatable:""`
+		Level            string `default:"${default_log_level}" help:"${help_log_level}"`
+		Format           string `default:"console"              help:"${help_log_format}"                                             enum:"${enum_log_format}"`
+		UUID             bool   `default:"false"                help:"Add instance UUID to all log messages."                         negatable:""`
+		RedactClientData bool   `default:"false"                help:"Mask all client data values in logs, keeping only field names." negatable:""`
 	} `embed:"" prefix:"log-"`
 
-	PostgreSQLURL string `name:"postgresql-url" default:"postgres://127.0.0.1:5432/postgres" help:"PostgreSQL URL."`
+	PostgreSQLURL         string   `name:"postgresql-url"          default:"postgres://127.0.0.1:5432/postgres" help:"PostgreSQL URL."`
+	PostgreSQLReplicaURLs []string `name:"postgresql-replica-urls" help:"PostgreSQL streaming replica URLs for reads with non-primary read preference."`
 
 	MetricsUUID bool `default:"false" help:"Add instance UUID to all metrics." negatable:""`
 
+	CurrentOpPlanSummary bool `name:"currentop-plan-summary" default:"false" help:"Report planSummary in currentOp output, running EXPLAIN for each operation." negatable:""`
+
 	OTel struct {
 		Traces struct {
 			URL string `default:"" help:"OpenTelemetry OTLP/HTTP traces endpoint URL (e.g. 'http://host:4318/v1/traces')."`
@@ -125,14 +191,26 @@ var (
 
 	logFormats = []string{"console", "text", "json"}
 
+	auditDestinations = []string{"", audit.DestinationFile, audit.DestinationSyslog}
+
 	kongOptions = []kong.Option{
 		kong.Vars{
-			"default_log_level": defaultLogLevel().String(),
-			"default_mode":      clientconn.AllModes[0],
-
-			"enum_log_format": strings.Join(logFormats, ","),
-			"enum_mode":       strings.Join(clientconn.AllModes, ","),
-
+			"default_compressors": strings.Join(compression.AllNames, ","),
+			"default_log_level":   defaultLogLevel().String(),
+			"default_mode":        clientconn.AllModes[0],
+
+			"enum_audit_destination": strings.Join(auditDestinations, ","),
+			"enum_log_format":        strings.Join(logFormats, ","),
+			"enum_mode":              strings.Join(clientconn.AllModes, ","),
+
+			"help_audit_destination": fmt.Sprintf(
+				"Audit log destination: '%s'; empty to disable.",
+				strings.Join(auditDestinations[1:], "', '"),
+			),
+			"help_compressors": fmt.Sprintf(
+				"Allowed OP_COMPRESSED compressors: '%s'; 'disabled' to disable.",
+				strings.Join(compression.AllNames, "', '"),
+			),
 			"help_log_format": fmt.Sprintf("Log format: '%s'.", strings.Join(logFormats, "', '")),
 			"help_log_level":  fmt.Sprintf("Log level: '%s'.", strings.Join(logLevels, "', '")),
 			"help_mode":       fmt.Sprintf("Operation mode: '%s'.", strings.Join(clientconn.AllModes, "', '")),
@@ -249,6 +327,33 @@ func checkFlags(logger *slog.Logger) {
 	if !cli.Auth {
 		logger.WarnContext(ctx, "Authentication is disabled. The server will accept any connection.")
 	}
+
+	if cli.PLAINAuth.File != "" && cli.PLAINAuth.LDAPURL != "" {
+		logger.LogAttrs(
+			ctx, logging.LevelFatal,
+			"--plain-auth-file and --plain-auth-ldap-url flags are mutually exclusive.",
+		)
+	}
+}
+
+// newPLAINVerifier returns PLAIN authentication verifier configured by CLI flags,
+// or nil if PLAIN authentication is disabled.
+func newPLAINVerifier() (plain.Verifier, error) {
+	switch {
+	case cli.PLAINAuth.File != "":
+		return plain.NewFileVerifier(cli.PLAINAuth.File)
+
+	case cli.PLAINAuth.LDAPURL != "":
+		return plain.NewLDAPVerifier(&plain.LDAPConfig{
+			URL:            cli.PLAINAuth.LDAPURL,
+			UserDNTemplate: cli.PLAINAuth.LDAPUserDNTemplate,
+			GroupBaseDN:    cli.PLAINAuth.LDAPGroupBaseDN,
+			GroupRoles:     cli.PLAINAuth.LDAPGroupRoles,
+		})
+
+	default:
+		return nil, nil
+	}
 }
 
 // dumpMetrics dumps all Prometheus metrics to stderr.
@@ -435,20 +540,105 @@ func run() {
 		}()
 	}
 
-	p, err := documentdb.NewPool(cli.PostgreSQLURL, logging.WithName(logger, "pool"), stateProvider)
+	cursors := cursor.Config{
+		Timeout:          cli.Cursor.Timeout,
+		MaxCursors:       cli.Cursor.Max,
+		MaxUserCursors:   cli.Cursor.MaxPerUser,
+		MaxPersisted:     cli.Cursor.MaxPersisted,
+		MaxUserPersisted: cli.Cursor.MaxPersistedPerUser,
+	}
+
+	p, err := documentdb.NewPool(
+		cli.PostgreSQLURL, cli.PostgreSQLReplicaURLs, cursors, cli.Cursor.Shared,
+		logging.WithName(logger, "pool"), stateProvider,
+	)
 	if err != nil {
 		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to construct pool", logging.Error(err))
 	}
 
 	defer p.Close()
 
+	compressors, err := compression.New(cli.Compressors, cli.ZlibCompressionLevel)
+	if err != nil {
+		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to set up compressors", logging.Error(err))
+	}
+
+	plainVerifier, err := newPLAINVerifier()
+	if err != nil {
+		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to set up PLAIN authentication", logging.Error(err))
+	}
+
+	var oidcVerifier *oidc.Verifier
+
+	if cli.OIDC.Issuer != "" {
+		oidcVerifier, err = oidc.NewVerifier(&oidc.Config{
+			Issuer:          cli.OIDC.Issuer,
+			Audience:        cli.OIDC.Audience,
+			JWKS:            cli.OIDC.JWKS,
+			RefreshInterval: cli.OIDC.JWKSRefreshInterval,
+			UsernameClaim:   cli.OIDC.UsernameClaim,
+			GroupsClaim:     cli.OIDC.GroupsClaim,
+			GroupRoles:      cli.OIDC.GroupRoles,
+			ClientID:        cli.OIDC.ClientID,
+			L:               logging.WithName(logger, "oidc"),
+		})
+		if err != nil {
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to set up MONGODB-OIDC authentication", logging.Error(err))
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			oidcVerifier.Run(ctx)
+		}()
+	}
+
+	var auditLogger *audit.Logger
+
+	if cli.Audit.Destination != "" {
+		auditLogger, err = audit.New(&audit.NewOpts{
+			Destination: cli.Audit.Destination,
+			Path:        cli.Audit.Path,
+			Filter:      cli.Audit.Filter,
+			L:           logging.WithName(logger, "audit"),
+		})
+		if err != nil {
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to set up audit log", logging.Error(err))
+		}
+
+		defer auditLogger.Close() //nolint:errcheck // nothing to do on error
+	}
+
 	handlerOpts := &handler.NewOpts{
 		Pool: p,
 		Auth: cli.Auth,
 
+		PLAINVerifier: plainVerifier,
+		OIDCVerifier:  oidcVerifier,
+
+		Audit: auditLogger,
+
+		RedactClientLogData: cli.Log.RedactClientData,
+
+		Lockout: lockout.Config{
+			Threshold:   cli.AuthLockout.Threshold,
+			Backoff:     cli.AuthLockout.Backoff,
+			Duration:    cli.AuthLockout.Duration,
+			MaxDuration: cli.AuthLockout.MaxDuration,
+		},
+
+		MaxTimeDefault: cli.MaxTime.Default,
+		MaxTimeLimit:   cli.MaxTime.Limit,
+
+		CurrentOpPlanSummary: cli.CurrentOpPlanSummary,
+
 		TCPHost:     cli.Listen.Addr,
 		ReplSetName: cli.ReplSetName,
 
+		Compressors: compressors,
+
 		L:             logging.WithName(logger, "handler"),
 		ConnMetrics:   metrics.ConnMetrics,
 		StateProvider: stateProvider,
@@ -468,6 +658,8 @@ func run() {
 		TLSKeyFile:  cli.Listen.TLSKeyFile,
 		TLSCAFile:   cli.Listen.TLSCaFile,
 
+		TLSRequireClientCert: cli.Listen.TLSRequireClientCert,
+
 		ProxyAddr:        cli.Proxy.Addr,
 		ProxyTLSCertFile: cli.Proxy.TLSCertFile,
 		ProxyTLSKeyFile:  cli.Proxy.TLSKeyFile,
@@ -484,6 +676,11 @@ func run() {
 	}
 
 	if addr := cli.Listen.DataAPIAddr; addr != "" && addr != "-" {
+		// with a random key, tokens issued by one instance are rejected by others
+		if cli.Cursor.Shared && cli.DataAPI.TokenKeyFile == "" {
+			logger.LogAttrs(ctx, logging.LevelFatal, "--data-api-token-key-file is required with --cursor-shared")
+		}
+
 		wg.Add(1)
 
 		go func() {
@@ -493,10 +690,21 @@ func run() {
 
 			var lis *dataapi.Listener
 
+			var tokenKey []byte
+
+			if f := cli.DataAPI.TokenKeyFile; f != "" {
+				if tokenKey, err = os.ReadFile(f); err != nil {
+					l.LogAttrs(ctx, logging.LevelFatal, "Failed to read Data API token key file", logging.Error(err))
+				}
+			}
+
 			lis, err = dataapi.Listen(&dataapi.ListenOpts{
-				TCPAddr: addr,
-				L:       l,
-				Handler: h,
+				TCPAddr:      addr,
+				L:            l,
+				Handler:      h,
+				TokenKey:     tokenKey,
+				TokenTTL:     cli.DataAPI.TokenTTL,
+				AuthCacheTTL: cli.DataAPI.AuthCacheTTL,
 			})
 			if err != nil {
 				l.LogAttrs(ctx, logging.LevelFatal, "Failed to construct DataAPI listener", logging.Error(err))
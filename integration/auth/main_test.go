@@ -12,11 +12,19 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package auth contains tests for authentication and user management commands:
+// Package auth contains tests for authentication, user and role management commands:
+//   - createRole;
 //   - createUser;
 //   - dropAllUsersFromDatabase;
+//   - dropRole;
 //   - dropUser;
+//   - grantPrivilegesToRole;
+//   - grantRolesToUser;
 //   - logout;
+//   - revokePrivilegesFromRole;
+//   - revokeRolesFromUser;
+//   - rolesInfo;
+//   - updateRole;
 //   - updateUser;
 //   - usersInfo.
 package auth
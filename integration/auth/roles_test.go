@@ -0,0 +1,199 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration"
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+// connectAs returns a database of the client authenticated as the given user.
+func connectAs(t *testing.T, s *setup.SetupResult, username, password string) *mongo.Database {
+	t.Helper()
+
+	db := s.Collection.Database()
+
+	credential := options.Credential{
+		AuthMechanism: "SCRAM-SHA-256",
+		AuthSource:    db.Name(),
+		Username:      username,
+		Password:      password,
+	}
+
+	client, err := mongo.Connect(s.Ctx, options.Client().ApplyURI(s.MongoDBURI).SetAuth(credential))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(s.Ctx))
+	})
+
+	return client.Database(db.Name())
+}
+
+// assertUnauthorized checks that err is an Unauthorized error.
+func assertUnauthorized(t *testing.T, err error) {
+	t.Helper()
+
+	var se mongo.ServerError
+	require.ErrorAs(t, err, &se)
+	assert.True(t, se.HasErrorCode(13), "expected Unauthorized error, got %v", err)
+}
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx, db := s.Ctx, s.Collection.Database()
+	collName := s.Collection.Name()
+
+	username, password, roleName := "roles_user", "password", "roles_find"
+
+	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/864
+	_ = db.RunCommand(ctx, bson.D{{"dropUser", username}})
+	_ = db.RunCommand(ctx, bson.D{{"dropRole", roleName}})
+
+	_, err := s.Collection.InsertOne(ctx, bson.D{{"_id", "existing"}})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createRole", roleName},
+		{"privileges", bson.A{bson.D{
+			{"resource", bson.D{{"db", db.Name()}, {"collection", collName}}},
+			{"actions", bson.A{"find"}},
+		}}},
+		{"roles", bson.A{}},
+	}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createRole", roleName},
+		{"privileges", bson.A{}},
+		{"roles", bson.A{}},
+	}).Err()
+	integration.AssertEqualCommandError(t, mongo.CommandError{
+		Code:    51002,
+		Name:    "Location51002",
+		Message: `Role "roles_find@` + db.Name() + `" already exists`,
+	}, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createUser", username},
+		{"roles", bson.A{roleName}},
+		{"pwd", password},
+	}).Err()
+	require.NoError(t, err)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{{"rolesInfo", roleName}, {"showPrivileges", true}}).Decode(&res)
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"roles", bson.A{bson.D{
+			{"role", roleName},
+			{"db", db.Name()},
+			{"isBuiltin", false},
+			{"roles", bson.A{}},
+			{"inheritedRoles", bson.A{}},
+			{"privileges", bson.A{bson.D{
+				{"resource", bson.D{{"db", db.Name()}, {"collection", collName}}},
+				{"actions", bson.A{"find"}},
+			}}},
+			{"inheritedPrivileges", bson.A{bson.D{
+				{"resource", bson.D{{"db", db.Name()}, {"collection", collName}}},
+				{"actions", bson.A{"find"}},
+			}}},
+		}}},
+		{"ok", float64(1)},
+	}
+	integration.AssertEqualDocuments(t, expected, res)
+
+	userDB := connectAs(t, s, username, password)
+	userColl := userDB.Collection(collName)
+
+	err = userColl.FindOne(ctx, bson.D{{"_id", "existing"}}).Err()
+	require.NoError(t, err)
+
+	_, err = userColl.InsertOne(ctx, bson.D{{"_id", "denied"}})
+	assertUnauthorized(t, err)
+
+	err = userDB.RunCommand(ctx, bson.D{{"dropDatabase", 1}}).Err()
+	assertUnauthorized(t, err)
+
+	err = userDB.RunCommand(ctx, bson.D{{"connectionStatus", 1}, {"showPrivileges", true}}).Decode(&res)
+	require.NoError(t, err)
+
+	authInfo, ok := res.Map()["authInfo"].(bson.D)
+	require.True(t, ok)
+	assert.Equal(t, bson.A{bson.D{{"role", roleName}, {"db", db.Name()}}}, authInfo.Map()["authenticatedUserRoles"])
+	assert.Equal(t, bson.A{bson.D{
+		{"resource", bson.D{{"db", db.Name()}, {"collection", collName}}},
+		{"actions", bson.A{"find"}},
+	}}, authInfo.Map()["authenticatedUserPrivileges"])
+
+	err = db.RunCommand(ctx, bson.D{{"grantRolesToUser", username}, {"roles", bson.A{"readWrite"}}}).Err()
+	require.NoError(t, err)
+
+	_, err = userColl.InsertOne(ctx, bson.D{{"_id", "allowed"}})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{{"revokeRolesFromUser", username}, {"roles", bson.A{"readWrite"}}}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"grantPrivilegesToRole", roleName},
+		{"privileges", bson.A{bson.D{
+			{"resource", bson.D{{"db", db.Name()}, {"collection", collName}}},
+			{"actions", bson.A{"insert"}},
+		}}},
+	}).Err()
+	require.NoError(t, err)
+
+	_, err = userColl.InsertOne(ctx, bson.D{{"_id", "granted"}})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"revokePrivilegesFromRole", roleName},
+		{"privileges", bson.A{bson.D{
+			{"resource", bson.D{{"db", db.Name()}, {"collection", collName}}},
+			{"actions", bson.A{"insert"}},
+		}}},
+	}).Err()
+	require.NoError(t, err)
+
+	_, err = userColl.InsertOne(ctx, bson.D{{"_id", "revoked"}})
+	assertUnauthorized(t, err)
+
+	err = db.RunCommand(ctx, bson.D{{"updateRole", "read"}, {"roles", bson.A{}}}).Err()
+	integration.AssertEqualCommandError(t, mongo.CommandError{
+		Code:    42,
+		Name:    "InvalidRoleModification",
+		Message: "Cannot modify built-in role: read@" + db.Name(),
+	}, err)
+
+	require.NoError(t, db.RunCommand(ctx, bson.D{{"dropRole", roleName}}).Err())
+
+	err = userColl.FindOne(ctx, bson.D{{"_id", "existing"}}).Err()
+	assertUnauthorized(t, err)
+
+	require.NoError(t, db.RunCommand(ctx, bson.D{{"dropUser", username}}).Err())
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestSCRAMSHA1(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx, db := s.Ctx, s.Collection.Database()
+
+	username, password := "scram_sha1_user", "password"
+
+	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/864
+	_ = db.RunCommand(ctx, bson.D{{"dropUser", username}})
+
+	err := db.RunCommand(ctx, bson.D{
+		{"createUser", username},
+		{"roles", bson.A{}},
+		{"pwd", password},
+		{"mechanisms", bson.A{"SCRAM-SHA-1"}},
+	}).Err()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.RunCommand(ctx, bson.D{{"dropUser", username}}).Err())
+	})
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{{"hello", 1}, {"saslSupportedMechs", db.Name() + "." + username}}).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, bson.A{"SCRAM-SHA-1"}, res.Map()["saslSupportedMechs"])
+
+	err = db.RunCommand(ctx, bson.D{{"usersInfo", username}}).Decode(&res)
+	require.NoError(t, err)
+
+	users := res.Map()["users"].(bson.A)
+	require.Len(t, users, 1)
+	assert.Equal(t, bson.A{"SCRAM-SHA-1"}, users[0].(bson.D).Map()["mechanisms"])
+
+	for name, tc := range map[string]struct {
+		mechanism string
+		password  string
+		ok        bool
+	}{
+		"SHA1":          {mechanism: "SCRAM-SHA-1", password: password, ok: true},
+		"WrongPassword": {mechanism: "SCRAM-SHA-1", password: "wrong", ok: false},
+		"SHA256":        {mechanism: "SCRAM-SHA-256", password: password, ok: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			credential := options.Credential{
+				AuthMechanism: tc.mechanism,
+				AuthSource:    db.Name(),
+				Username:      username,
+				Password:      tc.password,
+			}
+
+			client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.MongoDBURI).SetAuth(credential))
+			require.NoError(t, err)
+
+			defer client.Disconnect(ctx) //nolint:errcheck // we are only interested in authentication
+
+			err = client.Ping(ctx, nil)
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+// clientCertSubject returns the subject of the client certificate used by tests.
+func clientCertSubject(t *testing.T) string {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join(testutil.BuildCertsDir, "client-cert.pem"))
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(b)
+	require.NotNil(t, block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	return cert.Subject.String()
+}
+
+func TestX509(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx := s.Ctx
+
+	if !strings.Contains(s.MongoDBURI, "tlsCertificateKeyFile") {
+		t.Skip("X.509 authentication requires TLS connection with a client certificate")
+	}
+
+	username := clientCertSubject(t)
+	external := s.Collection.Database().Client().Database("$external")
+
+	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/864
+	_ = external.RunCommand(ctx, bson.D{{"dropUser", username}})
+
+	err := external.RunCommand(ctx, bson.D{
+		{"createUser", username},
+		{"roles", bson.A{bson.D{{"role", "read"}, {"db", s.Collection.Database().Name()}}}},
+	}).Err()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, external.RunCommand(ctx, bson.D{{"dropUser", username}}).Err())
+	})
+
+	err = external.RunCommand(ctx, bson.D{
+		{"createUser", "CN=with_password"},
+		{"roles", bson.A{}},
+		{"pwd", "password"},
+	}).Err()
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(2), ce.Code)
+
+	credential := options.Credential{
+		AuthMechanism: "MONGODB-X509",
+		AuthSource:    "$external",
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.MongoDBURI).SetAuth(credential))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	var res bson.D
+	err = client.Database("admin").RunCommand(ctx, bson.D{{"connectionStatus", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	authInfo := res.Map()["authInfo"].(bson.D)
+	expected := bson.A{bson.D{{"user", username}, {"db", "$external"}}}
+	assert.Equal(t, expected, authInfo.Map()["authenticatedUsers"])
+
+	db := client.Database(s.Collection.Database().Name())
+
+	_, err = db.Collection(s.Collection.Name()).Find(ctx, bson.D{})
+	require.NoError(t, err)
+
+	_, err = db.Collection(s.Collection.Name()).InsertOne(ctx, bson.D{{"_id", "x509"}})
+	assertUnauthorized(t, err)
+}
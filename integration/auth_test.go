@@ -101,15 +101,9 @@ func TestAuth(t *testing.T) {
 				_ = db.RunCommand(ctx, bson.D{{"dropUser", tc.username}})
 
 				// root role is only available in admin database, a role with sufficient privilege is used
-				roles := bson.A{"readWrite"}
-				if !setup.IsMongoDB(t) {
-					// TODO https://github.com/FerretDB/FerretDB/issues/3974
-					roles = bson.A{}
-				}
-
 				createPayload := bson.D{
 					{"createUser", tc.username},
-					{"roles", roles},
+					{"roles", bson.A{"readWrite"}},
 					{"pwd", tc.password},
 					{"mechanisms", tc.mechanisms},
 				}
@@ -784,6 +778,7 @@ func TestHelloSpeculative(t *testing.T) {
 		// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/566
 		res.Remove("hosts")
 		res.Remove("setName")
+		res.Remove("msg")
 		res.Remove("topologyVersion")
 		res.Remove("setVersion")
 		res.Remove("secondary")
@@ -990,6 +985,7 @@ func TestHelloOpQuerySASLSupportedMechs(t *testing.T) {
 	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/566
 	res.Remove("hosts")
 	res.Remove("setName")
+	res.Remove("msg")
 	res.Remove("topologyVersion")
 	res.Remove("setVersion")
 	res.Remove("secondary")
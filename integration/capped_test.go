@@ -0,0 +1,171 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestCappedCollection(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	name := collection.Name() + "_capped"
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1000).SetMaxDocuments(3)
+	require.NoError(t, db.CreateCollection(ctx, name, opts))
+
+	capped := db.Collection(name)
+
+	for i := range 5 {
+		_, err := capped.InsertOne(ctx, bson.D{{"_id", int32(i)}})
+		require.NoError(t, err)
+	}
+
+	var docs []bson.D
+	cursor, err := capped.Find(ctx, bson.D{})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &docs))
+	assert.Equal(t, []bson.D{{{"_id", int32(2)}}, {{"_id", int32(3)}}, {{"_id", int32(4)}}}, docs)
+
+	var stats bson.D
+	err = db.RunCommand(ctx, bson.D{{"collStats", name}}).Decode(&stats)
+	require.NoError(t, err)
+
+	m := stats.Map()
+	assert.Equal(t, true, m["capped"])
+	assert.EqualValues(t, 4096, m["maxSize"])
+	assert.EqualValues(t, 3, m["max"])
+
+	t.Run("SizeRequired", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"create", name + "_nosize"}, {"capped", true}}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(72), ce.Code)
+	})
+}
+
+func TestCappedTailableCursor(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	name := collection.Name() + "_capped"
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(100000)
+	require.NoError(t, db.CreateCollection(ctx, name, opts))
+
+	capped := db.Collection(name)
+
+	_, err := capped.InsertOne(ctx, bson.D{{"_id", int32(1)}})
+	require.NoError(t, err)
+
+	findOpts := options.Find().SetCursorType(options.TailableAwait).SetMaxAwaitTime(100 * time.Millisecond)
+	cursor, err := capped.Find(ctx, bson.D{}, findOpts)
+	require.NoError(t, err)
+
+	defer cursor.Close(ctx)
+
+	require.True(t, cursor.Next(ctx))
+	assert.Equal(t, int32(1), cursor.Current.Lookup("_id").Int32())
+
+	// the cursor stays open after reaching the end
+	require.False(t, cursor.TryNext(ctx))
+	require.NoError(t, cursor.Err())
+	require.NotZero(t, cursor.ID())
+
+	_, err = capped.InsertOne(ctx, bson.D{{"_id", int32(2)}})
+	require.NoError(t, err)
+
+	require.True(t, cursor.Next(ctx))
+	assert.Equal(t, int32(2), cursor.Current.Lookup("_id").Int32())
+
+	t.Run("NonCapped", func(t *testing.T) {
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", int32(1)}})
+		require.NoError(t, err)
+
+		_, err = collection.Find(ctx, bson.D{}, options.Find().SetCursorType(options.Tailable))
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(2), ce.Code)
+	})
+}
+
+func TestCappedConvertAndClone(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}},
+		bson.D{{"_id", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	clone := collection.Name() + "_clone"
+
+	err = db.RunCommand(ctx, bson.D{
+		{"cloneCollectionAsCapped", collection.Name()},
+		{"toCollection", clone},
+		{"size", int32(10000)},
+	}).Err()
+	require.NoError(t, err)
+
+	n, err := db.Collection(clone).CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"cloneCollectionAsCapped", collection.Name()},
+		{"toCollection", clone},
+		{"size", int32(10000)},
+	}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    48,
+		Name:    "NamespaceExists",
+		Message: "a collection '" + db.Name() + "." + clone + "' already exists",
+	}, err)
+
+	err = db.RunCommand(ctx, bson.D{{"convertToCapped", collection.Name()}, {"size", int32(10000)}}).Err()
+	require.NoError(t, err)
+
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{"name", collection.Name()}})
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+
+	var opts bson.D
+	require.NoError(t, bson.Unmarshal(specs[0].Options, &opts))
+	assert.Equal(t, true, opts.Map()["capped"])
+	assert.EqualValues(t, 10240, opts.Map()["size"])
+
+	err = db.RunCommand(ctx, bson.D{{"convertToCapped", "does_not_exist"}, {"size", int32(10000)}}).Err()
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(26), ce.Code)
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestCausalConsistency(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	var res bson.M
+	err := db.RunCommand(ctx, bson.D{
+		{"insert", collection.Name()},
+		{"documents", bson.A{bson.D{{"_id", "causal"}}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	operationTime, ok := res["operationTime"].(primitive.Timestamp)
+	require.True(t, ok, "operationTime is missing: %v", res)
+	assert.False(t, operationTime.IsZero())
+
+	clusterTime, ok := res["$clusterTime"].(bson.M)
+	require.True(t, ok, "$clusterTime is missing: %v", res)
+	assert.IsType(t, primitive.Timestamp{}, clusterTime["clusterTime"])
+
+	err = db.RunCommand(ctx, bson.D{
+		{"find", collection.Name()},
+		{"filter", bson.D{{"_id", "causal"}}},
+		{"readConcern", bson.D{{"afterClusterTime", operationTime}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	firstBatch := res["cursor"].(bson.M)["firstBatch"].(bson.A)
+	assert.Len(t, firstBatch, 1)
+
+	t.Run("WrongType", func(t *testing.T) {
+		t.Parallel()
+
+		err := db.RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"readConcern", bson.D{{"afterClusterTime", int64(42)}}},
+		}).Err()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+// nextChange returns the next change event of the stream, failing the test on timeout.
+func nextChange(t *testing.T, ctx context.Context, cs *mongo.ChangeStream) bson.D {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+
+	for time.Now().Before(deadline) {
+		if cs.TryNext(ctx) {
+			var res bson.D
+			require.NoError(t, cs.Decode(&res))
+
+			return res
+		}
+
+		require.NoError(t, cs.Err())
+	}
+
+	t.Fatal("timeout waiting for change event")
+
+	return nil
+}
+
+func TestChangeStreams(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "before"}})
+	require.NoError(t, err)
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	cs, err := collection.Watch(ctx, mongo.Pipeline{}, opts)
+	require.NoError(t, err)
+
+	defer cs.Close(ctx)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "doc"}, {"v", int32(1)}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "doc"}}, bson.D{{"$set", bson.D{{"v", int32(2)}}}})
+	require.NoError(t, err)
+
+	_, err = collection.DeleteOne(ctx, bson.D{{"_id", "doc"}})
+	require.NoError(t, err)
+
+	insert := nextChange(t, ctx, cs).Map()
+	assert.Equal(t, "insert", insert["operationType"])
+	assert.Equal(t, bson.D{{"_id", "doc"}}, insert["documentKey"])
+	assert.Equal(t, bson.D{{"_id", "doc"}, {"v", int32(1)}}, insert["fullDocument"])
+
+	update := nextChange(t, ctx, cs).Map()
+	assert.Equal(t, "update", update["operationType"])
+	assert.Equal(t, bson.D{{"v", int32(2)}}, update["updateDescription"].(bson.D).Map()["updatedFields"])
+
+	resumeToken := cs.ResumeToken()
+
+	del := nextChange(t, ctx, cs).Map()
+	assert.Equal(t, "delete", del["operationType"])
+	assert.Equal(t, bson.D{{"_id", "doc"}}, del["documentKey"])
+
+	t.Run("ResumeAfter", func(t *testing.T) {
+		resumed, err := collection.Watch(ctx, mongo.Pipeline{}, options.ChangeStream().SetResumeAfter(resumeToken))
+		require.NoError(t, err)
+
+		defer resumed.Close(ctx)
+
+		assert.Equal(t, "delete", nextChange(t, ctx, resumed).Map()["operationType"])
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		pipeline := mongo.Pipeline{{{"$match", bson.D{{"operationType", "insert"}}}}}
+
+		matched, err := collection.Watch(ctx, pipeline)
+		require.NoError(t, err)
+
+		defer matched.Close(ctx)
+
+		_, err = collection.DeleteOne(ctx, bson.D{{"_id", "before"}})
+		require.NoError(t, err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", "after"}})
+		require.NoError(t, err)
+
+		change := nextChange(t, ctx, matched).Map()
+		assert.Equal(t, "insert", change["operationType"])
+		assert.Equal(t, bson.D{{"_id", "after"}}, change["documentKey"])
+	})
+}
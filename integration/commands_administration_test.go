@@ -79,12 +79,10 @@ func TestCreateCollectionDropListCollections(t *testing.T) {
 	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, actual)
 }
 
-func TestDropDatabaseListDatabases(tt *testing.T) {
-	tt.Parallel()
-
-	t := setup.FailsForFerretDB(tt, "https://github.com/FerretDB/FerretDB-DocumentDB/issues/26")
+func TestDropDatabaseListDatabases(t *testing.T) {
+	t.Parallel()
 
-	ctx, collection := setup.Setup(tt) // no providers there
+	ctx, collection := setup.Setup(t) // no providers there
 
 	db := collection.Database()
 	name := db.Name()
@@ -247,10 +245,8 @@ func TestListDatabases(t *testing.T) {
 	}
 
 	for name, tc := range testCases {
-		t.Run(name, func(tt *testing.T) {
-			tt.Parallel()
-
-			t := setup.FailsForFerretDB(tt, "https://github.com/FerretDB/FerretDB-DocumentDB/issues/26")
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
 
 			actual, err := db.Client().ListDatabases(ctx, tc.filter, tc.opts...)
 			assert.NoError(t, err)
@@ -272,6 +268,91 @@ func TestListDatabases(t *testing.T) {
 	}
 }
 
+func TestListAuthorized(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{Providers: []shareddata.Provider{shareddata.Scalars}})
+	ctx, db := s.Ctx, s.Collection.Database()
+	username, password, roleName := t.Name()+"user", "testpass", t.Name()+"role"
+
+	_, err := db.Collection(s.Collection.Name()+"_other").InsertOne(ctx, bson.D{{"v", int32(42)}})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createRole", roleName},
+		{"privileges", bson.A{bson.D{
+			{"resource", bson.D{{"db", db.Name()}, {"collection", s.Collection.Name()}}},
+			{"actions", bson.A{"find"}},
+		}}},
+		{"roles", bson.A{}},
+	}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createUser", username},
+		{"roles", bson.A{roleName}},
+		{"pwd", password},
+		{"mechanisms", bson.A{"SCRAM-SHA-256"}},
+	}).Err()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.RunCommand(ctx, bson.D{{"dropUser", username}}).Err())
+		assert.NoError(t, db.RunCommand(ctx, bson.D{{"dropRole", roleName}}).Err())
+	})
+
+	credential := options.Credential{
+		AuthMechanism: "SCRAM-SHA-256",
+		AuthSource:    db.Name(),
+		Username:      username,
+		Password:      password,
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.MongoDBURI).SetAuth(credential))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, client.Disconnect(ctx))
+	})
+
+	userDB := client.Database(db.Name())
+
+	t.Run("ListDatabases", func(t *testing.T) {
+		t.Parallel()
+
+		names, err := client.ListDatabaseNames(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{db.Name()}, names)
+
+		err = client.Database("admin").RunCommand(ctx, bson.D{
+			{"listDatabases", 1},
+			{"authorizedDatabases", false},
+		}).Err()
+
+		expected := mongo.CommandError{
+			Code: 13,
+			Name: "Unauthorized",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+
+	t.Run("ListCollections", func(t *testing.T) {
+		t.Parallel()
+
+		names, err := userDB.ListCollectionNames(ctx, bson.D{}, options.ListCollections().SetAuthorizedCollections(true))
+		require.NoError(t, err)
+		assert.Equal(t, []string{s.Collection.Name()}, names)
+
+		_, err = userDB.ListCollections(ctx, bson.D{})
+
+		expected := mongo.CommandError{
+			Code: 13,
+			Name: "Unauthorized",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+}
+
 func TestListCollectionNames(t *testing.T) {
 	t.Parallel()
 
@@ -450,6 +531,13 @@ func TestGetParameterCommand(t *testing.T) {
 				"ok":    float64(1),
 			},
 		},
+		"RedactClientLogData": {
+			command: bson.D{{"getParameter", 1}, {"redactClientLogData", 1}, {"comment", "getParameter test"}},
+			expected: map[string]any{
+				"redactClientLogData": false,
+				"ok":                  float64(1),
+			},
+		},
 		"NonexistentParameters": {
 			command: bson.D{{"getParameter", 1}, {"quiet", 1}, {"quiet_other", 1}, {"comment", "getParameter test"}},
 			expected: map[string]any{
@@ -53,6 +53,7 @@ func TestCommandsReplication(t *testing.T) {
 			delete(m, "hosts")
 			delete(m, "lastWrite")
 			delete(m, "me")
+			delete(m, "msg")
 			delete(m, "operationTime")
 			delete(m, "primary")
 			delete(m, "secondary")
@@ -0,0 +1,82 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestCompressionHello(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	var res bson.D
+	err := db.RunCommand(ctx, bson.D{{"hello", 1}, {"compression", bson.A{"unknown", "zlib", "snappy"}}}).Decode(&res)
+	require.NoError(t, err)
+
+	assert.Equal(t, bson.A{"zlib", "snappy"}, res.Map()["compression"])
+
+	err = db.RunCommand(ctx, bson.D{{"hello", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	assert.NotContains(t, res.Map(), "compression")
+}
+
+func TestCompression(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx := s.Ctx
+
+	_, err := s.Collection.InsertOne(ctx, bson.D{{"_id", "large"}, {"v", strings.Repeat("compressible ", 10_000)}})
+	require.NoError(t, err)
+
+	for _, compressor := range []string{"snappy", "zlib", "zstd"} {
+		t.Run(compressor, func(t *testing.T) {
+			t.Parallel()
+
+			opts := options.Client().ApplyURI(s.MongoDBURI).SetCompressors([]string{compressor})
+
+			client, err := mongo.Connect(ctx, opts)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				require.NoError(t, client.Disconnect(ctx))
+			})
+
+			coll := client.Database(s.Collection.Database().Name()).Collection(s.Collection.Name())
+
+			_, err = coll.InsertOne(ctx, bson.D{{"_id", compressor}})
+			require.NoError(t, err)
+
+			var doc bson.D
+			require.NoError(t, coll.FindOne(ctx, bson.D{{"_id", "large"}}).Decode(&doc))
+			assert.Equal(t, strings.Repeat("compressible ", 10_000), doc.Map()["v"])
+
+			require.NoError(t, coll.FindOne(ctx, bson.D{{"_id", compressor}}).Err())
+		})
+	}
+}
@@ -136,6 +136,9 @@ func TestCurrentOpGetMore(t *testing.T) {
 	getMoreOp.Remove("planSummary")
 	getMoreOp.Remove("cursor")
 
+	// FerretDB-specific fields
+	getMoreOp.Remove("backendPid")
+
 	// MongoDB may contain other in-progress operations
 	err = res.Replace("inprog", wirebson.MustArray(getMoreOp))
 	require.NoError(t, err)
@@ -264,6 +267,9 @@ func TestCurrentOpExplain(t *testing.T) {
 	op.Remove("planSummary")
 	op.Remove("cursor")
 
+	// FerretDB-specific fields
+	op.Remove("backendPid")
+
 	// MongoDB may contain other in-progress operations
 	err = res.Replace("inprog", wirebson.MustArray(op))
 	require.NoError(t, err)
@@ -381,3 +387,89 @@ func inProgress(tb testing.TB, ctx context.Context, adminDB *mongo.Database, n i
 
 	panic("unreachable")
 }
+
+func TestCurrentOpFilter(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, new(setup.SetupOpts))
+	ctx, collection := s.Ctx, s.Collection
+	adminDB := collection.Database().Client().Database("admin")
+
+	var res struct {
+		InProg []bson.M `bson:"inprog"`
+	}
+
+	// idle connections do not have opid
+	err := adminDB.RunCommand(ctx, bson.D{
+		{"currentOp", 1},
+		{"$all", true},
+		{"type", "op"},
+		{"active", false},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	for _, op := range res.InProg {
+		assert.Equal(t, "op", op["type"])
+		assert.Equal(t, false, op["active"])
+		assert.NotContains(t, op, "opid")
+	}
+
+	err = adminDB.RunCommand(ctx, bson.D{
+		{"currentOp", 1},
+		{"$or", bson.A{bson.D{{"op", "no-such-op"}}, bson.D{{"type", "no-such-type"}}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+	assert.Empty(t, res.InProg)
+}
+
+func TestCurrentOpStage(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, new(setup.SetupOpts))
+	ctx, collection := s.Ctx, s.Collection
+	adminDB := collection.Database().Client().Database("admin")
+
+	t.Run("Match", func(t *testing.T) {
+		t.Parallel()
+
+		cursor, err := adminDB.Aggregate(ctx, bson.A{
+			bson.D{{"$currentOp", bson.D{{"allUsers", true}, {"idleConnections", true}}}},
+			bson.D{{"$match", bson.D{{"type", "op"}}}},
+			bson.D{{"$project", bson.D{{"type", 1}, {"active", 1}}}},
+		})
+		require.NoError(t, err)
+
+		var res []bson.M
+		require.NoError(t, cursor.All(ctx, &res))
+		require.NotEmpty(t, res)
+
+		for _, op := range res {
+			assert.Equal(t, "op", op["type"])
+		}
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := collection.Database().Aggregate(ctx, bson.A{
+			bson.D{{"$currentOp", bson.D{}}},
+		})
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(73), ce.Code)
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := adminDB.Aggregate(ctx, bson.A{
+			bson.D{{"$currentOp", bson.D{{"foo", true}}}},
+		})
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(40415), ce.Code)
+		assert.Equal(t, "BSON field '$currentOp.foo' is an unknown field.", ce.Message)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestKillOp(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, new(setup.SetupOpts))
+	ctx, collection := s.Ctx, s.Collection
+	adminDB := collection.Database().Client().Database("admin")
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := adminDB.RunCommand(ctx, bson.D{{"killOp", 1}, {"op", int32(math.MaxInt32)}}).Decode(&res)
+		require.NoError(t, err)
+
+		AssertEqualDocuments(t, bson.D{{"info", "attempting to kill op"}, {"ok", 1.0}}, res)
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		err := collection.Database().RunCommand(ctx, bson.D{{"killOp", 1}, {"op", int32(1)}}).Err()
+
+		expected := mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "killOp may only be run against the admin database.",
+		}
+		AssertEqualCommandError(t, expected, err)
+	})
+
+	t.Run("MissingOp", func(t *testing.T) {
+		t.Parallel()
+
+		err := adminDB.RunCommand(ctx, bson.D{{"killOp", 1}}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.NotZero(t, ce.Code)
+	})
+
+	t.Run("Own", func(t *testing.T) {
+		t.Parallel()
+
+		// opID returns the ID of the operation on this test's collection, or nil
+		opID := func(op bson.D) any {
+			var ns, id any
+
+			for _, e := range op {
+				switch e.Key {
+				case "ns":
+					ns = e.Value
+				case "opid":
+					id = e.Value
+				}
+			}
+
+			if ns != collection.Database().Name()+"."+collection.Name() {
+				return nil
+			}
+
+			return id
+		}
+
+		// insert enough documents for getMore operations to be visible
+		var docs []any
+		for i := range 100 {
+			docs = append(docs, bson.D{{"_id", int32(i)}})
+		}
+
+		_, err := collection.InsertMany(ctx, docs)
+		require.NoError(t, err)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			for range 50 {
+				cursor, err := collection.Find(ctx, bson.D{}) //nolint:vet // redeclare err to avoid datarace
+				if err != nil {
+					return
+				}
+
+				cursor.SetBatchSize(1)
+
+				// the operation may be killed
+				var res []bson.D
+				_ = cursor.All(ctx, &res)
+			}
+		}()
+
+		defer func() { <-done }()
+
+		for range 100 {
+			var res struct {
+				InProg []bson.D `bson:"inprog"`
+			}
+			err = adminDB.RunCommand(ctx, bson.D{{"currentOp", 1}, {"$ownOps", true}}).Decode(&res)
+			require.NoError(t, err)
+
+			for _, op := range res.InProg {
+				id := opID(op)
+				if id == nil {
+					continue
+				}
+
+				var killRes bson.D
+				err = adminDB.RunCommand(ctx, bson.D{{"killOp", 1}, {"op", id}}).Decode(&killRes)
+				require.NoError(t, err)
+
+				AssertEqualDocuments(t, bson.D{{"info", "attempting to kill op"}, {"ok", 1.0}}, killRes)
+
+				return
+			}
+		}
+
+		t.Skip("no in-progress operation found")
+	})
+}
@@ -146,6 +146,7 @@ func TestOpQueryIsMaster(t *testing.T) {
 			// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/566
 			res.Remove("hosts")
 			res.Remove("setName")
+			res.Remove("msg")
 			res.Remove("topologyVersion")
 			res.Remove("setVersion")
 			res.Remove("secondary")
@@ -215,6 +216,7 @@ func TestOpQueryIsMasterHelloOk(t *testing.T) {
 			// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/566
 			res.Remove("hosts")
 			res.Remove("setName")
+			res.Remove("msg")
 			res.Remove("topologyVersion")
 			res.Remove("setVersion")
 			res.Remove("secondary")
@@ -277,6 +279,7 @@ func TestOpQueryHello(tt *testing.T) {
 	res.Remove("topologyVersion")
 	res.Remove("hosts")
 	res.Remove("setName")
+	res.Remove("msg")
 	res.Remove("setVersion")
 	res.Remove("secondary")
 	res.Remove("primary")
@@ -0,0 +1,78 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestRetryableWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	sess, err := db.Client().StartSession()
+	require.NoError(t, err)
+
+	defer sess.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, sess, func(sctx mongo.SessionContext) error {
+		insert := bson.D{
+			{"insert", collection.Name()},
+			{"documents", bson.A{bson.D{{"_id", "retry"}}}},
+			{"txnNumber", int64(1)},
+		}
+
+		// the retried insert returns the stored result instead of the duplicate key error
+		for range 2 {
+			var res bson.D
+			require.NoError(t, db.RunCommand(sctx, insert).Decode(&res))
+			AssertEqualDocuments(t, bson.D{{"n", int32(1)}, {"ok", float64(1)}}, res)
+		}
+
+		update := bson.D{
+			{"update", collection.Name()},
+			{"updates", bson.A{bson.D{{"q", bson.D{{"_id", "retry"}}}, {"u", bson.D{{"$inc", bson.D{{"v", int32(1)}}}}}}}},
+			{"txnNumber", int64(2)},
+		}
+
+		// the retried update is not applied twice
+		for range 2 {
+			require.NoError(t, db.RunCommand(sctx, update).Err())
+		}
+
+		var doc bson.D
+		require.NoError(t, collection.FindOne(sctx, bson.D{{"_id", "retry"}}).Decode(&doc))
+		AssertEqualDocuments(t, bson.D{{"_id", "retry"}, {"v", int32(1)}}, doc)
+
+		insert[2].Value = int64(1)
+		err := db.RunCommand(sctx, insert).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(225), ce.Code)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -27,9 +27,12 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/compression"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/state"
 )
 
@@ -109,7 +112,7 @@ func setupListener(tb testing.TB, ctx context.Context, opts *ListenerOpts, logge
 		opts = new(ListenerOpts)
 	}
 
-	p, err := documentdb.NewPool(*postgreSQLURLF, logging.WithName(logger, "pool"), sp)
+	p, err := documentdb.NewPool(*postgreSQLURLF, nil, cursor.Config{}, false, logging.WithName(logger, "pool"), sp)
 	require.NoError(tb, err)
 
 	tb.Cleanup(p.Close)
@@ -122,6 +125,8 @@ func setupListener(tb testing.TB, ctx context.Context, opts *ListenerOpts, logge
 		TCPHost:     "",
 		ReplSetName: "",
 
+		Compressors: must.NotFail(compression.New(compression.AllNames, 6)),
+
 		L:             logging.WithName(logger, "handler"),
 		ConnMetrics:   listenerMetrics.ConnMetrics,
 		StateProvider: sp,
@@ -0,0 +1,165 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestShardingCommandsErrors(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB tests do not run against mongos")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	adminDB := collection.Database().Client().Database("admin")
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	for _, command := range []string{"shardCollection", "reshardCollection", "unshardCollection"} {
+		t.Run(command, func(t *testing.T) {
+			t.Parallel()
+
+			err := collection.Database().RunCommand(ctx, bson.D{{command, ns}, {"key", bson.D{{"_id", "hashed"}}}}).Err()
+			AssertEqualCommandError(t, mongo.CommandError{
+				Code:    13,
+				Name:    "Unauthorized",
+				Message: command + " may only be run against the admin database.",
+			}, err)
+
+			err = adminDB.RunCommand(ctx, bson.D{{command, collection.Name()}, {"key", bson.D{{"_id", "hashed"}}}}).Err()
+			AssertEqualCommandError(t, mongo.CommandError{
+				Code:    73,
+				Name:    "InvalidNamespace",
+				Message: "Invalid namespace specified '" + collection.Name() + "'",
+			}, err)
+		})
+	}
+
+	t.Run("CollStats", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := collection.Database().RunCommand(ctx, bson.D{{"collStats", collection.Name()}}).Decode(&res)
+		require.NoError(t, err)
+
+		// collections are not sharded by default
+		for _, e := range res {
+			require.NotEqual(t, "shardKey", e.Key)
+		}
+	})
+}
+
+func TestShardingCommands(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB tests do not run against mongos")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+	ns := db.Name() + "." + collection.Name()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "foo"}},
+		bson.D{{"_id", int32(2)}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	// shardKey returns shard key reported by collStats and listCollections;
+	// both should be the same.
+	shardKey := func(t *testing.T) any {
+		t.Helper()
+
+		var stats bson.D
+		err := db.RunCommand(ctx, bson.D{{"collStats", collection.Name()}}).Decode(&stats)
+		require.NoError(t, err)
+
+		m := stats.Map()
+
+		var res bson.D
+		err = db.RunCommand(ctx, bson.D{
+			{"listCollections", int32(1)},
+			{"filter", bson.D{{"name", collection.Name()}}},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		firstBatch := res.Map()["cursor"].(bson.D).Map()["firstBatch"].(bson.A)
+		require.Len(t, firstBatch, 1)
+
+		info, _ := firstBatch[0].(bson.D).Map()["info"].(bson.D)
+		assert.Equal(t, m["shardKey"], info.Map()["shardKey"])
+
+		if m["shardKey"] != nil {
+			assert.Equal(t, true, m["sharded"])
+		}
+
+		return m["shardKey"]
+	}
+
+	require.Nil(t, shardKey(t))
+
+	runAdmin := func(t *testing.T, command bson.D) bson.D {
+		t.Helper()
+
+		var res bson.D
+		require.NoError(t, adminDB.RunCommand(ctx, command).Decode(&res))
+
+		return res
+	}
+
+	res := runAdmin(t, bson.D{{"shardCollection", ns}, {"key", bson.D{{"_id", "hashed"}}}})
+	AssertEqualDocuments(t, bson.D{{"collectionsharded", ns}, {"ok", float64(1)}}, res)
+	assert.Equal(t, bson.D{{"_id", "hashed"}}, shardKey(t))
+
+	res = runAdmin(t, bson.D{{"reshardCollection", ns}, {"key", bson.D{{"v", "hashed"}}}})
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+	assert.Equal(t, bson.D{{"v", "hashed"}}, shardKey(t))
+
+	res = runAdmin(t, bson.D{{"unshardCollection", ns}})
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+	assert.Nil(t, shardKey(t))
+
+	// data is not lost by resharding
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestShardingMongosHello(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB tests do not run against mongos")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	var hello bson.D
+	require.NoError(t, db.RunCommand(ctx, bson.D{{"hello", int32(1)}}).Decode(&hello))
+
+	m := hello.Map()
+	assert.Equal(t, "isdbgrid", m["msg"])
+	assert.NotContains(t, m, "setName")
+
+	var status bson.D
+	require.NoError(t, db.RunCommand(ctx, bson.D{{"serverStatus", int32(1)}}).Decode(&status))
+	assert.Equal(t, "mongos", status.Map()["process"])
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestTransactions(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	client := collection.Database().Client()
+
+	t.Run("Commit", func(t *testing.T) {
+		sess, err := client.StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		_, err = sess.WithTransaction(ctx, func(sctx mongo.SessionContext) (any, error) {
+			if _, err := collection.InsertOne(sctx, bson.D{{"_id", "commit"}}); err != nil {
+				return nil, err
+			}
+
+			// the document is visible inside the transaction
+			return nil, collection.FindOne(sctx, bson.D{{"_id", "commit"}}).Err()
+		})
+		require.NoError(t, err)
+
+		err = collection.FindOne(ctx, bson.D{{"_id", "commit"}}).Err()
+		require.NoError(t, err)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		sess, err := client.StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		err = mongo.WithSession(ctx, sess, func(sctx mongo.SessionContext) error {
+			require.NoError(t, sess.StartTransaction())
+
+			_, err := collection.InsertOne(sctx, bson.D{{"_id", "abort"}})
+			require.NoError(t, err)
+
+			// the document is not visible outside the transaction
+			err = collection.FindOne(ctx, bson.D{{"_id", "abort"}}).Err()
+			require.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+			return sess.AbortTransaction(sctx)
+		})
+		require.NoError(t, err)
+
+		err = collection.FindOne(ctx, bson.D{{"_id", "abort"}}).Err()
+		require.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		sess, err := client.StartSession()
+		require.NoError(t, err)
+
+		defer sess.EndSession(ctx)
+
+		err = mongo.WithSession(ctx, sess, func(sctx mongo.SessionContext) error {
+			require.NoError(t, sess.StartTransaction())
+
+			defer sess.AbortTransaction(ctx) //nolint:errcheck // not needed
+
+			return collection.Database().RunCommand(sctx, bson.D{{"dbStats", 1}}).Err()
+		})
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(263), ce.Code)
+		assert.Equal(t, "OperationNotSupportedInTransaction", ce.Name)
+	})
+}
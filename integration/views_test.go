@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestViews(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "a"}},
+		bson.D{{"_id", int32(2)}, {"v", "b"}},
+	})
+	require.NoError(t, err)
+
+	viewName := collection.Name() + "_view"
+	pipeline := bson.A{bson.D{{"$match", bson.D{{"v", "a"}}}}}
+
+	err = db.CreateView(ctx, viewName, collection.Name(), pipeline)
+	require.NoError(t, err)
+
+	var docs []bson.D
+	cursor, err := db.Collection(viewName).Find(ctx, bson.D{})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &docs))
+	assert.Equal(t, []bson.D{{{"_id", int32(1)}, {"v", "a"}}}, docs)
+
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{"name", viewName}})
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "view", specs[0].Type)
+
+	var options bson.D
+	require.NoError(t, bson.Unmarshal(specs[0].Options, &options))
+	AssertEqualDocuments(t, bson.D{{"viewOn", collection.Name()}, {"pipeline", pipeline}}, options)
+
+	t.Run("CollMod", func(t *testing.T) {
+		newPipeline := bson.A{bson.D{{"$match", bson.D{{"v", "b"}}}}}
+
+		err = db.RunCommand(ctx, bson.D{
+			{"collMod", viewName},
+			{"viewOn", collection.Name()},
+			{"pipeline", newPipeline},
+		}).Err()
+		require.NoError(t, err)
+
+		cursor, err = db.Collection(viewName).Find(ctx, bson.D{})
+		require.NoError(t, err)
+		require.NoError(t, cursor.All(ctx, &docs))
+		assert.Equal(t, []bson.D{{{"_id", int32(2)}, {"v", "b"}}}, docs)
+	})
+
+	t.Run("PipelineWithoutViewOn", func(t *testing.T) {
+		err = db.RunCommand(ctx, bson.D{{"create", viewName + "_bad"}, {"pipeline", pipeline}}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(72), ce.Code)
+	})
+}
+
+func TestCreateNotImplementedOptions(t *testing.T) {
+	setup.SkipForMongoDB(t, "MongoDB supports all options")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{"create", collection.Name()},
+		{"collation", bson.D{{"locale", "en"}}},
+	}).Err()
+
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    238,
+		Name:    "NotImplemented",
+		Message: `create option "collation" is not implemented yet`,
+	}, err)
+}
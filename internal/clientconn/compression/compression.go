@@ -0,0 +1,229 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compression implements OP_COMPRESSED message compressors.
+package compression
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/FerretDB/wire"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// ID represents compressor ID as defined by the wire protocol.
+type ID uint8
+
+// Compressor IDs.
+const (
+	Noop   = ID(0)
+	Snappy = ID(1)
+	Zlib   = ID(2)
+	Zstd   = ID(3)
+)
+
+// names maps compressor names used in `hello` to IDs.
+var names = map[string]ID{
+	"noop":   Noop,
+	"snappy": Snappy,
+	"zlib":   Zlib,
+	"zstd":   Zstd,
+}
+
+// String returns compressor name.
+func (id ID) String() string {
+	for name, i := range names {
+		if i == id {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("ID(%d)", id)
+}
+
+// AllNames contains names of all supported compressors, with the first ones being preferred.
+var AllNames = []string{"snappy", "zstd", "zlib"}
+
+// Compressors represents a set of compressors allowed for client connections.
+//
+// Nil value has no allowed compressors.
+type Compressors struct {
+	names     []string
+	zlibLevel int
+
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+}
+
+// New creates a new set of allowed compressors with given names.
+//
+// Empty list, or a list with a single "disabled" value, disables compression.
+func New(allowed []string, zlibLevel int) (*Compressors, error) {
+	if zlibLevel < zlib.DefaultCompression || zlibLevel > zlib.BestCompression {
+		return nil, lazyerrors.Errorf("invalid zlib level %d", zlibLevel)
+	}
+
+	c := &Compressors{
+		zlibLevel: zlibLevel,
+	}
+
+	if len(allowed) == 1 && allowed[0] == "disabled" {
+		allowed = nil
+	}
+
+	for _, name := range allowed {
+		if !slices.Contains(AllNames, name) {
+			return nil, lazyerrors.Errorf("unknown compressor %q", name)
+		}
+
+		if !slices.Contains(c.names, name) {
+			c.names = append(c.names, name)
+		}
+	}
+
+	var err error
+
+	if c.zstdEncoder, err = zstd.NewWriter(nil); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if c.zstdDecoder, err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(wire.MaxMsgLen)); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return c, nil
+}
+
+// Negotiate returns allowed compressors requested by the client, in the client's order of preference.
+func (c *Compressors) Negotiate(requested []string) []string {
+	if c == nil {
+		return nil
+	}
+
+	var res []string
+
+	for _, name := range requested {
+		if slices.Contains(c.names, name) && !slices.Contains(res, name) {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+// Allowed returns true if the compressor with the given ID could be used.
+//
+// Noop compressor is always allowed.
+func (c *Compressors) Allowed(id ID) bool {
+	if id == Noop {
+		return true
+	}
+
+	if c == nil {
+		return false
+	}
+
+	return slices.Contains(c.names, id.String())
+}
+
+// Compress compresses b with the given compressor.
+func (c *Compressors) Compress(id ID, b []byte) ([]byte, error) {
+	switch id {
+	case Noop:
+		return b, nil
+
+	case Snappy:
+		return snappy.Encode(nil, b), nil
+
+	case Zlib:
+		var buf bytes.Buffer
+
+		w := must.NotFail(zlib.NewWriterLevel(&buf, c.zlibLevel))
+
+		if _, err := w.Write(b); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return buf.Bytes(), nil
+
+	case Zstd:
+		return c.zstdEncoder.EncodeAll(b, nil), nil
+
+	default:
+		return nil, lazyerrors.Errorf("unsupported compressor %s", id)
+	}
+}
+
+// Decompress decompresses b with the given compressor.
+// The result must have exactly the given size.
+func (c *Compressors) Decompress(id ID, b []byte, size int) ([]byte, error) {
+	var res []byte
+	var err error
+
+	switch id {
+	case Noop:
+		res = b
+
+	case Snappy:
+		var n int
+		if n, err = snappy.DecodedLen(b); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if n != size {
+			return nil, lazyerrors.Errorf("expected %d decompressed bytes, got %d", size, n)
+		}
+
+		res, err = snappy.Decode(nil, b)
+
+	case Zlib:
+		var r io.ReadCloser
+		if r, err = zlib.NewReader(bytes.NewReader(b)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		defer r.Close()
+
+		// read one more byte to detect too large messages
+		res, err = io.ReadAll(io.LimitReader(r, int64(size)+1))
+
+	case Zstd:
+		res, err = c.zstdDecoder.DecodeAll(b, make([]byte, 0, size))
+
+	default:
+		return nil, lazyerrors.Errorf("unsupported compressor %s", id)
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(res) != size {
+		return nil, lazyerrors.Errorf("expected %d decompressed bytes, got %d", size, len(res))
+	}
+
+	return res, nil
+}
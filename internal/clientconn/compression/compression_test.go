@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	c, err := New([]string{"zstd", "snappy", "zstd"}, 6)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"snappy", "zstd"}, c.Negotiate([]string{"zlib", "snappy", "zstd", "snappy"}))
+	assert.True(t, c.Allowed(Noop))
+	assert.True(t, c.Allowed(Zstd))
+	assert.False(t, c.Allowed(Zlib))
+
+	c, err = New([]string{"disabled"}, 6)
+	require.NoError(t, err)
+	assert.Empty(t, c.Negotiate(AllNames))
+
+	_, err = New([]string{"lz4"}, 6)
+	assert.Error(t, err)
+
+	_, err = New(AllNames, 10)
+	assert.Error(t, err)
+
+	_, err = New(AllNames, -2)
+	assert.Error(t, err)
+
+	var nilC *Compressors
+	assert.Empty(t, nilC.Negotiate(AllNames))
+	assert.False(t, nilC.Allowed(Snappy))
+}
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(AllNames, 6)
+	require.NoError(t, err)
+
+	msg := wire.MustOpMsg("find", "collection", "filter", strings.Repeat("a", 1000), "$db", "test")
+
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     1,
+		ResponseTo:    2,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	for _, id := range []ID{Noop, Snappy, Zlib, Zstd} {
+		t.Run(id.String(), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := bufio.NewWriter(&buf)
+
+			saved, err := c.WriteMessage(w, header, msg, id)
+			require.NoError(t, err)
+			require.NoError(t, w.Flush())
+
+			if id != Noop {
+				assert.Positive(t, saved)
+				assert.Equal(t, int(header.MessageLength)-saved, buf.Len())
+			}
+
+			actualHeader, actualBody, actualID, actualSaved, err := c.ReadMessage(bufio.NewReader(&buf), AllNames)
+			require.NoError(t, err)
+
+			assert.Equal(t, header, actualHeader)
+			assert.Equal(t, msg.StringIndent(), actualBody.StringIndent())
+			assert.Equal(t, id, actualID)
+			assert.Equal(t, saved, actualSaved)
+		})
+	}
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+
+		_, err := c.WriteMessage(w, header, msg, Zlib)
+		require.NoError(t, err)
+		require.NoError(t, w.Flush())
+
+		snappyOnly, err := New([]string{"snappy"}, 6)
+		require.NoError(t, err)
+
+		_, _, _, _, err = snappyOnly.ReadMessage(bufio.NewReader(&buf), AllNames)
+		assert.Error(t, err)
+	})
+
+	t.Run("NotNegotiated", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+
+		_, err := c.WriteMessage(w, header, msg, Zlib)
+		require.NoError(t, err)
+		require.NoError(t, w.Flush())
+
+		_, _, _, _, err = c.ReadMessage(bufio.NewReader(&buf), []string{"snappy"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// compressedHeaderLen is the length of OP_COMPRESSED fields before the compressed message:
+// original opcode, uncompressed size, and compressor ID.
+const compressedHeaderLen = 4 + 4 + 1
+
+// ReadMessage reads a message from the reader like [wire.ReadMessage] does,
+// transparently decompressing OP_COMPRESSED messages.
+//
+// It returns the header and body of the original message,
+// the compressor ID (that is [Noop] for uncompressed messages),
+// and the number of bytes saved by compression.
+//
+// Compressors other than [Noop] should be negotiated for the connection, see [Compressors.Negotiate].
+func (c *Compressors) ReadMessage(r *bufio.Reader, negotiated []string) (*wire.MsgHeader, wire.MsgBody, ID, int, error) {
+	// let wire.ReadMessage handle all errors, including ErrZeroRead
+	b, err := r.Peek(wire.MsgHeaderLen)
+	if err != nil || wire.OpCode(binary.LittleEndian.Uint32(b[12:16])) != wire.OpCodeCompressed {
+		header, body, err := wire.ReadMessage(r)
+		return header, body, Noop, 0, err
+	}
+
+	messageLength := int32(binary.LittleEndian.Uint32(b[0:4]))
+	if messageLength < wire.MsgHeaderLen+compressedHeaderLen || messageLength > wire.MaxMsgLen {
+		return nil, nil, Noop, 0, lazyerrors.Errorf("invalid message length %d", messageLength)
+	}
+
+	msg := make([]byte, messageLength)
+	if _, err = io.ReadFull(r, msg); err != nil {
+		return nil, nil, Noop, 0, lazyerrors.Error(err)
+	}
+
+	opCode := binary.LittleEndian.Uint32(msg[16:20])
+	size := int32(binary.LittleEndian.Uint32(msg[20:24]))
+	id := ID(msg[24])
+
+	if size < 0 || size > wire.MaxMsgLen-wire.MsgHeaderLen {
+		return nil, nil, id, 0, lazyerrors.Errorf("invalid uncompressed size %d", size)
+	}
+
+	if !c.Allowed(id) {
+		return nil, nil, id, 0, lazyerrors.Errorf("compressor %s is not allowed", id)
+	}
+
+	if id != Noop && !slices.Contains(negotiated, id.String()) {
+		return nil, nil, id, 0, lazyerrors.Errorf("compressor %s was not negotiated", id)
+	}
+
+	body, err := c.Decompress(id, msg[wire.MsgHeaderLen+compressedHeaderLen:], int(size))
+	if err != nil {
+		return nil, nil, id, 0, lazyerrors.Error(err)
+	}
+
+	// reconstruct the original message and let wire.ReadMessage parse and validate it
+	orig := make([]byte, wire.MsgHeaderLen, wire.MsgHeaderLen+len(body))
+	binary.LittleEndian.PutUint32(orig[0:4], uint32(wire.MsgHeaderLen+len(body)))
+	copy(orig[4:12], msg[4:12])
+	binary.LittleEndian.PutUint32(orig[12:16], opCode)
+	orig = append(orig, body...)
+
+	header, msgBody, err := wire.ReadMessage(bufio.NewReader(bytes.NewReader(orig)))
+	if err != nil {
+		return nil, nil, id, 0, lazyerrors.Error(err)
+	}
+
+	return header, msgBody, id, len(orig) - len(msg), nil
+}
+
+// WriteMessage writes a message to the writer like [wire.WriteMessage] does,
+// compressing it with the given compressor unless it is [Noop].
+//
+// It returns the number of bytes saved by compression.
+func (c *Compressors) WriteMessage(w *bufio.Writer, header *wire.MsgHeader, body wire.MsgBody, id ID) (int, error) {
+	if id == Noop {
+		return 0, wire.WriteMessage(w, header, body)
+	}
+
+	b, err := body.MarshalBinary()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	compressed, err := c.Compress(id, b)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	compressedHeader := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + compressedHeaderLen + len(compressed)),
+		RequestID:     header.RequestID,
+		ResponseTo:    header.ResponseTo,
+		OpCode:        wire.OpCodeCompressed,
+	}
+
+	hb, err := compressedHeader.MarshalBinary()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	fields := make([]byte, compressedHeaderLen)
+	binary.LittleEndian.PutUint32(fields[0:4], uint32(header.OpCode))
+	binary.LittleEndian.PutUint32(fields[4:8], uint32(len(b)))
+	fields[8] = byte(id)
+
+	for _, part := range [][]byte{hb, fields, compressed} {
+		if _, err = w.Write(part); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+	}
+
+	return int(header.MessageLength - compressedHeader.MessageLength), nil
+}
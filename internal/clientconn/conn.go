@@ -17,8 +17,10 @@ package clientconn
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -39,6 +41,7 @@ import (
 	otelcodes "go.opentelemetry.io/otel/codes"
 	oteltrace "go.opentelemetry.io/otel/trace"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/compression"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
@@ -48,6 +51,7 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/observability"
+	"github.com/FerretDB/FerretDB/v2/internal/util/redact"
 )
 
 // Mode represents FerretDB mode of operation.
@@ -148,10 +152,34 @@ func (c *conn) run(ctx context.Context) (err error) {
 		if err != nil {
 			return
 		}
+
+		connInfo.Local, err = netip.ParseAddrPort(c.netConn.LocalAddr().String())
+		if err != nil {
+			return
+		}
+	} else {
+		connInfo.Socket = c.netConn.LocalAddr().String()
+	}
+
+	// complete handshake to get a verified client certificate, if any
+	if tlsConn, ok := c.netConn.(*tls.Conn); ok {
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			err = lazyerrors.Error(err)
+			return
+		}
+
+		connInfo.TLS = true
+
+		if certs := tlsConn.ConnectionState().PeerCertificates; len(certs) > 0 {
+			connInfo.ClientCert = certs[0]
+		}
 	}
 
 	ctx = conninfo.Ctx(ctx, connInfo)
 
+	c.h.AddConn(connInfo)
+	defer c.h.RemoveConn(connInfo)
+
 	done := make(chan struct{})
 
 	// handle ctx cancellation
@@ -179,7 +207,11 @@ func (c *conn) run(ctx context.Context) (err error) {
 
 	bufr := bufio.NewReader(c.netConn)
 
-	// if test record path is set, split netConn reader to write to file and bufr
+	// rec is a test record writer; raw contains bytes read from netConn but not yet recorded
+	var rec io.Writer
+	var raw *bytes.Buffer
+
+	// if test record path is set, split netConn reader to write to raw buffer and bufr
 	if c.testRecordsDir != "" {
 		if err = os.MkdirAll(c.testRecordsDir, 0o777); err != nil {
 			return
@@ -226,8 +258,9 @@ func (c *conn) run(ctx context.Context) (err error) {
 			}
 		}()
 
-		r := io.TeeReader(c.netConn, io.MultiWriter(f, h))
-		bufr = bufio.NewReader(r)
+		rec = io.MultiWriter(f, h)
+		raw = new(bytes.Buffer)
+		bufr = bufio.NewReader(io.TeeReader(c.netConn, raw))
 	}
 
 	bufw := bufio.NewWriter(c.netConn)
@@ -249,15 +282,30 @@ func (c *conn) run(ctx context.Context) (err error) {
 		var reqBody wire.MsgBody
 		var resHeader *wire.MsgHeader
 		var resBody wire.MsgBody
+		var compressor compression.ID
+		var saved int
 
-		reqHeader, reqBody, err = wire.ReadMessage(bufr)
+		reqHeader, reqBody, compressor, saved, err = c.h.Compressors.ReadMessage(bufr, connInfo.Compressors())
 		if err != nil {
 			return
 		}
 
+		c.addCompressionSaved(compressor, "request", saved)
+
+		// get command name before handling that could modify reqBody's documents;
+		// it is used to mask secrets in both request and response
+		command := redact.Command(reqBody)
+
+		if rec != nil {
+			// bytes buffered by bufr belong to the next message
+			if err = writeRecord(rec, raw.Next(raw.Len()-bufr.Buffered()), reqHeader, reqBody, command); err != nil {
+				return
+			}
+		}
+
 		if c.l.Enabled(ctx, slog.LevelDebug) {
 			c.l.DebugContext(ctx, "Request header: "+reqHeader.String())
-			c.l.DebugContext(ctx, "Request message:\n"+reqBody.StringIndent()+"\n")
+			c.l.DebugContext(ctx, "Request message:\n"+c.logMessage(reqBody, command)+"\n")
 		}
 
 		// diffLogLevel provides the level of logging for the diff between the "normal" and "proxy" responses.
@@ -281,14 +329,14 @@ func (c *conn) run(ctx context.Context) (err error) {
 		var resCloseConn bool
 		if c.mode != ProxyMode {
 			resHeader, resBody, resCloseConn = c.route(ctx, reqHeader, reqBody)
-			if level := c.logResponse(ctx, "Response", resHeader, resBody, resCloseConn); level > diffLogLevel {
+			if level := c.logResponse(ctx, "Response", command, resHeader, resBody, resCloseConn); level > diffLogLevel {
 				diffLogLevel = level
 			}
 		}
 
 		// log proxy response after the normal response to make it less confusing
 		if c.mode != NormalMode {
-			if level := c.logResponse(ctx, "Proxy response", proxyHeader, proxyBody, false); level > diffLogLevel {
+			if level := c.logResponse(ctx, "Proxy response", command, proxyHeader, proxyBody, false); level > diffLogLevel {
 				diffLogLevel = level
 			}
 		}
@@ -311,11 +359,11 @@ func (c *conn) run(ctx context.Context) (err error) {
 			var resBodyString, proxyBodyString string
 
 			if resBody != nil {
-				resBodyString = resBody.StringIndent()
+				resBodyString = c.logMessage(resBody, command)
 			}
 
 			if proxyBody != nil {
-				proxyBodyString = proxyBody.StringIndent()
+				proxyBodyString = c.logMessage(proxyBody, command)
 			}
 
 			var diffBody string
@@ -345,12 +393,15 @@ func (c *conn) run(ctx context.Context) (err error) {
 			panic("no response to send to client")
 		}
 
-		if err = wire.WriteMessage(bufw, resHeader, resBody); err != nil {
+		// reply with the same compressor that was used for the request
+		if saved, err = c.h.Compressors.WriteMessage(bufw, resHeader, resBody, compressor); err != nil {
 			c.l.DebugContext(ctx, "Failed to write message", logging.Error(err))
 
 			return
 		}
 
+		c.addCompressionSaved(compressor, "response", saved)
+
 		if err = bufw.Flush(); err != nil {
 			c.l.DebugContext(ctx, "Failed to flush buffer", logging.Error(err))
 
@@ -433,7 +484,7 @@ func (c *conn) route(connCtx context.Context, reqHeader *wire.MsgHeader, reqBody
 		connCtx, span = otel.Tracer("").Start(connCtx, "")
 
 		if err == nil {
-			resBody = c.handleOpMsg(connCtx, msg, command)
+			resBody = c.addClusterTime(connCtx, c.handleOpMsg(connCtx, msg, command), command)
 		}
 
 	case wire.OpCodeQuery:
@@ -578,11 +629,84 @@ func (c *conn) handleOpMsg(connCtx context.Context, msg *wire.OpMsg, command str
 	return res
 }
 
+// noClusterTimeCommands contains handshake and heartbeat commands;
+// their responses do not include cluster time.
+var noClusterTimeCommands = map[string]struct{}{
+	"authenticate": {},
+	"hello":        {},
+	"isMaster":     {},
+	"ismaster":     {},
+	"ping":         {},
+	"saslContinue": {},
+	"saslStart":    {},
+}
+
+// addClusterTime returns OP_MSG response with `operationTime` and `$clusterTime` fields added,
+// so drivers could use causally consistent sessions.
+//
+// The response is returned as-is for handshake and heartbeat commands,
+// and if the cluster time is not known yet.
+func (c *conn) addClusterTime(connCtx context.Context, msg *wire.OpMsg, command string) *wire.OpMsg {
+	if _, ok := noClusterTimeCommands[command]; ok {
+		return msg
+	}
+
+	ts := c.h.Pool.ClusterTime()
+	if ts == 0 {
+		return msg
+	}
+
+	doc, err := msg.RawSection0().Decode()
+	if err != nil {
+		c.l.DebugContext(connCtx, "Failed to decode response", logging.Error(err))
+		return msg
+	}
+
+	// we don't have keys to sign cluster time, so the signature is always empty
+	clusterTime := wirebson.MustDocument(
+		"clusterTime", ts,
+		"signature", wirebson.MustDocument(
+			"hash", wirebson.Binary{B: make([]byte, 20)},
+			"keyId", int64(0),
+		),
+	)
+
+	must.NoError(doc.Add("$clusterTime", clusterTime))
+	must.NoError(doc.Add("operationTime", ts))
+
+	res, err := wire.NewOpMsg(doc)
+	if err != nil {
+		c.l.DebugContext(connCtx, "Failed to encode response", logging.Error(err))
+		return msg
+	}
+
+	return res
+}
+
+// addCompressionSaved updates metrics with the number of bytes saved by compression.
+func (c *conn) addCompressionSaved(compressor compression.ID, direction string, saved int) {
+	if compressor == compression.Noop {
+		return
+	}
+
+	// counters can't be decreased; small messages could become larger after compression
+	c.m.CompressionSaved.WithLabelValues(compressor.String(), direction).Add(float64(max(saved, 0)))
+}
+
+// logMessage returns a string representation of the request or response message for logging
+// with secrets (and, if configured, all client data) masked.
+//
+// The param `command` should be the name of the command of the request.
+func (c *conn) logMessage(body wire.MsgBody, command string) string {
+	return redact.LogMessage(body, command, c.h.RedactClientLogData)
+}
+
 // logResponse logs response's header and body and returns the log level that was used.
 //
 // The param `who` will be used in logs and should represent the type of the response,
 // for example "Response" or "Proxy Response".
-func (c *conn) logResponse(ctx context.Context, who string, resHeader *wire.MsgHeader, resBody wire.MsgBody, closeConn bool) slog.Level { //nolint:lll // for readability
+// The param `command` should be the name of the command of the request.
+func (c *conn) logResponse(ctx context.Context, who, command string, resHeader *wire.MsgHeader, resBody wire.MsgBody, closeConn bool) slog.Level { //nolint:lll // for readability
 	level := slog.LevelDebug
 
 	if resHeader.OpCode == wire.OpCodeMsg {
@@ -615,8 +739,46 @@ func (c *conn) logResponse(ctx context.Context, who string, resHeader *wire.MsgH
 
 	if c.l.Enabled(ctx, level) {
 		c.l.Log(ctx, level, who+" header: "+resHeader.String())
-		c.l.Log(ctx, level, who+" message:\n"+resBody.StringIndent()+"\n")
+		c.l.Log(ctx, level, who+" message:\n"+c.logMessage(resBody, command)+"\n")
 	}
 
 	return level
 }
+
+// writeRecord writes the request message to the test record with secrets masked.
+//
+// Raw message bytes are written as-is if there are no secrets.
+func writeRecord(w io.Writer, raw []byte, header *wire.MsgHeader, body wire.MsgBody, command string) error {
+	redacted, err := redact.Message(body, command)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if redacted == body {
+		if _, err = w.Write(raw); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	}
+
+	b, err := redacted.MarshalBinary()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	h := *header
+	h.MessageLength = int32(wire.MsgHeaderLen + len(b))
+
+	bufw := bufio.NewWriter(w)
+
+	if err = wire.WriteMessage(bufw, &h, redacted); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if err = bufw.Flush(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
@@ -16,26 +16,48 @@
 package conninfo
 
 import (
+	"crypto/x509"
 	"net/netip"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/scram"
 )
 
+// lastID is the last assigned connection ID.
+var lastID atomic.Int64
+
 // ConnInfo represents client connection information.
 type ConnInfo struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
-	conv         *scram.Conv    // protected by rw
-	Peer         netip.AddrPort // invalid for Unix domain sockets
-	rw           sync.RWMutex   // rw
-	metadataRecv bool           // protected by rw
-	steps        int            // protected by rw
+	conv        *scram.Conv        // protected by rw
+	metadata    *wirebson.Document // protected by rw
+	compressors []string           // protected by rw
+	ClientCert  *x509.Certificate  // verified TLS client certificate; nil if not provided
+	Socket      string             // Unix domain socket path; empty for TCP
+	Peer        netip.AddrPort     // invalid for Unix domain sockets
+	Local       netip.AddrPort     // invalid for Unix domain sockets
+	expires     time.Time          // protected by rw; zero if authentication does not expire
+	extUser     string             // protected by rw
+	user        string             // protected by rw
+	extMech     string             // protected by rw
+	extGroups   []string           // protected by rw
+	pending     string             // protected by rw
+	rw          sync.RWMutex       // rw
+	ID          int64              // unique for the process lifetime
+	steps       int                // protected by rw
+	TLS         bool               // true for TLS connections
 }
 
-// New creates a new ConnInfo.
+// New creates a new ConnInfo with a new unique ID.
 func New() *ConnInfo {
-	return new(ConnInfo)
+	return &ConnInfo{
+		ID: lastID.Add(1),
+	}
 }
 
 // Conv returns SCRAM conversation.
@@ -46,32 +68,205 @@ func (ci *ConnInfo) Conv() *scram.Conv {
 	return ci.conv
 }
 
-// SetConv sets SCRAM conversation.
-// It returns true if existing conversation was replaced.
+// SetConv sets SCRAM conversation and resets the external user.
+// It returns true if existing conversation or external user was replaced.
 func (ci *ConnInfo) SetConv(conv *scram.Conv) bool {
 	ci.rw.Lock()
 	defer ci.rw.Unlock()
 
-	was := ci.conv != nil
+	was := ci.conv != nil || ci.extUser != "" || ci.user != ""
 	ci.conv = conv
+	ci.extUser, ci.extMech, ci.extGroups, ci.user = "", "", nil, ""
+	ci.expires, ci.pending = time.Time{}, ""
 
 	return was
 }
 
+// ExternalUser returns the username and the mechanism of the user authenticated
+// without SCRAM conversation (for example, with MONGODB-X509), or empty strings.
+func (ci *ConnInfo) ExternalUser() (string, string) {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.extUser, ci.extMech
+}
+
+// ExternalGroups returns groups of the external user provided by the identity source
+// (for example, with PLAIN), or nil.
+// It should not be modified.
+func (ci *ConnInfo) ExternalGroups() []string {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.extGroups
+}
+
+// SetExternalUser sets the user authenticated with the given mechanism
+// without SCRAM conversation and resets SCRAM conversation.
+// Groups may be nil.
+func (ci *ConnInfo) SetExternalUser(username, mechanism string, groups []string) {
+	ci.rw.Lock()
+	defer ci.rw.Unlock()
+
+	ci.conv = nil
+	ci.extUser, ci.extMech, ci.extGroups, ci.user = username, mechanism, groups, ""
+	ci.expires, ci.pending = time.Time{}, ""
+}
+
+// SetUser sets the user authenticated outside of the MongoDB protocol
+// (for example, by the Data API with an API key or a bearer token)
+// and resets SCRAM conversation and the external user.
+//
+// Unlike the external user, that user is stored by DocumentDB.
+func (ci *ConnInfo) SetUser(username string) {
+	ci.rw.Lock()
+	defer ci.rw.Unlock()
+
+	ci.conv = nil
+	ci.extUser, ci.extMech, ci.extGroups, ci.user = "", "", nil, username
+	ci.expires, ci.pending = time.Time{}, ""
+}
+
+// SetExpires sets the time after which the authenticated user should re-authenticate
+// (for example, when the MONGODB-OIDC access token expires).
+func (ci *ConnInfo) SetExpires(expires time.Time) {
+	ci.rw.Lock()
+	defer ci.rw.Unlock()
+
+	ci.expires = expires
+}
+
+// Expired returns true if authentication expired and the user should re-authenticate.
+func (ci *ConnInfo) Expired() bool {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return !ci.expires.IsZero() && time.Now().After(ci.expires)
+}
+
+// PendingMechanism returns the mechanism of the started multi-step SASL conversation
+// that is not SCRAM (for example, MONGODB-OIDC), or empty string.
+func (ci *ConnInfo) PendingMechanism() string {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.pending
+}
+
+// SetPendingMechanism resets the authenticated user and sets the mechanism of the started
+// multi-step SASL conversation that is not SCRAM.
+func (ci *ConnInfo) SetPendingMechanism(mechanism string) {
+	ci.rw.Lock()
+	defer ci.rw.Unlock()
+
+	ci.conv = nil
+	ci.extUser, ci.extMech, ci.extGroups, ci.user = "", "", nil, ""
+	ci.expires, ci.pending = time.Time{}, mechanism
+}
+
+// Username returns the name of the external user, the user set by [ConnInfo.SetUser],
+// or the client's identification from SCRAM conversation.
+// It might not be authenticated, see [ConnInfo.Authenticated].
+func (ci *ConnInfo) Username() string {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	if ci.extUser != "" {
+		return ci.extUser
+	}
+
+	if ci.user != "" {
+		return ci.user
+	}
+
+	return ci.conv.Username()
+}
+
+// UserDB returns the username like [ConnInfo.Username] does, and the database of that user.
+// External users belong to the `$external` database;
+// other users are global and belong to the `admin` database.
+// Both are empty if there is no user.
+func (ci *ConnInfo) UserDB() (string, string) {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	if ci.extUser != "" {
+		return ci.extUser, "$external"
+	}
+
+	if ci.user != "" {
+		return ci.user, "admin"
+	}
+
+	if u := ci.conv.Username(); u != "" {
+		return u, "admin"
+	}
+
+	return "", ""
+}
+
+// UserID returns the namespaced identity of the user as `<username>@<database>`, see [ConnInfo.UserDB],
+// or empty string if there is no user.
+//
+// Unlike usernames, it is different for external and other users with the same name.
+func (ci *ConnInfo) UserID() string {
+	username, db := ci.UserDB()
+	if username == "" {
+		return ""
+	}
+
+	return username + "@" + db
+}
+
+// Authenticated returns true if the external user or the user set by [ConnInfo.SetUser] is set,
+// or SCRAM conversation succeeded.
+func (ci *ConnInfo) Authenticated() bool {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.extUser != "" || ci.user != "" || ci.conv.Succeed()
+}
+
 // MetadataRecv returns whatever client metadata was received already.
 func (ci *ConnInfo) MetadataRecv() bool {
 	ci.rw.RLock()
 	defer ci.rw.RUnlock()
 
-	return ci.metadataRecv
+	return ci.metadata != nil
+}
+
+// Metadata returns client metadata from the first `hello` command, or nil.
+// It should not be modified.
+func (ci *ConnInfo) Metadata() *wirebson.Document {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.metadata
+}
+
+// SetMetadata stores client metadata and marks it as received.
+func (ci *ConnInfo) SetMetadata(metadata *wirebson.Document) {
+	ci.rw.Lock()
+	defer ci.rw.Unlock()
+
+	ci.metadata = metadata
+}
+
+// Compressors returns OP_COMPRESSED compressors negotiated by the `hello` command, or nil.
+// It should not be modified.
+func (ci *ConnInfo) Compressors() []string {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.compressors
 }
 
-// SetMetadataRecv marks client metadata as received.
-func (ci *ConnInfo) SetMetadataRecv() {
+// SetCompressors stores OP_COMPRESSED compressors negotiated by the `hello` command.
+func (ci *ConnInfo) SetCompressors(compressors []string) {
 	ci.rw.Lock()
 	defer ci.rw.Unlock()
 
-	ci.metadataRecv = true
+	ci.compressors = compressors
 }
 
 // DecrementSteps decreases the steps counter and returns the number of steps left
@@ -26,8 +26,9 @@ import (
 
 // ConnMetrics represents metrics of an individual conn or a collection of conns.
 type ConnMetrics struct {
-	Requests  *prometheus.CounterVec
-	Responses *prometheus.CounterVec
+	Requests         *prometheus.CounterVec
+	Responses        *prometheus.CounterVec
+	CompressionSaved *prometheus.CounterVec
 }
 
 // commandMetrics represents command results metrics.
@@ -57,6 +58,15 @@ func newConnMetrics() *ConnMetrics {
 			},
 			[]string{"opcode", "command", "argument", "result"},
 		),
+		CompressionSaved: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "compression_saved_bytes_total",
+				Help:      "Total number of bytes saved by OP_COMPRESSED compression.",
+			},
+			[]string{"compressor", "direction"},
+		),
 	}
 }
 
@@ -64,12 +74,14 @@ func newConnMetrics() *ConnMetrics {
 func (cm *ConnMetrics) Describe(ch chan<- *prometheus.Desc) {
 	cm.Requests.Describe(ch)
 	cm.Responses.Describe(ch)
+	cm.CompressionSaved.Describe(ch)
 }
 
 // Collect implements [prometheus.Collector].
 func (cm *ConnMetrics) Collect(ch chan<- prometheus.Metric) {
 	cm.Requests.Collect(ch)
 	cm.Responses.Collect(ch)
+	cm.CompressionSaved.Collect(ch)
 }
 
 // GetResponses returns a map with all response metrics:
@@ -63,6 +63,9 @@ type NewListenerOpts struct {
 	TLSKeyFile  string
 	TLSCAFile   string
 
+	// TLSRequireClientCert requires verified client certificates if TLSCAFile is set.
+	TLSRequireClientCert bool
+
 	ProxyAddr        string
 	ProxyTLSCertFile string
 	ProxyTLSKeyFile  string
@@ -111,7 +114,7 @@ func Listen(opts *NewListenerOpts) (*Listener, error) {
 	if l.TLS != "" {
 		var config *tls.Config
 
-		if config, err = tlsutil.Config(l.TLSCertFile, l.TLSKeyFile, l.TLSCAFile); err != nil {
+		if config, err = tlsutil.Config(l.TLSCertFile, l.TLSKeyFile, l.TLSCAFile, l.TLSRequireClientCert); err != nil {
 			return nil, lazyerrors.Error(err)
 		}
 
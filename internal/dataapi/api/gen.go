@@ -42,6 +42,79 @@ type AggregateResponseBody struct {
 	Documents []map[string]interface{} `json:"documents"`
 }
 
+// CountDocumentsRequestBody defines model for CountDocumentsRequestBody.
+type CountDocumentsRequestBody struct {
+	// Collection The name of a collection in the specified database.
+	Collection string `json:"collection"`
+
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Database The name of a database in the specified data source.
+	Database string `json:"database"`
+
+	// Filter A MongoDB query filter that matches documents. For a list of all query operators that the Data API supports, see [Query Operators](https://www.mongodb.com/docs/atlas/app-services/mongodb/crud-and-aggregation-apis/#query-operators).
+	Filter *json.RawMessage `json:"filter,omitempty"`
+
+	// Limit The maximum number of matching documents to include the in the response.
+	Limit *float32 `json:"limit,omitempty"`
+
+	// Skip The number of matching documents to omit from the response.
+	Skip *float32 `json:"skip,omitempty"`
+}
+
+// CountDocumentsResponseBody defines model for CountDocumentsResponseBody.
+type CountDocumentsResponseBody struct {
+	// Count The number of documents that match the specified filter.
+	Count interface{} `json:"count"`
+}
+
+// CreateIndexRequestBody defines model for CreateIndexRequestBody.
+type CreateIndexRequestBody struct {
+	// Collection The name of a collection in the specified database.
+	Collection string `json:"collection"`
+
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Database The name of a database in the specified data source.
+	Database string `json:"database"`
+
+	// Keys A document that contains field and value pairs where the field is the index key
+	// and the value describes the type of index for that field.
+	Keys json.RawMessage `json:"keys"`
+
+	// Name The name of the index. If omitted, the name is generated from index keys.
+	Name *string `json:"name,omitempty"`
+
+	// Unique When `true`, the index rejects documents with duplicate values of index keys.
+	Unique *bool `json:"unique,omitempty"`
+}
+
+// CreateIndexResponseBody defines model for CreateIndexResponseBody.
+type CreateIndexResponseBody struct {
+	// IndexName The name of the created index.
+	IndexName string `json:"indexName"`
+}
+
+// DatabaseNamespace defines model for DatabaseNamespace.
+type DatabaseNamespace struct {
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Database The name of a database in the specified data source.
+	Database string `json:"database"`
+}
+
 // DeleteRequestBody defines model for DeleteRequestBody.
 type DeleteRequestBody struct {
 	// Collection The name of a collection in the specified database.
@@ -66,6 +139,39 @@ type DeleteResponseBody struct {
 	DeletedCount interface{} `json:"deletedCount"`
 }
 
+// DistinctRequestBody defines model for DistinctRequestBody.
+type DistinctRequestBody struct {
+	// Collection The name of a collection in the specified database.
+	Collection string `json:"collection"`
+
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Database The name of a database in the specified data source.
+	Database string `json:"database"`
+
+	// Filter A MongoDB query filter that matches documents. For a list of all query operators that the Data API supports, see [Query Operators](https://www.mongodb.com/docs/atlas/app-services/mongodb/crud-and-aggregation-apis/#query-operators).
+	Filter *json.RawMessage `json:"filter,omitempty"`
+
+	// Key The field for which to return distinct values.
+	Key string `json:"key"`
+}
+
+// DistinctResponseBody defines model for DistinctResponseBody.
+type DistinctResponseBody struct {
+	// Values A list of distinct values of the specified field in documents that match the specified filter.
+	Values []interface{} `json:"values"`
+}
+
+// DropCollectionResponseBody defines model for DropCollectionResponseBody.
+type DropCollectionResponseBody struct {
+	// Dropped When `false`, the collection did not exist.
+	Dropped bool `json:"dropped"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// Error A message that describes the error.
@@ -147,6 +253,41 @@ type FindManyResponseBody struct {
 	Documents *[]map[string]interface{} `json:"documents,omitempty"`
 }
 
+// FindOneAndUpdateRequestBody defines model for FindOneAndUpdateRequestBody.
+type FindOneAndUpdateRequestBody struct {
+	// Collection The name of a collection in the specified database.
+	Collection string `json:"collection"`
+
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Database The name of a database in the specified data source.
+	Database string `json:"database"`
+
+	// Filter A MongoDB query filter that matches documents. For a list of all query operators that the Data API supports, see [Query Operators](https://www.mongodb.com/docs/atlas/app-services/mongodb/crud-and-aggregation-apis/#query-operators).
+	Filter *json.RawMessage `json:"filter,omitempty"`
+
+	// Projection A [MongoDB projection](https://www.mongodb.com/docs/manual/tutorial/project-fields-from-query-results/) for matched documents returned by the operation.
+	Projection *json.RawMessage `json:"projection,omitempty"`
+
+	// ReturnNewDocument When `true`, the updated document is returned instead of the original one.
+	ReturnNewDocument *bool `json:"returnNewDocument,omitempty"`
+
+	// Sort A [MongoDB sort expression](https://www.mongodb.com/docs/manual/reference/method/cursor.sort/) that indicates sorted field names and directions.
+	Sort *json.RawMessage `json:"sort,omitempty"`
+
+	// Update A MongoDB update expression to apply to the matching document. For a list of all update operators that the Data API supports, see [Update Operators](https://www.mongodb.com/docs/atlas/app-services/mongodb/crud-and-aggregation-apis/#update-operators).
+	Update json.RawMessage `json:"update"`
+
+	// Upsert When `true`, if the update filter does not match any
+	// existing documents, then insert a new document based on
+	// the filter and the specified update operation.
+	Upsert *bool `json:"upsert,omitempty"`
+}
+
 // FindOneRequestBody defines model for FindOneRequestBody.
 type FindOneRequestBody struct {
 	// Collection The name of a collection in the specified database.
@@ -228,6 +369,60 @@ type Limit struct {
 	Limit *float32 `json:"limit,omitempty"`
 }
 
+// ListCollectionsRequestBody defines model for ListCollectionsRequestBody.
+type ListCollectionsRequestBody struct {
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Database The name of a database in the specified data source.
+	Database string `json:"database"`
+
+	// Filter A MongoDB query filter that matches documents. For a list of all query operators that the Data API supports, see [Query Operators](https://www.mongodb.com/docs/atlas/app-services/mongodb/crud-and-aggregation-apis/#query-operators).
+	Filter *json.RawMessage `json:"filter,omitempty"`
+
+	// NameOnly When `true`, only names and types of collections are returned.
+	NameOnly *bool `json:"nameOnly,omitempty"`
+}
+
+// ListCollectionsResponseBody defines model for ListCollectionsResponseBody.
+type ListCollectionsResponseBody struct {
+	// Collections A list of collections that match the specified filter.
+	Collections []map[string]interface{} `json:"collections"`
+}
+
+// ListDatabasesRequestBody defines model for ListDatabasesRequestBody.
+type ListDatabasesRequestBody struct {
+	// DataSource The name of a linked MongoDB Atlas data source. This is
+	// commonly `"mongodb-atlas"` though it may be different in
+	// your App if you chose a different name when you created the
+	// data source.
+	DataSource string `json:"dataSource"`
+
+	// Filter A MongoDB query filter that matches documents. For a list of all query operators that the Data API supports, see [Query Operators](https://www.mongodb.com/docs/atlas/app-services/mongodb/crud-and-aggregation-apis/#query-operators).
+	Filter *json.RawMessage `json:"filter,omitempty"`
+
+	// NameOnly When `true`, only names of databases are returned.
+	NameOnly *bool `json:"nameOnly,omitempty"`
+}
+
+// ListDatabasesResponseBody defines model for ListDatabasesResponseBody.
+type ListDatabasesResponseBody struct {
+	// Databases A list of databases that match the specified filter.
+	Databases []map[string]interface{} `json:"databases"`
+
+	// TotalSize The total size of all listed databases in bytes.
+	TotalSize *interface{} `json:"totalSize,omitempty"`
+}
+
+// ListIndexesResponseBody defines model for ListIndexesResponseBody.
+type ListIndexesResponseBody struct {
+	// Indexes A list of indexes of the specified collection.
+	Indexes []map[string]interface{} `json:"indexes"`
+}
+
 // Namespace defines model for Namespace.
 type Namespace struct {
 	// Collection The name of a collection in the specified database.
@@ -311,24 +506,48 @@ type UnauthorizedRequestError = ErrorUserNotFound
 // AggregateJSONBody defines parameters for Aggregate.
 type AggregateJSONBody = AggregateRequestBody
 
+// CountDocumentsJSONBody defines parameters for CountDocuments.
+type CountDocumentsJSONBody = CountDocumentsRequestBody
+
+// CreateIndexJSONBody defines parameters for CreateIndex.
+type CreateIndexJSONBody = CreateIndexRequestBody
+
 // DeleteManyJSONBody defines parameters for DeleteMany.
 type DeleteManyJSONBody = DeleteRequestBody
 
 // DeleteOneJSONBody defines parameters for DeleteOne.
 type DeleteOneJSONBody = DeleteRequestBody
 
+// DistinctJSONBody defines parameters for Distinct.
+type DistinctJSONBody = DistinctRequestBody
+
+// DropCollectionJSONBody defines parameters for DropCollection.
+type DropCollectionJSONBody = Namespace
+
 // FindJSONBody defines parameters for Find.
 type FindJSONBody = FindManyRequestBody
 
 // FindOneJSONBody defines parameters for FindOne.
 type FindOneJSONBody = FindOneRequestBody
 
+// FindOneAndUpdateJSONBody defines parameters for FindOneAndUpdate.
+type FindOneAndUpdateJSONBody = FindOneAndUpdateRequestBody
+
 // InsertManyJSONBody defines parameters for InsertMany.
 type InsertManyJSONBody = InsertManyRequestBody
 
 // InsertOneJSONBody defines parameters for InsertOne.
 type InsertOneJSONBody = InsertOneRequestBody
 
+// ListCollectionsJSONBody defines parameters for ListCollections.
+type ListCollectionsJSONBody = ListCollectionsRequestBody
+
+// ListDatabasesJSONBody defines parameters for ListDatabases.
+type ListDatabasesJSONBody = ListDatabasesRequestBody
+
+// ListIndexesJSONBody defines parameters for ListIndexes.
+type ListIndexesJSONBody = Namespace
+
 // UpdateManyJSONBody defines parameters for UpdateMany.
 type UpdateManyJSONBody = UpdateRequestBody
 
@@ -338,24 +557,48 @@ type UpdateOneJSONBody = UpdateRequestBody
 // AggregateJSONRequestBody defines body for Aggregate for application/json ContentType.
 type AggregateJSONRequestBody = AggregateJSONBody
 
+// CountDocumentsJSONRequestBody defines body for CountDocuments for application/json ContentType.
+type CountDocumentsJSONRequestBody = CountDocumentsJSONBody
+
+// CreateIndexJSONRequestBody defines body for CreateIndex for application/json ContentType.
+type CreateIndexJSONRequestBody = CreateIndexJSONBody
+
 // DeleteManyJSONRequestBody defines body for DeleteMany for application/json ContentType.
 type DeleteManyJSONRequestBody = DeleteManyJSONBody
 
 // DeleteOneJSONRequestBody defines body for DeleteOne for application/json ContentType.
 type DeleteOneJSONRequestBody = DeleteOneJSONBody
 
+// DistinctJSONRequestBody defines body for Distinct for application/json ContentType.
+type DistinctJSONRequestBody = DistinctJSONBody
+
+// DropCollectionJSONRequestBody defines body for DropCollection for application/json ContentType.
+type DropCollectionJSONRequestBody = DropCollectionJSONBody
+
 // FindJSONRequestBody defines body for Find for application/json ContentType.
 type FindJSONRequestBody = FindJSONBody
 
 // FindOneJSONRequestBody defines body for FindOne for application/json ContentType.
 type FindOneJSONRequestBody = FindOneJSONBody
 
+// FindOneAndUpdateJSONRequestBody defines body for FindOneAndUpdate for application/json ContentType.
+type FindOneAndUpdateJSONRequestBody = FindOneAndUpdateJSONBody
+
 // InsertManyJSONRequestBody defines body for InsertMany for application/json ContentType.
 type InsertManyJSONRequestBody = InsertManyJSONBody
 
 // InsertOneJSONRequestBody defines body for InsertOne for application/json ContentType.
 type InsertOneJSONRequestBody = InsertOneJSONBody
 
+// ListCollectionsJSONRequestBody defines body for ListCollections for application/json ContentType.
+type ListCollectionsJSONRequestBody = ListCollectionsJSONBody
+
+// ListDatabasesJSONRequestBody defines body for ListDatabases for application/json ContentType.
+type ListDatabasesJSONRequestBody = ListDatabasesJSONBody
+
+// ListIndexesJSONRequestBody defines body for ListIndexes for application/json ContentType.
+type ListIndexesJSONRequestBody = ListIndexesJSONBody
+
 // UpdateManyJSONRequestBody defines body for UpdateMany for application/json ContentType.
 type UpdateManyJSONRequestBody = UpdateManyJSONBody
 
@@ -429,24 +672,48 @@ type ServerInterface interface {
 	// Aggregate Documents
 	// (POST /action/aggregate)
 	Aggregate(w http.ResponseWriter, r *http.Request)
+	// Count Documents
+	// (POST /action/countDocuments)
+	CountDocuments(w http.ResponseWriter, r *http.Request)
+	// Create Index
+	// (POST /action/createIndex)
+	CreateIndex(w http.ResponseWriter, r *http.Request)
 	// Delete Documents
 	// (POST /action/deleteMany)
 	DeleteMany(w http.ResponseWriter, r *http.Request)
 	// Delete One Document
 	// (POST /action/deleteOne)
 	DeleteOne(w http.ResponseWriter, r *http.Request)
+	// Find Distinct Values
+	// (POST /action/distinct)
+	Distinct(w http.ResponseWriter, r *http.Request)
+	// Drop Collection
+	// (POST /action/dropCollection)
+	DropCollection(w http.ResponseWriter, r *http.Request)
 	// Find Documents
 	// (POST /action/find)
 	Find(w http.ResponseWriter, r *http.Request)
 	// Find One Document
 	// (POST /action/findOne)
 	FindOne(w http.ResponseWriter, r *http.Request)
+	// Find One Document and Update It
+	// (POST /action/findOneAndUpdate)
+	FindOneAndUpdate(w http.ResponseWriter, r *http.Request)
 	// Insert Documents
 	// (POST /action/insertMany)
 	InsertMany(w http.ResponseWriter, r *http.Request)
 	// Insert One Document
 	// (POST /action/insertOne)
 	InsertOne(w http.ResponseWriter, r *http.Request)
+	// List Collections
+	// (POST /action/listCollections)
+	ListCollections(w http.ResponseWriter, r *http.Request)
+	// List Databases
+	// (POST /action/listDatabases)
+	ListDatabases(w http.ResponseWriter, r *http.Request)
+	// List Indexes
+	// (POST /action/listIndexes)
+	ListIndexes(w http.ResponseWriter, r *http.Request)
 	// Update Documents
 	// (POST /action/updateMany)
 	UpdateMany(w http.ResponseWriter, r *http.Request)
@@ -484,6 +751,46 @@ func (siw *ServerInterfaceWrapper) Aggregate(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
+// CountDocuments operation middleware
+func (siw *ServerInterfaceWrapper) CountDocuments(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CountDocuments(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateIndex operation middleware
+func (siw *ServerInterfaceWrapper) CreateIndex(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateIndex(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // DeleteMany operation middleware
 func (siw *ServerInterfaceWrapper) DeleteMany(w http.ResponseWriter, r *http.Request) {
 
@@ -524,6 +831,46 @@ func (siw *ServerInterfaceWrapper) DeleteOne(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
+// Distinct operation middleware
+func (siw *ServerInterfaceWrapper) Distinct(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Distinct(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DropCollection operation middleware
+func (siw *ServerInterfaceWrapper) DropCollection(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DropCollection(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // Find operation middleware
 func (siw *ServerInterfaceWrapper) Find(w http.ResponseWriter, r *http.Request) {
 
@@ -564,6 +911,26 @@ func (siw *ServerInterfaceWrapper) FindOne(w http.ResponseWriter, r *http.Reques
 	handler.ServeHTTP(w, r)
 }
 
+// FindOneAndUpdate operation middleware
+func (siw *ServerInterfaceWrapper) FindOneAndUpdate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FindOneAndUpdate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // InsertMany operation middleware
 func (siw *ServerInterfaceWrapper) InsertMany(w http.ResponseWriter, r *http.Request) {
 
@@ -604,6 +971,66 @@ func (siw *ServerInterfaceWrapper) InsertOne(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
+// ListCollections operation middleware
+func (siw *ServerInterfaceWrapper) ListCollections(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListCollections(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListDatabases operation middleware
+func (siw *ServerInterfaceWrapper) ListDatabases(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListDatabases(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListIndexes operation middleware
+func (siw *ServerInterfaceWrapper) ListIndexes(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, HttpAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListIndexes(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // UpdateMany operation middleware
 func (siw *ServerInterfaceWrapper) UpdateMany(w http.ResponseWriter, r *http.Request) {
 
@@ -765,12 +1192,20 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 	}
 
 	m.HandleFunc("POST "+options.BaseURL+"/action/aggregate", wrapper.Aggregate)
+	m.HandleFunc("POST "+options.BaseURL+"/action/countDocuments", wrapper.CountDocuments)
+	m.HandleFunc("POST "+options.BaseURL+"/action/createIndex", wrapper.CreateIndex)
 	m.HandleFunc("POST "+options.BaseURL+"/action/deleteMany", wrapper.DeleteMany)
 	m.HandleFunc("POST "+options.BaseURL+"/action/deleteOne", wrapper.DeleteOne)
+	m.HandleFunc("POST "+options.BaseURL+"/action/distinct", wrapper.Distinct)
+	m.HandleFunc("POST "+options.BaseURL+"/action/dropCollection", wrapper.DropCollection)
 	m.HandleFunc("POST "+options.BaseURL+"/action/find", wrapper.Find)
 	m.HandleFunc("POST "+options.BaseURL+"/action/findOne", wrapper.FindOne)
+	m.HandleFunc("POST "+options.BaseURL+"/action/findOneAndUpdate", wrapper.FindOneAndUpdate)
 	m.HandleFunc("POST "+options.BaseURL+"/action/insertMany", wrapper.InsertMany)
 	m.HandleFunc("POST "+options.BaseURL+"/action/insertOne", wrapper.InsertOne)
+	m.HandleFunc("POST "+options.BaseURL+"/action/listCollections", wrapper.ListCollections)
+	m.HandleFunc("POST "+options.BaseURL+"/action/listDatabases", wrapper.ListDatabases)
+	m.HandleFunc("POST "+options.BaseURL+"/action/listIndexes", wrapper.ListIndexes)
 	m.HandleFunc("POST "+options.BaseURL+"/action/updateMany", wrapper.UpdateMany)
 	m.HandleFunc("POST "+options.BaseURL+"/action/updateOne", wrapper.UpdateOne)
 
@@ -22,6 +22,7 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/FerretDB/FerretDB/v2/internal/dataapi/api"
 	"github.com/FerretDB/FerretDB/v2/internal/dataapi/server"
@@ -42,10 +43,24 @@ type ListenOpts struct {
 	L       *slog.Logger
 	Handler *handler.Handler
 	TCPAddr string
+
+	// Authentication options; see [server.AuthOpts].
+	TokenKey     []byte
+	TokenTTL     time.Duration
+	AuthCacheTTL time.Duration
 }
 
 // Listen creates a new dataapi handler and starts listener on the given TCP address.
 func Listen(opts *ListenOpts) (*Listener, error) {
+	srv, err := server.New(opts.L, opts.Handler, &server.AuthOpts{
+		TokenKey: opts.TokenKey,
+		TokenTTL: opts.TokenTTL,
+		CacheTTL: opts.AuthCacheTTL,
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	lis, err := net.Listen("tcp", opts.TCPAddr)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -54,7 +69,7 @@ func Listen(opts *ListenOpts) (*Listener, error) {
 	return &Listener{
 		opts: opts,
 		lis:  lis,
-		srv:  server.New(opts.L, opts.Handler),
+		srv:  srv,
 	}, nil
 }
 
@@ -65,7 +80,13 @@ func (lis *Listener) Run(ctx context.Context) {
 	srvHandler := api.HandlerFromMux(lis.srv, http.NewServeMux())
 
 	if lis.opts.Handler.Auth {
-		srvHandler = lis.srv.AuthMiddleware(srvHandler)
+		mux := http.NewServeMux()
+
+		// login endpoint is not a part of the OpenAPI description and does not require authentication
+		mux.HandleFunc("POST /auth/login", lis.srv.Login)
+		mux.Handle("/", lis.srv.AuthMiddleware(srvHandler))
+
+		srvHandler = mux
 	}
 
 	srv := &http.Server{
@@ -17,6 +17,7 @@ package dataapi
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
@@ -32,6 +33,7 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/state"
@@ -215,6 +217,140 @@ func TestSmokeDataAPI(t *testing.T) {
 		require.NoError(t, err)
 		assert.JSONEq(t, `{"documents":[`+docs[2]+`]}`, string(body))
 	})
+	t.Run("CountDocuments", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"collection": "` + coll + `",
+			"filter": {}
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/countDocuments", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"count":1}`, string(body))
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"collection": "` + coll + `",
+			"key": "v"
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/distinct", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"values":[{"foo":"bar"}]}`, string(body))
+	})
+
+	t.Run("FindOneAndUpdate", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"collection": "` + coll + `",
+			"filter": {"_id":3},
+			"update": {"$set":{"v":"baz"}},
+			"returnNewDocument": true
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/findOneAndUpdate", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"document":{"_id":3,"v":"baz"}}`, string(body))
+	})
+
+	t.Run("CreateIndex", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"collection": "` + coll + `",
+			"keys": {"v":1}
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/createIndex", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"indexName":"v_1"}`, string(body))
+	})
+
+	t.Run("ListIndexes", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"collection": "` + coll + `"
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/listIndexes", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var actual struct {
+			Indexes []struct {
+				Name string `json:"name"`
+			} `json:"indexes"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&actual))
+		require.Len(t, actual.Indexes, 2)
+		assert.Equal(t, "_id_", actual.Indexes[0].Name)
+		assert.Equal(t, "v_1", actual.Indexes[1].Name)
+	})
+
+	t.Run("ListCollections", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"filter": {"name":"` + coll + `"},
+			"nameOnly": true
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/listCollections", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"collections":[{"name":"`+coll+`","type":"collection"}]}`, string(body))
+	})
+
+	t.Run("ListDatabases", func(t *testing.T) {
+		jsonBody := `{
+			"filter": {"name":"` + db + `"},
+			"nameOnly": true
+		}`
+
+		res, err := postJSON(t, "http://"+addr+"/action/listDatabases", jsonBody)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"databases":[{"name":"`+db+`"}]}`, string(body))
+	})
+
+	t.Run("DropCollection", func(t *testing.T) {
+		jsonBody := `{
+			"database": "` + db + `",
+			"collection": "` + coll + `"
+		}`
+
+		for _, expected := range []string{`{"dropped":true}`, `{"dropped":false}`} {
+			res, err := postJSON(t, "http://"+addr+"/action/dropCollection", jsonBody)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, expected, string(body))
+		}
+	})
 }
 
 // postJSON sends POST request with provided JSON to data API under provided uri.
@@ -242,7 +378,7 @@ func setupDataAPI(tb testing.TB) (addr string, dbName string) {
 
 	l := testutil.Logger(tb)
 
-	p, err := documentdb.NewPool(uri, logging.WithName(l, "pool"), sp)
+	p, err := documentdb.NewPool(uri, nil, cursor.Config{}, false, logging.WithName(l, "pool"), sp)
 	require.NoError(tb, err)
 
 	handlerOpts := &handler.NewOpts{
@@ -0,0 +1,335 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/xdg-go/scram"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/util/apikey"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// apiKeyHeader is the request header with the API key.
+const apiKeyHeader = "apiKey"
+
+// Authentication errors returned by [Server.authenticate].
+var (
+	errNoAuthentication      = errors.New("no authentication methods were specified")
+	errMissingAuthentication = errors.New("missing authentication parameter")
+	errAuthenticationFailed  = errors.New("authentication failed")
+)
+
+// AuthMiddleware authenticates requests with API keys, bearer tokens issued by [Server.Login],
+// or usernames and passwords (with SCRAM handshake).
+// Successful authentications are cached for a short time.
+//
+// It calls the next handler with the authenticated user in the connInfo in context.
+func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := s.connInfoCtx(r)
+
+		username, err := s.authenticate(ctx, r)
+		if err != nil {
+			s.writeAuthError(ctx, w, err)
+			return
+		}
+
+		conninfo.Get(ctx).SetUser(username)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Login handles `/auth/login` endpoint.
+//
+// It authenticates the user with username and password given with Basic authentication
+// or in the JSON body, and returns a bearer token.
+func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := s.connInfoCtx(r)
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		var req struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+
+		if err := decodeJsonRequest(r, &req); err != nil {
+			writeError(w, errorNoAuthenticationSpecified, http.StatusBadRequest)
+			return
+		}
+
+		username, password = req.Username, req.Password
+	}
+
+	username, err := s.authenticateBasic(ctx, username, password)
+	if err != nil {
+		s.writeAuthError(ctx, w, err)
+		return
+	}
+
+	generation, err := s.handler.Pool.UserTokenGeneration(ctx, username)
+	if err != nil {
+		s.writeAuthError(ctx, w, lazyerrors.Error(err))
+		return
+	}
+
+	res := struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{
+		AccessToken: signToken(s.tokenKey, username, generation, time.Now(), s.tokenTTL),
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.tokenTTL.Seconds()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	// ignore error, as writing to connection may fail if the client disconnects
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// connInfoCtx returns the request context with a new connInfo.
+func (s *Server) connInfoCtx(r *http.Request) context.Context {
+	ci := conninfo.New()
+
+	// that allows brute-force protection by address and auditing
+	if addr, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		ci.Peer = addr
+	}
+
+	return conninfo.Ctx(r.Context(), ci)
+}
+
+// writeAuthError writes the error returned by [Server.authenticate].
+func (s *Server) writeAuthError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errNoAuthentication):
+		writeError(w, errorNoAuthenticationSpecified, http.StatusBadRequest)
+
+	case errors.Is(err, errMissingAuthentication):
+		writeError(w, errorMissingAuthenticationParameter, http.StatusBadRequest)
+
+	case errors.Is(err, errAuthenticationFailed):
+		s.l.DebugContext(ctx, "Data API authentication failed", logging.Error(err))
+		writeError(w, errorInvalidSession, http.StatusUnauthorized)
+
+	default:
+		s.l.ErrorContext(ctx, "Data API authentication error", logging.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// authenticate authenticates the request and returns the username.
+func (s *Server) authenticate(ctx context.Context, r *http.Request) (string, error) {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return s.authenticateAPIKey(ctx, key)
+	}
+
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+			return s.authenticateToken(ctx, token)
+		}
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return "", errNoAuthentication
+	}
+
+	return s.authenticateBasic(ctx, username, password)
+}
+
+// cacheKeyFor returns the authentication cache key derived from the given credentials.
+func (s *Server) cacheKeyFor(kind string, credentials ...string) string {
+	mac := hmac.New(sha256.New, s.cacheKey)
+	must.NotFail(mac.Write([]byte(kind)))
+
+	for _, c := range credentials {
+		must.NotFail(mac.Write([]byte{0}))
+		must.NotFail(mac.Write([]byte(c)))
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// authenticateToken authenticates the bearer token and returns its username.
+//
+// Tokens are revoked when the user's password changes or the user is dropped.
+func (s *Server) authenticateToken(ctx context.Context, token string) (string, error) {
+	username, generation, err := verifyToken(s.tokenKey, token, time.Now())
+	if err != nil {
+		return "", errors.Join(errAuthenticationFailed, err)
+	}
+
+	current, err := s.handler.Pool.UserTokenGeneration(ctx, username)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	if generation != current {
+		return "", errors.Join(errAuthenticationFailed, errors.New("revoked token"))
+	}
+
+	return username, nil
+}
+
+// authenticateAPIKey authenticates the API key and returns its username.
+func (s *Server) authenticateAPIKey(ctx context.Context, value string) (string, error) {
+	now := time.Now()
+	version := s.handler.CredentialsVersion()
+	cacheKey := s.cacheKeyFor("apiKey", value)
+
+	if username := s.cache.get(cacheKey, version, now); username != "" {
+		return username, nil
+	}
+
+	key, err := apikey.Parse(value)
+	if err != nil {
+		return "", errors.Join(errAuthenticationFailed, err)
+	}
+
+	info, err := s.handler.Pool.GetAPIKey(ctx, key.ID)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	if info == nil || !key.Verify(info.Hash) {
+		return "", errors.Join(errAuthenticationFailed, errors.New("unknown API key"))
+	}
+
+	s.l.DebugContext(ctx, "Data API key authenticated", slog.String("username", info.Username), slog.String("key_id", key.ID))
+
+	s.cache.set(cacheKey, info.Username, version, now)
+
+	return info.Username, nil
+}
+
+// authenticateBasic authenticates the username and password and returns the username.
+func (s *Server) authenticateBasic(ctx context.Context, username, password string) (string, error) {
+	if username == "" || password == "" {
+		return "", errMissingAuthentication
+	}
+
+	now := time.Now()
+	version := s.handler.CredentialsVersion()
+	cacheKey := s.cacheKeyFor("basic", username, password)
+
+	if cached := s.cache.get(cacheKey, version, now); cached != "" {
+		return cached, nil
+	}
+
+	if err := s.scramHandshake(ctx, username, password); err != nil {
+		return "", err
+	}
+
+	username = conninfo.Get(ctx).Username()
+	s.cache.set(cacheKey, username, version, now)
+
+	return username, nil
+}
+
+// scramHandshake performs SCRAM handshake through `saslStart` and `saslContinue` commands.
+//
+// On success, the conversation is stored in the connInfo in context.
+func (s *Server) scramHandshake(ctx context.Context, username, password string) error {
+	client, err := scram.SHA256.NewClient(username, password, "")
+	if err != nil {
+		return errors.Join(errAuthenticationFailed, err)
+	}
+
+	conv := client.NewConversation()
+
+	payload, err := conv.Step("")
+	if err != nil {
+		return errors.Join(errAuthenticationFailed, err)
+	}
+
+	msg := must.NotFail(wire.NewOpMsg(must.NotFail(must.NotFail(wirebson.NewDocument(
+		"saslStart", int32(1),
+		"mechanism", "SCRAM-SHA-256",
+		"payload", wirebson.Binary{B: []byte(payload)},
+		// use skipEmptyExchange to complete the handshake with one `saslStart` and one `saslContinue`
+		"options", wirebson.MustDocument("skipEmptyExchange", true),
+		"$db", "admin",
+	)).Encode())))
+
+	res, err := s.handler.Commands()["saslStart"].Handler(ctx, msg)
+	if err != nil {
+		return errors.Join(errAuthenticationFailed, err)
+	}
+
+	resDoc, err := res.DecodeDeepDocument()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	convID, _ := resDoc.Get("conversationId").(int32)
+	serverPayload, _ := resDoc.Get("payload").(wirebson.Binary)
+
+	if payload, err = conv.Step(string(serverPayload.B)); err != nil {
+		return errors.Join(errAuthenticationFailed, err)
+	}
+
+	msg = must.NotFail(wire.NewOpMsg(must.NotFail(must.NotFail(wirebson.NewDocument(
+		"saslContinue", int32(1),
+		"conversationId", convID,
+		"payload", wirebson.Binary{B: []byte(payload)},
+		"$db", "admin",
+	)).Encode())))
+
+	if res, err = s.handler.Commands()["saslContinue"].Handler(ctx, msg); err != nil {
+		return errors.Join(errAuthenticationFailed, err)
+	}
+
+	if resDoc, err = res.DecodeDeepDocument(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if done, _ := resDoc.Get("done").(bool); !done {
+		return errors.Join(errAuthenticationFailed, errors.New("SCRAM handshake is not done"))
+	}
+
+	serverPayload, _ = resDoc.Get("payload").(wirebson.Binary)
+
+	if _, err = conv.Step(string(serverPayload.B)); err != nil {
+		return errors.Join(errAuthenticationFailed, err)
+	}
+
+	if !conv.Valid() {
+		return errors.Join(errAuthenticationFailed, errors.New("invalid SCRAM conversation"))
+	}
+
+	return nil
+}
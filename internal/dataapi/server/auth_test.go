@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{1}, minTokenKeyLen)
+	otherKey := bytes.Repeat([]byte{2}, minTokenKeyLen)
+	now := time.Now()
+
+	token := signToken(key, "user", 3, now, time.Hour)
+
+	username, generation, err := verifyToken(key, token, now.Add(59*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, int64(3), generation)
+
+	_, _, err = verifyToken(key, token, now.Add(time.Hour))
+	assert.ErrorIs(t, err, errInvalidToken, "expired")
+
+	_, _, err = verifyToken(otherKey, token, now)
+	assert.ErrorIs(t, err, errInvalidToken, "other key")
+
+	parts := strings.Split(token, ".")
+	otherParts := strings.Split(signToken(key, "admin", 3, now, time.Hour), ".")
+
+	for name, tampered := range map[string]string{
+		"Empty":     "",
+		"Payload":   parts[0] + "." + otherParts[1] + "." + parts[2],
+		"Signature": parts[0] + "." + parts[1] + ".",
+		"Header":    "eyJhbGciOiJub25lIn0." + parts[1] + "." + parts[2],
+		"Extra":     token + ".",
+	} {
+		_, _, err = verifyToken(key, tampered, now)
+		assert.ErrorIs(t, err, errInvalidToken, name)
+	}
+}
+
+func TestAuthCache(t *testing.T) {
+	t.Parallel()
+
+	c := newAuthCache(time.Minute)
+	now := time.Now()
+
+	assert.Empty(t, c.get("key", 1, now))
+
+	c.set("key", "user", 1, now)
+	assert.Equal(t, "user", c.get("key", 1, now.Add(59*time.Second)))
+	assert.Empty(t, c.get("key", 1, now.Add(time.Minute)))
+
+	// changed credentials invalidate entries
+	assert.Empty(t, c.get("key", 2, now))
+
+	// expired entries are removed
+	c.set("other", "user", 1, now.Add(2*time.Minute))
+	assert.Len(t, c.m, 1)
+
+	disabled := newAuthCache(0)
+	disabled.set("key", "user", 1, now)
+	assert.Empty(t, disabled.get("key", 1, now))
+}
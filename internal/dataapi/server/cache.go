@@ -0,0 +1,101 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// authCacheEntry represents a cached successful authentication.
+type authCacheEntry struct {
+	expires  time.Time
+	username string
+	version  uint64
+}
+
+// authCache caches successful authentications for a short time,
+// so repeated requests with the same credentials do not hit SCRAM handshake or PostgreSQL.
+//
+// Keys are derived from credentials by the caller; they should not contain credentials themselves.
+// Entries are valid only for the same credentials version (see [handler.Handler.CredentialsVersion]),
+// so changed passwords and dropped users are not accepted.
+//
+//nolint:vet // for readability
+type authCache struct {
+	ttl time.Duration
+
+	rw        sync.RWMutex
+	m         map[string]authCacheEntry
+	lastSweep time.Time
+}
+
+// newAuthCache creates a new cache.
+//
+// It is disabled if ttl is not positive.
+func newAuthCache(ttl time.Duration) *authCache {
+	return &authCache{
+		ttl: ttl,
+		m:   map[string]authCacheEntry{},
+	}
+}
+
+// get returns the cached username for the given key and credentials version, or empty string.
+func (c *authCache) get(key string, version uint64, now time.Time) string {
+	if c.ttl <= 0 {
+		return ""
+	}
+
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	e, ok := c.m[key]
+	if !ok || e.version != version || !now.Before(e.expires) {
+		return ""
+	}
+
+	return e.username
+}
+
+// set caches the username for the given key and credentials version.
+//
+// The version should be taken before the authentication,
+// so credentials changed during it invalidate the entry.
+func (c *authCache) set(key, username string, version uint64, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	c.m[key] = authCacheEntry{
+		expires:  now.Add(c.ttl),
+		username: username,
+		version:  version,
+	}
+
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+
+	for k, e := range c.m {
+		if !now.Before(e.expires) {
+			delete(c.m, k)
+		}
+	}
+
+	c.lastSweep = now
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/dataapi/api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// CreateIndex implements [ServerInterface].
+func (s *Server) CreateIndex(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s.logRequest(ctx, r)
+
+	var req api.CreateIndexRequestBody
+	if err := decodeJsonRequest(r, &req); err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	keys, err := unmarshalSingleJSON(&req.Keys)
+	if err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	keysDoc, ok := keys.(wirebson.RawDocument)
+	if !ok {
+		http.Error(w, lazyerrors.Errorf("keys must be an object, got %T", keys).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var name string
+	if req.Name != nil {
+		name = *req.Name
+	} else if name, err = indexName(keysDoc); err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	indexDoc, err := prepareDocument(
+		"key", keysDoc,
+		"name", name,
+		"unique", req.Unique,
+	)
+	if err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	msg, err := prepareOpMsg(
+		"createIndexes", req.Collection,
+		"$db", req.Database,
+		"indexes", wirebson.MustArray(indexDoc),
+	)
+	if err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err = s.handler.Commands()["createIndexes"].Handler(ctx, msg); err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := must.NotFail(wirebson.NewDocument(
+		"indexName", name,
+	))
+
+	s.writeJsonResponse(ctx, w, res)
+}
+
+// indexName returns the default index name for the given keys,
+// the same way drivers do: `{"a": 1, "b": -1}` becomes `a_1_b_-1`.
+func indexName(keys wirebson.RawDocument) (string, error) {
+	doc, err := keys.Decode()
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	parts := make([]string, 0, doc.Len()*2)
+
+	for k, v := range doc.All() {
+		parts = append(parts, k, fmt.Sprint(v))
+	}
+
+	if len(parts) == 0 {
+		return "", lazyerrors.New("index keys are empty")
+	}
+
+	return strings.Join(parts, "_"), nil
+}
@@ -34,6 +34,12 @@ var (
 			"(either email+password, api-key, or jwt) in the request header or body",
 		ErrorCode: "MissingParameter",
 	}
+
+	// Authentication failed or the token is invalid or expired.
+	errorInvalidSession = api.Error{
+		Error:     "invalid session: authentication failed",
+		ErrorCode: "InvalidSession",
+	}
 )
 
 // writeError encodes [api.Error] into JSON and writes it to w
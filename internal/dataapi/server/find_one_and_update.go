@@ -0,0 +1,72 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/dataapi/api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// FindOneAndUpdate implements [ServerInterface].
+func (s *Server) FindOneAndUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s.logRequest(ctx, r)
+
+	var req api.FindOneAndUpdateRequestBody
+	if err := decodeJsonRequest(r, &req); err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	msg, err := prepareOpMsg(
+		"findAndModify", req.Collection,
+		"$db", req.Database,
+		"query", req.Filter,
+		"fields", req.Projection,
+		"sort", req.Sort,
+		"update", req.Update,
+		"upsert", req.Upsert,
+		"new", req.ReturnNewDocument,
+	)
+	if err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resMsg, err := s.handler.Commands()["findAndModify"].Handler(ctx, msg)
+	if err != nil {
+		http.Error(w, lazyerrors.Error(err).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resDoc := must.NotFail(must.NotFail(resMsg.RawDocument()).Decode())
+
+	value := resDoc.Get("value")
+	if value == nil {
+		value = wirebson.Null
+	}
+
+	res := must.NotFail(wirebson.NewDocument(
+		"document", value,
+	))
+
+	s.writeJsonResponse(ctx, w, res)
+}
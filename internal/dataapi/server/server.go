@@ -18,129 +18,125 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/http/httputil"
 	"strings"
+	"time"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
-	"github.com/xdg-go/scram"
 
-	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/dataapi/api"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/redact"
 )
 
-// New creates a new Server.
-func New(l *slog.Logger, handler *handler.Handler) *Server {
-	return &Server{
-		l:       l,
-		handler: handler,
-	}
-}
+// minTokenKeyLen is the minimal length of the bearer tokens signing key in bytes.
+const minTokenKeyLen = 32
 
-// Server implements services described by OpenAPI description file.
-type Server struct {
-	l       *slog.Logger
-	handler *handler.Handler
-}
+// AuthOpts represents Data API authentication options.
+type AuthOpts struct {
+	// TokenKey is the key used to sign bearer tokens.
+	// If empty, a random key is generated, and tokens are accepted only by this process until restart;
+	// that is not suitable when cursors are shared between FerretDB instances.
+	TokenKey []byte
 
-// AuthMiddleware handles SCRAM authentication based on the username and password specified in request.
-// After successful handshake it calls the next handler with the proper connInfo in context.
-func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := conninfo.Ctx(r.Context(), conninfo.New())
-		username, password, ok := r.BasicAuth()
+	// TokenTTL is the lifetime of bearer tokens.
+	TokenTTL time.Duration
 
-		if !ok {
-			writeError(w, errorNoAuthenticationSpecified, http.StatusBadRequest)
-			return
-		}
+	// CacheTTL is the lifetime of cached successful authentications.
+	// Zero disables caching.
+	CacheTTL time.Duration
+}
 
-		if password == "" || username == "" {
-			writeError(w, errorMissingAuthenticationParameter, http.StatusBadRequest)
-			return
+// New creates a new Server.
+func New(l *slog.Logger, handler *handler.Handler, auth *AuthOpts) (*Server, error) {
+	tokenKey := auth.TokenKey
+
+	switch {
+	case len(tokenKey) == 0:
+		tokenKey = make([]byte, minTokenKeyLen)
+		if _, err := rand.Read(tokenKey); err != nil {
+			return nil, lazyerrors.Error(err)
 		}
 
-		client, err := scram.SHA256.NewClient(username, password, "")
-		if err != nil {
-			http.Error(w, lazyerrors.Error(err).Error(), http.StatusBadRequest)
-			return
-		}
+	case len(tokenKey) < minTokenKeyLen:
+		return nil, lazyerrors.Errorf("token key should be at least %d bytes long, got %d", minTokenKeyLen, len(tokenKey))
+	}
 
-		conv := client.NewConversation()
+	// cache keys are derived from credentials with a random key,
+	// so they are useless outside of this process
+	cacheKey := make([]byte, 32)
+	if _, err := rand.Read(cacheKey); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
-		payload, err := conv.Step("")
-		if err != nil {
-			http.Error(w, lazyerrors.Error(err).Error(), http.StatusBadRequest)
-			return
-		}
+	return &Server{
+		l:        l,
+		handler:  handler,
+		cache:    newAuthCache(auth.CacheTTL),
+		cacheKey: cacheKey,
+		tokenKey: tokenKey,
+		tokenTTL: auth.TokenTTL,
+	}, nil
+}
 
-		msg := must.NotFail(wire.NewOpMsg(must.NotFail(must.NotFail(wirebson.NewDocument(
-			"saslStart", int32(1),
-			"mechanism", "SCRAM-SHA-256",
-			"payload", wirebson.Binary{B: []byte(payload)},
-			// use skipEmptyExchange to complete the handshake with one `saslStart` and one `saslContinue`
-			"options", wirebson.MustDocument("skipEmptyExchange", true),
-			"$db", "admin",
-		)).Encode())))
-
-		res, err := s.handler.Commands()["saslStart"].Handler(ctx, msg)
-		if err != nil {
-			http.Error(w, lazyerrors.Error(err).Error(), http.StatusUnauthorized)
-			return
-		}
+// Server implements services described by OpenAPI description file.
+type Server struct {
+	l        *slog.Logger
+	handler  *handler.Handler
+	cache    *authCache
+	cacheKey []byte
+	tokenKey []byte
+	tokenTTL time.Duration
+}
 
-		resDoc := must.NotFail(must.NotFail(res.RawDocument()).Decode())
-		convId := resDoc.Get("conversationId").(int32)
+// logRequest logs the request at debug level.
+//
+// Credentials are always masked.
+// If all client data should be masked, only field names of the request body are kept.
+func (s *Server) logRequest(ctx context.Context, r *http.Request) {
+	if !s.l.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
 
-		payloadBytes := resDoc.Get("payload").(wirebson.Binary).B
+	all := s.handler.RedactClientLogData
 
-		payload, err = conv.Step(string(payloadBytes))
-		if err != nil {
-			http.Error(w, lazyerrors.Error(err).Error(), http.StatusUnauthorized)
-			return
+	dumpReq := r.Clone(ctx)
+	for _, h := range []string{"Authorization", apiKeyHeader} {
+		if dumpReq.Header.Get(h) != "" {
+			dumpReq.Header.Set(h, redact.Placeholder)
 		}
+	}
 
-		msg = must.NotFail(wire.NewOpMsg(must.NotFail(must.NotFail(wirebson.NewDocument(
-			"saslContinue", int32(1),
-			"conversationId", convId,
-			"payload", wirebson.Binary{B: []byte(payload)},
-			"$db", "admin",
-		)).Encode())))
-
-		res, err = s.handler.Commands()["saslContinue"].Handler(ctx, msg)
-		if err != nil {
-			http.Error(w, lazyerrors.Error(err).Error(), http.StatusUnauthorized)
-			return
-		}
+	dump := must.NotFail(httputil.DumpRequest(dumpReq, !all))
 
-		resDoc = must.NotFail(must.NotFail(res.RawDocument()).Decode())
-		if !resDoc.Get("done").(bool) {
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-			return
-		}
+	// DumpRequest replaces the read body with a copy
+	r.Body = dumpReq.Body
 
-		payloadBytes = resDoc.Get("payload").(wirebson.Binary).B
+	if all {
+		body := must.NotFail(io.ReadAll(r.Body))
+		r.Body = io.NopCloser(bytes.NewReader(body))
 
-		if _, err = conv.Step(string(payloadBytes)); err != nil {
-			http.Error(w, lazyerrors.Error(err).Error(), http.StatusUnauthorized)
-			return
-		}
+		masked := redact.Placeholder
 
-		if !conv.Valid() {
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-			return
+		var doc wirebson.Document
+		if err := doc.UnmarshalJSON(body); err == nil {
+			masked = redact.All(&doc).LogMessageIndent()
 		}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+		dump = append(dump, masked...)
+	}
+
+	s.l.DebugContext(ctx, fmt.Sprintf("Request:\n%s\n", dump))
 }
 
 // writeJsonResponse marshals provided res document into extended json and
@@ -159,13 +155,17 @@ func (s *Server) writeJsonResponse(ctx context.Context, w http.ResponseWriter, r
 	var resWriter io.Writer = w
 
 	if l.Enabled(ctx, slog.LevelDebug) {
-		buf := new(bytes.Buffer)
+		if s.handler.RedactClientLogData {
+			l.DebugContext(ctx, "Results:\n"+redact.All(res).LogMessageIndent()+"\n")
+		} else {
+			buf := new(bytes.Buffer)
 
-		resWriter = io.MultiWriter(w, buf)
+			resWriter = io.MultiWriter(w, buf)
 
-		defer func() {
-			l.DebugContext(ctx, fmt.Sprintf("Results:\n%s\n", buf.String()))
-		}()
+			defer func() {
+				l.DebugContext(ctx, fmt.Sprintf("Results:\n%s\n", buf.String()))
+			}()
+		}
 	}
 
 	if err = marshalJSON(resRaw, resWriter); err != nil {
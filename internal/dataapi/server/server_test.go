@@ -24,6 +24,8 @@ import (
 	"github.com/FerretDB/wire/wirebson"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 func TestPrepareOpMsg(t *testing.T) {
@@ -66,3 +68,36 @@ func TestPrepareOpMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexName(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		keys     *wirebson.Document
+		expected string
+	}{
+		"Single": {
+			keys:     wirebson.MustDocument("v", int32(1)),
+			expected: "v_1",
+		},
+		"Compound": {
+			keys:     wirebson.MustDocument("a", int32(1), "b.c", int32(-1)),
+			expected: "a_1_b.c_-1",
+		},
+		"Text": {
+			keys:     wirebson.MustDocument("v", "text"),
+			expected: "v_text",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := indexName(must.NotFail(tc.keys.Encode()))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err := indexName(must.NotFail(wirebson.MustDocument().Encode()))
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// tokenHeader is the encoded header of all bearer tokens.
+//
+// Tokens are JWTs signed with HMAC using SHA-256;
+// they are issued and verified only by FerretDB.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// errInvalidToken is returned by [verifyToken] for invalid or expired tokens.
+var errInvalidToken = errors.New("invalid or expired token")
+
+// tokenClaims represents bearer token claims.
+type tokenClaims struct {
+	Subject    string `json:"sub"`
+	IssuedAt   int64  `json:"iat"`
+	ExpiresAt  int64  `json:"exp"`
+	Generation int64  `json:"gen"` // see [documentdb.Pool.UserTokenGeneration]
+}
+
+// signToken returns a new bearer token for the given username and token generation that expires after ttl.
+func signToken(key []byte, username string, generation int64, now time.Time, ttl time.Duration) string {
+	claims := tokenClaims{
+		Subject:    username,
+		IssuedAt:   now.Unix(),
+		ExpiresAt:  now.Add(ttl).Unix(),
+		Generation: generation,
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(must.NotFail(json.Marshal(claims)))
+	signed := tokenHeader + "." + payload
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(tokenSignature(key, signed))
+}
+
+// verifyToken verifies the bearer token and returns its username and token generation.
+//
+// The caller should check that the generation is not revoked.
+func verifyToken(key []byte, token string, now time.Time) (string, int64, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return "", 0, errInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", 0, errInvalidToken
+	}
+
+	if !hmac.Equal(sig, tokenSignature(key, parts[0]+"."+parts[1])) {
+		return "", 0, errInvalidToken
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", 0, lazyerrors.Error(err)
+	}
+
+	var claims tokenClaims
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return "", 0, lazyerrors.Error(err)
+	}
+
+	if claims.Subject == "" || now.Unix() >= claims.ExpiresAt {
+		return "", 0, errInvalidToken
+	}
+
+	return claims.Subject, claims.Generation, nil
+}
+
+// tokenSignature returns HMAC-SHA256 signature of the signed part of the token.
+func tokenSignature(key []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, key)
+	must.NotFail(mac.Write([]byte(signed)))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,637 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+const (
+	// tailableDefaultBatchSize is the default number of documents returned by tailable cursors.
+	tailableDefaultBatchSize = 101
+
+	// tailableMaxBatchSize is the maximum number of documents returned in a single batch.
+	tailableMaxBatchSize = 1000
+
+	// tailableDefaultAwait is the default time `getMore` on awaitData cursors waits for new documents.
+	tailableDefaultAwait = time.Second
+
+	// cappedPollInterval is the time between checks for new documents in capped collections.
+	cappedPollInterval = 100 * time.Millisecond
+)
+
+// CappedInfo represents limits of the capped collection.
+type CappedInfo struct {
+	Size int64 // in bytes
+	Max  int64 // 0 for no limit
+}
+
+// TailableFindParams represents parameters of the `find` command with the `tailable` option.
+type TailableFindParams struct {
+	DB              string
+	Collection      string
+	Filter          wirebson.RawDocument
+	Projection      wirebson.RawDocument
+	BatchSize       int64
+	AwaitData       bool
+	NoCursorTimeout bool
+}
+
+// tailableFindCursor represents the state of the tailable cursor on a capped collection.
+// It is stored as the cursor's continuation.
+type tailableFindCursor struct {
+	collectionID int64
+	db           string
+	collection   string
+	ns           string
+	filter       wirebson.RawDocument
+	projection   wirebson.RawDocument
+	lastSeq      int64
+	awaitData    bool
+}
+
+// encode returns the cursor state as a continuation document.
+func (c *tailableFindCursor) encode() wirebson.RawDocument {
+	doc := wirebson.MustDocument(
+		"collectionID", c.collectionID,
+		"db", c.db,
+		"collection", c.collection,
+		"ns", c.ns,
+		"lastSeq", c.lastSeq,
+		"awaitData", c.awaitData,
+	)
+
+	if c.filter != nil {
+		must.NoError(doc.Add("filter", c.filter))
+	}
+
+	if c.projection != nil {
+		must.NoError(doc.Add("projection", c.projection))
+	}
+
+	return must.NotFail(doc.Encode())
+}
+
+// decodeTailableFindCursor returns the cursor state stored in the continuation document,
+// or nil if the continuation belongs to a different kind of tailable cursor.
+func decodeTailableFindCursor(continuation wirebson.RawDocument) (*tailableFindCursor, error) {
+	doc, err := continuation.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	collectionID, ok := doc.Get("collectionID").(int64)
+	if !ok {
+		return nil, nil
+	}
+
+	res := &tailableFindCursor{
+		collectionID: collectionID,
+		db:           doc.Get("db").(string),
+		collection:   doc.Get("collection").(string),
+		ns:           doc.Get("ns").(string),
+		lastSeq:      doc.Get("lastSeq").(int64),
+		awaitData:    doc.Get("awaitData").(bool),
+	}
+
+	if v := doc.Get("filter"); v != nil {
+		res.filter = v.(wirebson.RawDocument)
+	}
+
+	if v := doc.Get("projection"); v != nil {
+		res.projection = v.(wirebson.RawDocument)
+	}
+
+	return res, nil
+}
+
+// cappedSetup tracks whether capped collections tables were created.
+type cappedSetup struct {
+	m     sync.Mutex
+	ready bool
+}
+
+// setupCapped creates capped collections tables and functions if needed.
+//
+// It is safe to call it multiple times and from multiple FerretDB instances.
+func (p *Pool) setupCapped(ctx context.Context) error {
+	p.cp.m.Lock()
+	defer p.cp.m.Unlock()
+
+	if p.cp.ready {
+		return nil
+	}
+
+	conn, err := p.p.Acquire(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", cappedLockID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, cappedSetupSQL)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	p.cp.ready = true
+
+	return nil
+}
+
+// cappedExist returns true if capped collections tables exist.
+//
+// They are created only when the first capped collection is created,
+// possibly by another FerretDB instance, so other commands should not create them.
+func (p *Pool) cappedExist(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	p.cp.m.Lock()
+	ready := p.cp.ready
+	p.cp.m.Unlock()
+
+	if ready {
+		return true, nil
+	}
+
+	var exists bool
+
+	q := `SELECT to_regclass('ferretdb.capped_collections') IS NOT NULL`
+	if err := conn.QueryRow(ctx, q).Scan(&exists); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return exists, nil
+}
+
+// collectionID returns the DocumentDB ID of the given collection, or 0 if it does not exist.
+func collectionID(ctx context.Context, conn *pgx.Conn, db, collection string) (int64, error) {
+	q := `SELECT collection_id FROM documentdb_api_catalog.collections WHERE database_name = $1 AND collection_name = $2`
+
+	var id int64
+
+	err := conn.QueryRow(ctx, q, db, collection).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return id, nil
+}
+
+// SetCapped makes the existing collection capped with the given limits,
+// removing the oldest documents if they are exceeded.
+// It is a part of the implementation of `create` and `convertToCapped` commands.
+//
+// Existing documents are considered to be inserted in the _id order.
+func (p *Pool) SetCapped(ctx context.Context, db, collection string, info *CappedInfo) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.SetCapped")
+	defer span.End()
+
+	if err := p.setupCapped(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		id, err := collectionID(ctx, conn, db, collection)
+		if err != nil {
+			return err
+		}
+
+		if id == 0 {
+			return mongoerrors.New(
+				mongoerrors.ErrNamespaceNotFound,
+				fmt.Sprintf("source collection %s.%s does not exist", db, collection),
+			)
+		}
+
+		return cappedInstall(ctx, conn, id, info)
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// CloneCollectionAsCapped creates a new capped collection with the given limits
+// and copies documents of the existing collection into it.
+// It is a part of the implementation of the `cloneCollectionAsCapped` command.
+//
+// It is done in a single transaction. Documents are copied one by one in the natural order,
+// so the oldest ones are removed if limits are exceeded.
+func (p *Pool) CloneCollectionAsCapped(ctx context.Context, db, from, to string, info *CappedInfo) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CloneCollectionAsCapped")
+	defer span.End()
+
+	if err := p.setupCapped(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			fromID, err := collectionID(ctx, tx.Conn(), db, from)
+			if err != nil {
+				return err
+			}
+
+			if fromID == 0 {
+				return mongoerrors.New(
+					mongoerrors.ErrNamespaceNotFound,
+					fmt.Sprintf("source collection %s.%s does not exist", db, from),
+				)
+			}
+
+			toID, err := collectionID(ctx, tx.Conn(), db, to)
+			if err != nil {
+				return err
+			}
+
+			if toID != 0 {
+				return mongoerrors.New(
+					mongoerrors.ErrNamespaceExists,
+					fmt.Sprintf("a collection '%s.%s' already exists", db, to),
+				)
+			}
+
+			if _, err = documentdb_api.CreateCollection(ctx, tx.Conn(), p.l, db, to); err != nil {
+				return err
+			}
+
+			if toID, err = collectionID(ctx, tx.Conn(), db, to); err != nil {
+				return err
+			}
+
+			if err = cappedInstall(ctx, tx.Conn(), toID, info); err != nil {
+				return err
+			}
+
+			// the trigger installed above tracks rows in the insertion order;
+			// shard key value of unsharded collections is the collection ID
+			q := fmt.Sprintf(
+				`INSERT INTO documentdb_data.documents_%d (shard_key_value, object_id, document, creation_time) `+
+					`SELECT %d, object_id, document, creation_time FROM documentdb_data.documents_%d ORDER BY ctid`,
+				toID, toID, fromID,
+			)
+
+			_, err = tx.Exec(ctx, q)
+
+			return err
+		})
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// cappedInstall stores limits of the capped collection with the given ID
+// and starts tracking its documents.
+func cappedInstall(ctx context.Context, conn *pgx.Conn, id int64, info *CappedInfo) error {
+	if _, err := conn.Exec(ctx, "SELECT ferretdb.capped_install($1, $2, $3)", id, info.Size, info.Max); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// CappedCollections returns limits of capped collections in the given database, keyed by collection name.
+// Collections that are not capped are not included.
+func (p *Pool) CappedCollections(ctx context.Context, db string) (map[string]*CappedInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CappedCollections")
+	defer span.End()
+
+	q := `
+	SELECT c.collection_name, cc.max_size, cc.max_documents
+	FROM documentdb_api_catalog.collections c
+	JOIN ferretdb.capped_collections cc ON cc.collection_id = c.collection_id
+	WHERE c.database_name = $1
+	`
+
+	res := map[string]*CappedInfo{}
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		exists, err := p.cappedExist(ctx, conn)
+		if err != nil || !exists {
+			return err
+		}
+
+		rows, err := conn.Query(ctx, q, db)
+		if err != nil {
+			return err
+		}
+
+		var collectionName string
+		var info CappedInfo
+		scans := []any{&collectionName, &info.Size, &info.Max}
+
+		_, err = pgx.ForEachRow(rows, scans, func() error {
+			res[collectionName] = &CappedInfo{Size: info.Size, Max: info.Max}
+			return nil
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// TailableFind opens a tailable cursor on the capped collection.
+// It returns the first page of the cursor and its ID.
+// It is a part of the implementation of the `find` command with the `tailable` option.
+//
+// Unlike other cursors, tailable cursors stay open after returning the last document;
+// the next `getMore` returns documents inserted after that.
+func (p *Pool) TailableFind(ctx context.Context, params *TailableFindParams) (wirebson.RawDocument, int64, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.TailableFind")
+	defer span.End()
+
+	c := &tailableFindCursor{
+		db:         params.DB,
+		collection: params.Collection,
+		ns:         params.DB + "." + params.Collection,
+		filter:     params.Filter,
+		projection: params.Projection,
+		awaitData:  params.AwaitData,
+	}
+
+	var capped bool
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		var err error
+		if c.collectionID, err = collectionID(ctx, conn, params.DB, params.Collection); err != nil {
+			return err
+		}
+
+		if c.collectionID == 0 {
+			return nil
+		}
+
+		exists, err := p.cappedExist(ctx, conn)
+		if err != nil || !exists {
+			return err
+		}
+
+		q := `SELECT EXISTS (SELECT 1 FROM ferretdb.capped_collections WHERE collection_id = $1)`
+
+		return conn.QueryRow(ctx, q, c.collectionID).Scan(&capped)
+	})
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	// like MongoDB, return an empty result for a non-existent collection
+	if c.collectionID == 0 {
+		return tailableFindPage("firstBatch", wirebson.MakeArray(0), c.ns, 0), 0, nil
+	}
+
+	if !capped {
+		return nil, 0, mongoerrors.NewWithArgument(
+			mongoerrors.ErrBadValue,
+			fmt.Sprintf("error processing query: ns=%s tailable cursor requested on non capped collection", c.ns),
+			"find",
+		)
+	}
+
+	batchSize := params.BatchSize
+	if batchSize <= 0 || batchSize > tailableMaxBatchSize {
+		batchSize = tailableDefaultBatchSize
+	}
+
+	batch, _, err := p.tailableFindBatch(ctx, c, int(batchSize), 0)
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	cursorID, err := p.newTailableCursorID(ctx)
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	if err = p.newTailableCursor(ctx, cursorID, c.encode(), cursorParams(ctx, params.NoCursorTimeout)); err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	return tailableFindPage("firstBatch", batch, c.ns, cursorID), cursorID, nil
+}
+
+// tailableGetMore returns the next page of the tailable cursor.
+// It is a part of the implementation of the `getMore` command.
+func (p *Pool) tailableGetMore(ctx context.Context, spec wirebson.RawDocument, cursorID int64, continuation wirebson.RawDocument) (wirebson.RawDocument, error) { //nolint:lll // for readability
+	c, err := decodeTailableFindCursor(continuation)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if c == nil {
+		return p.changeStreamGetMore(ctx, spec, cursorID, continuation)
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	batchSize := tailableMaxBatchSize
+	if v, ok := toInt64(doc.Get("batchSize")); ok && v > 0 && v < tailableMaxBatchSize {
+		batchSize = int(v)
+	}
+
+	var await time.Duration
+
+	if c.awaitData {
+		await = tailableDefaultAwait
+		if v, ok := toInt64(doc.Get("maxAwaitTimeMS")); ok && v >= 0 {
+			await = time.Duration(v) * time.Millisecond
+		}
+	}
+
+	batch, dropped, err := p.tailableFindBatch(ctx, c, batchSize, await)
+	if err != nil {
+		p.closeCursor(ctx, cursorID)
+		return nil, lazyerrors.Error(err)
+	}
+
+	if dropped {
+		p.closeCursor(ctx, cursorID)
+
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrQueryPlanKilled,
+			fmt.Sprintf("collection dropped. UUID %s", c.ns),
+			"getMore",
+		)
+	}
+
+	if err = p.updateCursor(ctx, cursorID, c.encode()); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return tailableFindPage("nextBatch", batch, c.ns, cursorID), nil
+}
+
+// tailableFindBatch returns the next batch of matching documents for the cursor,
+// waiting up to await for new documents if there are none.
+// It updates the cursor's last sequence number.
+//
+// It returns true if the collection was dropped and the cursor should be closed.
+func (p *Pool) tailableFindBatch(ctx context.Context, c *tailableFindCursor, batchSize int, await time.Duration) (*wirebson.Array, bool, error) { //nolint:lll // for readability
+	deadline := time.Now().Add(await)
+
+	stages := wirebson.MakeArray(2)
+
+	if c.filter != nil {
+		must.NoError(stages.Add(wirebson.MustDocument("$match", c.filter)))
+	}
+
+	if c.projection != nil {
+		must.NoError(stages.Add(wirebson.MustDocument("$project", c.projection)))
+	}
+
+	for {
+		var res *wirebson.Array
+		var dropped, more bool
+
+		err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+			id, err := collectionID(ctx, conn, c.db, c.collection)
+			if err != nil {
+				return err
+			}
+
+			if id != c.collectionID {
+				dropped = true
+				return nil
+			}
+
+			var docs *wirebson.Array
+			if docs, err = fetchCappedDocuments(ctx, conn, c, batchSize); err != nil {
+				return err
+			}
+
+			more = docs.Len() == batchSize
+			res = docs
+
+			if stages.Len() == 0 || docs.Len() == 0 {
+				return nil
+			}
+
+			res, err = documentsPipeline(ctx, conn, p.l, c.db, docs, stages, docs.Len())
+
+			return err
+		})
+		if err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		if dropped {
+			return nil, true, nil
+		}
+
+		if res.Len() > 0 {
+			return res, false, nil
+		}
+
+		// all fetched documents were filtered out; check the next ones without waiting
+		if more {
+			continue
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return res, false, nil
+		}
+
+		t := time.NewTimer(min(remaining, cappedPollInterval))
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, false, lazyerrors.Error(context.Cause(ctx))
+		}
+	}
+}
+
+// fetchCappedDocuments returns up to limit documents inserted into the capped collection
+// after the cursor's last sequence number, and updates it.
+func fetchCappedDocuments(ctx context.Context, conn *pgx.Conn, c *tailableFindCursor, limit int) (*wirebson.Array, error) { //nolint:lll // for readability
+	q := fmt.Sprintf(`
+	SELECT c.seq, d.document::bytea
+	FROM ferretdb.capped_documents c
+	JOIN documentdb_data.documents_%d d ON d.object_id = c.object_id::documentdb_core.bson
+	WHERE c.collection_id = $1 AND c.seq > $2
+	ORDER BY c.seq
+	LIMIT $3
+	`, c.collectionID,
+	)
+
+	rows, err := conn.Query(ctx, q, c.collectionID, c.lastSeq, limit)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := wirebson.MakeArray(limit)
+
+	var seq int64
+	var doc []byte
+	scans := []any{&seq, &doc}
+
+	_, err = pgx.ForEachRow(rows, scans, func() error {
+		c.lastSeq = seq
+		return res.Add(wirebson.RawDocument(doc))
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// tailableFindPage returns the tailable cursor page.
+func tailableFindPage(batchName string, batch *wirebson.Array, ns string, cursorID int64) wirebson.RawDocument {
+	return must.NotFail(wirebson.MustDocument(
+		"cursor", wirebson.MustDocument(
+			batchName, batch,
+			"id", cursorID,
+			"ns", ns,
+		),
+		"ok", float64(1),
+	).Encode())
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+// cappedLockID is an advisory lock ID used to serialize setup of capped collections tables.
+const cappedLockID = 0x0fe77e7db0c7
+
+// cappedSetupSQL creates (or updates) tables and functions for capped collections.
+//
+// DocumentDB does not support capped collections, so FerretDB stores their limits itself
+// and tracks the insertion order of documents with a trigger on the data table.
+// The same trigger removes the oldest documents when limits are exceeded.
+// The newest document is always kept, even if it alone exceeds the size limit.
+//
+// Collection IDs are never reused, so rows of dropped collections are harmless;
+// they are removed when another collection becomes capped.
+//
+// It should be executed in a transaction that holds cappedLockID advisory lock.
+const cappedSetupSQL = `
+CREATE SCHEMA IF NOT EXISTS ferretdb;
+
+CREATE TABLE IF NOT EXISTS ferretdb.capped_collections (
+	collection_id bigint PRIMARY KEY,
+	max_size      bigint NOT NULL,
+	max_documents bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ferretdb.capped_documents (
+	collection_id bigint NOT NULL,
+	seq           bigserial NOT NULL,
+	object_id     bytea NOT NULL,
+	size          bigint NOT NULL,
+	PRIMARY KEY (collection_id, seq)
+);
+
+CREATE INDEX IF NOT EXISTS capped_documents_object_id ON ferretdb.capped_documents (collection_id, object_id);
+
+CREATE OR REPLACE FUNCTION ferretdb.capped_trim(id bigint, keep bytea) RETURNS void LANGUAGE plpgsql AS $$
+DECLARE
+	lim   record;
+	total bigint;
+	n     bigint;
+	r     record;
+BEGIN
+	-- serializes concurrent inserts into the same collection
+	SELECT max_size, max_documents INTO lim FROM ferretdb.capped_collections WHERE collection_id = id FOR UPDATE;
+	IF NOT FOUND THEN
+		RETURN;
+	END IF;
+
+	SELECT coalesce(sum(size), 0), count(*) INTO total, n FROM ferretdb.capped_documents WHERE collection_id = id;
+
+	FOR r IN SELECT object_id, size FROM ferretdb.capped_documents WHERE collection_id = id ORDER BY seq LOOP
+		EXIT WHEN total <= lim.max_size AND (lim.max_documents <= 0 OR n <= lim.max_documents);
+		EXIT WHEN r.object_id = keep;
+
+		EXECUTE format(
+			'DELETE FROM documentdb_data.documents_%s WHERE object_id = $1::documentdb_core.bson', id
+		) USING r.object_id;
+
+		DELETE FROM ferretdb.capped_documents WHERE collection_id = id AND object_id = r.object_id;
+
+		total := total - r.size;
+		n := n - 1;
+	END LOOP;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION ferretdb.capped_documents() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	id bigint := TG_ARGV[0]::bigint;
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO ferretdb.capped_documents (collection_id, object_id, size)
+		VALUES (id, NEW.object_id::bytea, length(NEW.document::bytea));
+
+		PERFORM ferretdb.capped_trim(id, NEW.object_id::bytea);
+	ELSIF TG_OP = 'UPDATE' THEN
+		UPDATE ferretdb.capped_documents SET size = length(NEW.document::bytea)
+		WHERE collection_id = id AND object_id = NEW.object_id::bytea;
+	ELSE
+		DELETE FROM ferretdb.capped_documents WHERE collection_id = id AND object_id = OLD.object_id::bytea;
+	END IF;
+
+	RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION ferretdb.capped_install(id bigint, p_max_size bigint, p_max_documents bigint) RETURNS void LANGUAGE plpgsql AS $$
+BEGIN
+	DELETE FROM ferretdb.capped_collections
+	WHERE collection_id NOT IN (SELECT collection_id FROM documentdb_api_catalog.collections);
+
+	DELETE FROM ferretdb.capped_documents
+	WHERE collection_id NOT IN (SELECT collection_id FROM documentdb_api_catalog.collections);
+
+	INSERT INTO ferretdb.capped_collections (collection_id, max_size, max_documents)
+	VALUES (id, p_max_size, p_max_documents)
+	ON CONFLICT (collection_id) DO UPDATE SET max_size = EXCLUDED.max_size, max_documents = EXCLUDED.max_documents;
+
+	-- existing documents have no insertion order; use _id order
+	DELETE FROM ferretdb.capped_documents WHERE collection_id = id;
+
+	EXECUTE format(
+		'INSERT INTO ferretdb.capped_documents (collection_id, object_id, size) '
+		'SELECT %s, object_id::bytea, length(document::bytea) FROM documentdb_data.documents_%s ORDER BY object_id',
+		id, id
+	);
+
+	EXECUTE format(
+		'CREATE OR REPLACE TRIGGER ferretdb_capped '
+		'AFTER INSERT OR UPDATE OR DELETE ON documentdb_data.documents_%s '
+		'FOR EACH ROW EXECUTE FUNCTION ferretdb.capped_documents(%s)',
+		id, id
+	);
+
+	PERFORM ferretdb.capped_trim(id, NULL);
+END
+$$;
+`
@@ -0,0 +1,914 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+const (
+	// changeStreamRetention is the time change events are kept for resuming change streams.
+	changeStreamRetention = 24 * time.Hour
+
+	// changeStreamMaxBatchSize is the maximum number of change events returned in a single batch.
+	changeStreamMaxBatchSize = 1000
+
+	// changeStreamDefaultAwait is the default time `getMore` waits for new change events.
+	changeStreamDefaultAwait = time.Second
+
+	// changeStreamPollInterval is the maximum time between checks for new change events
+	// when notifications are not delivered (for example, when listener is reconnecting).
+	changeStreamPollInterval = time.Second
+)
+
+// ChangeStreamParams represents parameters of the `$changeStream` aggregation stage.
+type ChangeStreamParams struct {
+	DB         string
+	Collection string // empty for the whole database or cluster
+
+	// AllChangesForCluster is true for the whole cluster change streams.
+	AllChangesForCluster bool
+
+	// FullDocument is "default" or "updateLookup".
+	FullDocument string
+
+	// ResumeAfter is the value of `resumeAfter` option, if any.
+	ResumeAfter wirebson.RawDocument
+
+	// StartAfter is the value of `startAfter` option, if any.
+	// Unlike ResumeAfter, it could be the token of the invalidate event.
+	StartAfter wirebson.RawDocument
+
+	// Pipeline contains stages that follow `$changeStream`, if any.
+	Pipeline wirebson.RawArray
+
+	BatchSize int64
+}
+
+// changeStreamCursor represents the state of the change stream cursor.
+// It is stored as the cursor's continuation.
+type changeStreamCursor struct {
+	db           string
+	collection   string
+	ns           string
+	fullDocument string
+	pipeline     wirebson.RawArray
+	lastSeq      int64
+	cluster      bool
+	invalidated  bool // not stored, as invalidated cursors are closed
+}
+
+// encode returns the cursor state as a continuation document.
+func (c *changeStreamCursor) encode() wirebson.RawDocument {
+	doc := wirebson.MustDocument(
+		"db", c.db,
+		"collection", c.collection,
+		"cluster", c.cluster,
+		"ns", c.ns,
+		"fullDocument", c.fullDocument,
+		"lastSeq", c.lastSeq,
+	)
+
+	if c.pipeline != nil {
+		must.NoError(doc.Add("pipeline", c.pipeline))
+	}
+
+	return must.NotFail(doc.Encode())
+}
+
+// decodeChangeStreamCursor returns the cursor state stored in the continuation document.
+func decodeChangeStreamCursor(continuation wirebson.RawDocument) (*changeStreamCursor, error) {
+	doc, err := continuation.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res changeStreamCursor
+	var ok bool
+
+	if res.db, ok = doc.Get("db").(string); !ok {
+		return nil, lazyerrors.Errorf("no db in %s", doc.LogMessage())
+	}
+
+	if res.collection, ok = doc.Get("collection").(string); !ok {
+		return nil, lazyerrors.Errorf("no collection in %s", doc.LogMessage())
+	}
+
+	if res.cluster, ok = doc.Get("cluster").(bool); !ok {
+		return nil, lazyerrors.Errorf("no cluster in %s", doc.LogMessage())
+	}
+
+	if res.ns, ok = doc.Get("ns").(string); !ok {
+		return nil, lazyerrors.Errorf("no ns in %s", doc.LogMessage())
+	}
+
+	if res.fullDocument, ok = doc.Get("fullDocument").(string); !ok {
+		return nil, lazyerrors.Errorf("no fullDocument in %s", doc.LogMessage())
+	}
+
+	if res.lastSeq, ok = doc.Get("lastSeq").(int64); !ok {
+		return nil, lazyerrors.Errorf("no lastSeq in %s", doc.LogMessage())
+	}
+
+	if v := doc.Get("pipeline"); v != nil {
+		if res.pipeline, ok = v.(wirebson.RawArray); !ok {
+			return nil, lazyerrors.Errorf("invalid pipeline in %s", doc.LogMessage())
+		}
+	}
+
+	return &res, nil
+}
+
+// invalidatedBy returns true if the change event invalidates the change stream.
+//
+// Drop and rename of the watched collection do that.
+func (c *changeStreamCursor) invalidatedBy(e *changeEvent) bool {
+	return c.collection != "" && (e.operation == "drop" || e.operation == "rename")
+}
+
+// changeEvent represents a single row of the change events table.
+type changeEvent struct {
+	created          time.Time
+	toDatabaseName   *string
+	toCollectionName *string
+	databaseName     string
+	collectionName   string
+	operation        string
+	oldDocument      []byte
+	newDocument      []byte
+	seq              int64
+}
+
+// changeStreams contains the pool-level state shared by all change stream cursors.
+type changeStreams struct {
+	m      sync.Mutex
+	ready  bool          // change events table and triggers are set up
+	ch     chan struct{} // closed and replaced on new change events
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newChangeStreams creates a new change streams state.
+func newChangeStreams() *changeStreams {
+	return &changeStreams{
+		ch: make(chan struct{}),
+	}
+}
+
+// wait returns a channel that is closed when new change events might be available.
+func (cs *changeStreams) wait() <-chan struct{} {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	return cs.ch
+}
+
+// notify wakes up all waiting change stream cursors.
+func (cs *changeStreams) notify() {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	close(cs.ch)
+	cs.ch = make(chan struct{})
+}
+
+// close stops the notifications listener, if it is running.
+func (cs *changeStreams) close() {
+	cs.m.Lock()
+	cancel, done := cs.cancel, cs.done
+	cs.m.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// setupChangeStreams creates the change events table and triggers if needed,
+// and starts the notifications listener.
+//
+// It is safe to call it multiple times and from multiple FerretDB instances.
+func (p *Pool) setupChangeStreams(ctx context.Context) error {
+	p.cs.m.Lock()
+	defer p.cs.m.Unlock()
+
+	if p.cs.ready {
+		return nil
+	}
+
+	conn, err := p.p.Acquire(todoCtx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", changeEventsLockID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, changeEventsSetupSQL)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	listenerCtx, cancel := context.WithCancel(context.Background())
+	p.cs.cancel = cancel
+	p.cs.done = make(chan struct{})
+
+	go func() {
+		defer close(p.cs.done)
+		p.listenChangeEvents(listenerCtx)
+	}()
+
+	p.cs.ready = true
+
+	return nil
+}
+
+// listenChangeEvents listens for change events notifications until ctx is canceled,
+// reconnecting on errors.
+func (p *Pool) listenChangeEvents(ctx context.Context) {
+	l := logging.WithName(p.l, "changestreams")
+
+	for attempt := int64(1); ctx.Err() == nil; attempt++ {
+		err := p.listenChangeEventsConn(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.WarnContext(ctx, "Change events listener failed", logging.Error(err))
+
+		// wake up cursors so they could poll the table while we are reconnecting
+		p.cs.notify()
+
+		ctxutil.SleepWithJitter(ctx, time.Second, attempt)
+	}
+}
+
+// listenChangeEventsConn listens for change events notifications on a dedicated connection
+// until error or ctx cancelation.
+func (p *Pool) listenChangeEventsConn(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, p.p.Config().ConnConfig.Copy())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer closeConn(context.Background(), conn)
+
+	if _, err = conn.Exec(ctx, "LISTEN "+changeEventsChannel); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for {
+		if _, err = conn.WaitForNotification(ctx); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		p.cs.notify()
+	}
+}
+
+// ChangeStream opens a change stream cursor.
+// It returns the first page of the cursor and its ID.
+// It is a part of the implementation of the `aggregate` command with the `$changeStream` stage.
+func (p *Pool) ChangeStream(ctx context.Context, params *ChangeStreamParams) (wirebson.RawDocument, int64, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.ChangeStream")
+	defer span.End()
+
+	if err := p.setupChangeStreams(ctx); err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	ns := params.DB + "." + params.Collection
+	if params.Collection == "" {
+		ns = params.DB + ".$cmd.aggregate"
+	}
+
+	c := &changeStreamCursor{
+		db:           params.DB,
+		collection:   params.Collection,
+		cluster:      params.AllChangesForCluster,
+		ns:           ns,
+		fullDocument: params.FullDocument,
+		pipeline:     params.Pipeline,
+	}
+
+	var resumeSeq int64
+	var fromInvalidate bool
+	var err error
+
+	switch {
+	case params.ResumeAfter != nil:
+		if resumeSeq, fromInvalidate, err = parseResumeToken(params.ResumeAfter); err != nil {
+			return nil, 0, err
+		}
+
+		if fromInvalidate {
+			msg := "Attempting to resume a change stream using 'resumeAfter' is not allowed from an invalidate notification."
+			return nil, 0, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidResumeToken, msg, "$changeStream")
+		}
+
+	case params.StartAfter != nil:
+		if resumeSeq, fromInvalidate, err = parseResumeToken(params.StartAfter); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if c.lastSeq, err = p.changeStreamStart(ctx, resumeSeq); err != nil {
+		return nil, 0, err
+	}
+
+	batchSize := params.BatchSize
+	if batchSize <= 0 || batchSize > changeStreamMaxBatchSize {
+		batchSize = changeStreamMaxBatchSize
+	}
+
+	var batch *wirebson.Array
+	var invalidated bool
+
+	// resuming after the event that invalidated the change stream returns the invalidate event again;
+	// starting after the invalidate event opens a new change stream
+	if resumeSeq != 0 && !fromInvalidate {
+		if batch, err = p.changeStreamResumeInvalidated(ctx, c, resumeSeq); err != nil {
+			return nil, 0, lazyerrors.Error(err)
+		}
+
+		invalidated = batch != nil
+	}
+
+	if !invalidated {
+		if batch, invalidated, err = p.changeStreamBatch(ctx, c, int(batchSize), 0); err != nil {
+			return nil, 0, lazyerrors.Error(err)
+		}
+	}
+
+	var cursorID int64
+
+	if !invalidated {
+		if cursorID, err = p.newTailableCursorID(ctx); err != nil {
+			return nil, 0, lazyerrors.Error(err)
+		}
+
+		if err = p.newTailableCursor(ctx, cursorID, c.encode(), cursorParams(ctx, false)); err != nil {
+			return nil, 0, lazyerrors.Error(err)
+		}
+	}
+
+	return changeStreamPage("firstBatch", batch, c, cursorID), cursorID, nil
+}
+
+// changeStreamResumeInvalidated returns the batch with the invalidate event
+// if the change event with the given sequence number invalidated the change stream.
+// Otherwise, it returns nil.
+func (p *Pool) changeStreamResumeInvalidated(ctx context.Context, c *changeStreamCursor, seq int64) (*wirebson.Array, error) { //nolint:lll // for readability
+	var res *wirebson.Array
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		prev := *c
+		prev.lastSeq = seq - 1
+
+		events, err := fetchChangeEvents(ctx, conn, &prev, 1)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 || events[0].seq != seq || !c.invalidatedBy(events[0]) {
+			return nil
+		}
+
+		c.invalidated = true
+		res = wirebson.MustArray(invalidateEvent(events[0]))
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// changeStreamGetMore returns the next page of the change stream cursor.
+// It is a part of the implementation of the `getMore` command.
+func (p *Pool) changeStreamGetMore(ctx context.Context, spec wirebson.RawDocument, cursorID int64, continuation wirebson.RawDocument) (wirebson.RawDocument, error) { //nolint:lll // for readability
+	c, err := decodeChangeStreamCursor(continuation)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	batchSize := changeStreamMaxBatchSize
+	if v, ok := toInt64(doc.Get("batchSize")); ok && v > 0 && v < changeStreamMaxBatchSize {
+		batchSize = int(v)
+	}
+
+	await := changeStreamDefaultAwait
+	if v, ok := toInt64(doc.Get("maxAwaitTimeMS")); ok && v >= 0 {
+		await = time.Duration(v) * time.Millisecond
+	}
+
+	batch, invalidated, err := p.changeStreamBatch(ctx, c, batchSize, await)
+	if err != nil {
+		p.closeCursor(ctx, cursorID)
+		return nil, lazyerrors.Error(err)
+	}
+
+	if invalidated {
+		p.closeCursor(ctx, cursorID)
+		cursorID = 0
+	} else if err = p.updateCursor(ctx, cursorID, c.encode()); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return changeStreamPage("nextBatch", batch, c, cursorID), nil
+}
+
+// changeStreamStart returns the sequence number after which the change stream starts.
+//
+// If resumeSeq is zero, the change stream starts after the latest change event.
+func (p *Pool) changeStreamStart(ctx context.Context, resumeSeq int64) (int64, error) {
+	var minSeq, maxSeq int64
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		if err := changeEventsSequence(ctx, conn); err != nil {
+			return err
+		}
+
+		q := "SELECT coalesce(min(seq), 0), coalesce(max(seq), 0) FROM ferretdb.change_events WHERE seq IS NOT NULL"
+
+		return conn.QueryRow(ctx, q).Scan(&minSeq, &maxSeq)
+	})
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	if resumeSeq == 0 {
+		return maxSeq, nil
+	}
+
+	if resumeSeq > maxSeq {
+		msg := fmt.Sprintf("cannot resume stream; the resume token was not found. %s", resumeToken(resumeSeq).LogMessage())
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrChangeStreamFatalError, msg, "$changeStream")
+	}
+
+	if resumeSeq < minSeq-1 {
+		msg := "Resume of change stream was not possible, as the resume point may no longer be in the oplog."
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrChangeStreamHistoryLost, msg, "$changeStream")
+	}
+
+	return resumeSeq, nil
+}
+
+// changeStreamBatch returns the next batch of change events for the cursor,
+// waiting up to await for new events if there are none.
+// It updates the cursor's last sequence number.
+//
+// It returns true if the change stream was invalidated and the cursor should be closed.
+func (p *Pool) changeStreamBatch(ctx context.Context, c *changeStreamCursor, batchSize int, await time.Duration) (*wirebson.Array, bool, error) { //nolint:lll // for readability
+	deadline := time.Now().Add(await)
+
+	for {
+		// get the channel before the query so notifications are not lost
+		ch := p.cs.wait()
+
+		var res *wirebson.Array
+		var invalidated bool
+
+		err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+			events, err := fetchChangeEvents(ctx, conn, c, batchSize)
+			if err != nil {
+				return err
+			}
+
+			res = wirebson.MakeArray(len(events))
+
+			for _, e := range events {
+				c.lastSeq = e.seq
+
+				var doc *wirebson.Document
+				if doc, err = p.changeEventDocument(ctx, conn, e, c.fullDocument); err != nil {
+					return err
+				}
+
+				must.NoError(res.Add(doc))
+
+				if c.invalidatedBy(e) {
+					must.NoError(res.Add(invalidateEvent(e)))
+
+					c.invalidated = true
+					invalidated = true
+
+					break
+				}
+			}
+
+			if c.pipeline == nil || res.Len() == 0 {
+				return nil
+			}
+
+			res, err = p.changeStreamPipeline(ctx, conn, c, res)
+
+			return err
+		})
+		if err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		if res.Len() > 0 || invalidated {
+			return res, invalidated, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return res, false, nil
+		}
+
+		t := time.NewTimer(min(remaining, changeStreamPollInterval))
+
+		select {
+		case <-ch:
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, false, lazyerrors.Error(context.Cause(ctx))
+		}
+
+		t.Stop()
+	}
+}
+
+// changeEventsSequence assigns sequence numbers to committed change events.
+func changeEventsSequence(ctx context.Context, conn *pgx.Conn) error {
+	_, err := conn.Exec(
+		ctx, "SELECT ferretdb.change_events_sequence($1, $2)",
+		changeEventsLockID, changeStreamRetention,
+	)
+
+	return err
+}
+
+// fetchChangeEvents returns up to limit change events for the cursor after its last sequence number.
+func fetchChangeEvents(ctx context.Context, conn *pgx.Conn, c *changeStreamCursor, limit int) ([]*changeEvent, error) {
+	if err := changeEventsSequence(ctx, conn); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	q := `SELECT seq, created, database_name, collection_name, operation, ` +
+		`old_document, new_document, to_database_name, to_collection_name ` +
+		`FROM ferretdb.change_events WHERE seq > $1`
+	args := []any{c.lastSeq}
+
+	if !c.cluster {
+		args = append(args, c.db)
+		q += fmt.Sprintf(" AND database_name = $%d", len(args))
+	}
+
+	if c.collection != "" {
+		args = append(args, c.collection)
+		q += fmt.Sprintf(" AND collection_name = $%d", len(args))
+	}
+
+	args = append(args, limit)
+	q += fmt.Sprintf(" ORDER BY seq LIMIT $%d", len(args))
+
+	rows, err := conn.Query(ctx, q, args...)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res []*changeEvent
+
+	var e changeEvent
+	scans := []any{
+		&e.seq, &e.created, &e.databaseName, &e.collectionName, &e.operation,
+		&e.oldDocument, &e.newDocument, &e.toDatabaseName, &e.toCollectionName,
+	}
+
+	_, err = pgx.ForEachRow(rows, scans, func() error {
+		ev := e
+		res = append(res, &ev)
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// changeEventDocument returns the MongoDB-shaped change event document.
+func (p *Pool) changeEventDocument(ctx context.Context, conn *pgx.Conn, e *changeEvent, fullDocument string) (*wirebson.Document, error) { //nolint:lll // for readability
+	var oldDoc, newDoc *wirebson.Document
+	var err error
+
+	if e.oldDocument != nil {
+		if oldDoc, err = wirebson.RawDocument(e.oldDocument).Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if e.newDocument != nil {
+		if newDoc, err = wirebson.RawDocument(e.newDocument).Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	op := e.operation
+
+	var updateDescription *wirebson.Document
+
+	if op == "update" {
+		var replace bool
+		if updateDescription, replace, err = diffDocuments(oldDoc, newDoc); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if replace {
+			op = "replace"
+		}
+	}
+
+	res := wirebson.MustDocument(
+		"_id", resumeToken(e.seq),
+		"operationType", op,
+		"clusterTime", clusterTime(e),
+		"wallTime", e.created,
+		"ns", wirebson.MustDocument("db", e.databaseName, "coll", e.collectionName),
+	)
+
+	switch op {
+	case "insert", "replace":
+		must.NoError(res.Add("documentKey", wirebson.MustDocument("_id", newDoc.Get("_id"))))
+		must.NoError(res.Add("fullDocument", newDoc))
+
+	case "update":
+		id := newDoc.Get("_id")
+		must.NoError(res.Add("documentKey", wirebson.MustDocument("_id", id)))
+		must.NoError(res.Add("updateDescription", updateDescription))
+
+		if fullDocument == "updateLookup" {
+			var doc any
+			if doc, err = p.lookupDocument(ctx, conn, e.databaseName, e.collectionName, id); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			must.NoError(res.Add("fullDocument", doc))
+		}
+
+	case "delete":
+		must.NoError(res.Add("documentKey", wirebson.MustDocument("_id", oldDoc.Get("_id"))))
+
+	case "rename":
+		to := wirebson.MustDocument("db", *e.toDatabaseName, "coll", *e.toCollectionName)
+		must.NoError(res.Add("to", to))
+	}
+
+	return res, nil
+}
+
+// lookupDocument returns the current version of the document with the given _id,
+// or null if it does not exist.
+func (p *Pool) lookupDocument(ctx context.Context, conn *pgx.Conn, db, collection string, id any) (any, error) {
+	spec := must.NotFail(wirebson.MustDocument(
+		"find", collection,
+		"filter", wirebson.MustDocument("_id", id),
+		"limit", int64(1),
+		"singleBatch", true,
+		"$db", db,
+	).Encode())
+
+	page, _, _, _, err := documentdb_api.FindCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	batch, err := pageBatch(page, "firstBatch")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if batch.Len() == 0 {
+		return wirebson.Null, nil
+	}
+
+	return batch.Get(0), nil
+}
+
+// changeStreamPipeline applies stages that follow `$changeStream` to the change events.
+//
+// It uses the `$documents` stage, so the whole pipeline is executed by DocumentDB.
+func (p *Pool) changeStreamPipeline(ctx context.Context, conn *pgx.Conn, c *changeStreamCursor, events *wirebson.Array) (*wirebson.Array, error) { //nolint:lll // for readability
+	stages, err := c.pipeline.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return documentsPipeline(ctx, conn, p.l, c.db, events, stages, changeStreamMaxBatchSize)
+}
+
+// pageBatch returns the batch of documents from the cursor page.
+func pageBatch(page wirebson.RawDocument, batchName string) (*wirebson.Array, error) {
+	doc, err := page.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	cursor, ok := doc.Get("cursor").(wirebson.RawDocument)
+	if !ok {
+		return nil, lazyerrors.Errorf("no cursor in %s", doc.LogMessage())
+	}
+
+	cursorDoc, err := cursor.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	batch, ok := cursorDoc.Get(batchName).(wirebson.RawArray)
+	if !ok {
+		return nil, lazyerrors.Errorf("no %s in %s", batchName, cursorDoc.LogMessage())
+	}
+
+	return batch.Decode()
+}
+
+// changeStreamPage returns the change stream cursor page.
+func changeStreamPage(batchName string, batch *wirebson.Array, c *changeStreamCursor, cursorID int64) wirebson.RawDocument {
+	token := resumeToken(c.lastSeq)
+	if c.invalidated {
+		token = invalidateResumeToken(c.lastSeq)
+	}
+
+	return must.NotFail(wirebson.MustDocument(
+		"cursor", wirebson.MustDocument(
+			batchName, batch,
+			"postBatchResumeToken", token,
+			"id", cursorID,
+			"ns", c.ns,
+		),
+		"ok", float64(1),
+	).Encode())
+}
+
+// diffDocuments returns the `updateDescription` for old and new versions of the document.
+//
+// Triggers do not know whether the update used operators or a replacement document,
+// so the change is reported as a replacement
+// if some fields were removed and no fields other than _id were left unchanged.
+func diffDocuments(oldDoc, newDoc *wirebson.Document) (*wirebson.Document, bool, error) {
+	updated := wirebson.MakeDocument(0)
+	removed := wirebson.MakeArray(0)
+
+	var unchanged int
+
+	for k, v := range newDoc.All() {
+		oldV := oldDoc.Get(k)
+		if oldV != nil {
+			equal, err := valuesEqual(oldV, v)
+			if err != nil {
+				return nil, false, lazyerrors.Error(err)
+			}
+
+			if equal {
+				if k != "_id" {
+					unchanged++
+				}
+
+				continue
+			}
+		}
+
+		must.NoError(updated.Add(k, v))
+	}
+
+	for k := range oldDoc.Fields() {
+		if newDoc.Get(k) == nil {
+			must.NoError(removed.Add(k))
+		}
+	}
+
+	res := wirebson.MustDocument(
+		"updatedFields", updated,
+		"removedFields", removed,
+		"truncatedArrays", wirebson.MakeArray(0),
+	)
+
+	return res, removed.Len() > 0 && unchanged == 0, nil
+}
+
+// valuesEqual returns true if both BSON values have the same encoding.
+func valuesEqual(a, b any) (bool, error) {
+	ra, err := wirebson.MustDocument("", a).Encode()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	rb, err := wirebson.MustDocument("", b).Encode()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return string(ra) == string(rb), nil
+}
+
+// invalidateTokenSuffix is appended to the resume token data of the invalidate event.
+const invalidateTokenSuffix = "-I"
+
+// resumeToken returns the resume token for the given change event sequence number.
+func resumeToken(seq int64) *wirebson.Document {
+	return wirebson.MustDocument("_data", fmt.Sprintf("%016X", seq))
+}
+
+// invalidateResumeToken returns the resume token for the invalidate event
+// caused by the change event with the given sequence number.
+func invalidateResumeToken(seq int64) *wirebson.Document {
+	return wirebson.MustDocument("_data", fmt.Sprintf("%016X%s", seq, invalidateTokenSuffix))
+}
+
+// invalidateEvent returns the invalidate event document for the change event that caused it.
+func invalidateEvent(e *changeEvent) *wirebson.Document {
+	return wirebson.MustDocument(
+		"_id", invalidateResumeToken(e.seq),
+		"operationType", "invalidate",
+		"clusterTime", clusterTime(e),
+		"wallTime", e.created,
+	)
+}
+
+// parseResumeToken returns the change event sequence number from the resume token,
+// and true if it is the token of the invalidate event.
+func parseResumeToken(token wirebson.RawDocument) (int64, bool, error) {
+	doc, err := token.Decode()
+	if err != nil {
+		return 0, false, lazyerrors.Error(err)
+	}
+
+	data, _ := doc.Get("_data").(string)
+	data, fromInvalidate := strings.CutSuffix(data, invalidateTokenSuffix)
+
+	seq, err := strconv.ParseInt(data, 16, 64)
+	if err != nil || seq < 0 {
+		msg := fmt.Sprintf("invalid resume token: %s", doc.LogMessage())
+		return 0, false, mongoerrors.NewWithArgument(mongoerrors.ErrChangeStreamBadResumeToken, msg, "$changeStream")
+	}
+
+	return seq, fromInvalidate, nil
+}
+
+// clusterTime returns the cluster time of the change event.
+func clusterTime(e *changeEvent) wirebson.Timestamp {
+	return wirebson.Timestamp(uint64(e.created.Unix())<<32 | uint64(uint32(e.seq)))
+}
+
+// toInt64 converts BSON number to int64.
+func toInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+// changeEventsChannel is a PostgreSQL notification channel used to wake up change stream cursors.
+const changeEventsChannel = "ferretdb_change_events"
+
+// changeEventsLockID is an advisory lock ID used to serialize setup and sequencing of change events.
+const changeEventsLockID = 0x0fe77e7db0c5
+
+// changeEventsSetupSQL creates (or updates) the change events table,
+// and installs triggers on DocumentDB catalog and data tables.
+//
+// Triggers record changes into the change events table in the same transaction.
+// Rows get their `seq` values later, after commit, by ferretdb.change_events_sequence function,
+// so the order of `seq` values matches the commit order.
+// That allows change stream cursors to never miss events of concurrent transactions.
+//
+// Collections created later get the trigger from the catalog table triggers:
+// immediately if the data table already exists, and at commit otherwise.
+//
+// It should be executed in a transaction that holds changeEventsLockID advisory lock.
+const changeEventsSetupSQL = `
+CREATE SCHEMA IF NOT EXISTS ferretdb;
+
+CREATE TABLE IF NOT EXISTS ferretdb.change_events (
+	id                 bigserial PRIMARY KEY,
+	seq                bigint UNIQUE,
+	created            timestamptz NOT NULL DEFAULT clock_timestamp(),
+	database_name      text NOT NULL,
+	collection_name    text NOT NULL,
+	operation          text NOT NULL,
+	old_document       bytea,
+	new_document       bytea,
+	to_database_name   text,
+	to_collection_name text
+);
+
+CREATE INDEX IF NOT EXISTS change_events_unsequenced ON ferretdb.change_events (id) WHERE seq IS NULL;
+CREATE INDEX IF NOT EXISTS change_events_created ON ferretdb.change_events (created);
+
+CREATE SEQUENCE IF NOT EXISTS ferretdb.change_events_seq;
+
+CREATE OR REPLACE FUNCTION ferretdb.change_events_documents() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+	db   text;
+	coll text;
+BEGIN
+	SELECT database_name, collection_name INTO db, coll
+	FROM documentdb_api_catalog.collections
+	WHERE collection_id = TG_ARGV[0]::bigint;
+
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO ferretdb.change_events (database_name, collection_name, operation, new_document)
+		VALUES (db, coll, 'insert', NEW.document::bytea);
+	ELSIF TG_OP = 'UPDATE' THEN
+		INSERT INTO ferretdb.change_events (database_name, collection_name, operation, old_document, new_document)
+		VALUES (db, coll, 'update', OLD.document::bytea, NEW.document::bytea);
+	ELSE
+		INSERT INTO ferretdb.change_events (database_name, collection_name, operation, old_document)
+		VALUES (db, coll, 'delete', OLD.document::bytea);
+	END IF;
+
+	PERFORM pg_notify('` + changeEventsChannel + `', '');
+
+	RETURN NULL;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION ferretdb.change_events_install(id bigint) RETURNS void LANGUAGE plpgsql AS $$
+BEGIN
+	IF to_regclass(format('documentdb_data.documents_%s', id)) IS NULL THEN
+		RETURN;
+	END IF;
+
+	EXECUTE format(
+		'CREATE OR REPLACE TRIGGER ferretdb_change_events '
+		'AFTER INSERT OR UPDATE OR DELETE ON documentdb_data.documents_%s '
+		'FOR EACH ROW EXECUTE FUNCTION ferretdb.change_events_documents(%s)',
+		id, id
+	);
+END
+$$;
+
+CREATE OR REPLACE FUNCTION ferretdb.change_events_collections() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		PERFORM ferretdb.change_events_install(NEW.collection_id);
+		RETURN NULL;
+	END IF;
+
+	IF TG_OP = 'UPDATE' THEN
+		IF OLD.database_name = NEW.database_name AND OLD.collection_name = NEW.collection_name THEN
+			RETURN NULL;
+		END IF;
+
+		INSERT INTO ferretdb.change_events
+			(database_name, collection_name, operation, to_database_name, to_collection_name)
+		VALUES (OLD.database_name, OLD.collection_name, 'rename', NEW.database_name, NEW.collection_name);
+	ELSE
+		INSERT INTO ferretdb.change_events (database_name, collection_name, operation)
+		VALUES (OLD.database_name, OLD.collection_name, 'drop');
+	END IF;
+
+	PERFORM pg_notify('` + changeEventsChannel + `', '');
+
+	RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS ferretdb_change_events_install ON documentdb_api_catalog.collections;
+
+CREATE CONSTRAINT TRIGGER ferretdb_change_events_install
+AFTER INSERT ON documentdb_api_catalog.collections
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION ferretdb.change_events_collections();
+
+CREATE OR REPLACE TRIGGER ferretdb_change_events
+AFTER INSERT OR UPDATE OR DELETE ON documentdb_api_catalog.collections
+FOR EACH ROW EXECUTE FUNCTION ferretdb.change_events_collections();
+
+SELECT ferretdb.change_events_install(collection_id) FROM documentdb_api_catalog.collections;
+
+CREATE OR REPLACE FUNCTION ferretdb.change_events_sequence(lock_id bigint, retention interval) RETURNS void LANGUAGE plpgsql AS $$
+DECLARE
+	r record;
+BEGIN
+	-- some other client is sequencing events right now
+	IF NOT pg_try_advisory_xact_lock(lock_id) THEN
+		RETURN;
+	END IF;
+
+	FOR r IN SELECT id FROM ferretdb.change_events WHERE seq IS NULL ORDER BY id LOOP
+		UPDATE ferretdb.change_events SET seq = nextval('ferretdb.change_events_seq') WHERE id = r.id;
+	END LOOP;
+
+	DELETE FROM ferretdb.change_events WHERE seq IS NOT NULL AND created < clock_timestamp() - retention;
+END
+$$;
+`
@@ -0,0 +1,99 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+func TestResumeToken(t *testing.T) {
+	t.Parallel()
+
+	token := must.NotFail(resumeToken(42).Encode())
+
+	seq, fromInvalidate, err := parseResumeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), seq)
+	assert.False(t, fromInvalidate)
+
+	token = must.NotFail(invalidateResumeToken(42).Encode())
+
+	seq, fromInvalidate, err = parseResumeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), seq)
+	assert.True(t, fromInvalidate)
+
+	_, _, err = parseResumeToken(must.NotFail(wirebson.MustDocument("_data", "invalid").Encode()))
+	require.Error(t, err)
+}
+
+func TestChangeStreamCursor(t *testing.T) {
+	t.Parallel()
+
+	c := &changeStreamCursor{
+		db:           "test",
+		collection:   "coll",
+		ns:           "test.coll",
+		fullDocument: "default",
+		pipeline:     must.NotFail(wirebson.MustArray(wirebson.MustDocument("$match", wirebson.MakeDocument(0))).Encode()),
+		lastSeq:      42,
+	}
+
+	actual, err := decodeChangeStreamCursor(c.encode())
+	require.NoError(t, err)
+	assert.Equal(t, c, actual)
+
+	_, err = decodeChangeStreamCursor(must.NotFail(wirebson.MustDocument("db", int32(1)).Encode()))
+	assert.Error(t, err)
+}
+
+func TestDiffDocuments(t *testing.T) {
+	t.Parallel()
+
+	oldDoc := wirebson.MustDocument("_id", int32(1), "a", int32(1), "b", "foo", "c", wirebson.MustArray(int32(1)))
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		newDoc := wirebson.MustDocument("_id", int32(1), "a", int32(2), "c", wirebson.MustArray(int32(1)))
+
+		desc, replace, err := diffDocuments(oldDoc, newDoc)
+		require.NoError(t, err)
+		assert.False(t, replace)
+
+		expected := wirebson.MustDocument(
+			"updatedFields", wirebson.MustDocument("a", int32(2)),
+			"removedFields", wirebson.MustArray("b"),
+			"truncatedArrays", wirebson.MakeArray(0),
+		)
+		assert.Equal(t, must.NotFail(expected.Encode()), must.NotFail(desc.Encode()))
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+
+		newDoc := wirebson.MustDocument("_id", int32(1), "d", true)
+
+		_, replace, err := diffDocuments(oldDoc, newDoc)
+		require.NoError(t, err)
+		assert.True(t, replace)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// clusterTimePollInterval is the interval between checks of the standby's replay position
+// in [Pool.WaitForClusterTime].
+const clusterTimePollInterval = 10 * time.Millisecond
+
+// walPosition returns the current WAL position of the PostgreSQL server,
+// and true if that server is a standby.
+//
+// For primaries, that's the current insert position; all committed transactions are before it,
+// even with asynchronous commits.
+// For standbys, that's the last replayed position; all transactions before it are visible to queries.
+func walPosition(ctx context.Context, conn *pgx.Conn) (uint64, bool, error) {
+	q := `SELECT pg_wal_lsn_diff(CASE WHEN pg_is_in_recovery() ` +
+		`THEN COALESCE(pg_last_wal_replay_lsn(), '0/0') ELSE pg_current_wal_insert_lsn() END, '0/0')::bigint, ` +
+		`pg_is_in_recovery()`
+
+	var lsn int64
+	var standby bool
+
+	if err := conn.QueryRow(ctx, q).Scan(&lsn, &standby); err != nil {
+		return 0, false, lazyerrors.Error(err)
+	}
+
+	return uint64(lsn), standby, nil
+}
+
+// ClusterTime returns the current cluster time for `operationTime` and `$clusterTime` response fields.
+//
+// It is the latest WAL position of the primary PostgreSQL server seen by this pool,
+// see [Pool.AdvanceClusterTime]. It is zero if no position was seen yet.
+// The 64-bit LSN is stored as-is in BSON timestamp,
+// so cluster times are ordered the same way as LSNs.
+//
+// It does not query PostgreSQL, so it could be used for every response.
+func (p *Pool) ClusterTime() wirebson.Timestamp {
+	return wirebson.Timestamp(p.clusterTime.Load())
+}
+
+// AdvanceClusterTime advances the cluster time returned by [Pool.ClusterTime]
+// to the current WAL position of the PostgreSQL server used by ctx (see [Pool.Acquire]).
+// It should be called after commands that write data, so their `operationTime` includes their writes.
+//
+// Positions of standbys are ignored.
+func (p *Pool) AdvanceClusterTime(ctx context.Context) error {
+	var lsn uint64
+	var standby bool
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		var err error
+		lsn, standby, err = walPosition(ctx, conn)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !standby {
+		p.advanceClusterTime(lsn)
+	}
+
+	return nil
+}
+
+// advanceClusterTime sets the cluster time to the given WAL position of the primary
+// if it is greater than the current one.
+func (p *Pool) advanceClusterTime(lsn uint64) {
+	for {
+		cur := p.clusterTime.Load()
+		if lsn <= cur || p.clusterTime.CompareAndSwap(cur, lsn) {
+			return
+		}
+	}
+}
+
+// WaitForClusterTime waits until the PostgreSQL server used by ctx (see [Pool.Acquire])
+// reaches the given cluster time returned by [Pool.ClusterTime] of this or another FerretDB instance.
+// That is used to implement `readConcern.afterClusterTime`.
+//
+// Standbys are polled until they replay WAL up to that position, or until ctx is done.
+// Primaries never wait; for them, the cluster time in the future is an error.
+func (p *Pool) WaitForClusterTime(ctx context.Context, ts wirebson.Timestamp) error {
+	for {
+		var lsn uint64
+		var standby bool
+
+		err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+			var err error
+			lsn, standby, err = walPosition(ctx, conn)
+
+			return err
+		})
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if !standby {
+			p.advanceClusterTime(lsn)
+		}
+
+		if uint64(ts) <= lsn {
+			return nil
+		}
+
+		if !standby {
+			msg := fmt.Sprintf(
+				"readConcern afterClusterTime value must not be greater than the current clusterTime. "+
+					"Requested clusterTime: %s; current clusterTime: %s",
+				formatTimestamp(ts), formatTimestamp(wirebson.Timestamp(lsn)),
+			)
+
+			return mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "afterClusterTime")
+		}
+
+		ctxutil.Sleep(ctx, clusterTimePollInterval)
+
+		if err = ctx.Err(); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+}
+
+// formatTimestamp returns a string representation of BSON timestamp
+// in the same format as MongoDB.
+func formatTimestamp(ts wirebson.Timestamp) string {
+	return fmt.Sprintf("Timestamp(%d, %d)", uint64(ts)>>32, uint32(ts))
+}
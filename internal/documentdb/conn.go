@@ -15,18 +15,28 @@
 package documentdb
 
 import (
+	"context"
+	"time"
+
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
 )
 
 // Conn represents a pooled PostgreSQL connection.
 // It wraps [*pgxpool.Conn] with resource tracking.
+//
+// It also could represent a connection pinned to the transaction.
+// In that case, it is not returned to the pool on [Conn.Release].
 type Conn struct {
-	conn  *pgxpool.Conn
-	token *resource.Token
+	conn         *pgxpool.Conn
+	txn          *Txn   // only if pinned to the transaction
+	onRelease    func() // called once on Release, if set
+	resetTimeout bool   // statement timeout was set by Acquire
+	token        *resource.Token
 }
 
 // newConn returns [*Conn] for the given [*pgxpool.Conn].
@@ -41,19 +51,60 @@ func newConn(conn *pgxpool.Conn) *Conn {
 	return res
 }
 
-// Release returns connection back to the pool, unless it was persisted/hijacked.
+// newTxnConn returns [*Conn] for the connection pinned to the given transaction.
+// The transaction is locked until [Conn.Release] is called.
+func newTxnConn(txn *Txn) *Conn {
+	txn.m.Lock()
+
+	res := &Conn{
+		txn:   txn,
+		token: resource.NewToken(),
+	}
+
+	resource.Track(res, res.token)
+
+	return res
+}
+
+// Release returns connection back to the pool, unless it was persisted/hijacked
+// or pinned to the transaction.
 // It is safe to call this method multiple times.
 func (conn *Conn) Release() {
+	if conn.resetTimeout {
+		conn.resetTimeout = false
+
+		if err := resetStatementTimeout(conn.Conn()); err != nil {
+			conn.closeBroken()
+		}
+	}
+
+	// call it before the connection could be used by anything else, see [BackendPIDCtx]
+	if conn.onRelease != nil {
+		conn.onRelease()
+		conn.onRelease = nil
+	}
+
 	if conn.conn != nil {
 		conn.conn.Release()
 		conn.conn = nil
 	}
 
+	if conn.txn != nil {
+		conn.txn.m.Unlock()
+		conn.txn = nil
+	}
+
 	resource.Untrack(conn, conn.token)
 }
 
 // Conn returns the underlying [*pgx.Conn]. It should not be retained by the caller.
 func (conn *Conn) Conn() *pgx.Conn {
+	if conn.txn != nil {
+		must.NotBeZero(conn.txn.conn)
+
+		return conn.txn.conn
+	}
+
 	must.NotBeZero(conn.conn)
 
 	return conn.conn.Conn()
@@ -64,11 +115,53 @@ func (conn *Conn) Conn() *pgx.Conn {
 //
 // All code that use persisted/hijacked connections should be in that package.
 // The returned connection should be wrapped in a cursors for resource tracking.
+//
+// Connections pinned to the transaction are never hijacked; nil is returned instead,
+// and the transaction's connection is used for the cursor's next pages.
 func (conn *Conn) hijack() *pgx.Conn {
+	if conn.txn != nil {
+		return nil
+	}
+
 	must.NotBeZero(conn.conn)
 
 	res := conn.conn.Hijack()
 	conn.conn = nil
 
+	// the next pages are fetched by GetMore with their own timeouts;
+	// errors are ignored there as the broken connection fails the next page anyway
+	if conn.resetTimeout {
+		conn.resetTimeout = false
+		_ = resetStatementTimeout(res)
+	}
+
 	return res
 }
+
+// setStatementTimeout sets the statement timeout if ctx was returned by [MaxTimeCtx].
+func (conn *Conn) setStatementTimeout(ctx context.Context) error {
+	set, err := setStatementTimeout(ctx, conn.Conn())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	conn.resetTimeout = set
+
+	return nil
+}
+
+// closeBroken closes the pooled connection that could not be restored to the default state,
+// so it is not reused. The pool removes closed connections on release.
+//
+// Connections pinned to the transaction are left as is:
+// the failure means that the transaction is aborted, and settings are rolled back with it.
+func (conn *Conn) closeBroken() {
+	if conn.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(todoCtx, 3*time.Second)
+	defer cancel()
+
+	_ = conn.conn.Conn().Close(ctx)
+}
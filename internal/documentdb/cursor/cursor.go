@@ -26,25 +26,47 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
 )
 
+// Params represents cursor parameters set by the command that created it.
+type Params struct {
+	Owner     string        // user that created the cursor; used for per-user limits
+	MaxTime   time.Duration // applied to each next page; zero if not set
+	NoTimeout bool          // if true, idle cursor is not closed
+	Replica   string        // host:port of the replica that the cursor reads from; empty for the primary
+}
+
 // cursor stores DocumentDB's cursor state.
 type cursor struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
 	created      time.Time
+	lastUsed     time.Time
 	token        *resource.Token
-	conn         *pgx.Conn // only if persisted/hijacked
+	conn         *pgx.Conn     // only if persisted/hijacked
+	maxTime      time.Duration // applied to each page; zero if not set
+	owner        string
+	replica      string
 	continuation wirebson.RawDocument
+	noTimeout    bool
+	tailable     bool // handled by FerretDB itself, not by DocumentDB
 }
 
-// newCursor creates a new cursor for the given continuation and connection (if any).
-func newCursor(continuation wirebson.RawDocument, conn *pgx.Conn) *cursor {
+// newCursor creates a new cursor for the given continuation, connection (if any), and parameters.
+func newCursor(continuation wirebson.RawDocument, conn *pgx.Conn, params *Params, tailable bool) *cursor {
 	must.BeTrue(len(continuation) > 0)
 
+	now := time.Now()
+
 	res := &cursor{
 		continuation: continuation,
 		conn:         conn,
+		maxTime:      params.MaxTime,
+		owner:        params.Owner,
+		replica:      params.Replica,
+		noTimeout:    params.NoTimeout,
+		tailable:     tailable,
 		token:        resource.NewToken(),
-		created:      time.Now(),
+		created:      now,
+		lastUsed:     now,
 	}
 
 	resource.Track(res, res.token)
@@ -71,3 +93,17 @@ func (c *cursor) close(ctx context.Context) {
 
 	resource.Untrack(c, c.token)
 }
+
+// typ returns cursor type for metrics.
+//
+// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/97
+func (c *cursor) typ() string {
+	switch {
+	case c.tailable:
+		return "tailable"
+	case c.conn != nil:
+		return "persist"
+	default:
+		return "normal"
+	}
+}
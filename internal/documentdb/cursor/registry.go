@@ -16,7 +16,9 @@ package cursor
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"sync"
 	"time"
 
@@ -24,6 +26,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
 )
@@ -34,23 +37,71 @@ const (
 	subsystem = "cursors"
 )
 
+// DefaultTimeout is the default idle cursor timeout, the same as MongoDB's `cursorTimeoutMillis`.
+const DefaultTimeout = 10 * time.Minute
+
+// Config represents cursor timeout and limits configuration.
+type Config struct {
+	// Timeout is the time after which idle cursors are closed,
+	// unless they were created with `noCursorTimeout`.
+	// Zero means [DefaultTimeout].
+	Timeout time.Duration
+
+	// MaxCursors caps the number of open cursors. Zero means no limit.
+	MaxCursors int
+
+	// MaxUserCursors caps the number of open cursors of a single user. Zero means no limit.
+	MaxUserCursors int
+
+	// MaxPersisted caps the number of cursors with persisted PostgreSQL connections.
+	// They are counted separately because each of them holds a PostgreSQL backend.
+	// Zero means no limit.
+	MaxPersisted int
+
+	// MaxUserPersisted caps the number of cursors with persisted PostgreSQL connections of a single user.
+	// Zero means no limit.
+	MaxUserPersisted int
+}
+
+// Stats represents cursor statistics.
+type Stats struct {
+	Open        int
+	NoTimeout   int
+	Pinned      int
+	TotalOpened int64
+	TimedOut    int64
+	Rejected    int64
+}
+
 // Registry provides access to DocumentDB cursors.
 //
 //nolint:vet // for readability
 type Registry struct {
+	config Config
+
 	rw      sync.RWMutex
 	cursors map[int64]*cursor
+	stats   Stats
 
 	l     *slog.Logger
 	token *resource.Token
 
 	created  *prometheus.CounterVec
 	duration *prometheus.HistogramVec
+	timedOut prometheus.Counter
+	rejected *prometheus.CounterVec
+	open     *prometheus.Desc
+	pinned   *prometheus.Desc
 }
 
 // NewRegistry creates a new cursor registry.
-func NewRegistry(l *slog.Logger) *Registry {
+func NewRegistry(config Config, l *slog.Logger) *Registry {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+
 	res := &Registry{
+		config:  config,
 		cursors: map[int64]*cursor{},
 		l:       l,
 		token:   resource.NewToken(),
@@ -87,10 +138,42 @@ func NewRegistry(l *slog.Logger) *Registry {
 			},
 			[]string{"type"},
 		),
+		timedOut: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "timed_out_total",
+				Help:      "Total number of idle cursors closed due to timeout.",
+			},
+		),
+		rejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "rejected_total",
+				Help:      "Total number of cursors rejected due to limits.",
+			},
+			[]string{"type"},
+		),
+		open: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "open"),
+			"The current number of open cursors.",
+			nil, nil,
+		),
+		pinned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "pinned"),
+			"The current number of cursors pinned to persisted PostgreSQL connections.",
+			nil, nil,
+		),
 	}
 
 	res.created.WithLabelValues("normal")
 	res.duration.WithLabelValues("normal")
+	res.created.WithLabelValues("tailable")
+	res.duration.WithLabelValues("tailable")
+	res.rejected.WithLabelValues("normal")
+	res.rejected.WithLabelValues("persist")
+	res.rejected.WithLabelValues("tailable")
 
 	resource.Track(res, res.token)
 
@@ -111,11 +194,13 @@ func (r *Registry) Close(ctx context.Context) {
 	resource.Untrack(r, r.token)
 }
 
-// NewCursor stores a cursor with given continuation and connection (if any).
+// NewCursor stores a cursor with given continuation, connection (if any), and parameters.
+//
+// If cursor limits are exceeded, the connection is closed, and an error is returned.
 //
 // As a special case, if continuation is empty, this method does nothing.
 // That simplifies the typical usage.
-func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn) {
+func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn, params *Params) error {
 	// to have better logging for now
 	var cont *wirebson.Document
 	if len(continuation) > 0 {
@@ -142,7 +227,7 @@ func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *
 			)
 		}
 
-		return
+		return nil
 	}
 
 	must.NotBeZero(id)
@@ -155,31 +240,165 @@ func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *
 		r.closeCursor(context.TODO(), id)
 	}
 
+	c := newCursor(continuation, conn, params, false)
+
+	if err := r.checkLimits(c); err != nil {
+		r.l.Warn(
+			"Rejecting new cursor",
+			slog.Int64("id", id), slog.Bool("persist", persist), slog.String("error", err.Error()),
+		)
+
+		r.rejected.WithLabelValues(c.typ()).Inc()
+		r.stats.Rejected++
+		c.close(context.TODO())
+
+		return err
+	}
+
 	r.l.Debug("Creating new cursor",
 		slog.Int64("id", id), slog.Any("continuation", cont), slog.Bool("persist", persist),
 	)
 
-	r.cursors[id] = newCursor(continuation, conn)
+	r.addCursor(id, c)
+
+	return nil
+}
+
+// NewTailableCursor stores a tailable cursor with given continuation and parameters.
+//
+// Tailable cursors (such as change streams) are handled by FerretDB itself, not by DocumentDB;
+// the continuation format is defined by the caller.
+//
+// If cursor limits are exceeded, an error is returned.
+func (r *Registry) NewTailableCursor(id int64, continuation wirebson.RawDocument, params *Params) error {
+	must.NotBeZero(id)
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if _, ok := r.cursors[id]; ok {
+		r.l.Error("Replacing existing cursor", slog.Int64("id", id))
+		r.closeCursor(context.TODO(), id)
+	}
+
+	c := newCursor(continuation, nil, params, true)
+
+	if err := r.checkLimits(c); err != nil {
+		r.l.Warn("Rejecting new tailable cursor", slog.Int64("id", id), slog.String("error", err.Error()))
 
-	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/97
-	t := "normal"
-	if persist {
-		t = "persist"
+		r.rejected.WithLabelValues(c.typ()).Inc()
+		r.stats.Rejected++
+		c.close(context.TODO())
+
+		return err
 	}
 
-	r.created.WithLabelValues(t).Inc()
+	r.l.Debug("Creating new tailable cursor", slog.Int64("id", id))
+
+	r.addCursor(id, c)
+
+	return nil
 }
 
-// GetCursor returns the continuation and the connection for the given cursor id.
-func (r *Registry) GetCursor(id int64) (wirebson.RawDocument, *pgx.Conn) {
+// NewID returns a new random cursor ID that is not used by any cursor in the registry.
+//
+// It is used for tailable cursors; DocumentDB generates IDs of other cursors itself.
+func (r *Registry) NewID() int64 {
 	r.rw.RLock()
 	defer r.rw.RUnlock()
 
+	for {
+		id := rand.Int64()
+		if _, ok := r.cursors[id]; id != 0 && !ok {
+			return id
+		}
+	}
+}
+
+// checkLimits returns an error if adding the given cursor would exceed configured limits.
+//
+// It does not hold RWMutex, hence caller should hold RWMutex.
+func (r *Registry) checkLimits(c *cursor) error {
+	persist := c.conn != nil
+
+	var total, user, totalPersisted, userPersisted int
+
+	for _, oc := range r.cursors {
+		total++
+
+		if oc.conn != nil {
+			totalPersisted++
+		}
+
+		if oc.owner == c.owner {
+			user++
+
+			if oc.conn != nil {
+				userPersisted++
+			}
+		}
+	}
+
+	var msg string
+
+	switch {
+	case r.config.MaxCursors > 0 && total >= r.config.MaxCursors:
+		msg = fmt.Sprintf("Too many open cursors: limit is %d", r.config.MaxCursors)
+	case r.config.MaxUserCursors > 0 && user >= r.config.MaxUserCursors:
+		msg = fmt.Sprintf("Too many open cursors for the user: limit is %d", r.config.MaxUserCursors)
+	case persist && r.config.MaxPersisted > 0 && totalPersisted >= r.config.MaxPersisted:
+		msg = fmt.Sprintf("Too many cursors with persisted connections: limit is %d", r.config.MaxPersisted)
+	case persist && r.config.MaxUserPersisted > 0 && userPersisted >= r.config.MaxUserPersisted:
+		msg = fmt.Sprintf("Too many cursors with persisted connections for the user: limit is %d", r.config.MaxUserPersisted)
+	default:
+		return nil
+	}
+
+	return mongoerrors.NewWithArgument(mongoerrors.ErrOperationFailed, msg, "NewCursor")
+}
+
+// addCursor adds the given cursor to the registry.
+//
+// It does not hold RWMutex, hence caller should hold RWMutex.
+func (r *Registry) addCursor(id int64, c *cursor) {
+	r.cursors[id] = c
+
+	r.created.WithLabelValues(c.typ()).Inc()
+	r.stats.TotalOpened++
+}
+
+// GetCursor returns the continuation, the connection, and the parameters for the given cursor id,
+// and whether the cursor is tailable.
+//
+// It also marks the cursor as used, resetting its idle timeout.
+func (r *Registry) GetCursor(id int64) (wirebson.RawDocument, *pgx.Conn, Params, bool) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
 	if c := r.cursors[id]; c != nil {
-		return c.continuation, c.conn
+		c.lastUsed = time.Now()
+
+		params := Params{
+			Owner:     c.owner,
+			MaxTime:   c.maxTime,
+			NoTimeout: c.noTimeout,
+			Replica:   c.replica,
+		}
+
+		return c.continuation, c.conn, params, c.tailable
 	}
 
-	return nil, nil
+	return nil, nil, Params{}, false
+}
+
+// HasCursor returns true if the cursor with the given id exists.
+func (r *Registry) HasCursor(id int64) bool {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	_, ok := r.cursors[id]
+
+	return ok
 }
 
 // UpdateCursor updates existing cursor with given continuation.
@@ -217,6 +436,7 @@ func (r *Registry) UpdateCursor(id int64, continuation wirebson.RawDocument) {
 		slog.Int64("id", id), slog.Any("continuation", cont), slog.Bool("persist", persist),
 	)
 	c.continuation = continuation
+	c.lastUsed = time.Now()
 }
 
 // CloseCursor closes the cursor with the given id and removes it from the registry.
@@ -232,6 +452,58 @@ func (r *Registry) CloseCursor(ctx context.Context, id int64) bool {
 	return r.closeCursor(ctx, id)
 }
 
+// CloseIdle closes cursors that were not used for the configured timeout
+// and removes them from the registry.
+// Cursors created with `noCursorTimeout` are not closed.
+// It returns IDs of closed cursors.
+func (r *Registry) CloseIdle(ctx context.Context) []int64 {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var res []int64
+
+	for id, c := range r.cursors {
+		if c.noTimeout || time.Since(c.lastUsed) < r.config.Timeout {
+			continue
+		}
+
+		r.l.InfoContext(
+			ctx, "Closing idle cursor",
+			slog.Int64("id", id), slog.String("type", c.typ()), slog.Duration("idle", time.Since(c.lastUsed)),
+		)
+
+		r.closeCursor(ctx, id)
+
+		r.timedOut.Inc()
+		r.stats.TimedOut++
+
+		res = append(res, id)
+	}
+
+	return res
+}
+
+// Stats returns cursor statistics.
+func (r *Registry) Stats() *Stats {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := r.stats
+	res.Open = len(r.cursors)
+
+	for _, c := range r.cursors {
+		if c.noTimeout {
+			res.NoTimeout++
+		}
+
+		if c.conn != nil {
+			res.Pinned++
+		}
+	}
+
+	return &res
+}
+
 // closeCursor is a private function that is wrapped by CloseCursor.
 // It doesn't block RWMutex, hence it should be used only if necessary.
 func (r *Registry) closeCursor(ctx context.Context, id int64) bool {
@@ -242,21 +514,15 @@ func (r *Registry) closeCursor(ctx context.Context, id int64) bool {
 	}
 
 	dur := time.Since(c.created)
-	persist := c.conn != nil
+	t := c.typ()
 
 	r.l.DebugContext(
 		ctx, "Closing and removing cursor",
-		slog.Int64("id", id), slog.Bool("persist", persist), slog.Duration("duration", dur),
+		slog.Int64("id", id), slog.String("type", t), slog.Duration("duration", dur),
 	)
 	c.close(ctx)
 	delete(r.cursors, id)
 
-	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/97
-	t := "normal"
-	if persist {
-		t = "persist"
-	}
-
 	r.duration.WithLabelValues(t).Observe(dur.Seconds())
 
 	return true
@@ -266,12 +532,22 @@ func (r *Registry) closeCursor(ctx context.Context, id int64) bool {
 func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 	r.created.Describe(ch)
 	r.duration.Describe(ch)
+	r.timedOut.Describe(ch)
+	r.rejected.Describe(ch)
+	ch <- r.open
+	ch <- r.pinned
 }
 
 // Collect implements [prometheus.Collector].
 func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 	r.created.Collect(ch)
 	r.duration.Collect(ch)
+	r.timedOut.Collect(ch)
+	r.rejected.Collect(ch)
+
+	stats := r.Stats()
+	ch <- prometheus.MustNewConstMetric(r.open, prometheus.GaugeValue, float64(stats.Open))
+	ch <- prometheus.MustNewConstMetric(r.pinned, prometheus.GaugeValue, float64(stats.Pinned))
 }
 
 // check interfaces
@@ -0,0 +1,91 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestRegistryLimits(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	r := NewRegistry(Config{MaxCursors: 3, MaxUserCursors: 2}, testutil.Logger(t))
+	t.Cleanup(func() { r.Close(ctx) })
+
+	continuation := must.NotFail(wirebson.MustDocument("continuation", int32(1)).Encode())
+
+	require.NoError(t, r.NewCursor(1, continuation, nil, &Params{Owner: "alice"}))
+	require.NoError(t, r.NewCursor(2, continuation, nil, &Params{Owner: "alice"}))
+	require.Error(t, r.NewCursor(3, continuation, nil, &Params{Owner: "alice"}))
+	require.NoError(t, r.NewTailableCursor(4, continuation, &Params{Owner: "bob"}))
+	require.Error(t, r.NewCursor(5, continuation, nil, &Params{Owner: "bob"}))
+
+	assert.True(t, r.CloseCursor(ctx, 1))
+	require.NoError(t, r.NewCursor(6, continuation, nil, &Params{Owner: "bob"}))
+
+	expected := &Stats{
+		Open:        3,
+		TotalOpened: 4,
+		Rejected:    2,
+	}
+	assert.Equal(t, expected, r.Stats())
+}
+
+func TestRegistryCloseIdle(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	r := NewRegistry(Config{Timeout: time.Hour}, testutil.Logger(t))
+	t.Cleanup(func() { r.Close(ctx) })
+
+	continuation := must.NotFail(wirebson.MustDocument("continuation", int32(1)).Encode())
+
+	require.NoError(t, r.NewCursor(1, continuation, nil, new(Params)))
+	require.NoError(t, r.NewCursor(2, continuation, nil, &Params{NoTimeout: true}))
+	require.NoError(t, r.NewCursor(3, continuation, nil, &Params{Replica: "127.0.0.1:5433"}))
+
+	assert.Empty(t, r.CloseIdle(ctx))
+
+	r.rw.Lock()
+	for _, c := range r.cursors {
+		c.lastUsed = c.lastUsed.Add(-2 * time.Hour)
+	}
+	r.rw.Unlock()
+
+	// used cursor is not closed
+	_, _, params, _ := r.GetCursor(3)
+	assert.Equal(t, Params{Replica: "127.0.0.1:5433"}, params)
+
+	assert.Equal(t, []int64{1}, r.CloseIdle(ctx))
+
+	expected := &Stats{
+		Open:        2,
+		NoTimeout:   1,
+		TotalOpened: 3,
+		TimedOut:    1,
+	}
+	assert.Equal(t, expected, r.Stats())
+}
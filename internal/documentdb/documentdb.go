@@ -29,7 +29,6 @@ import "context"
 // TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/1148
 //
 // We use that schema for `listDatabases` and `explain` commands.
-// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/26
 // TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/143
 
 // Code for `documentdb_api_internal` can't be generated yet:
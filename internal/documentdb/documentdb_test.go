@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/FerretDB/v2/build/version"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
@@ -100,13 +101,13 @@ func TestError(t *testing.T) {
 
 	l := testutil.Logger(t)
 
-	pool, err := NewPool(uri, l, sp)
+	pool, err := NewPool(uri, nil, cursor.Config{}, false, l, sp)
 	require.NoError(t, err)
 	defer pool.Close()
 
 	var res wirebson.RawDocument
 
-	err = pool.WithConn(func(conn *pgx.Conn) error {
+	err = pool.WithConn(ctx, func(conn *pgx.Conn) error {
 		b := must.NotFail(wirebson.MustDocument(
 			"delete", testutil.CollectionName(t),
 			"deletes", wirebson.MustArray(wirebson.MustDocument(
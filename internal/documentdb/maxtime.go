@@ -0,0 +1,76 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MaxTimeCtx returns a derived context that is canceled after d.
+//
+// [Pool] methods called with that context also set PostgreSQL `statement_timeout`
+// of the used connection to the time left until the deadline,
+// so the query is canceled by PostgreSQL itself even if the client is gone.
+// That is used to implement `maxTimeMS`.
+func MaxTimeCtx(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return context.WithValue(ctx, maxTimeKey, d), cancel
+}
+
+// maxTime returns the time limit set by [MaxTimeCtx], or zero.
+func maxTime(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(maxTimeKey).(time.Duration)
+	return d
+}
+
+// setStatementTimeout sets `statement_timeout` of the given connection
+// if ctx was returned by [MaxTimeCtx].
+// It returns true if the timeout was set and should be reset later.
+func setStatementTimeout(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	if maxTime(ctx) == 0 {
+		return false, nil
+	}
+
+	deadline, _ := ctx.Deadline()
+
+	// zero disables the timeout, so round up
+	ms := time.Until(deadline).Milliseconds() + 1
+	if ms <= 1 {
+		return false, lazyerrors.Error(context.DeadlineExceeded)
+	}
+
+	if _, err := conn.Exec(ctx, fmt.Sprintf("SET statement_timeout = %d", ms)); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return true, nil
+}
+
+// resetStatementTimeout resets `statement_timeout` of the given connection to the default value.
+//
+// It uses a separate context, because the one passed to [setStatementTimeout] could be already expired.
+func resetStatementTimeout(conn *pgx.Conn) error {
+	if _, err := conn.Exec(todoCtx, "RESET statement_timeout"); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
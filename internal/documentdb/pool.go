@@ -15,7 +15,9 @@
 package documentdb
 
 import (
+	"context"
 	"log/slog"
+	"sync/atomic"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -37,15 +39,27 @@ const (
 
 // Pool represent a pool of PostgreSQL connections.
 type Pool struct {
-	p     *pgxpool.Pool
-	r     *cursor.Registry
-	l     *slog.Logger
-	token *resource.Token
+	p      *pgxpool.Pool
+	rp     *replicas
+	r      *cursor.Registry
+	cs     *changeStreams
+	rs     *rolesSetup
+	cp     *cappedSetup
+	shared *sharedState // nil if shared cursors are disabled
+	l      *slog.Logger
+	token  *resource.Token
+
+	clusterTime atomic.Uint64 // see ClusterTime
 }
 
 // NewPool creates a new pool of PostgreSQL connections.
 // No actual connections are established.
-func NewPool(uri string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
+//
+// Reads could be routed to PostgreSQL streaming replicas with the given URIs; see [Pool.ReadPreferenceCtx].
+// Cursor timeout and limits are set by the given configuration.
+// If shared is true, cursors without persisted connections and their sessions are stored in PostgreSQL,
+// so they could be used by all FerretDB instances connected to the same database.
+func NewPool(uri string, replicaURIs []string, cursors cursor.Config, shared bool, l *slog.Logger, sp *state.Provider) (*Pool, error) { //nolint:lll // for readability
 	must.NotBeZero(sp)
 
 	p, err := newPgxPool(uri, logging.WithName(l, "pgx"), sp)
@@ -53,12 +67,27 @@ func NewPool(uri string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
 		return nil, lazyerrors.Error(err)
 	}
 
+	rp, err := newReplicas(replicaURIs, logging.WithName(l, "replicas"), sp)
+	if err != nil {
+		p.Close()
+		return nil, lazyerrors.Error(err)
+	}
+
 	res := &Pool{
 		p:     p,
-		r:     cursor.NewRegistry(logging.WithName(l, "cursors")),
+		rp:    rp,
+		r:     cursor.NewRegistry(cursors, logging.WithName(l, "cursors")),
+		cs:    newChangeStreams(),
+		rs:    new(rolesSetup),
+		cp:    new(cappedSetup),
 		l:     l,
 		token: resource.NewToken(),
 	}
+
+	if shared {
+		res.shared = &sharedState{config: cursors}
+	}
+
 	resource.Track(res, res.token)
 
 	return res, nil
@@ -66,8 +95,10 @@ func NewPool(uri string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
 
 // Close closes all connections in the pool.
 func (p *Pool) Close() {
+	p.cs.close()
 	p.r.Close(todoCtx)
 
+	p.rp.close()
 	p.p.Close()
 
 	resource.Untrack(p, p.token)
@@ -75,21 +106,123 @@ func (p *Pool) Close() {
 
 // Acquire acquires a connection from the pool.
 //
+// If ctx contains a transaction (see [TxnCtx]), the connection pinned to that transaction is returned instead.
+// If ctx contains a backend PID callback (see [BackendPIDCtx]), it is called with the connection's PID
+// and cancellation function.
+// If ctx was returned by [MaxTimeCtx], the connection's statement timeout is set until [Conn.Release].
+// If ctx was returned by [Pool.ReadPreferenceCtx], the connection to the selected replica is returned.
+// The context is not used to cancel the acquisition itself,
+// see https://github.com/jackc/pgx/issues/1726#issuecomment-1711612138.
+//
 // It is caller's responsibility to call [Conn.Release].
 // Most callers should use [Pool.WithConn] instead.
-func (p *Pool) Acquire() (*Conn, error) {
-	conn, err := p.p.Acquire(todoCtx)
+func (p *Pool) Acquire(ctx context.Context) (*Conn, error) {
+	if txn := GetTxn(ctx); txn != nil {
+		conn := newTxnConn(txn)
+
+		if txn.conn == nil {
+			conn.Release()
+			return nil, lazyerrors.New("transaction already ended")
+		}
+
+		if err := conn.setStatementTimeout(ctx); err != nil {
+			conn.Release()
+			return nil, lazyerrors.Error(err)
+		}
+
+		trackBackendPID(ctx, conn)
+
+		return conn, nil
+	}
+
+	pgConn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	conn := newConn(pgConn)
+
+	if err = conn.setStatementTimeout(ctx); err != nil {
+		conn.Release()
+		return nil, lazyerrors.Error(err)
+	}
+
+	trackBackendPID(ctx, conn)
+
+	return conn, nil
+}
+
+// BackendPIDCtx returns a derived context with the given callback.
+//
+// [Pool] methods called with that context call f with the PostgreSQL backend PID
+// of the acquired connection and the function that cancels the query running on it,
+// and with 0 and nil when that connection is released.
+//
+// The connection is not released (and could not be used by anything else)
+// until that call of f returns. That allows the caller to serialize cancellation with the release,
+// so the query of another client that acquires the same connection later is never canceled.
+func BackendPIDCtx(ctx context.Context, f func(pid uint32, cancel func(context.Context) error)) context.Context {
+	return context.WithValue(ctx, backendPIDKey, f)
+}
+
+// trackBackendPID calls the callback stored in ctx by [BackendPIDCtx], if any.
+func trackBackendPID(ctx context.Context, conn *Conn) {
+	f, _ := ctx.Value(backendPIDKey).(func(uint32, func(context.Context) error))
+	if f == nil {
+		return
+	}
+
+	pgConn := conn.Conn().PgConn()
+
+	f(pgConn.PID(), pgConn.CancelRequest)
+	conn.onRelease = func() { f(0, nil) }
+}
+
+// WaitingForLock returns a set of the given PostgreSQL backend PIDs that are waiting for a lock.
+func (p *Pool) WaitingForLock(ctx context.Context, pids []uint32) (map[uint32]bool, error) {
+	res := make(map[uint32]bool, len(pids))
+
+	if len(pids) == 0 {
+		return res, nil
+	}
+
+	args := make([]int32, len(pids))
+	for i, pid := range pids {
+		args[i] = int32(pid)
+	}
+
+	q := `SELECT pid FROM pg_stat_activity WHERE pid = ANY($1) AND wait_event_type = 'Lock'`
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		rows, err := conn.Query(ctx, q, args)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		pids, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for _, pid := range pids {
+			res[uint32(pid)] = true
+		}
+
+		return nil
+	})
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	return newConn(conn), nil
+	return res, nil
 }
 
 // WithConn acquires a connection from the pool and calls the provided function with it.
 // The connection is automatically released after the function returns.
-func (p *Pool) WithConn(f func(*pgx.Conn) error) error {
-	conn, err := p.Acquire()
+//
+// See [Pool.Acquire] for the context usage.
+func (p *Pool) WithConn(ctx context.Context, f func(*pgx.Conn) error) error {
+	conn, err := p.Acquire(ctx)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -105,13 +238,33 @@ func (p *Pool) WithConn(f func(*pgx.Conn) error) error {
 
 // Describe implements [prometheus.Collector].
 func (p *Pool) Describe(ch chan<- *prometheus.Desc) {
-	prometheus.DescribeByCollect(p, ch)
+	p.r.Describe(ch)
+
+	// some replica metrics are collected only after the first check,
+	// so they can't be described by collecting
+	p.rp.Describe(ch)
+
+	metrics := make(chan prometheus.Metric)
+
+	go func() {
+		p.collectStats(metrics)
+		close(metrics)
+	}()
+
+	for m := range metrics {
+		ch <- m.Desc()
+	}
 }
 
 // Collect implements [prometheus.Collector].
 func (p *Pool) Collect(ch chan<- prometheus.Metric) {
 	p.r.Collect(ch)
+	p.rp.Collect(ch)
+	p.collectStats(ch)
+}
 
+// collectStats sends metrics of the primary pool to ch.
+func (p *Pool) collectStats(ch chan<- prometheus.Metric) {
 	stats := p.p.Stat()
 
 	ch <- prometheus.MustNewConstMetric(
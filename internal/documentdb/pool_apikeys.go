@@ -0,0 +1,149 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// APIKeyInfo represents a Data API key stored by FerretDB.
+type APIKeyInfo struct {
+	Created     time.Time
+	ID          string
+	Username    string
+	Description string
+	Hash        []byte
+}
+
+// CreateAPIKey stores a new API key.
+func (p *Pool) CreateAPIKey(ctx context.Context, key *APIKeyInfo) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CreateAPIKey")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `INSERT INTO ferretdb.api_keys (key_id, key_hash, user_name, description) VALUES ($1, $2, $3, $4)`
+		_, err := conn.Exec(ctx, q, key.ID, key.Hash, key.Username, key.Description)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// GetAPIKey returns the API key with the given ID, or nil if it does not exist.
+func (p *Pool) GetAPIKey(ctx context.Context, id string) (*APIKeyInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.GetAPIKey")
+	defer span.End()
+
+	res := APIKeyInfo{ID: id}
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT key_hash, user_name, description, created_at FROM ferretdb.api_keys WHERE key_id = $1`
+		return conn.QueryRow(ctx, q, id).Scan(&res.Hash, &res.Username, &res.Description, &res.Created)
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
+// ListAPIKeys returns API keys of the given user, or of all users if username is empty.
+// Hashes are not returned.
+func (p *Pool) ListAPIKeys(ctx context.Context, username string) ([]*APIKeyInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.ListAPIKeys")
+	defer span.End()
+
+	var res []*APIKeyInfo
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT key_id, user_name, description, created_at FROM ferretdb.api_keys ` +
+			`WHERE $1 = '' OR user_name = $1 ORDER BY user_name, created_at, key_id`
+
+		rows, err := conn.Query(ctx, q, username)
+		if err != nil {
+			return err
+		}
+
+		var key APIKeyInfo
+		scans := []any{&key.ID, &key.Username, &key.Description, &key.Created}
+
+		_, err = pgx.ForEachRow(rows, scans, func() error {
+			k := key
+			res = append(res, &k)
+
+			return nil
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// DropAPIKey removes the API key with the given ID.
+// It returns false if the key does not exist.
+func (p *Pool) DropAPIKey(ctx context.Context, id string) (bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.DropAPIKey")
+	defer span.End()
+
+	var dropped bool
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		tag, err := conn.Exec(ctx, `DELETE FROM ferretdb.api_keys WHERE key_id = $1`, id)
+		dropped = tag.RowsAffected() > 0
+
+		return err
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return dropped, nil
+}
+
+// DropUserAPIKeys removes all API keys of the given user.
+func (p *Pool) DropUserAPIKeys(ctx context.Context, username string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.DropUserAPIKeys")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, `DELETE FROM ferretdb.api_keys WHERE user_name = $1`, username)
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
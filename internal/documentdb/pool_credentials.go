@@ -0,0 +1,175 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// UserCredentials represents authentication mechanisms and credentials of the user stored by FerretDB.
+//
+// They are stored only for users created or updated with explicit mechanisms;
+// other users have only SCRAM-SHA-256 credentials stored by DocumentDB.
+type UserCredentials struct {
+	Mechanisms []string
+	SCRAMSHA1  wirebson.RawDocument // nil if SCRAM-SHA-1 is not enabled for the user
+}
+
+// GetUserCredentials returns credentials stored for the user, or nil.
+func (p *Pool) GetUserCredentials(ctx context.Context, username string) (*UserCredentials, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.GetUserCredentials")
+	defer span.End()
+
+	var res UserCredentials
+	var sha1 []byte
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT mechanisms, scram_sha1 FROM ferretdb.user_credentials WHERE user_name = $1`
+		return conn.QueryRow(ctx, q, username).Scan(&res.Mechanisms, &sha1)
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if sha1 != nil {
+		res.SCRAMSHA1 = wirebson.RawDocument(sha1)
+	}
+
+	return &res, nil
+}
+
+// SetUserCredentials replaces credentials stored for the user.
+func (p *Pool) SetUserCredentials(ctx context.Context, username string, creds *UserCredentials) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.SetUserCredentials")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		return setUserCredentials(ctx, conn, username, creds)
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// setUserCredentials replaces credentials stored for the user using the given connection.
+func setUserCredentials(ctx context.Context, conn *pgx.Conn, username string, creds *UserCredentials) error {
+	var sha1 []byte
+	if creds.SCRAMSHA1 != nil {
+		sha1 = []byte(creds.SCRAMSHA1)
+	}
+
+	q := `INSERT INTO ferretdb.user_credentials (user_name, mechanisms, scram_sha1) VALUES ($1, $2, $3) ` +
+		`ON CONFLICT (user_name) DO UPDATE SET mechanisms = EXCLUDED.mechanisms, scram_sha1 = EXCLUDED.scram_sha1`
+
+	if _, err := conn.Exec(ctx, q, username, creds.Mechanisms, sha1); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// DeleteUserCredentials removes credentials stored for the user.
+func (p *Pool) DeleteUserCredentials(ctx context.Context, username string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.DeleteUserCredentials")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, `DELETE FROM ferretdb.user_credentials WHERE user_name = $1`, username)
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// UserTokenGeneration returns the generation of Data API bearer tokens of the user.
+// Tokens issued with an older generation are revoked.
+func (p *Pool) UserTokenGeneration(ctx context.Context, username string) (int64, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.UserTokenGeneration")
+	defer span.End()
+
+	var res int64
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT generation FROM ferretdb.user_tokens WHERE user_name = $1`
+		return conn.QueryRow(ctx, q, username).Scan(&res)
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// RevokeUserTokens revokes all Data API bearer tokens of the user by incrementing their generation.
+func (p *Pool) RevokeUserTokens(ctx context.Context, username string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.RevokeUserTokens")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `INSERT INTO ferretdb.user_tokens (user_name, generation) VALUES ($1, 1) ` +
+			`ON CONFLICT (user_name) DO UPDATE SET generation = ferretdb.user_tokens.generation + 1`
+		_, err := conn.Exec(ctx, q, username)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// UserExists returns true if the PostgreSQL role with the given name exists and can log in.
+//
+// DocumentDB users are such roles.
+func (p *Pool) UserExists(ctx context.Context, username string) (bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.UserExists")
+	defer span.End()
+
+	var res bool
+
+	err := p.p.QueryRow(ctx, `SELECT rolcanlogin FROM pg_roles WHERE rolname = $1`, username).Scan(&res)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
@@ -18,22 +18,68 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
+// CursorOwnerCtx returns a derived context with the given cursor owner and session.
+//
+// Cursors created by [Pool] methods called with that context belong to that owner
+// for the purpose of per-user cursor limits.
+// Shared cursors could be used and killed only with the context with the same owner and session.
+func CursorOwnerCtx(ctx context.Context, owner string, sessionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, cursorOwnerKey, cursorOwner{owner: owner, sessionID: sessionID})
+}
+
+// cursorParams returns parameters for a new cursor created with the given context.
+func cursorParams(ctx context.Context, noTimeout bool) *cursor.Params {
+	owner, _ := getCursorOwner(ctx)
+
+	res := &cursor.Params{
+		Owner:     owner.owner,
+		MaxTime:   maxTime(ctx),
+		NoTimeout: noTimeout,
+	}
+
+	if r, _ := ctx.Value(replicaKey).(*replica); r != nil {
+		res.Replica = r.host
+	}
+
+	return res
+}
+
 // GetMore returns the next page of the cursor.
 // It is a part of the implementation of the `getMore` command.
 func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument, cursorID int64) (wirebson.RawDocument, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "pool.GetMore")
 	defer span.End()
 
-	continuation, conn := p.r.GetCursor(cursorID)
+	continuation, conn, params, tailable := p.r.GetCursor(cursorID)
+
+	if continuation == nil && p.shared != nil {
+		c, err := p.leaseSharedCursor(ctx, cursorID)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if c != nil {
+			continuation, params.MaxTime, params.Replica, tailable = c.continuation, c.maxTime, c.replica, c.tailable
+
+			ctx = context.WithValue(ctx, leaseKey, c)
+			defer p.releaseSharedCursor(ctx, cursorID, c)
+		}
+	}
+
 	if continuation == nil {
 		return nil, mongoerrors.NewWithArgument(
 			mongoerrors.ErrCursorNotFound,
@@ -42,8 +88,25 @@ func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument
 		)
 	}
 
+	// the next pages are read from the same server as the first one
+	if r := p.rp.get(params.Replica); r != nil {
+		ctx = context.WithValue(ctx, replicaKey, r)
+	}
+
+	if tailable {
+		return p.tailableGetMore(ctx, spec, cursorID, continuation)
+	}
+
+	// the limit set by the command that created the cursor applies to each page
+	if params.MaxTime > 0 && maxTime(ctx) == 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = MaxTimeCtx(ctx, params.MaxTime)
+
+		defer cancel()
+	}
+
 	if conn == nil {
-		poolConn, err := p.Acquire()
+		poolConn, err := p.Acquire(ctx)
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
@@ -51,11 +114,22 @@ func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument
 		defer poolConn.Release()
 
 		conn = poolConn.Conn()
+	} else {
+		// persisted connection is not acquired, so set timeout for this page explicitly
+		set, err := setStatementTimeout(ctx, conn)
+		if err != nil {
+			p.closeCursor(ctx, cursorID)
+			return nil, lazyerrors.Error(err)
+		}
+
+		if set {
+			defer resetStatementTimeout(conn) //nolint:errcheck // broken connection fails the next page anyway
+		}
 	}
 
 	page, continuation, err := documentdb_api.CursorGetMore(ctx, conn, p.l, db, spec, continuation)
 	if err != nil {
-		p.r.CloseCursor(ctx, cursorID)
+		p.closeCursor(ctx, cursorID)
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -64,7 +138,9 @@ func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument
 		slog.Any("page", page), slog.Any("continuation", continuation),
 	)
 
-	p.r.UpdateCursor(cursorID, continuation)
+	if err = p.updateCursor(ctx, cursorID, continuation); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
 	return page, nil
 }
@@ -76,29 +152,73 @@ func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument
 // It attempts a clean close by sending the exit message to PostgreSQL.
 // However, this could block so ctx is available to limit the time to wait (up to 3 seconds).
 // The underlying connection will always be called regardless of any other errors.
+//
+// Shared cursors of other users are not killed if ctx contains a cursor owner (see [CursorOwnerCtx]).
 func (p *Pool) KillCursor(ctx context.Context, id int64) bool {
 	ctx, span := otel.Tracer("").Start(ctx, "pool.KillCursor")
 	defer span.End()
 
-	return p.r.CloseCursor(ctx, id)
+	if p.LocalCursor(id) {
+		return p.r.CloseCursor(ctx, id)
+	}
+
+	killed, err := p.killSharedCursor(ctx, id)
+	if err != nil {
+		p.l.WarnContext(ctx, "Failed to kill shared cursor", slog.Int64("id", id), logging.Error(err))
+	}
+
+	return killed
+}
+
+// CloseIdleCursors closes cursors that were idle for longer than the configured timeout,
+// including shared ones.
+// It returns IDs of closed cursors.
+func (p *Pool) CloseIdleCursors(ctx context.Context) []int64 {
+	res := p.r.CloseIdle(ctx)
+
+	if p.shared == nil {
+		return res
+	}
+
+	shared, err := p.closeIdleSharedCursors(ctx)
+	if err != nil {
+		p.l.WarnContext(ctx, "Failed to close idle shared cursors", logging.Error(err))
+	}
+
+	return append(res, shared...)
+}
+
+// CursorStats returns cursor statistics.
+func (p *Pool) CursorStats() *cursor.Stats {
+	return p.r.Stats()
 }
 
-// ListCollections returns the first page of the `listCollections` cursor and the cursor ID.
-func (p *Pool) ListCollections(ctx context.Context, db string, spec wirebson.RawDocument) (wirebson.RawDocument, int64, error) {
+// ListCollections returns all collections of the `listCollections` command in a single page
+// with the closed cursor.
+//
+// All pages are fetched using the same connection, so the handler could sort and filter
+// the whole result instead of only the first page.
+// Shard keys of all collections are reported with the same connection.
+func (p *Pool) ListCollections(ctx context.Context, db string, spec wirebson.RawDocument) (wirebson.RawDocument, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "pool.ListCollections")
 	defer span.End()
 
-	poolConn, err := p.Acquire()
+	poolConn, err := p.Acquire(ctx)
 	if err != nil {
-		return nil, 0, lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 	defer poolConn.Release()
 
 	conn := poolConn.Conn()
 
-	page, continuation, persist, cursorID, err := documentdb_api.ListCollectionsCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	id, err := p.newCursorID(ctx)
 	if err != nil {
-		return nil, 0, lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
+	}
+
+	page, continuation, persist, cursorID, err := documentdb_api.ListCollectionsCursorFirstPage(ctx, conn, p.l, db, spec, id)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
 	}
 
 	p.l.DebugContext(
@@ -107,15 +227,102 @@ func (p *Pool) ListCollections(ctx context.Context, db string, spec wirebson.Raw
 		slog.Bool("persist", persist), slog.Int64("cursor", cursorID),
 	)
 
+	res, err := page.DecodeDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, ok := res.Get("cursor").(*wirebson.Document)
+	if !ok {
+		return nil, lazyerrors.Errorf("no cursor in %v", res)
+	}
+
+	batch, ok := c.Get("firstBatch").(*wirebson.Array)
+	if !ok {
+		return nil, lazyerrors.Errorf("no firstBatch in %v", c)
+	}
+
+	// cursor state is kept in the persisted connection, so it should not be reused after that
 	if persist {
-		conn = poolConn.hijack()
-	} else {
-		conn = nil
+		if hijacked := poolConn.hijack(); hijacked != nil {
+			conn = hijacked
+
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(todoCtx, 3*time.Second)
+				defer cancel()
+
+				_ = conn.Close(closeCtx)
+			}()
+		}
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	getMore := must.NotFail(wirebson.MustDocument(
+		"getMore", cursorID,
+		"collection", "$cmd.listCollections",
+	).Encode())
 
-	return page, cursorID, nil
+	for cursorID != 0 {
+		if page, continuation, err = documentdb_api.CursorGetMore(ctx, conn, p.l, db, getMore, continuation); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var next *wirebson.Document
+		if next, err = page.DecodeDeep(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if c, ok = next.Get("cursor").(*wirebson.Document); !ok {
+			return nil, lazyerrors.Errorf("no cursor in %v", next)
+		}
+
+		nextBatch, ok := c.Get("nextBatch").(*wirebson.Array)
+		if !ok {
+			return nil, lazyerrors.Errorf("no nextBatch in %v", c)
+		}
+
+		for v := range nextBatch.Values() {
+			must.NoError(batch.Add(v))
+		}
+
+		if cursorID, ok = c.Get("id").(int64); !ok {
+			return nil, lazyerrors.Errorf("no cursor ID in %v", c)
+		}
+	}
+
+	keys, err := shardKeys(ctx, conn, db, "")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for v := range batch.Values() {
+		coll, ok := v.(*wirebson.Document)
+		if !ok {
+			return nil, lazyerrors.Errorf("unexpected collection %v", v)
+		}
+
+		name, _ := coll.Get("name").(string)
+
+		shardKey, ok := keys[name]
+		if !ok {
+			continue
+		}
+
+		info, _ := coll.Get("info").(*wirebson.Document)
+		if info == nil {
+			info = wirebson.MakeDocument(1)
+			must.NoError(coll.Add("info", info))
+		}
+
+		must.NoError(info.Add("shardKey", shardKey))
+	}
+
+	must.NoError(res.Get("cursor").(*wirebson.Document).Replace("id", int64(0)))
+
+	if page, err = res.Encode(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return page, nil
 }
 
 // Find returns the first page of the `find` cursor and the cursor ID.
@@ -123,7 +330,14 @@ func (p *Pool) Find(ctx context.Context, db string, spec wirebson.RawDocument) (
 	ctx, span := otel.Tracer("").Start(ctx, "pool.Find")
 	defer span.End()
 
-	poolConn, err := p.Acquire()
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	noCursorTimeout, _ := doc.Get("noCursorTimeout").(bool)
+
+	poolConn, err := p.Acquire(ctx)
 	if err != nil {
 		return nil, 0, lazyerrors.Error(err)
 	}
@@ -131,7 +345,12 @@ func (p *Pool) Find(ctx context.Context, db string, spec wirebson.RawDocument) (
 
 	conn := poolConn.Conn()
 
-	page, continuation, persist, cursorID, err := documentdb_api.FindCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	id, err := p.newCursorID(ctx)
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	page, continuation, persist, cursorID, err := documentdb_api.FindCursorFirstPage(ctx, conn, p.l, db, spec, id)
 	if err != nil {
 		return nil, 0, lazyerrors.Error(err)
 	}
@@ -148,7 +367,9 @@ func (p *Pool) Find(ctx context.Context, db string, spec wirebson.RawDocument) (
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	if err = p.newCursor(ctx, cursorID, continuation, conn, cursorParams(ctx, noCursorTimeout)); err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
 
 	return page, cursorID, nil
 }
@@ -158,7 +379,7 @@ func (p *Pool) Aggregate(ctx context.Context, db string, spec wirebson.RawDocume
 	ctx, span := otel.Tracer("").Start(ctx, "pool.Aggregate")
 	defer span.End()
 
-	poolConn, err := p.Acquire()
+	poolConn, err := p.Acquire(ctx)
 	if err != nil {
 		return nil, 0, lazyerrors.Error(err)
 	}
@@ -166,7 +387,12 @@ func (p *Pool) Aggregate(ctx context.Context, db string, spec wirebson.RawDocume
 
 	conn := poolConn.Conn()
 
-	page, continuation, persist, cursorID, err := documentdb_api.AggregateCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	id, err := p.newCursorID(ctx)
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	page, continuation, persist, cursorID, err := documentdb_api.AggregateCursorFirstPage(ctx, conn, p.l, db, spec, id)
 	if err != nil {
 		return nil, 0, lazyerrors.Error(err)
 	}
@@ -183,7 +409,9 @@ func (p *Pool) Aggregate(ctx context.Context, db string, spec wirebson.RawDocume
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	if err = p.newCursor(ctx, cursorID, continuation, conn, cursorParams(ctx, false)); err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
 
 	return page, cursorID, nil
 }
@@ -193,7 +421,7 @@ func (p *Pool) ListIndexes(ctx context.Context, db string, spec wirebson.RawDocu
 	ctx, span := otel.Tracer("").Start(ctx, "pool.ListIndexes")
 	defer span.End()
 
-	poolConn, err := p.Acquire()
+	poolConn, err := p.Acquire(ctx)
 	if err != nil {
 		return nil, 0, lazyerrors.Error(err)
 	}
@@ -202,7 +430,12 @@ func (p *Pool) ListIndexes(ctx context.Context, db string, spec wirebson.RawDocu
 
 	conn := poolConn.Conn()
 
-	page, continuation, persist, cursorID, err := documentdb_api.ListIndexesCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	id, err := p.newCursorID(ctx)
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	page, continuation, persist, cursorID, err := documentdb_api.ListIndexesCursorFirstPage(ctx, conn, p.l, db, spec, id)
 	if err != nil {
 		return nil, 0, lazyerrors.Error(err)
 	}
@@ -219,7 +452,9 @@ func (p *Pool) ListIndexes(ctx context.Context, db string, spec wirebson.RawDocu
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	if err = p.newCursor(ctx, cursorID, continuation, conn, cursorParams(ctx, false)); err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
 
 	return page, cursorID, nil
 }
@@ -25,17 +25,29 @@ import (
 
 // DatabaseInfo represents an information about a single database.
 type DatabaseInfo struct {
-	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/26
+	// Size is the total size of all collections' tables, including indexes and TOAST, in bytes.
+	Size int64
+
+	// Collections is the number of collections and views.
+	Collections int64
 }
 
 // ListDatabases returns a list of existing databases and their information.
-//
-// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/26
 func (p *Pool) ListDatabases(ctx context.Context) (map[string]DatabaseInfo, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "pool.ListDatabases")
 	defer span.End()
 
-	rows, err := p.p.Query(ctx, "SELECT DISTINCT database_name FROM documentdb_api_catalog.collections")
+	// collections' tables could be dropped concurrently, so to_regclass is used to skip them
+	q := `
+		SELECT
+			database_name,
+			COALESCE(SUM(pg_total_relation_size(to_regclass(format('documentdb_data.documents_%s', collection_id)))), 0)::bigint,
+			COUNT(*)
+		FROM documentdb_api_catalog.collections
+		GROUP BY database_name
+	`
+
+	rows, err := p.p.Query(ctx, q)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -43,10 +55,11 @@ func (p *Pool) ListDatabases(ctx context.Context) (map[string]DatabaseInfo, erro
 	res := map[string]DatabaseInfo{}
 
 	var databaseName string
-	scans := []any{&databaseName}
+	var info DatabaseInfo
+	scans := []any{&databaseName, &info.Size, &info.Collections}
 
 	_, err = pgx.ForEachRow(rows, scans, func() error {
-		res[databaseName] = DatabaseInfo{}
+		res[databaseName] = info
 		return nil
 	})
 	if err != nil {
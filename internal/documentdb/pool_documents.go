@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// DocumentsPipeline applies aggregation pipeline stages to the given documents
+// and returns up to batchSize resulting documents.
+//
+// It uses the `$documents` stage, so the whole pipeline is executed by DocumentDB
+// with the same semantics as for the stored documents.
+func (p *Pool) DocumentsPipeline(ctx context.Context, db string, docs, stages *wirebson.Array, batchSize int) (*wirebson.Array, error) { //nolint:lll // for readability
+	var res *wirebson.Array
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		var err error
+		res, err = documentsPipeline(ctx, conn, p.l, db, docs, stages, batchSize)
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// documentsPipeline implements [Pool.DocumentsPipeline] using the given connection.
+func documentsPipeline(ctx context.Context, conn *pgx.Conn, l *slog.Logger, db string, docs, stages *wirebson.Array, batchSize int) (*wirebson.Array, error) { //nolint:lll // for readability
+	pipeline := wirebson.MakeArray(stages.Len() + 1)
+	must.NoError(pipeline.Add(wirebson.MustDocument("$documents", docs)))
+
+	for v := range stages.Values() {
+		must.NoError(pipeline.Add(v))
+	}
+
+	spec, err := wirebson.MustDocument(
+		"aggregate", int32(1),
+		"pipeline", pipeline,
+		"cursor", wirebson.MustDocument("batchSize", int32(batchSize)),
+		"$db", db,
+	).Encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	page, _, _, _, err := documentdb_api.AggregateCursorFirstPage(ctx, conn, l, db, spec, 0)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return pageBatch(page, "firstBatch")
+}
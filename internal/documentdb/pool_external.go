@@ -0,0 +1,141 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// ErrUserExists is returned by [Pool.CreateExternalUser] if the user already exists.
+var ErrUserExists = errors.New("user already exists")
+
+// ExternalUserInfo represents a user of the `$external` database stored by FerretDB.
+//
+// Such users are authenticated by external mechanisms such as MONGODB-X509
+// and do not have PostgreSQL roles.
+type ExternalUserInfo struct {
+	Name string
+	ID   [16]byte
+}
+
+// CreateExternalUser stores a new external user.
+// It returns [ErrUserExists] if the user already exists.
+func (p *Pool) CreateExternalUser(ctx context.Context, user *ExternalUserInfo) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CreateExternalUser")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `INSERT INTO ferretdb.external_users (user_name, user_id) VALUES ($1, $2)`
+		_, err := conn.Exec(ctx, q, user.Name, user.ID)
+
+		return err
+	})
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		return ErrUserExists
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// DropExternalUser removes the external user.
+// It returns false if the user does not exist.
+func (p *Pool) DropExternalUser(ctx context.Context, name string) (bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.DropExternalUser")
+	defer span.End()
+
+	var dropped bool
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		tag, err := conn.Exec(ctx, `DELETE FROM ferretdb.external_users WHERE user_name = $1`, name)
+		dropped = tag.RowsAffected() > 0
+
+		return err
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return dropped, nil
+}
+
+// GetExternalUser returns the external user, or nil if it does not exist.
+func (p *Pool) GetExternalUser(ctx context.Context, name string) (*ExternalUserInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.GetExternalUser")
+	defer span.End()
+
+	res := ExternalUserInfo{Name: name}
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT user_id FROM ferretdb.external_users WHERE user_name = $1`
+		return conn.QueryRow(ctx, q, name).Scan(&res.ID)
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
+// ListExternalUsers returns all external users ordered by name.
+func (p *Pool) ListExternalUsers(ctx context.Context) ([]*ExternalUserInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.ListExternalUsers")
+	defer span.End()
+
+	var res []*ExternalUserInfo
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		rows, err := conn.Query(ctx, `SELECT user_name, user_id FROM ferretdb.external_users ORDER BY user_name`)
+		if err != nil {
+			return err
+		}
+
+		var user ExternalUserInfo
+
+		_, err = pgx.ForEachRow(rows, []any{&user.Name, &user.ID}, func() error {
+			res = append(res, &ExternalUserInfo{
+				Name: user.Name,
+				ID:   user.ID,
+			})
+
+			return nil
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
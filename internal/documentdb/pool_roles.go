@@ -0,0 +1,410 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// rolesLockID is an advisory lock ID used to serialize setup of roles tables.
+const rolesLockID = 0x0fe77e7db0c6
+
+// ErrRoleExists is returned by [Pool.CreateRole] if the role already exists.
+var ErrRoleExists = errors.New("role already exists")
+
+// rolesSetupSQL creates tables for user-defined roles, users' role grants, users' credentials,
+// external users, Data API keys, and generations of Data API bearer tokens.
+//
+// DocumentDB supports only a few fixed roles, only SCRAM-SHA-256 credentials,
+// no external users, and no API keys, so FerretDB stores them itself.
+// Token generations are never removed, so tokens of dropped users stay invalid if they are created again.
+//
+// It should be executed in a transaction that holds rolesLockID advisory lock.
+const rolesSetupSQL = `
+CREATE SCHEMA IF NOT EXISTS ferretdb;
+
+CREATE TABLE IF NOT EXISTS ferretdb.roles (
+	database_name text NOT NULL,
+	role_name     text NOT NULL,
+	privileges    bytea NOT NULL,
+	roles         bytea NOT NULL,
+	PRIMARY KEY (database_name, role_name)
+);
+
+CREATE TABLE IF NOT EXISTS ferretdb.user_roles (
+	user_name text PRIMARY KEY,
+	roles     bytea NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ferretdb.user_credentials (
+	user_name  text PRIMARY KEY,
+	mechanisms text[] NOT NULL,
+	scram_sha1 bytea
+);
+
+CREATE TABLE IF NOT EXISTS ferretdb.external_users (
+	user_name text PRIMARY KEY,
+	user_id   uuid NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ferretdb.user_tokens (
+	user_name  text PRIMARY KEY,
+	generation bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ferretdb.api_keys (
+	key_id      text PRIMARY KEY,
+	key_hash    bytea NOT NULL,
+	user_name   text NOT NULL,
+	description text NOT NULL,
+	created_at  timestamptz NOT NULL DEFAULT now()
+);
+`
+
+// RoleInfo represents a user-defined role stored by FerretDB.
+type RoleInfo struct {
+	DB         string
+	Name       string
+	Privileges wirebson.RawArray
+	Roles      wirebson.RawArray
+}
+
+// rolesSetup tracks whether roles tables were created.
+type rolesSetup struct {
+	m     sync.Mutex
+	ready bool
+}
+
+// setupRoles creates roles tables if needed.
+//
+// It is safe to call it multiple times and from multiple FerretDB instances.
+func (p *Pool) setupRoles(ctx context.Context) error {
+	p.rs.m.Lock()
+	defer p.rs.m.Unlock()
+
+	if p.rs.ready {
+		return nil
+	}
+
+	conn, err := p.p.Acquire(todoCtx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", rolesLockID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, rolesSetupSQL)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	p.rs.ready = true
+
+	return nil
+}
+
+// withRoles sets up roles tables and calls f with a connection.
+func (p *Pool) withRoles(ctx context.Context, f func(*pgx.Conn) error) error {
+	if err := p.setupRoles(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	conn, err := p.p.Acquire(todoCtx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	return f(conn.Conn())
+}
+
+// CreateRole stores a new user-defined role.
+// It returns [ErrRoleExists] if the role already exists.
+func (p *Pool) CreateRole(ctx context.Context, role *RoleInfo) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CreateRole")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `INSERT INTO ferretdb.roles (database_name, role_name, privileges, roles) VALUES ($1, $2, $3, $4)`
+		_, err := conn.Exec(ctx, q, role.DB, role.Name, []byte(role.Privileges), []byte(role.Roles))
+
+		return err
+	})
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		return ErrRoleExists
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// UpdateRole replaces privileges and inherited roles of the user-defined role.
+// It returns false if the role does not exist.
+func (p *Pool) UpdateRole(ctx context.Context, role *RoleInfo) (bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.UpdateRole")
+	defer span.End()
+
+	var updated bool
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `UPDATE ferretdb.roles SET privileges = $3, roles = $4 WHERE database_name = $1 AND role_name = $2`
+
+		tag, err := conn.Exec(ctx, q, role.DB, role.Name, []byte(role.Privileges), []byte(role.Roles))
+		updated = tag.RowsAffected() > 0
+
+		return err
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return updated, nil
+}
+
+// DropRole removes the user-defined role.
+// It returns false if the role does not exist.
+func (p *Pool) DropRole(ctx context.Context, db, name string) (bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.DropRole")
+	defer span.End()
+
+	var dropped bool
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `DELETE FROM ferretdb.roles WHERE database_name = $1 AND role_name = $2`
+
+		tag, err := conn.Exec(ctx, q, db, name)
+		dropped = tag.RowsAffected() > 0
+
+		return err
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return dropped, nil
+}
+
+// GetRole returns the user-defined role, or nil if it does not exist.
+func (p *Pool) GetRole(ctx context.Context, db, name string) (*RoleInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.GetRole")
+	defer span.End()
+
+	roles, err := p.listRoles(ctx, "WHERE database_name = $1 AND role_name = $2", db, name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	return roles[0], nil
+}
+
+// ListRoles returns all user-defined roles of the given database, or of all databases if db is empty.
+func (p *Pool) ListRoles(ctx context.Context, db string) ([]*RoleInfo, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.ListRoles")
+	defer span.End()
+
+	if db == "" {
+		return p.listRoles(ctx, "")
+	}
+
+	return p.listRoles(ctx, "WHERE database_name = $1", db)
+}
+
+// listRoles returns user-defined roles matching the given WHERE clause.
+func (p *Pool) listRoles(ctx context.Context, where string, args ...any) ([]*RoleInfo, error) {
+	var res []*RoleInfo
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT database_name, role_name, privileges, roles FROM ferretdb.roles ` + where +
+			` ORDER BY database_name, role_name`
+
+		rows, err := conn.Query(ctx, q, args...)
+		if err != nil {
+			return err
+		}
+
+		var role RoleInfo
+		var privileges, roles []byte
+
+		_, err = pgx.ForEachRow(rows, []any{&role.DB, &role.Name, &privileges, &roles}, func() error {
+			res = append(res, &RoleInfo{
+				DB:         role.DB,
+				Name:       role.Name,
+				Privileges: wirebson.RawArray(privileges),
+				Roles:      wirebson.RawArray(roles),
+			})
+
+			return nil
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// GetUserRoles returns roles granted to the user.
+// It returns false if roles were never set for that user.
+func (p *Pool) GetUserRoles(ctx context.Context, username string) (wirebson.RawArray, bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.GetUserRoles")
+	defer span.End()
+
+	var roles []byte
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		q := `SELECT roles FROM ferretdb.user_roles WHERE user_name = $1`
+		return conn.QueryRow(ctx, q, username).Scan(&roles)
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	return wirebson.RawArray(roles), true, nil
+}
+
+// SetUserRoles replaces roles granted to the user.
+func (p *Pool) SetUserRoles(ctx context.Context, username string, roles wirebson.RawArray) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.SetUserRoles")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		return setUserRoles(ctx, conn, username, roles)
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// setUserRoles replaces roles granted to the user using the given connection.
+func setUserRoles(ctx context.Context, conn *pgx.Conn, username string, roles wirebson.RawArray) error {
+	q := `INSERT INTO ferretdb.user_roles (user_name, roles) VALUES ($1, $2) ` +
+		`ON CONFLICT (user_name) DO UPDATE SET roles = EXCLUDED.roles`
+
+	if _, err := conn.Exec(ctx, q, username, []byte(roles)); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// CreateUser creates a DocumentDB user with the given `createUser` spec.
+//
+// Roles granted to the user and credentials (if not nil) are stored in the same transaction,
+// so the user is never visible without them.
+func (p *Pool) CreateUser(ctx context.Context, spec wirebson.RawDocument, username string, roles wirebson.RawArray, creds *UserCredentials) (wirebson.RawDocument, error) { //nolint:lll // for readability
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CreateUser")
+	defer span.End()
+
+	var res wirebson.RawDocument
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if err := setUserRoles(ctx, tx.Conn(), username, roles); err != nil {
+				return err
+			}
+
+			if creds != nil {
+				if err := setUserCredentials(ctx, tx.Conn(), username, creds); err != nil {
+					return err
+				}
+			}
+
+			var err error
+			res, err = documentdb_api.CreateUser(ctx, tx.Conn(), p.l, spec)
+
+			return err
+		})
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// DeleteUserRoles removes roles granted to the user.
+func (p *Pool) DeleteUserRoles(ctx context.Context, username string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.DeleteUserRoles")
+	defer span.End()
+
+	err := p.withRoles(ctx, func(conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, `DELETE FROM ferretdb.user_roles WHERE user_name = $1`, username)
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// IsSuperuser returns true if the PostgreSQL role with the given name is a superuser.
+//
+// Such roles are not managed by DocumentDB, but could be used for authentication.
+func (p *Pool) IsSuperuser(ctx context.Context, username string) (bool, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.IsSuperuser")
+	defer span.End()
+
+	var res bool
+
+	err := p.p.QueryRow(ctx, `SELECT rolsuper FROM pg_roles WHERE rolname = $1`, username).Scan(&res)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
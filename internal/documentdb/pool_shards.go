@@ -0,0 +1,110 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// CollStats returns `collStats` command's response for the given collection.
+//
+// Shard keys are reported with the same connection (and transaction, if any)
+// as collection statistics.
+func (p *Pool) CollStats(ctx context.Context, db, collection string, scale float64) (wirebson.RawDocument, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CollStats")
+	defer span.End()
+
+	var res wirebson.RawDocument
+
+	err := p.WithConn(ctx, func(conn *pgx.Conn) error {
+		page, err := documentdb_api.CollStats(ctx, conn, p.l, db, collection, scale)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		keys, err := shardKeys(ctx, conn, db, collection)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		shardKey, ok := keys[collection]
+		if !ok {
+			res = page
+			return nil
+		}
+
+		doc, err := page.Decode()
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		// keep `ok` the last field
+		okV := doc.Get("ok")
+		doc.Remove("ok")
+
+		must.NoError(doc.Add("sharded", true))
+		must.NoError(doc.Add("shardKey", shardKey))
+		must.NoError(doc.Add("ok", okV))
+
+		res, err = doc.Encode()
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// shardKeys returns shard keys of sharded collections in the given database, keyed by collection name.
+// If collection is not empty, only that collection is checked.
+// Collections that are not sharded are not included.
+func shardKeys(ctx context.Context, conn *pgx.Conn, db, collection string) (map[string]wirebson.RawDocument, error) {
+	q := `
+	SELECT collection_name, shard_key::bytea
+	FROM documentdb_api_catalog.collections
+	WHERE database_name = $1 AND ($2 = '' OR collection_name = $2) AND shard_key IS NOT NULL
+	`
+
+	rows, err := conn.Query(ctx, q, db, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := map[string]wirebson.RawDocument{}
+
+	var collectionName string
+	var shardKey []byte
+	scans := []any{&collectionName, &shardKey}
+
+	_, err = pgx.ForEachRow(rows, scans, func() error {
+		res[collectionName] = wirebson.RawDocument(shardKey)
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
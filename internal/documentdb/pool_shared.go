@@ -0,0 +1,615 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// cursorNamespaceBits is the number of random low bits of generated cursor IDs.
+// Higher bits (except the sign bit) contain the instance's namespace.
+const cursorNamespaceBits = 48
+
+// sharedState tracks the setup of tables for cursors and sessions shared between FerretDB instances.
+// See [sharedSetupSQL].
+type sharedState struct {
+	config cursor.Config
+
+	m     sync.Mutex
+	ready bool
+	ns    int64 // cursor ID prefix
+}
+
+// timeout returns the idle timeout of shared cursors.
+func (s *sharedState) timeout() time.Duration {
+	if s.config.Timeout == 0 {
+		return cursor.DefaultTimeout
+	}
+
+	return s.config.Timeout
+}
+
+// sharedCursor represents a cursor stored in PostgreSQL.
+type sharedCursor struct {
+	continuation wirebson.RawDocument
+	maxTime      time.Duration
+	replica      string
+	lease        uuid.UUID // zero if released
+	tailable     bool
+}
+
+// cursorOwner represents a user and a session stored by [CursorOwnerCtx].
+type cursorOwner struct {
+	owner     string
+	sessionID uuid.UUID
+}
+
+// setupShared creates tables for shared cursors and sessions if needed,
+// and takes the cursor ID namespace for this instance.
+//
+// It is safe to call it multiple times and from multiple FerretDB instances.
+func (p *Pool) setupShared(ctx context.Context) error {
+	p.shared.m.Lock()
+	defer p.shared.m.Unlock()
+
+	if p.shared.ready {
+		return nil
+	}
+
+	conn, err := p.p.Acquire(todoCtx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	var ns int64
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", sharedLockID); err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, sharedSetupSQL); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, "SELECT nextval('ferretdb.cursor_namespaces')").Scan(&ns)
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	// namespaces are reused after 32767 instance starts; primary key prevents collisions then
+	p.shared.ns = (ns-1)%(1<<(63-cursorNamespaceBits)-1) + 1
+	p.shared.ready = true
+
+	p.l.InfoContext(ctx, "Shared cursors are enabled", slog.Int64("namespace", p.shared.ns))
+
+	return nil
+}
+
+// withShared sets up shared cursors tables and calls f with a connection.
+//
+// The connection is not a part of the client's transaction, and has no statement timeout.
+func (p *Pool) withShared(ctx context.Context, f func(*pgx.Conn) error) error {
+	if err := p.setupShared(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	conn, err := p.p.Acquire(todoCtx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	return f(conn.Conn())
+}
+
+// newCursorID returns a new cursor ID with this instance's namespace,
+// or zero if shared cursors are disabled and DocumentDB should generate cursor IDs itself.
+func (p *Pool) newCursorID(ctx context.Context) (int64, error) {
+	if p.shared == nil {
+		return 0, nil
+	}
+
+	if err := p.setupShared(ctx); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	var id int64
+	for id == 0 {
+		id = rand.Int63n(1 << cursorNamespaceBits)
+	}
+
+	return p.shared.ns<<cursorNamespaceBits | id, nil
+}
+
+// newTailableCursorID returns a new ID for the tailable cursor.
+// Unlike [Pool.newCursorID], it never returns zero, as DocumentDB does not handle such cursors.
+func (p *Pool) newTailableCursorID(ctx context.Context) (int64, error) {
+	id, err := p.newCursorID(ctx)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	if id == 0 {
+		id = p.r.NewID()
+	}
+
+	return id, nil
+}
+
+// getCursorOwner returns the cursor owner stored in ctx by [CursorOwnerCtx].
+func getCursorOwner(ctx context.Context) (cursorOwner, bool) {
+	owner, ok := ctx.Value(cursorOwnerKey).(cursorOwner)
+	return owner, ok
+}
+
+// LocalCursor returns true if the cursor with the given ID is stored in this instance's memory,
+// and false if it is shared between instances (or does not exist in shared mode).
+func (p *Pool) LocalCursor(id int64) bool {
+	return p.shared == nil || p.r.HasCursor(id)
+}
+
+// newCursor stores a new cursor with the given continuation and connection (if any).
+//
+// If shared cursors are enabled, cursors without connections are stored in PostgreSQL.
+func (p *Pool) newCursor(ctx context.Context, id int64, continuation wirebson.RawDocument, conn *pgx.Conn, params *cursor.Params) error { //nolint:lll // for readability
+	if p.shared == nil || conn != nil || len(continuation) == 0 {
+		return p.r.NewCursor(id, continuation, conn, params)
+	}
+
+	return p.storeSharedCursor(ctx, id, continuation, params, false)
+}
+
+// newTailableCursor stores a new tailable cursor with the given continuation.
+//
+// If shared cursors are enabled, it is stored in PostgreSQL.
+func (p *Pool) newTailableCursor(ctx context.Context, id int64, continuation wirebson.RawDocument, params *cursor.Params) error {
+	if p.shared == nil {
+		return p.r.NewTailableCursor(id, continuation, params)
+	}
+
+	return p.storeSharedCursor(ctx, id, continuation, params, true)
+}
+
+// updateCursor updates the continuation of the local or shared cursor.
+// Empty continuation closes the cursor.
+func (p *Pool) updateCursor(ctx context.Context, id int64, continuation wirebson.RawDocument) error {
+	if p.LocalCursor(id) {
+		p.r.UpdateCursor(id, continuation)
+		return nil
+	}
+
+	c, _ := ctx.Value(leaseKey).(*sharedCursor)
+	must.NotBeZero(c)
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		if len(continuation) == 0 {
+			_, err := conn.Exec(ctx, `DELETE FROM ferretdb.cursors WHERE cursor_id = $1`, id)
+			return err
+		}
+
+		// the lease could be taken by another command if this one took longer than the timeout
+		q := `UPDATE ferretdb.cursors SET continuation = $2, last_used = now(), lease = NULL ` +
+			`WHERE cursor_id = $1 AND lease = $3`
+		_, err := conn.Exec(ctx, q, id, []byte(continuation), c.lease)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	c.lease = uuid.Nil
+
+	return nil
+}
+
+// closeCursor closes the local or shared cursor.
+func (p *Pool) closeCursor(ctx context.Context, id int64) {
+	if p.LocalCursor(id) {
+		p.r.CloseCursor(ctx, id)
+		return
+	}
+
+	if _, err := p.killSharedCursor(ctx, id); err != nil {
+		p.l.WarnContext(ctx, "Failed to close shared cursor", slog.Int64("id", id), logging.Error(err))
+	}
+}
+
+// storeSharedCursor stores a new cursor in PostgreSQL, checking global and per-user limits.
+func (p *Pool) storeSharedCursor(ctx context.Context, id int64, continuation wirebson.RawDocument, params *cursor.Params, tailable bool) error { //nolint:lll // for readability
+	owner, _ := getCursorOwner(ctx)
+	config := p.shared.config
+
+	var stored bool
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			// concurrent transactions do not see each other's cursors, so checks should be serialized
+			if config.MaxCursors > 0 || config.MaxUserCursors > 0 {
+				if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", sharedLimitsLockID); err != nil {
+					return err
+				}
+			}
+
+			q := `INSERT INTO ferretdb.cursors ` +
+				`(cursor_id, owner, session_id, continuation, tailable, max_time_ms, no_timeout, replica) ` +
+				`SELECT $1, $2, $3, $4, $5, $6, $7, $8 ` +
+				`WHERE ($9 = 0 OR (SELECT count(*) FROM ferretdb.cursors) < $9) ` +
+				`AND ($10 = 0 OR (SELECT count(*) FROM ferretdb.cursors WHERE owner = $2) < $10)`
+
+			tag, err := tx.Exec(
+				ctx, q,
+				id, owner.owner, owner.sessionID, []byte(continuation), tailable, params.MaxTime.Milliseconds(), params.NoTimeout,
+				params.Replica, config.MaxCursors, config.MaxUserCursors,
+			)
+			if err != nil {
+				return err
+			}
+
+			if stored = tag.RowsAffected() == 1; !stored {
+				return nil
+			}
+
+			return touchSharedSession(ctx, tx.Conn(), owner)
+		})
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !stored {
+		msg := fmt.Sprintf(
+			"Too many open cursors: limits are %d in total and %d per user",
+			config.MaxCursors, config.MaxUserCursors,
+		)
+
+		return mongoerrors.NewWithArgument(mongoerrors.ErrOperationFailed, msg, "NewCursor")
+	}
+
+	return nil
+}
+
+// leaseSharedCursor returns the cursor stored in PostgreSQL, or nil if it does not exist.
+// The cursor is leased until [Pool.updateCursor] or [Pool.releaseSharedCursor] is called.
+//
+// It returns an error if the cursor belongs to a different user or session,
+// or if it is leased by another command.
+func (p *Pool) leaseSharedCursor(ctx context.Context, id int64) (*sharedCursor, error) {
+	owner, _ := getCursorOwner(ctx)
+
+	var res *sharedCursor
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			c := sharedCursor{lease: uuid.New()}
+
+			var cursorOwner string
+			var cursorSessionID uuid.UUID
+			var maxTimeMS int64
+			var leased bool
+
+			q := `SELECT owner, session_id, continuation, tailable, max_time_ms, replica, ` +
+				`lease IS NOT NULL AND last_used >= now() - $2 * interval '1 millisecond' ` +
+				`FROM ferretdb.cursors WHERE cursor_id = $1 FOR UPDATE`
+
+			err := tx.QueryRow(ctx, q, id, p.shared.timeout().Milliseconds()).Scan(
+				&cursorOwner, &cursorSessionID, &c.continuation, &c.tailable, &maxTimeMS, &c.replica, &leased,
+			)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err = checkCursorOwner(id, owner, cursorOwner, cursorSessionID); err != nil {
+				return err
+			}
+
+			if leased {
+				return mongoerrors.NewWithArgument(
+					mongoerrors.ErrOperationFailed,
+					fmt.Sprintf("cursor id %d is already in use", id),
+					"getMore",
+				)
+			}
+
+			q = `UPDATE ferretdb.cursors SET last_used = now(), lease = $2 WHERE cursor_id = $1`
+			if _, err = tx.Exec(ctx, q, id, c.lease); err != nil {
+				return err
+			}
+
+			if err = touchSharedSession(ctx, tx.Conn(), owner); err != nil {
+				return err
+			}
+
+			c.maxTime = time.Duration(maxTimeMS) * time.Millisecond
+			res = &c
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// releaseSharedCursor releases the lease of the cursor taken by [Pool.leaseSharedCursor],
+// unless it was already released by [Pool.updateCursor].
+func (p *Pool) releaseSharedCursor(ctx context.Context, id int64, c *sharedCursor) {
+	if c.lease == uuid.Nil {
+		return
+	}
+
+	// release the lease even if the command was canceled
+	ctx = context.WithoutCancel(ctx)
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `UPDATE ferretdb.cursors SET lease = NULL WHERE cursor_id = $1 AND lease = $2`
+		_, err := conn.Exec(ctx, q, id, c.lease)
+
+		return err
+	})
+	if err != nil {
+		p.l.WarnContext(ctx, "Failed to release shared cursor", slog.Int64("id", id), logging.Error(err))
+		return
+	}
+
+	c.lease = uuid.Nil
+}
+
+// checkCursorOwner returns an error if the shared cursor was created by a different user or in a different session.
+// The messages are the same as for local cursors.
+func checkCursorOwner(id int64, current cursorOwner, owner string, sessionID uuid.UUID) error {
+	if current.owner != owner && sessionID == uuid.Nil && current.sessionID == uuid.Nil {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			fmt.Sprintf("cursor id %d was not created by the authenticated user", id),
+			"getMore",
+		)
+	}
+
+	if current.owner == owner && current.sessionID == sessionID {
+		return nil
+	}
+
+	cursorSession := "none"
+	if sessionID != uuid.Nil {
+		cursorSession = fmt.Sprintf("%s - %s -  - ", sessionID.String(), owner)
+	}
+
+	currentSession := "none"
+	if current.sessionID != uuid.Nil {
+		currentSession = fmt.Sprintf("%s - %s -  - ", current.sessionID.String(), current.owner)
+	}
+
+	msg := fmt.Sprintf(
+		"Cursor session id (%s) is not the same as the operation context's session id (%s)",
+		cursorSession,
+		currentSession,
+	)
+
+	return mongoerrors.NewWithArgument(mongoerrors.ErrUnauthorized, msg, "getMore")
+}
+
+// killSharedCursor removes the cursor stored in PostgreSQL.
+// It returns true if the cursor was found and removed.
+//
+// If ctx contains a cursor owner, cursors of other users are not removed.
+func (p *Pool) killSharedCursor(ctx context.Context, id int64) (bool, error) {
+	var owner *string
+	if o, ok := getCursorOwner(ctx); ok {
+		owner = &o.owner
+	}
+
+	var killed bool
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `DELETE FROM ferretdb.cursors WHERE cursor_id = $1 AND ($2::text IS NULL OR owner = $2)`
+
+		tag, err := conn.Exec(ctx, q, id, owner)
+		if err != nil {
+			return err
+		}
+
+		killed = tag.RowsAffected() > 0
+
+		return nil
+	})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return killed, nil
+}
+
+// closeIdleSharedCursors removes shared cursors that were idle for longer than the configured timeout.
+// It returns IDs of removed cursors.
+func (p *Pool) closeIdleSharedCursors(ctx context.Context) ([]int64, error) {
+	timeout := p.shared.timeout()
+
+	var res []int64
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `DELETE FROM ferretdb.cursors ` +
+			`WHERE NOT no_timeout AND last_used < now() - $1 * interval '1 millisecond' RETURNING cursor_id`
+
+		rows, err := conn.Query(ctx, q, timeout.Milliseconds())
+		if err != nil {
+			return err
+		}
+
+		res, err = pgx.CollectRows(rows, pgx.RowTo[int64])
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// touchSharedSession updates the last used time of the session that owns shared cursors.
+func touchSharedSession(ctx context.Context, conn *pgx.Conn, owner cursorOwner) error {
+	if owner.sessionID == uuid.Nil {
+		return nil
+	}
+
+	q := `INSERT INTO ferretdb.sessions (owner, session_id) VALUES ($1, $2) ` +
+		`ON CONFLICT (owner, session_id) DO UPDATE SET last_used = now()`
+	_, err := conn.Exec(ctx, q, owner.owner, owner.sessionID)
+
+	return err
+}
+
+// RefreshSessions updates the last used time of the given user's sessions
+// that own shared cursors, so they are not expired by any FerretDB instance.
+// It is a part of the implementation of the `refreshSessions` command.
+//
+// It does nothing if shared cursors are disabled.
+func (p *Pool) RefreshSessions(ctx context.Context, owner string, sessionIDs []uuid.UUID) error {
+	if p.shared == nil || len(sessionIDs) == 0 {
+		return nil
+	}
+
+	ctx, span := otel.Tracer("").Start(ctx, "pool.RefreshSessions")
+	defer span.End()
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `UPDATE ferretdb.sessions SET last_used = now() WHERE owner = $1 AND session_id = ANY($2)`
+		_, err := conn.Exec(ctx, q, owner, sessionIDs)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// EndSessions removes the given user's sessions and their shared cursors.
+// If owner is empty, sessions of any user are removed.
+// It is a part of the implementation of `endSessions` and `killSessions` commands.
+//
+// It does nothing if shared cursors are disabled.
+func (p *Pool) EndSessions(ctx context.Context, owner string, sessionIDs []uuid.UUID) error {
+	if p.shared == nil || len(sessionIDs) == 0 {
+		return nil
+	}
+
+	ctx, span := otel.Tracer("").Start(ctx, "pool.EndSessions")
+	defer span.End()
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `DELETE FROM ferretdb.cursors WHERE ($1 = '' OR owner = $1) AND session_id = ANY($2)`
+		if _, err := conn.Exec(ctx, q, owner, sessionIDs); err != nil {
+			return err
+		}
+
+		q = `DELETE FROM ferretdb.sessions WHERE ($1 = '' OR owner = $1) AND session_id = ANY($2)`
+		_, err := conn.Exec(ctx, q, owner, sessionIDs)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// EndUserSessions removes all sessions of the given users (of all users if owners is nil)
+// and their shared cursors, including cursors without sessions.
+// It is a part of the implementation of `killSessions` and `killAllSessions` commands.
+//
+// It does nothing if shared cursors are disabled.
+func (p *Pool) EndUserSessions(ctx context.Context, owners []string) error {
+	if p.shared == nil {
+		return nil
+	}
+
+	ctx, span := otel.Tracer("").Start(ctx, "pool.EndUserSessions")
+	defer span.End()
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `DELETE FROM ferretdb.cursors WHERE $1::text[] IS NULL OR owner = ANY($1)`
+		if _, err := conn.Exec(ctx, q, owners); err != nil {
+			return err
+		}
+
+		q = `DELETE FROM ferretdb.sessions WHERE $1::text[] IS NULL OR owner = ANY($1)`
+		_, err := conn.Exec(ctx, q, owners)
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// ExpireSessions removes sessions that own shared cursors and were not used for the given time,
+// together with their cursors.
+//
+// It does nothing if shared cursors are disabled.
+func (p *Pool) ExpireSessions(ctx context.Context, timeout time.Duration) error {
+	if p.shared == nil {
+		return nil
+	}
+
+	err := p.withShared(ctx, func(conn *pgx.Conn) error {
+		q := `WITH expired AS (` +
+			`DELETE FROM ferretdb.sessions WHERE last_used < now() - $1 * interval '1 millisecond' ` +
+			`RETURNING owner, session_id` +
+			`) ` +
+			`DELETE FROM ferretdb.cursors c USING expired e WHERE c.owner = e.owner AND c.session_id = e.session_id`
+		_, err := conn.Exec(ctx, q, timeout.Milliseconds())
+
+		return err
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
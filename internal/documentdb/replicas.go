@@ -0,0 +1,337 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/state"
+)
+
+// ReadMode represents read preference mode.
+type ReadMode string
+
+// Read preference modes.
+const (
+	ReadPrimary            ReadMode = "primary"
+	ReadPrimaryPreferred   ReadMode = "primaryPreferred"
+	ReadSecondary          ReadMode = "secondary"
+	ReadSecondaryPreferred ReadMode = "secondaryPreferred"
+	ReadNearest            ReadMode = "nearest"
+)
+
+const (
+	// replicaCheckInterval is the minimal interval between replay lag checks of the same replica.
+	replicaCheckInterval = time.Second
+
+	// replicaCheckTimeout is the timeout of a single replay lag check.
+	replicaCheckTimeout = 2 * time.Second
+)
+
+// replica represents a pool of connections to a single PostgreSQL streaming replica.
+type replica struct {
+	p    *pgxpool.Pool
+	host string // for logging and metrics; does not contain credentials
+
+	m       sync.Mutex
+	checked time.Time
+	lag     time.Duration
+	err     error // of the last check
+}
+
+// replicas represents all PostgreSQL streaming replicas of the pool.
+type replicas struct {
+	rs    []*replica
+	l     *slog.Logger
+	reads *prometheus.CounterVec
+
+	lag           *prometheus.Desc
+	acquiredTotal *prometheus.Desc
+	acquired      *prometheus.Desc
+	size          *prometheus.Desc
+}
+
+// newReplicas creates pools for the given replica URIs.
+// No actual connections are established.
+func newReplicas(uris []string, l *slog.Logger, sp *state.Provider) (*replicas, error) {
+	res := &replicas{
+		l: l,
+		reads: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "reads_total",
+				Help:      "Total number of read commands by the server they were routed to.",
+			},
+			[]string{"target"},
+		),
+		lag: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "replica_lag_seconds"),
+			"The replay lag of the replica at the last check.",
+			[]string{"replica"}, nil,
+		),
+		acquiredTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "replica_acquired_total"),
+			"The total count of successful acquires from the replica pool.",
+			[]string{"replica"}, nil,
+		),
+		acquired: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "replica_acquired"),
+			"The number of currently acquired connection in the replica pool.",
+			[]string{"replica"}, nil,
+		),
+		size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "replica_size"),
+			"The current number of connections in the replica pool.",
+			[]string{"replica"}, nil,
+		),
+	}
+
+	res.reads.WithLabelValues("primary")
+	res.reads.WithLabelValues("replica")
+
+	for _, uri := range uris {
+		p, err := newPgxPool(uri, logging.WithName(l, "pgx"), sp)
+		if err != nil {
+			res.close()
+			return nil, lazyerrors.Error(err)
+		}
+
+		c := p.Config().ConnConfig
+
+		res.rs = append(res.rs, &replica{
+			p:    p,
+			host: net.JoinHostPort(c.Host, strconv.Itoa(int(c.Port))),
+		})
+	}
+
+	return res, nil
+}
+
+// close closes all replica pools.
+func (rs *replicas) close() {
+	for _, r := range rs.rs {
+		r.p.Close()
+	}
+}
+
+// checkLag returns the replay lag of the replica.
+//
+// The result of the last check is reused for [replicaCheckInterval].
+// Lag is zero when the replica has replayed all received WAL,
+// so idle primaries do not make replicas look stale.
+func (r *replica) checkLag(ctx context.Context) (time.Duration, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if time.Since(r.checked) < replicaCheckInterval {
+		return r.lag, r.err
+	}
+
+	// do not cache the error of the canceled command
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), replicaCheckTimeout)
+	defer cancel()
+
+	// A replica that replayed all received WAL is as fresh as the primary only while
+	// its WAL receiver is streaming; otherwise, it may be arbitrarily far behind.
+	// The status is visible only to roles with pg_read_all_stats privileges.
+	q := `SELECT pg_is_in_recovery(), ` +
+		`COALESCE((SELECT status FROM pg_stat_wal_receiver), ''), ` +
+		`CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0 ` +
+		`ELSE COALESCE(extract(epoch FROM now() - pg_last_xact_replay_timestamp()), 0) END::float8`
+
+	var standby bool
+	var status string
+	var lag float64
+
+	r.lag, r.err = 0, nil
+	r.checked = time.Now()
+
+	if err := r.p.QueryRow(ctx, q).Scan(&standby, &status, &lag); err != nil {
+		r.err = lazyerrors.Error(err)
+		return r.lag, r.err
+	}
+
+	if !standby {
+		r.err = lazyerrors.Errorf("%s is not a standby", r.host)
+		return r.lag, r.err
+	}
+
+	if status != "streaming" {
+		r.err = lazyerrors.Errorf("%s WAL receiver is not streaming (status %q)", r.host, status)
+		return r.lag, r.err
+	}
+
+	r.lag = time.Duration(lag * float64(time.Second))
+
+	return r.lag, r.err
+}
+
+// get returns the replica with the given host:port, or nil if there is none.
+// Other FerretDB instances sharing cursors may use replicas that are not configured for this one.
+func (rs *replicas) get(host string) *replica {
+	if host == "" {
+		return nil
+	}
+
+	for _, r := range rs.rs {
+		if r.host == host {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// eligible returns replicas with the replay lag not greater than maxStaleness (zero means no limit).
+func (rs *replicas) eligible(ctx context.Context, maxStaleness time.Duration) []*replica {
+	res := make([]*replica, 0, len(rs.rs))
+
+	for _, r := range rs.rs {
+		lag, err := r.checkLag(ctx)
+		if err != nil {
+			rs.l.DebugContext(ctx, "Replica is not available", slog.String("replica", r.host), logging.Error(err))
+			continue
+		}
+
+		if maxStaleness > 0 && lag > maxStaleness {
+			rs.l.DebugContext(ctx, "Replica is stale", slog.String("replica", r.host), slog.Duration("lag", lag))
+			continue
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// ReadPreferenceCtx returns a derived context for the read command with the given read preference mode
+// and `maxStalenessSeconds` (zero means no limit).
+//
+// [Pool] methods called with that context use the PostgreSQL streaming replica selected by this method,
+// or the primary. The selection is made once, so all queries of the command use the same server.
+// Replicas with the replay lag greater than maxStaleness, and unavailable replicas are not selected.
+//
+//   - [ReadPrimary] and [ReadPrimaryPreferred] always use the primary;
+//   - [ReadSecondary] uses a random replica, or returns an error if there are none;
+//   - [ReadSecondaryPreferred] uses a random replica, or the primary if there are none;
+//   - [ReadNearest] uses a random server, including the primary.
+//
+// If the pool has no replicas configured, the primary is used for all modes.
+// Transactions (see [TxnCtx]) always use the primary.
+func (p *Pool) ReadPreferenceCtx(ctx context.Context, mode ReadMode, maxStaleness time.Duration) (context.Context, error) {
+	if GetTxn(ctx) != nil || len(p.rp.rs) == 0 {
+		p.rp.reads.WithLabelValues("primary").Inc()
+		return ctx, nil
+	}
+
+	var candidates []*replica
+
+	switch mode {
+	case ReadPrimary, ReadPrimaryPreferred:
+		// nothing
+	case ReadSecondary, ReadSecondaryPreferred:
+		candidates = p.rp.eligible(ctx, maxStaleness)
+	case ReadNearest:
+		// nil means the primary
+		candidates = append(p.rp.eligible(ctx, maxStaleness), nil)
+	default:
+		panic(fmt.Sprintf("unexpected read preference mode %q", mode))
+	}
+
+	if len(candidates) == 0 {
+		if mode == ReadSecondary {
+			msg := fmt.Sprintf("No replica available for read preference mode %q", mode)
+			if maxStaleness > 0 {
+				msg += fmt.Sprintf(" and maxStalenessSeconds %.0f", maxStaleness.Seconds())
+			}
+
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToSatisfyReadPreference, msg, "$readPreference")
+		}
+
+		candidates = append(candidates, nil)
+	}
+
+	r := candidates[rand.IntN(len(candidates))]
+	if r == nil {
+		p.rp.reads.WithLabelValues("primary").Inc()
+		return ctx, nil
+	}
+
+	p.rp.reads.WithLabelValues("replica").Inc()
+
+	return context.WithValue(ctx, replicaKey, r), nil
+}
+
+// acquire acquires a connection from the pool of the replica selected by [Pool.ReadPreferenceCtx],
+// or from the primary pool.
+func (p *Pool) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	pool := p.p
+	if r, _ := ctx.Value(replicaKey).(*replica); r != nil {
+		pool = r.p
+	}
+
+	return pool.Acquire(todoCtx)
+}
+
+// Describe implements [prometheus.Collector].
+func (rs *replicas) Describe(ch chan<- *prometheus.Desc) {
+	rs.reads.Describe(ch)
+	ch <- rs.lag
+	ch <- rs.acquiredTotal
+	ch <- rs.acquired
+	ch <- rs.size
+}
+
+// Collect implements [prometheus.Collector].
+//
+// The replay lag is collected only after the first successful check of the replica.
+func (rs *replicas) Collect(ch chan<- prometheus.Metric) {
+	rs.reads.Collect(ch)
+
+	for _, r := range rs.rs {
+		r.m.Lock()
+		lag, checked := r.lag, !r.checked.IsZero() && r.err == nil
+		r.m.Unlock()
+
+		if checked {
+			ch <- prometheus.MustNewConstMetric(rs.lag, prometheus.GaugeValue, lag.Seconds(), r.host)
+		}
+
+		stats := r.p.Stat()
+
+		ch <- prometheus.MustNewConstMetric(rs.acquiredTotal, prometheus.CounterValue, float64(stats.AcquireCount()), r.host)
+		ch <- prometheus.MustNewConstMetric(rs.acquired, prometheus.GaugeValue, float64(stats.AcquiredConns()), r.host)
+		ch <- prometheus.MustNewConstMetric(rs.size, prometheus.GaugeValue, float64(stats.TotalConns()), r.host)
+	}
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*replicas)(nil)
+)
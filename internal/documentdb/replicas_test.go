@@ -0,0 +1,61 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/state"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestReplicasMetrics(t *testing.T) {
+	t.Parallel()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	rs, err := newReplicas([]string{"postgres://127.0.0.1:5433/postgres"}, testutil.Logger(t), sp)
+	require.NoError(t, err)
+
+	t.Cleanup(rs.close)
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(rs))
+
+	_, err = reg.Gather()
+	require.NoError(t, err)
+
+	// the replay lag appears only after the first successful check
+	r := rs.rs[0]
+	r.m.Lock()
+	r.checked = time.Now()
+	r.m.Unlock()
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+
+	var names []string
+	for _, mf := range mfs {
+		names = append(names, mf.GetName())
+	}
+
+	assert.Contains(t, names, "ferretdb_pool_replica_lag_seconds")
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+// sharedLockID is an advisory lock ID used to serialize setup of shared cursors and sessions tables.
+const sharedLockID = 0x0fe77e7db0c8
+
+// sharedLimitsLockID is an advisory lock ID used to serialize checking limits of shared cursors.
+const sharedLimitsLockID = 0x0fe77e7db0c9
+
+// sharedSetupSQL creates tables for cursors and sessions shared between FerretDB instances.
+//
+// Only cursors without persisted connections are stored there;
+// cursors pinned to connections can be used only by the instance that created them.
+// Sessions are stored only if they own shared cursors.
+//
+// A cursor is leased by the getMore command that uses it, so concurrent commands of different instances
+// can't use the same continuation. Leases of cursors idle for longer than the timeout are ignored.
+//
+// Each instance takes a number from the cursor_namespaces sequence on startup
+// and uses it as a prefix of cursor IDs it generates.
+//
+// It should be executed in a transaction that holds sharedLockID advisory lock.
+const sharedSetupSQL = `
+CREATE SCHEMA IF NOT EXISTS ferretdb;
+
+CREATE SEQUENCE IF NOT EXISTS ferretdb.cursor_namespaces;
+
+CREATE TABLE IF NOT EXISTS ferretdb.cursors (
+	cursor_id    bigint PRIMARY KEY,
+	owner        text NOT NULL,
+	session_id   uuid NOT NULL,
+	continuation bytea NOT NULL,
+	tailable     boolean NOT NULL,
+	max_time_ms  bigint NOT NULL,
+	no_timeout   boolean NOT NULL,
+	replica      text NOT NULL DEFAULT '',
+	lease        uuid,
+	last_used    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS cursors_session ON ferretdb.cursors (owner, session_id);
+
+CREATE TABLE IF NOT EXISTS ferretdb.sessions (
+	owner      text NOT NULL,
+	session_id uuid NOT NULL,
+	last_used  timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (owner, session_id)
+);
+`
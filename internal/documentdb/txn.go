@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
+)
+
+// Txn represents a PostgreSQL transaction used for a multi-document transaction.
+//
+// It uses a hijacked connection (the same way persisted cursors do)
+// that is pinned to the client session until the transaction is committed or rolled back.
+// The connection is closed after that.
+//
+// Connection is accessed by [Pool] methods when [TxnCtx] is used.
+type Txn struct {
+	m     sync.Mutex // protects conn usage by concurrent requests of the same session
+	conn  *pgx.Conn
+	l     *slog.Logger
+	token *resource.Token
+}
+
+// BeginTxn acquires a connection, removes it from the pool, and starts a transaction on it.
+//
+// It is caller's responsibility to call [Txn.Commit] or [Txn.Rollback].
+func (p *Pool) BeginTxn(ctx context.Context) (*Txn, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.BeginTxn")
+	defer span.End()
+
+	poolConn, err := p.Acquire(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer poolConn.Release()
+
+	if poolConn.txn != nil {
+		return nil, lazyerrors.New("nested transactions are not supported")
+	}
+
+	conn := poolConn.hijack()
+
+	if _, err = conn.Exec(ctx, "BEGIN ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		closeConn(ctx, conn)
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := &Txn{
+		conn:  conn,
+		l:     p.l,
+		token: resource.NewToken(),
+	}
+
+	resource.Track(res, res.token)
+
+	return res, nil
+}
+
+// Commit commits the transaction and closes the connection.
+func (t *Txn) Commit(ctx context.Context) error {
+	ctx, span := otel.Tracer("").Start(ctx, "txn.Commit")
+	defer span.End()
+
+	return t.end(ctx, "COMMIT")
+}
+
+// Rollback rolls back the transaction and closes the connection.
+//
+// It is safe to call this method multiple times and after [Txn.Commit].
+func (t *Txn) Rollback(ctx context.Context) error {
+	ctx, span := otel.Tracer("").Start(ctx, "txn.Rollback")
+	defer span.End()
+
+	return t.end(ctx, "ROLLBACK")
+}
+
+// end executes the given SQL statement and closes the connection.
+func (t *Txn) end(ctx context.Context, sql string) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.conn == nil {
+		if sql == "ROLLBACK" {
+			return nil
+		}
+
+		return lazyerrors.New("transaction already ended")
+	}
+
+	_, err := t.conn.Exec(ctx, sql)
+
+	closeConn(ctx, t.conn)
+	t.conn = nil
+
+	resource.Untrack(t, t.token)
+
+	if err != nil {
+		t.l.DebugContext(ctx, "Transaction end failed", slog.String("sql", sql), logging.Error(err))
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// closeConn closes the hijacked connection,
+// waiting up to 3 seconds for the clean close.
+func closeConn(ctx context.Context, conn *pgx.Conn) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_ = conn.Close(ctx)
+}
+
+// contextKey is a named unexported type for the safe use of [context.WithValue].
+type contextKey int
+
+// Context keys used by this package.
+const (
+	txnKey         contextKey = iota // current transaction, see TxnCtx
+	backendPIDKey                    // backend PID callback, see BackendPIDCtx
+	maxTimeKey                       // time limit, see MaxTimeCtx
+	cursorOwnerKey                   // cursor owner, see CursorOwnerCtx
+	replicaKey                       // selected replica, see Pool.ReadPreferenceCtx
+	leaseKey                         // leased shared cursor, see Pool.leaseSharedCursor
+)
+
+// TxnCtx returns a derived context with the given transaction.
+//
+// [Pool] methods called with that context use the transaction's connection
+// instead of acquiring a new one.
+func TxnCtx(ctx context.Context, txn *Txn) context.Context {
+	return context.WithValue(ctx, txnKey, txn)
+}
+
+// GetTxn returns the transaction stored in ctx by [TxnCtx], or nil.
+func GetTxn(ctx context.Context) *Txn {
+	txn, _ := ctx.Value(txnKey).(*Txn)
+	return txn
+}
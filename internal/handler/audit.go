@@ -0,0 +1,276 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/netip"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// auditEntry represents a single audit event produced by the command.
+type auditEntry struct {
+	atype string
+	param map[string]any
+}
+
+// auditedCommands maps commands recorded in the audit log to functions
+// that return audit events for the given command document.
+var auditedCommands = map[string]func(doc *wirebson.Document, dbName string) []auditEntry{
+	"create": func(doc *wirebson.Document, dbName string) []auditEntry {
+		param := map[string]any{"ns": auditNS(doc, "create", dbName)}
+		if viewOn, ok := doc.Get("viewOn").(string); ok {
+			param["viewOn"] = dbName + "." + viewOn
+		}
+
+		return []auditEntry{{atype: audit.TypeCreateCollection, param: param}}
+	},
+	"drop": func(doc *wirebson.Document, dbName string) []auditEntry {
+		return []auditEntry{{atype: audit.TypeDropCollection, param: map[string]any{"ns": auditNS(doc, "drop", dbName)}}}
+	},
+	"dropDatabase": func(doc *wirebson.Document, dbName string) []auditEntry {
+		return []auditEntry{{atype: audit.TypeDropDatabase, param: map[string]any{"ns": dbName}}}
+	},
+	"renameCollection": func(doc *wirebson.Document, dbName string) []auditEntry {
+		return []auditEntry{{atype: audit.TypeRenameCollection, param: map[string]any{
+			"old": doc.Get("renameCollection"),
+			"new": doc.Get("to"),
+		}}}
+	},
+	"createIndexes": func(doc *wirebson.Document, dbName string) []auditEntry {
+		ns := auditNS(doc, "createIndexes", dbName)
+
+		indexes, _ := doc.Get("indexes").(*wirebson.Array)
+		if indexes == nil {
+			return []auditEntry{{atype: audit.TypeCreateIndex, param: map[string]any{"ns": ns}}}
+		}
+
+		var res []auditEntry
+
+		for v := range indexes.Values() {
+			param := map[string]any{"ns": ns, "indexSpec": v}
+			if index, ok := v.(*wirebson.Document); ok {
+				param["indexName"] = index.Get("name")
+			}
+
+			res = append(res, auditEntry{atype: audit.TypeCreateIndex, param: param})
+		}
+
+		return res
+	},
+	"dropIndexes": func(doc *wirebson.Document, dbName string) []auditEntry {
+		ns := auditNS(doc, "dropIndexes", dbName)
+
+		switch index := doc.Get("index").(type) {
+		case string:
+			return []auditEntry{{atype: audit.TypeDropIndex, param: map[string]any{"ns": ns, "indexName": index}}}
+
+		case *wirebson.Array:
+			var res []auditEntry
+			for v := range index.Values() {
+				res = append(res, auditEntry{atype: audit.TypeDropIndex, param: map[string]any{"ns": ns, "indexName": v}})
+			}
+
+			return res
+
+		default:
+			return []auditEntry{{atype: audit.TypeDropIndex, param: map[string]any{"ns": ns, "indexSpec": index}}}
+		}
+	},
+	"createUser": func(doc *wirebson.Document, dbName string) []auditEntry {
+		param := auditUserParam(doc, "createUser", dbName)
+		return []auditEntry{{atype: audit.TypeCreateUser, param: param}}
+	},
+	"dropUser": func(doc *wirebson.Document, dbName string) []auditEntry {
+		param := map[string]any{"user": doc.Get("dropUser"), "db": dbName}
+		return []auditEntry{{atype: audit.TypeDropUser, param: param}}
+	},
+	"dropAllUsersFromDatabase": func(doc *wirebson.Document, dbName string) []auditEntry {
+		return []auditEntry{{atype: audit.TypeDropAllUsersFromDatabase, param: map[string]any{"db": dbName}}}
+	},
+	"updateUser": func(doc *wirebson.Document, dbName string) []auditEntry {
+		param := auditUserParam(doc, "updateUser", dbName)
+		param["passwordChanged"] = doc.Get("pwd") != nil
+
+		return []auditEntry{{atype: audit.TypeUpdateUser, param: param}}
+	},
+	"grantRolesToUser": func(doc *wirebson.Document, dbName string) []auditEntry {
+		param := auditUserParam(doc, "grantRolesToUser", dbName)
+		return []auditEntry{{atype: audit.TypeGrantRolesToUser, param: param}}
+	},
+	"revokeRolesFromUser": func(doc *wirebson.Document, dbName string) []auditEntry {
+		param := auditUserParam(doc, "revokeRolesFromUser", dbName)
+		return []auditEntry{{atype: audit.TypeRevokeRolesFromUser, param: param}}
+	},
+}
+
+// auditNS returns the namespace of the collection given by the command's field.
+func auditNS(doc *wirebson.Document, command, dbName string) string {
+	collection, _ := doc.Get(command).(string)
+	return dbName + "." + collection
+}
+
+// auditUserParam returns audit event parameters for the user management command.
+// Passwords are never included.
+func auditUserParam(doc *wirebson.Document, command, dbName string) map[string]any {
+	param := map[string]any{"user": doc.Get(command), "db": dbName}
+
+	if roles := doc.Get("roles"); roles != nil {
+		param["roles"] = roles
+	}
+
+	if customData := doc.Get("customData"); customData != nil {
+		param["customData"] = customData
+	}
+
+	return param
+}
+
+// withAudit wraps the command handler to record its audit events, if it is audited.
+func (h *Handler) withAudit(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	entries := auditedCommands[command]
+	if h.Audit == nil || entries == nil {
+		return cmdHandler
+	}
+
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		res, err := cmdHandler(connCtx, msg)
+
+		doc, decodeErr := msg.RawSection0().DecodeDeep()
+		if decodeErr != nil {
+			h.L.WarnContext(connCtx, "Failed to decode audited command", logging.Error(decodeErr))
+			return res, err
+		}
+
+		dbName, _ := doc.Get("$db").(string)
+
+		for _, e := range entries(doc, dbName) {
+			h.auditLog(connCtx, e.atype, e.param, err)
+		}
+
+		return res, err
+	}
+}
+
+// auditAuthenticate records the authentication attempt.
+func (h *Handler) auditAuthenticate(ctx context.Context, username, dbName, mechanism string, err error) {
+	h.auditLog(ctx, audit.TypeAuthenticate, map[string]any{
+		"user":      username,
+		"db":        dbName,
+		"mechanism": mechanism,
+	}, err)
+}
+
+// auditAuthCheck records the command that failed authentication or authorization check.
+func (h *Handler) auditAuthCheck(ctx context.Context, command string, msg *wire.OpMsg, err error) {
+	param := map[string]any{"command": command}
+
+	if doc, decodeErr := msg.RawSection0().Decode(); decodeErr == nil {
+		dbName, _ := doc.Get("$db").(string)
+		param["ns"] = dbName
+
+		if collection, ok := doc.Get(command).(string); ok && collection != "" {
+			param["ns"] = dbName + "." + collection
+		}
+	}
+
+	h.auditLog(ctx, audit.TypeAuthCheck, param, err)
+}
+
+// auditLog records the audit event with the given type and parameters,
+// and the result based on the error.
+// It does nothing if the audit log is disabled.
+func (h *Handler) auditLog(ctx context.Context, atype string, param map[string]any, err error) {
+	if h.Audit == nil {
+		return
+	}
+
+	ci := conninfo.Get(ctx)
+
+	e := &audit.Event{
+		Type:  atype,
+		Time:  time.Now(),
+		Param: param,
+	}
+
+	if ci.Socket != "" {
+		e.Local = audit.Endpoint{Unix: ci.Socket}
+		e.Remote = audit.Endpoint{Unix: ci.Socket}
+	} else {
+		e.Local = auditEndpoint(ci.Local)
+		e.Remote = auditEndpoint(ci.Peer)
+	}
+
+	if err != nil {
+		e.Result = int32(mongoerrors.ErrInternalError)
+
+		var mErr *mongoerrors.Error
+		if errors.As(err, &mErr) {
+			e.Result = mErr.Code
+		}
+	}
+
+	if ci.Authenticated() {
+		e.Users, e.Roles = h.auditUsers(ctx, ci)
+	}
+
+	h.Audit.Log(e)
+}
+
+// auditEndpoint returns the audit event endpoint for the given address, if it is valid.
+func auditEndpoint(addr netip.AddrPort) audit.Endpoint {
+	if !addr.IsValid() {
+		return audit.Endpoint{}
+	}
+
+	return audit.Endpoint{IP: addr.Addr().String(), Port: addr.Port()}
+}
+
+// auditUsers returns the authenticated user and their roles, including inherited ones.
+func (h *Handler) auditUsers(ctx context.Context, ci *conninfo.ConnInfo) ([]audit.User, []audit.Role) {
+	dbName := "admin"
+	if extUser, _ := ci.ExternalUser(); extUser != "" {
+		dbName = externalDB
+	}
+
+	users := []audit.User{{User: ci.Username(), DB: dbName}}
+
+	if !h.Auth {
+		return users, nil
+	}
+
+	up, err := h.getConnPrivileges(ctx)
+	if err != nil {
+		h.L.WarnContext(ctx, "Failed to get roles for audit event", slog.String("username", ci.Username()), logging.Error(err))
+		return users, nil
+	}
+
+	roles := make([]audit.Role, len(up.roles))
+	for i, r := range up.roles {
+		roles[i] = audit.Role{Role: r.Name.Role, DB: r.Name.DB}
+	}
+
+	return users, roles
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
+)
+
+func TestAuditedCommands(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateIndexes", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument(
+			"createIndexes", "coll",
+			"indexes", wirebson.MustArray(
+				wirebson.MustDocument("key", wirebson.MustDocument("v", int32(1)), "name", "v_1"),
+				wirebson.MustDocument("key", wirebson.MustDocument("w", int32(-1)), "name", "w_-1"),
+			),
+		)
+
+		entries := auditedCommands["createIndexes"](doc, "test")
+		require.Len(t, entries, 2)
+
+		for i, name := range []string{"v_1", "w_-1"} {
+			assert.Equal(t, audit.TypeCreateIndex, entries[i].atype)
+			assert.Equal(t, "test.coll", entries[i].param["ns"])
+			assert.Equal(t, name, entries[i].param["indexName"])
+		}
+
+		b, err := json.Marshal(&audit.Event{Type: entries[0].atype, Param: entries[0].param})
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"indexSpec":{"key":{"v":{"$numberInt":"1"}},"name":"v_1"}`)
+	})
+
+	t.Run("DropIndexes", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument("dropIndexes", "coll", "index", wirebson.MustArray("v_1", "w_-1"))
+
+		entries := auditedCommands["dropIndexes"](doc, "test")
+		require.Len(t, entries, 2)
+		assert.Equal(t, map[string]any{"ns": "test.coll", "indexName": "w_-1"}, entries[1].param)
+	})
+
+	t.Run("UpdateUser", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument(
+			"updateUser", "user",
+			"pwd", "secret",
+			"roles", wirebson.MustArray("readWrite"),
+		)
+
+		entries := auditedCommands["updateUser"](doc, "admin")
+		require.Len(t, entries, 1)
+
+		expected := map[string]any{
+			"user":            "user",
+			"db":              "admin",
+			"roles":           wirebson.MustArray("readWrite"),
+			"passwordChanged": true,
+		}
+		assert.Equal(t, expected, entries[0].param)
+	})
+
+	t.Run("RenameCollection", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument("renameCollection", "test.old", "to", "test.new")
+
+		entries := auditedCommands["renameCollection"](doc, "admin")
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.TypeRenameCollection, entries[0].atype)
+		assert.Equal(t, map[string]any{"old": "test.old", "new": "test.new"}, entries[0].param)
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// cappedMinSize is the minimal size of the capped collection in bytes.
+const cappedMinSize = 4096
+
+// tailableFindFields contains `find` fields that are supported together with the `tailable` option.
+var tailableFindFields = []string{
+	"find",
+	"filter",
+	"projection",
+	"batchSize",
+	"tailable",
+	"awaitData",
+	"sort",
+	"noCursorTimeout",
+}
+
+// getCappedNumberParam returns the whole non-negative number value of the command's field.
+func getCappedNumberParam(command, key string, v any) (int64, error) {
+	var res int64
+
+	switch v := v.(type) {
+	case int32:
+		res = int64(v)
+	case int64:
+		res = v
+	case float64:
+		if v != math.Trunc(v) || v > math.MaxInt64 || v < math.MinInt64 {
+			msg := fmt.Sprintf("BSON field '%s.%s' value must be a whole number, got %v", command, key, v)
+			return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+
+		res = int64(v)
+	default:
+		msg := fmt.Sprintf(
+			"BSON field '%s.%s' is the wrong type '%s', expected types '[long, int, decimal, double]'",
+			command, key, aliasFromType(v),
+		)
+
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+	}
+
+	if res < 0 {
+		msg := fmt.Sprintf("BSON field '%s.%s' value must be >= 0, actual value '%d'", command, key, res)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	return res, nil
+}
+
+// getCappedSizeParam returns the size of the capped collection from the required `size` field.
+//
+// Like MongoDB, it raises the size to the minimum or to the next multiple of 256.
+func getCappedSizeParam(doc *wirebson.Document, command string) (int64, error) {
+	v := doc.Get("size")
+	if v == nil {
+		msg := fmt.Sprintf("BSON field '%s.size' is missing but a required field", command)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrLocation40414, msg, command)
+	}
+
+	size, err := getCappedNumberParam(command, "size", v)
+	if err != nil {
+		return 0, err
+	}
+
+	return roundCappedSize(size), nil
+}
+
+// roundCappedSize returns the capped collection size raised to the minimum or to the next multiple of 256.
+func roundCappedSize(size int64) int64 {
+	if size <= cappedMinSize {
+		return cappedMinSize
+	}
+
+	return (size + 255) &^ 255
+}
+
+// getCreateCappedParams returns capped collection limits from `create` command options,
+// or nil if the collection is not capped.
+func getCreateCappedParams(doc *wirebson.Document) (*documentdb.CappedInfo, error) {
+	capped, err := getBoolParam("capped", doc.Get("capped"))
+	if err != nil {
+		return nil, err
+	}
+
+	if !capped {
+		for _, k := range []string{"size", "max"} {
+			if doc.Get(k) != nil {
+				msg := fmt.Sprintf("the '%s' field is only valid for capped collections", k)
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "create")
+			}
+		}
+
+		return nil, nil
+	}
+
+	if doc.Get("size") == nil {
+		msg := "the 'size' field is required when 'capped' is true"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "create")
+	}
+
+	var res documentdb.CappedInfo
+
+	if res.Size, err = getCappedSizeParam(doc, "create"); err != nil {
+		return nil, err
+	}
+
+	if v := doc.Get("max"); v != nil {
+		if res.Max, err = getCappedNumberParam("create", "max", v); err != nil {
+			return nil, err
+		}
+	}
+
+	return &res, nil
+}
+
+// getTailableFindParams returns parameters of the `find` command with the `tailable` option,
+// or nil if that option is not set.
+func getTailableFindParams(doc *wirebson.Document, dbName string) (*documentdb.TailableFindParams, error) {
+	tailable, err := getBoolParam("tailable", doc.Get("tailable"))
+	if err != nil {
+		return nil, err
+	}
+
+	awaitData, err := getBoolParam("awaitData", doc.Get("awaitData"))
+	if err != nil {
+		return nil, err
+	}
+
+	if !tailable {
+		if awaitData {
+			msg := "Cannot set 'awaitData' without also setting 'tailable'"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "find")
+		}
+
+		return nil, nil
+	}
+
+	collection, err := getRequiredParam[string](doc, "find")
+	if err != nil {
+		return nil, err
+	}
+
+	res := &documentdb.TailableFindParams{
+		DB:         dbName,
+		Collection: collection,
+		AwaitData:  awaitData,
+	}
+
+	for k, v := range doc.All() {
+		if slices.Contains(genericFields, k) {
+			continue
+		}
+
+		if !slices.Contains(tailableFindFields, k) {
+			msg := fmt.Sprintf("find option %q is not implemented yet for tailable cursors", k)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "find")
+		}
+
+		switch k {
+		case "filter", "projection":
+			d, ok := v.(wirebson.AnyDocument)
+			if !ok {
+				msg := fmt.Sprintf(
+					"BSON field 'FindCommandRequest.%s' is the wrong type '%s', expected type 'object'",
+					k, aliasFromType(v),
+				)
+
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "find")
+			}
+
+			var raw wirebson.RawDocument
+			if raw, err = d.Encode(); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if k == "filter" {
+				res.Filter = raw
+			} else {
+				res.Projection = raw
+			}
+
+		case "batchSize":
+			if res.BatchSize, err = getCappedNumberParam("find", k, v); err != nil {
+				return nil, err
+			}
+
+		case "sort":
+			if !isNaturalSort(v) {
+				msg := "error processing query: tailable cursor requested with a sort other than {$natural: 1}"
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "find")
+			}
+
+		case "noCursorTimeout":
+			if res.NoCursorTimeout, err = getBoolParam(k, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// isNaturalSort returns true if the sort specification is empty or `{$natural: 1}`.
+func isNaturalSort(v any) bool {
+	d, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		return false
+	}
+
+	sort, err := d.Decode()
+	if err != nil {
+		return false
+	}
+
+	switch sort.Len() {
+	case 0:
+		return true
+	case 1:
+		order, _ := getCappedNumberParam("find", "sort", sort.Get("$natural"))
+		return order == 1
+	default:
+		return false
+	}
+}
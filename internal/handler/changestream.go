@@ -0,0 +1,176 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// getChangeStreamParams returns change stream parameters
+// if the first stage of the `aggregate` command pipeline is `$changeStream`.
+// Otherwise, it returns nil.
+func getChangeStreamParams(doc *wirebson.Document, dbName string) (*documentdb.ChangeStreamParams, error) {
+	pipelineV, ok := doc.Get("pipeline").(wirebson.AnyArray)
+	if !ok {
+		return nil, nil
+	}
+
+	pipeline, err := pipelineV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if pipeline.Len() == 0 {
+		return nil, nil
+	}
+
+	stageV, ok := pipeline.Get(0).(wirebson.AnyDocument)
+	if !ok {
+		return nil, nil
+	}
+
+	stage, err := stageV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if stage.Command() != "$changeStream" {
+		return nil, nil
+	}
+
+	optsV, ok := stage.Get("$changeStream").(wirebson.AnyDocument)
+	if !ok {
+		msg := "the $changeStream stage specification must be an object"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "$changeStream")
+	}
+
+	opts, err := optsV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := &documentdb.ChangeStreamParams{
+		DB:           dbName,
+		FullDocument: "default",
+	}
+
+	// `aggregate: 1` watches the whole database
+	if collection, ok := doc.Get(doc.Command()).(string); ok {
+		res.Collection = collection
+	}
+
+	for k, v := range opts.All() {
+		switch k {
+		case "fullDocument":
+			fullDocument, _ := v.(string)
+
+			switch fullDocument {
+			case "default", "updateLookup":
+				res.FullDocument = fullDocument
+			case "whenAvailable", "required":
+				msg := fmt.Sprintf("$changeStream option fullDocument: %q is not implemented yet", fullDocument)
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "$changeStream")
+			default:
+				msg := fmt.Sprintf("unrecognized value for the 'fullDocument' option: %v", v)
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "$changeStream")
+			}
+
+		case "resumeAfter":
+			if res.ResumeAfter, err = getResumeToken(k, v); err != nil {
+				return nil, err
+			}
+
+		case "startAfter":
+			if res.StartAfter, err = getResumeToken(k, v); err != nil {
+				return nil, err
+			}
+
+		case "allChangesForCluster":
+			if res.AllChangesForCluster, err = getBoolParam(k, v); err != nil {
+				return nil, err
+			}
+
+		default:
+			msg := fmt.Sprintf("$changeStream option %q is not implemented yet", k)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "$changeStream")
+		}
+	}
+
+	if res.ResumeAfter != nil && res.StartAfter != nil {
+		msg := "Only one type of resume option is allowed, but multiple were found."
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "$changeStream")
+	}
+
+	if res.AllChangesForCluster && (dbName != "admin" || res.Collection != "") {
+		msg := "A $changeStream with 'allChangesForCluster:true' may only be opened on the 'admin' database, " +
+			"and with no collection name"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "$changeStream")
+	}
+
+	if pipeline.Len() > 1 {
+		rest := wirebson.MakeArray(pipeline.Len() - 1)
+
+		for i, v := range pipeline.All() {
+			if i > 0 {
+				must.NoError(rest.Add(v))
+			}
+		}
+
+		if res.Pipeline, err = rest.Encode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if cursorV, ok := doc.Get("cursor").(wirebson.AnyDocument); ok {
+		var cursor *wirebson.Document
+		if cursor, err = cursorV.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch batchSize := cursor.Get("batchSize").(type) {
+		case int32:
+			res.BatchSize = int64(batchSize)
+		case int64:
+			res.BatchSize = batchSize
+		case float64:
+			res.BatchSize = int64(batchSize)
+		}
+	}
+
+	return res, nil
+}
+
+// getResumeToken returns the resume token given by the `resumeAfter` or `startAfter` option.
+func getResumeToken(option string, v any) (wirebson.RawDocument, error) {
+	token, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf("BSON field '$changeStream.%s' is the wrong type, expected type 'object'", option)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "$changeStream")
+	}
+
+	res, err := token.Encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// clusterTimeCommands contains commands that could write data;
+// the cluster time is advanced after them, see [Handler.withClusterTime].
+var clusterTimeCommands = map[string]struct{}{
+	"aggregate":               {},
+	"cloneCollectionAsCapped": {},
+	"collMod":                 {},
+	"commitTransaction":       {},
+	"convertToCapped":         {},
+	"create":                  {},
+	"createIndexes":           {},
+	"delete":                  {},
+	"drop":                    {},
+	"dropDatabase":            {},
+	"dropIndexes":             {},
+	"findAndModify":           {},
+	"findandmodify":           {},
+	"insert":                  {},
+	"renameCollection":        {},
+	"reshardCollection":       {},
+	"shardCollection":         {},
+	"unshardCollection":       {},
+	"update":                  {},
+}
+
+// getAfterClusterTime returns the value of `readConcern.afterClusterTime` field, or zero if it is not set.
+func getAfterClusterTime(doc *wirebson.Document) (wirebson.Timestamp, error) {
+	v := doc.Get("readConcern")
+	if v == nil {
+		return 0, nil
+	}
+
+	rc, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf("BSON field 'readConcern' is the wrong type '%s', expected type 'object'", aliasFromType(v))
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "readConcern")
+	}
+
+	rcDoc, err := rc.Decode()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	v = rcDoc.Get("afterClusterTime")
+	if v == nil {
+		return 0, nil
+	}
+
+	ts, ok := v.(wirebson.Timestamp)
+	if !ok {
+		msg := fmt.Sprintf(
+			"BSON field 'readConcern.afterClusterTime' is the wrong type '%s', expected type 'timestamp'",
+			aliasFromType(v),
+		)
+
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "readConcern")
+	}
+
+	if ts == 0 {
+		msg := "afterClusterTime cannot be a null timestamp"
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "readConcern")
+	}
+
+	return ts, nil
+}
+
+// withAfterClusterTime returns a command handler that runs the given handler
+// after the PostgreSQL server has reached the cluster time set by `readConcern.afterClusterTime` field;
+// see [documentdb.Pool.WaitForClusterTime].
+//
+// It should be called with the context returned by [Handler.withMaxTime],
+// so that waiting is limited by `maxTimeMS`,
+// and by [Handler.withReadPreference], so that the replica that runs the command is waited for.
+func (h *Handler) withAfterClusterTime(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	// getMore does not accept read concern; the command that created the cursor waited already
+	if command == "getMore" {
+		return cmdHandler
+	}
+
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		doc, err := msg.RawSection0().Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		ts, err := getAfterClusterTime(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if ts != 0 {
+			if err = h.Pool.WaitForClusterTime(connCtx, ts); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		return cmdHandler(connCtx, msg)
+	}
+}
+
+// withClusterTime returns a command handler that advances the cluster time
+// after the given handler successfully runs the command that could write data,
+// so `operationTime` of the response includes those writes; see [documentdb.Pool.AdvanceClusterTime].
+//
+// Other commands do not query the cluster time at all.
+// Commands in transactions are skipped too; the cluster time is advanced by `commitTransaction`.
+func (h *Handler) withClusterTime(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if _, ok := clusterTimeCommands[command]; !ok {
+		return cmdHandler
+	}
+
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		res, err := cmdHandler(connCtx, msg)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := msg.RawSection0().Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if autocommit, ok := doc.Get("autocommit").(bool); ok && !autocommit && command != "commitTransaction" {
+			return res, nil
+		}
+
+		if command == "aggregate" {
+			if readOnly, _ := readOnlyAggregate(doc); readOnly {
+				return res, nil
+			}
+		}
+
+		// the older cluster time is still valid, so the command does not fail
+		if err = h.Pool.AdvanceClusterTime(connCtx); err != nil {
+			h.L.WarnContext(connCtx, "Failed to advance cluster time", logging.Error(err))
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAfterClusterTime(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v        any
+		expected wirebson.Timestamp
+		err      string
+	}{
+		"Timestamp": {
+			v:        wirebson.MustDocument("afterClusterTime", wirebson.Timestamp(42)),
+			expected: wirebson.Timestamp(42),
+		},
+		"LevelOnly": {
+			v: wirebson.MustDocument("level", "majority"),
+		},
+		"Null": {
+			v:   wirebson.MustDocument("afterClusterTime", wirebson.Timestamp(0)),
+			err: "afterClusterTime cannot be a null timestamp",
+		},
+		"WrongType": {
+			v:   wirebson.MustDocument("afterClusterTime", int64(42)),
+			err: "BSON field 'readConcern.afterClusterTime' is the wrong type 'long', expected type 'timestamp'",
+		},
+		"NotDocument": {
+			v:   "majority",
+			err: "BSON field 'readConcern' is the wrong type 'string', expected type 'object'",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts, err := getAfterClusterTime(wirebson.MustDocument("find", "test", "readConcern", tc.v))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ts)
+		})
+	}
+
+	ts, err := getAfterClusterTime(wirebson.MustDocument("find", "test"))
+	require.NoError(t, err)
+	assert.Zero(t, ts)
+}
@@ -72,7 +72,7 @@ func (h *Handler) CmdQuery(connCtx context.Context, query *wire.OpQuery) (*wire.
 			)
 		}
 
-		reply, err := h.saslStart(connCtx, q)
+		reply, err := h.saslStart(connCtx, q, strings.TrimSuffix(collection, suffix))
 		if err != nil {
 			return nil, err
 		}
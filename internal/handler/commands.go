@@ -30,6 +30,10 @@ type command struct {
 	// anonymous indicates that the command does not require authentication.
 	anonymous bool
 
+	// action is a privilege action required to execute the command.
+	// If empty, only authentication is required.
+	action string
+
 	// Handler processes this command.
 	//
 	// The passed context is canceled when the client disconnects.
@@ -44,10 +48,20 @@ type command struct {
 func (h *Handler) initCommands() {
 	h.commands = map[string]*command{
 		// sorted alphabetically
+		"abortTransaction": {
+			Handler: h.MsgAbortTransaction,
+			Help:    "Aborts a multi-document transaction.",
+		},
 		"aggregate": {
 			Handler: h.MsgAggregate,
+			action:  "find",
 			Help:    "Returns aggregated data.",
 		},
+		"authenticate": {
+			Handler:   h.MsgAuthenticate,
+			anonymous: true,
+			Help:      "Authenticates the connection using a TLS client certificate.",
+		},
 		"buildInfo": {
 			Handler:   h.MsgBuildInfo,
 			anonymous: true,
@@ -58,16 +72,28 @@ func (h *Handler) initCommands() {
 			anonymous: true,
 			Help:      "", // hidden
 		},
+		"cloneCollectionAsCapped": {
+			Handler: h.MsgCloneCollectionAsCapped,
+			action:  "find",
+			Help:    "Creates a capped collection from the existing collection.",
+		},
 		"collMod": {
 			Handler: h.MsgCollMod,
+			action:  "collMod",
 			Help:    "Adds options to a collection or modify view definitions.",
 		},
 		"collStats": {
 			Handler: h.MsgCollStats,
+			action:  "collStats",
 			Help:    "Returns storage data for a collection.",
 		},
+		"commitTransaction": {
+			Handler: h.MsgCommitTransaction,
+			Help:    "Commits a multi-document transaction.",
+		},
 		"compact": {
 			Handler: h.MsgCompact,
+			action:  "compact",
 			Help:    "Reduces the disk space collection takes and refreshes its statistics.",
 		},
 		"connectionStatus": {
@@ -76,36 +102,59 @@ func (h *Handler) initCommands() {
 			Help: "Returns information about the current connection, " +
 				"specifically the state of authenticated users and their available permissions.",
 		},
+		"convertToCapped": {
+			Handler: h.MsgConvertToCapped,
+			action:  "convertToCapped",
+			Help:    "Converts the collection to a capped collection.",
+		},
 		"count": {
 			Handler: h.MsgCount,
+			action:  "find",
 			Help:    "Returns the count of documents that's matched by the query.",
 		},
 		"create": {
 			Handler: h.MsgCreate,
+			action:  "createCollection",
 			Help:    "Creates the collection.",
 		},
+		"createAPIKey": {
+			Handler: h.MsgCreateAPIKey,
+			action:  "manageAPIKeys",
+			Help:    "Creates a new Data API key for the user.",
+		},
 		"createIndexes": {
 			Handler: h.MsgCreateIndexes,
+			action:  "createIndex",
 			Help:    "Creates indexes on a collection.",
 		},
+		"createRole": {
+			Handler: h.MsgCreateRole,
+			action:  "createRole",
+			Help:    "Creates a new role.",
+		},
 		"createUser": {
 			Handler: h.MsgCreateUser,
+			action:  "createUser",
 			Help:    "Creates a new user.",
 		},
 		"currentOp": {
 			Handler: h.MsgCurrentOp,
+			action:  "inprog",
 			Help:    "Returns information about operations currently in progress.",
 		},
 		"dataSize": {
 			Handler: h.MsgDataSize,
+			action:  "find",
 			Help:    "Returns the size of the collection in bytes.",
 		},
 		"dbStats": {
 			Handler: h.MsgDBStats,
+			action:  "dbStats",
 			Help:    "Returns the statistics of the database.",
 		},
 		"dbstats": { // old lowercase variant
 			Handler: h.MsgDBStats,
+			action:  "dbStats",
 			Help:    "", // hidden
 		},
 		"debugError": {
@@ -114,30 +163,47 @@ func (h *Handler) initCommands() {
 		},
 		"delete": {
 			Handler: h.MsgDelete,
+			action:  "remove",
 			Help:    "Deletes documents matched by the query.",
 		},
 		"distinct": {
 			Handler: h.MsgDistinct,
+			action:  "find",
 			Help:    "Returns an array of distinct values for the given field.",
 		},
 		"drop": {
 			Handler: h.MsgDrop,
+			action:  "dropCollection",
 			Help:    "Drops the collection.",
 		},
 		"dropAllUsersFromDatabase": {
 			Handler: h.MsgDropAllUsersFromDatabase,
+			action:  "dropUser",
 			Help:    "Drops all user from database.",
 		},
+		"dropAPIKey": {
+			Handler: h.MsgDropAPIKey,
+			action:  "manageAPIKeys",
+			Help:    "Removes a Data API key.",
+		},
 		"dropDatabase": {
 			Handler: h.MsgDropDatabase,
+			action:  "dropDatabase",
 			Help:    "Drops production database.",
 		},
 		"dropIndexes": {
 			Handler: h.MsgDropIndexes,
+			action:  "dropIndex",
 			Help:    "Drops indexes on a collection.",
 		},
+		"dropRole": {
+			Handler: h.MsgDropRole,
+			action:  "dropRole",
+			Help:    "Drops role.",
+		},
 		"dropUser": {
 			Handler: h.MsgDropUser,
+			action:  "dropUser",
 			Help:    "Drops user.",
 		},
 		"endSessions": {
@@ -146,22 +212,27 @@ func (h *Handler) initCommands() {
 		},
 		"explain": {
 			Handler: h.MsgExplain,
+			action:  "find",
 			Help:    "Returns the execution plan.",
 		},
 		"find": {
 			Handler: h.MsgFind,
+			action:  "find",
 			Help:    "Returns documents matched by the query.",
 		},
 		"findAndModify": {
 			Handler: h.MsgFindAndModify,
+			action:  "update",
 			Help:    "Updates or deletes, and returns a document matched by the query.",
 		},
 		"findandmodify": { // old lowercase variant
 			Handler: h.MsgFindAndModify,
+			action:  "update",
 			Help:    "", // hidden
 		},
 		"getCmdLineOpts": {
 			Handler: h.MsgGetCmdLineOpts,
+			action:  "getCmdLineOpts",
 			Help:    "Returns a summary of all runtime and configuration options.",
 		},
 		"getFreeMonitoringStatus": {
@@ -170,6 +241,7 @@ func (h *Handler) initCommands() {
 		},
 		"getLog": {
 			Handler: h.MsgGetLog,
+			action:  "getLog",
 			Help:    "Returns the most recent logged events from memory.",
 		},
 		"getMore": {
@@ -178,8 +250,19 @@ func (h *Handler) initCommands() {
 		},
 		"getParameter": {
 			Handler: h.MsgGetParameter,
+			action:  "getParameter",
 			Help:    "Returns the value of the parameter.",
 		},
+		"grantPrivilegesToRole": {
+			Handler: h.MsgGrantPrivilegesToRole,
+			action:  "grantRole",
+			Help:    "Grants privileges to role.",
+		},
+		"grantRolesToUser": {
+			Handler: h.MsgGrantRolesToUser,
+			action:  "grantRole",
+			Help:    "Grants roles to user.",
+		},
 		"hello": {
 			Handler:   h.MsgHello,
 			anonymous: true,
@@ -187,10 +270,12 @@ func (h *Handler) initCommands() {
 		},
 		"hostInfo": {
 			Handler: h.MsgHostInfo,
+			action:  "hostInfo",
 			Help:    "Returns a summary of the system information.",
 		},
 		"insert": {
 			Handler: h.MsgInsert,
+			action:  "insert",
 			Help:    "Inserts documents into the database.",
 		},
 		"isMaster": {
@@ -205,22 +290,35 @@ func (h *Handler) initCommands() {
 		},
 		"killAllSessions": {
 			Handler: h.MsgKillAllSessions,
+			action:  "killAnySession",
 			Help:    "Kills all sessions.",
 		},
 		"killAllSessionsByPattern": {
 			Handler: h.MsgKillAllSessionsByPattern,
+			action:  "killAnySession",
 			Help:    "Kills all sessions that match the pattern.",
 		},
 		"killCursors": {
 			Handler: h.MsgKillCursors,
 			Help:    "Closes server cursors.",
 		},
+		"killOp": {
+			Handler: h.MsgKillOp,
+			action:  "killop",
+			Help:    "Terminates an operation as specified by the operation ID.",
+		},
 		"killSessions": {
 			Handler: h.MsgKillSessions,
 			Help:    "Kills sessions.",
 		},
+		"listAPIKeys": {
+			Handler: h.MsgListAPIKeys,
+			action:  "manageAPIKeys",
+			Help:    "Returns Data API keys without their values.",
+		},
 		"listCollections": {
 			Handler: h.MsgListCollections,
+			action:  "listCollections",
 			Help:    "Returns the information of the collections and views in the database.",
 		},
 		"listCommands": {
@@ -229,10 +327,12 @@ func (h *Handler) initCommands() {
 		},
 		"listDatabases": {
 			Handler: h.MsgListDatabases,
+			action:  "listDatabases",
 			Help:    "Returns a summary of all the databases.",
 		},
 		"listIndexes": {
 			Handler: h.MsgListIndexes,
+			action:  "listIndexes",
 			Help:    "Returns a summary of indexes of the specified collection.",
 		},
 		"logout": {
@@ -251,8 +351,29 @@ func (h *Handler) initCommands() {
 		},
 		"renameCollection": {
 			Handler: h.MsgRenameCollection,
+			action:  "renameCollectionSameDB",
 			Help:    "Changes the name of an existing collection.",
 		},
+		"reshardCollection": {
+			Handler: h.MsgReshardCollection,
+			action:  "reshardCollection",
+			Help:    "Changes the shard key of a sharded collection.",
+		},
+		"revokePrivilegesFromRole": {
+			Handler: h.MsgRevokePrivilegesFromRole,
+			action:  "revokeRole",
+			Help:    "Revokes privileges from role.",
+		},
+		"revokeRolesFromUser": {
+			Handler: h.MsgRevokeRolesFromUser,
+			action:  "revokeRole",
+			Help:    "Revokes roles from user.",
+		},
+		"rolesInfo": {
+			Handler: h.MsgRolesInfo,
+			action:  "viewRole",
+			Help:    "Returns information about roles.",
+		},
 		"saslStart": {
 			Handler:   h.MsgSASLStart,
 			anonymous: true,
@@ -265,30 +386,56 @@ func (h *Handler) initCommands() {
 		},
 		"serverStatus": {
 			Handler: h.MsgServerStatus,
+			action:  "serverStatus",
 			Help:    "Returns an overview of the databases state.",
 		},
 		"setFreeMonitoring": {
 			Handler: h.MsgSetFreeMonitoring,
+			action:  "setFreeMonitoring",
 			Help:    "Toggles free monitoring.",
 		},
+		"shardCollection": {
+			Handler: h.MsgShardCollection,
+			action:  "enableSharding",
+			Help:    "Distributes a collection by the given shard key.",
+		},
 		"startSession": {
 			Handler: h.MsgStartSession,
 			Help:    "Returns a session.",
 		},
+		"unshardCollection": {
+			Handler: h.MsgUnshardCollection,
+			action:  "unshardCollection",
+			Help:    "Moves all data of a sharded collection to a single node.",
+		},
+		"unlockAccount": {
+			Handler: h.MsgUnlockAccount,
+			action:  "unlockAccount",
+			Help:    "Unlocks usernames and addresses locked out by brute-force protection.",
+		},
 		"update": {
 			Handler: h.MsgUpdate,
+			action:  "update",
 			Help:    "Updates documents that are matched by the query.",
 		},
+		"updateRole": {
+			Handler: h.MsgUpdateRole,
+			action:  "updateRole",
+			Help:    "Updates role.",
+		},
 		"updateUser": {
 			Handler: h.MsgUpdateUser,
+			action:  "changePassword",
 			Help:    "Updates user.",
 		},
 		"usersInfo": {
 			Handler: h.MsgUsersInfo,
+			action:  "viewUser",
 			Help:    "Returns information about users.",
 		},
 		"validate": {
 			Handler: h.MsgValidate,
+			action:  "validate",
 			Help:    "Validates collection.",
 		},
 		"whatsmyuri": {
@@ -299,6 +446,11 @@ func (h *Handler) initCommands() {
 		// please keep sorted alphabetically
 	}
 
+	for name, cmd := range h.commands {
+		cmdHandler := h.withMaxTime(name, h.withReadPreference(name, h.withAfterClusterTime(name, cmd.Handler)))
+		cmd.Handler = h.withAudit(name, h.withClusterTime(name, h.withTxn(name, h.withRetryableWrite(name, cmdHandler))))
+	}
+
 	if !h.Auth {
 		return
 	}
@@ -312,6 +464,12 @@ func (h *Handler) initCommands() {
 
 		h.commands[name].Handler = func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
 			if err := checkAuthentication(ctx, name, h.L); err != nil {
+				h.auditAuthCheck(ctx, name, msg, err)
+				return nil, err
+			}
+
+			if err := h.checkPrivileges(ctx, name, cmd, msg); err != nil {
+				h.auditAuthCheck(ctx, name, msg, err)
 				return nil, err
 			}
 
@@ -320,21 +478,32 @@ func (h *Handler) initCommands() {
 	}
 }
 
-// checkAuthentication returns error if SCRAM conversation is absent or did not succeed.
+// checkAuthentication returns error if there is no external user or user set by the Data API,
+// and SCRAM conversation is absent or did not succeed,
+// or if authentication expired.
 func checkAuthentication(ctx context.Context, command string, l *slog.Logger) error {
-	conv := conninfo.Get(ctx).Conv()
-	succeed := conv.Succeed()
-	username := conv.Username()
+	ci := conninfo.Get(ctx)
+	username := ci.Username()
+
+	if ci.Expired() {
+		l.DebugContext(ctx, "checkAuthentication: authentication expired", slog.String("username", username))
+
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrReauthenticationRequired,
+			"Access token is expired",
+			"checkAuthentication",
+		)
+	}
 
 	switch {
-	case conv == nil:
+	case ci.Conv() == nil && !ci.Authenticated():
 		l.WarnContext(ctx, "checkAuthentication: no existing conversation")
 
-	case !succeed:
+	case !ci.Authenticated():
 		l.WarnContext(ctx, "checkAuthentication: conversation did not succeed", slog.String("username", username))
 
 	default:
-		l.DebugContext(ctx, "checkAuthentication: passed", slog.String("username", conv.Username()))
+		l.DebugContext(ctx, "checkAuthentication: passed", slog.String("username", username))
 
 		return nil
 	}
@@ -0,0 +1,300 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/oidc"
+	"github.com/FerretDB/FerretDB/v2/internal/util/plain"
+	"github.com/FerretDB/FerretDB/v2/internal/util/scram"
+)
+
+// supportedMechanisms contains supported SCRAM mechanisms in the order used in responses.
+var supportedMechanisms = []string{scram.SHA1, scram.SHA256}
+
+// defaultMechanisms contains mechanisms of users that were created without explicit mechanisms.
+// Only SCRAM-SHA-256 credentials are stored by DocumentDB for them.
+var defaultMechanisms = []string{scram.SHA256}
+
+// getMechanismsParam returns authentication mechanisms from the `mechanisms` field,
+// or nil if it is absent.
+func getMechanismsParam(doc *wirebson.Document, command string) ([]string, error) {
+	v := doc.Get("mechanisms")
+	if v == nil {
+		return nil, nil
+	}
+
+	arrV, ok := v.(wirebson.AnyArray)
+	if !ok {
+		msg := fmt.Sprintf(
+			"BSON field '%s.mechanisms' is the wrong type '%s', expected type 'array'",
+			command, aliasFromType(v),
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+	}
+
+	arr, err := arrV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if arr.Len() == 0 {
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, "mechanisms field must not be empty", command)
+	}
+
+	set := map[string]struct{}{}
+
+	for v := range arr.Values() {
+		m, _ := v.(string)
+		if !slices.Contains(supportedMechanisms, m) {
+			msg := fmt.Sprintf("Unknown auth mechanism '%v'", v)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+
+		set[m] = struct{}{}
+	}
+
+	res := make([]string, 0, len(set))
+
+	for _, m := range supportedMechanisms {
+		if _, ok := set[m]; ok {
+			res = append(res, m)
+		}
+	}
+
+	return res, nil
+}
+
+// newUserCredentials returns credentials to be stored by FerretDB for the given mechanisms and password,
+// or nil if the user uses only default mechanisms.
+func newUserCredentials(username, password string, mechanisms []string) (*documentdb.UserCredentials, error) {
+	if slices.Equal(mechanisms, defaultMechanisms) {
+		return nil, nil
+	}
+
+	res := &documentdb.UserCredentials{
+		Mechanisms: mechanisms,
+	}
+
+	if slices.Contains(mechanisms, scram.SHA1) {
+		creds, err := scram.NewSHA1Credentials(username, password)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.SCRAMSHA1 = must.NotFail(creds.Document().Encode())
+	}
+
+	return res, nil
+}
+
+// setUserCredentials stores credentials of the user, or removes them if creds is nil.
+func (h *Handler) setUserCredentials(ctx context.Context, username string, creds *documentdb.UserCredentials) error {
+	var err error
+
+	if creds == nil {
+		err = h.Pool.DeleteUserCredentials(ctx, username)
+	} else {
+		err = h.Pool.SetUserCredentials(ctx, username, creds)
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	// it is called when the password changes or the user is dropped
+	defer h.credentialsVersion.Add(1)
+
+	if err = h.Pool.RevokeUserTokens(ctx, username); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// CredentialsVersion returns a number that changes when credentials of any user change,
+// users are dropped, or API keys are dropped, by this FerretDB instance.
+// It is used to invalidate cached authentications.
+func (h *Handler) CredentialsVersion() uint64 {
+	return h.credentialsVersion.Load()
+}
+
+// getUserMechanisms returns authentication mechanisms available for the user,
+// or nil if the user does not exist.
+func (h *Handler) getUserMechanisms(ctx context.Context, username string) ([]string, error) {
+	creds, err := h.Pool.GetUserCredentials(ctx, username)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if creds != nil {
+		return creds.Mechanisms, nil
+	}
+
+	exists, err := h.Pool.UserExists(ctx, username)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	return defaultMechanisms, nil
+}
+
+// getSHA1Credentials returns SCRAM-SHA-1 credentials of the user, or nil if there are none.
+func (h *Handler) getSHA1Credentials(ctx context.Context, username string) (*scram.Credentials, error) {
+	creds, err := h.Pool.GetUserCredentials(ctx, username)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if creds == nil || creds.SCRAMSHA1 == nil {
+		return nil, nil
+	}
+
+	doc, err := creds.SCRAMSHA1.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := scram.CredentialsFromDocument(doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// getSASLSupportedMechs returns mechanisms available for the user specified
+// in the `saslSupportedMechs` field of `hello` as `<db>.<username>`.
+// It returns nil if the field is absent or the user does not exist.
+func (h *Handler) getSASLSupportedMechs(ctx context.Context, doc *wirebson.Document) (*wirebson.Array, error) {
+	v := doc.Get("saslSupportedMechs")
+	if v == nil {
+		return nil, nil
+	}
+
+	ns, ok := v.(string)
+	if !ok {
+		msg := fmt.Sprintf(
+			"BSON field '%s.saslSupportedMechs' is the wrong type '%s', expected type 'string'",
+			doc.Command(), aliasFromType(v),
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, doc.Command())
+	}
+
+	// users are not scoped by database in DocumentDB
+	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/864
+	dbName, username, ok := strings.Cut(ns, ".")
+	if !ok || username == "" {
+		msg := fmt.Sprintf("UserName must contain a '.' separated database.user pair: %s", ns)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, doc.Command())
+	}
+
+	var mechanisms []string
+	var err error
+
+	// `$external` users are verified by external sources, so all enabled SASL mechanisms are reported
+	if dbName == externalDB {
+		mechanisms = h.externalSASLMechanisms()
+	} else if mechanisms, err = h.getUserMechanisms(ctx, username); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if mechanisms == nil {
+		return nil, nil
+	}
+
+	res := wirebson.MakeArray(len(mechanisms))
+	for _, m := range mechanisms {
+		must.NoError(res.Add(m))
+	}
+
+	return res, nil
+}
+
+// externalSASLMechanisms returns enabled SASL mechanisms for `$external` users, or nil.
+func (h *Handler) externalSASLMechanisms() []string {
+	var res []string
+
+	if h.PLAINVerifier != nil {
+		res = append(res, plain.Mechanism)
+	}
+
+	if h.OIDCVerifier != nil {
+		res = append(res, oidc.Mechanism)
+	}
+
+	return res
+}
+
+// replaceUserCredentials replaces mechanisms and credentials returned by DocumentDB's `usersInfo`
+// with ones stored by FerretDB, if any.
+func (h *Handler) replaceUserCredentials(ctx context.Context, user *wirebson.Document, username string) error {
+	creds, err := h.Pool.GetUserCredentials(ctx, username)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if creds == nil {
+		return nil
+	}
+
+	mechanisms := wirebson.MakeArray(len(creds.Mechanisms))
+	for _, m := range creds.Mechanisms {
+		must.NoError(mechanisms.Add(m))
+	}
+
+	must.NoError(user.Replace("mechanisms", mechanisms))
+
+	// present only with `showCredentials`
+	stored, _ := user.Get("credentials").(*wirebson.Document)
+	if stored == nil {
+		return nil
+	}
+
+	credentials := wirebson.MakeDocument(2)
+
+	if creds.SCRAMSHA1 != nil {
+		var doc *wirebson.Document
+		if doc, err = creds.SCRAMSHA1.Decode(); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		must.NoError(credentials.Add(scram.SHA1, doc))
+	}
+
+	if v := stored.Get(scram.SHA256); v != nil && slices.Contains(creds.Mechanisms, scram.SHA256) {
+		must.NoError(credentials.Add(scram.SHA256, v))
+	}
+
+	must.NoError(user.Replace("credentials", credentials))
+
+	return nil
+}
@@ -0,0 +1,563 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/operation"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// planSummaryTimeout is the time limit of the EXPLAIN query used for the operation's plan summary.
+const planSummaryTimeout = time.Second
+
+// currentOpParams represents parameters of `currentOp` command and `$currentOp` aggregation stage.
+type currentOpParams struct {
+	allUsers        bool
+	idleConnections bool
+	idleCursors     bool
+	idleSessions    bool
+}
+
+// getCurrentOpStageParams returns `$currentOp` stage parameters and remaining pipeline stages
+// if the first stage of the `aggregate` command pipeline is `$currentOp`.
+// Otherwise, it returns nil.
+func getCurrentOpStageParams(doc *wirebson.Document, dbName string) (*currentOpParams, *wirebson.Array, error) {
+	pipelineV, ok := doc.Get("pipeline").(wirebson.AnyArray)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	pipeline, err := pipelineV.Decode()
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	if pipeline.Len() == 0 {
+		return nil, nil, nil
+	}
+
+	stageV, ok := pipeline.Get(0).(wirebson.AnyDocument)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	stage, err := stageV.Decode()
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	if stage.Command() != "$currentOp" {
+		return nil, nil, nil
+	}
+
+	var aggregateOne bool
+
+	switch v := doc.Get(doc.Command()).(type) {
+	case int32:
+		aggregateOne = v == 1
+	case int64:
+		aggregateOne = v == 1
+	case float64:
+		aggregateOne = v == 1
+	}
+
+	if dbName != "admin" || !aggregateOne {
+		msg := "$currentOp must be run against the 'admin' database with {aggregate: 1}"
+		return nil, nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidNamespace, msg, "$currentOp")
+	}
+
+	optsV, ok := stage.Get("$currentOp").(wirebson.AnyDocument)
+	if !ok {
+		msg := "$currentOp options must be specified in an object, but found: " + aliasFromType(stage.Get("$currentOp"))
+		return nil, nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "$currentOp")
+	}
+
+	opts, err := optsV.Decode()
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	var res currentOpParams
+
+	for k, v := range opts.All() {
+		var dst *bool
+
+		switch k {
+		case "allUsers":
+			dst = &res.allUsers
+		case "idleConnections":
+			dst = &res.idleConnections
+		case "idleCursors":
+			dst = &res.idleCursors
+		case "idleSessions":
+			dst = &res.idleSessions
+		case "localOps", "truncateOps", "backtrace":
+			// there is only one instance and no truncation or backtraces
+		default:
+			msg := fmt.Sprintf("BSON field '$currentOp.%s' is an unknown field.", k)
+			return nil, nil, mongoerrors.NewWithArgument(mongoerrors.ErrUnknownBsonField, msg, "$currentOp")
+		}
+
+		b, ok := v.(bool)
+		if !ok {
+			msg := fmt.Sprintf(
+				"BSON field '$currentOp.%s' is the wrong type '%s', expected type 'bool'",
+				k, aliasFromType(v),
+			)
+
+			return nil, nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "$currentOp")
+		}
+
+		if dst != nil {
+			*dst = b
+		}
+	}
+
+	rest := wirebson.MakeArray(pipeline.Len() - 1)
+	for i := 1; i < pipeline.Len(); i++ {
+		must.NoError(rest.Add(pipeline.Get(i)))
+	}
+
+	return &res, rest, nil
+}
+
+// getCurrentOpParams returns `currentOp` command parameters and the filter document.
+//
+// All fields that are not command options or generic command fields are a part of the filter.
+func getCurrentOpParams(doc *wirebson.Document) (*currentOpParams, *wirebson.Document, error) {
+	res := &currentOpParams{
+		allUsers: true,
+	}
+
+	filter := wirebson.MakeDocument(0)
+
+	for k, v := range doc.All() {
+		if k == doc.Command() || slices.Contains(genericFields, k) {
+			continue
+		}
+
+		var dst *bool
+
+		switch k {
+		case "$all":
+			all, err := getBoolParam(k, v)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			res.idleConnections = res.idleConnections || all
+			res.idleCursors = res.idleCursors || all
+			res.idleSessions = res.idleSessions || all
+
+			continue
+
+		case "$ownOps":
+			ownOps, err := getBoolParam(k, v)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			res.allUsers = !ownOps
+
+			continue
+
+		case "idleConnections":
+			dst = &res.idleConnections
+		case "idleCursors":
+			dst = &res.idleCursors
+		case "idleSessions":
+			dst = &res.idleSessions
+		}
+
+		if dst != nil {
+			b, err := getBoolParam(k, v)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			*dst = *dst || b
+
+			continue
+		}
+
+		must.NoError(filter.Add(k, v))
+	}
+
+	return res, filter, nil
+}
+
+// currentOp returns documents describing in-progress operations
+// and, optionally, idle connections, cursors, and sessions.
+func (h *Handler) currentOp(ctx context.Context, params *currentOpParams) (*wirebson.Array, error) {
+	connInfo := conninfo.Get(ctx)
+
+	// namespaced IDs are compared, so external users can't see operations of same-named users
+	userID := connInfo.UserID()
+
+	own := func(id string) bool {
+		return params.allUsers || id == userID
+	}
+
+	ops := h.operations.Operations()
+
+	var pids []uint32
+
+	for _, op := range ops {
+		if op.BackendPID != 0 {
+			pids = append(pids, op.BackendPID)
+		}
+	}
+
+	waitingForLock, err := h.Pool.WaitingForLock(ctx, pids)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := wirebson.MakeArray(len(ops))
+
+	activeConns := map[int64]struct{}{}
+	activeSessions := map[uuid.UUID]struct{}{}
+	activeCursors := map[int64]struct{}{}
+
+	for _, op := range ops {
+		if op.Conn != nil {
+			activeConns[op.Conn.ID] = struct{}{}
+		}
+
+		var lsid any
+
+		if op.Command != nil {
+			lsid = op.Command.Get("lsid")
+
+			if id := lsidUUID(lsid); id != uuid.Nil {
+				activeSessions[id] = struct{}{}
+			}
+
+			if cursorID, ok := op.Command.Get("getMore").(int64); ok {
+				activeCursors[cursorID] = struct{}{}
+			}
+		}
+
+		if !own(op.UserID) {
+			continue
+		}
+
+		since := time.Since(op.CurrentOpTime)
+
+		var ns string
+		if op.DB != "" && op.Collection != "" {
+			ns = op.DB + "." + op.Collection
+		}
+
+		opCommand := op.Command
+		if opCommand == nil {
+			opCommand = must.NotFail(wirebson.NewDocument())
+		}
+
+		doc := wirebson.MakeDocument(20)
+		must.NoError(doc.Add("type", "op"))
+		addConnFields(doc, op.Conn)
+		must.NoError(doc.Add("active", op.Active))
+		must.NoError(doc.Add("currentOpTime", time.Now().Format(time.RFC3339)))
+
+		if op.UserID != "" {
+			must.NoError(doc.Add("effectiveUsers", effectiveUsers(op.UserID)))
+		}
+
+		must.NoError(doc.Add("opid", op.OpID))
+
+		if lsid != nil {
+			must.NoError(doc.Add("lsid", lsid))
+		}
+
+		must.NoError(doc.Add("secs_running", int64(since.Truncate(time.Second).Seconds())))
+		must.NoError(doc.Add("microsecs_running", since.Microseconds()))
+		must.NoError(doc.Add("op", op.Op))
+		must.NoError(doc.Add("ns", ns))
+		must.NoError(doc.Add("command", opCommand))
+
+		if h.CurrentOpPlanSummary {
+			if planSummary := h.planSummary(ctx, &op); planSummary != "" {
+				must.NoError(doc.Add("planSummary", planSummary))
+			}
+		}
+
+		must.NoError(doc.Add("waitingForLock", waitingForLock[op.BackendPID]))
+		must.NoError(doc.Add("backendPid", int64(op.BackendPID)))
+
+		if op.Killed {
+			must.NoError(doc.Add("killPending", true))
+		}
+
+		must.NoError(res.Add(doc))
+	}
+
+	if params.idleConnections {
+		for _, ci := range h.operations.Conns() {
+			if _, ok := activeConns[ci.ID]; ok {
+				continue
+			}
+
+			id := ci.UserID()
+			if !own(id) {
+				continue
+			}
+
+			doc := wirebson.MakeDocument(10)
+			must.NoError(doc.Add("type", "op"))
+			addConnFields(doc, ci)
+			must.NoError(doc.Add("active", false))
+			must.NoError(doc.Add("currentOpTime", time.Now().Format(time.RFC3339)))
+
+			if id != "" {
+				must.NoError(doc.Add("effectiveUsers", effectiveUsers(id)))
+			}
+
+			must.NoError(res.Add(doc))
+		}
+	}
+
+	if !params.idleCursors && !params.idleSessions {
+		return res, nil
+	}
+
+	username, db := connInfo.UserDB()
+	ownUserID := session.GetUIDFromUsername(db, username)
+
+	for _, s := range h.s.Sessions() {
+		if !params.allUsers && s.UserID != ownUserID {
+			continue
+		}
+
+		var lsid *wirebson.Document
+		if s.ID != uuid.Nil {
+			lsid = wirebson.MustDocument(
+				"id", wirebson.Binary{B: s.ID[:], Subtype: wirebson.BinaryUUID},
+				"uid", wirebson.Binary{B: s.UserID[:], Subtype: wirebson.BinaryGeneric},
+			)
+		}
+
+		if params.idleCursors {
+			for _, cursorID := range s.CursorIDs {
+				if _, ok := activeCursors[cursorID]; ok {
+					continue
+				}
+
+				doc := wirebson.MustDocument(
+					"type", "idleCursor",
+					"active", false,
+					"currentOpTime", time.Now().Format(time.RFC3339),
+				)
+
+				if lsid != nil {
+					must.NoError(doc.Add("lsid", lsid))
+				}
+
+				must.NoError(doc.Add("cursor", wirebson.MustDocument(
+					"cursorId", cursorID,
+					"lastAccessDate", s.LastUsed.Format(time.RFC3339),
+				)))
+
+				must.NoError(res.Add(doc))
+			}
+		}
+
+		if !params.idleSessions || lsid == nil {
+			continue
+		}
+
+		if _, ok := activeSessions[s.ID]; ok {
+			continue
+		}
+
+		must.NoError(res.Add(wirebson.MustDocument(
+			"type", "idleSession",
+			"active", false,
+			"currentOpTime", time.Now().Format(time.RFC3339),
+			"lsid", lsid,
+		)))
+	}
+
+	return res, nil
+}
+
+// addConnFields adds fields describing the client connection to the `currentOp` document.
+func addConnFields(doc *wirebson.Document, ci *conninfo.ConnInfo) {
+	if ci == nil {
+		return
+	}
+
+	must.NoError(doc.Add("desc", fmt.Sprintf("conn%d", ci.ID)))
+	must.NoError(doc.Add("connectionId", ci.ID))
+
+	if ci.Peer.IsValid() {
+		must.NoError(doc.Add("client", ci.Peer.String()))
+	}
+
+	metadata := ci.Metadata()
+	if metadata == nil {
+		return
+	}
+
+	if app, ok := metadata.Get("application").(wirebson.AnyDocument); ok {
+		if appDoc, err := app.Decode(); err == nil {
+			if name, ok := appDoc.Get("name").(string); ok {
+				must.NoError(doc.Add("appName", name))
+			}
+		}
+	}
+
+	must.NoError(doc.Add("clientMetadata", metadata))
+}
+
+// effectiveUsers returns `effectiveUsers` array for the given user ID, see [conninfo.ConnInfo.UserID].
+func effectiveUsers(userID string) *wirebson.Array {
+	// database names do not contain `@`, but usernames could
+	i := strings.LastIndex(userID, "@")
+
+	return wirebson.MustArray(wirebson.MustDocument(
+		"user", userID[:i],
+		"db", userID[i+1:],
+	))
+}
+
+// lsidUUID returns the session UUID from the `lsid` value, or an empty UUID.
+func lsidUUID(lsid any) uuid.UUID {
+	lsidV, ok := lsid.(wirebson.AnyDocument)
+	if !ok {
+		return uuid.Nil
+	}
+
+	doc, err := lsidV.Decode()
+	if err != nil {
+		return uuid.Nil
+	}
+
+	id, ok := doc.Get("id").(wirebson.Binary)
+	if !ok || id.Subtype != wirebson.BinaryUUID {
+		return uuid.Nil
+	}
+
+	res, err := uuid.FromBytes(id.B)
+	if err != nil {
+		return uuid.Nil
+	}
+
+	return res
+}
+
+// planSummary returns the plan summary of the operation in MongoDB format, or an empty string.
+//
+// The plan is received from PostgreSQL once and stored in the operation.
+// Only operations that could be explained have a plan summary.
+//
+// It is used only if enabled by [NewOpts.CurrentOpPlanSummary], as it runs EXPLAIN for each new operation.
+func (h *Handler) planSummary(ctx context.Context, op *operation.Operation) string {
+	if op.PlanSummary != "" {
+		return op.PlanSummary
+	}
+
+	if op.Command == nil || op.DB == "" || op.Collection == "" {
+		return ""
+	}
+
+	cmd := op.Command.Command()
+
+	switch cmd {
+	case "find", "aggregate", "count":
+	default:
+		return ""
+	}
+
+	// remove fields that are not accepted by explain functions
+	spec := wirebson.MakeDocument(op.Command.Len())
+
+	for k, v := range op.Command.All() {
+		if !slices.Contains(genericFields, k) || k == "$db" {
+			must.NoError(spec.Add(k, v))
+		}
+	}
+
+	raw, err := spec.Encode()
+	if err != nil {
+		return ""
+	}
+
+	// do not use the caller's transaction, and do not wait for too long
+	ctx, cancel := context.WithTimeout(documentdb.TxnCtx(ctx, nil), planSummaryTimeout)
+	defer cancel()
+
+	plan, err := h.explainPlan(ctx, op.DB, cmd, raw)
+	if err != nil {
+		h.L.DebugContext(ctx, "Failed to get plan summary", slog.Int("opid", int(op.OpID)), logging.Error(err))
+		return ""
+	}
+
+	var stages []string
+	planStages(plan, &stages)
+
+	res := strings.Join(stages, ", ")
+	h.operations.SetPlanSummary(op.OpID, res)
+
+	return res
+}
+
+// planStages adds MongoDB plan stage names for scan nodes of the PostgreSQL plan to stages.
+func planStages(plan *wirebson.Document, stages *[]string) {
+	var stage string
+
+	switch nodeType, _ := plan.Get("Node Type").(string); nodeType {
+	case "Seq Scan":
+		stage = "COLLSCAN"
+	case "Index Scan", "Index Only Scan", "Bitmap Index Scan":
+		indexName, _ := plan.Get("Index Name").(string)
+		stage = fmt.Sprintf("IXSCAN { %s }", indexName)
+	}
+
+	if stage != "" && !slices.Contains(*stages, stage) {
+		*stages = append(*stages, stage)
+	}
+
+	if p, ok := plan.Get("Plan").(*wirebson.Document); ok {
+		planStages(p, stages)
+	}
+
+	plans, ok := plan.Get("Plans").(*wirebson.Array)
+	if !ok {
+		return
+	}
+
+	for v := range plans.Values() {
+		if p, ok := v.(*wirebson.Document); ok {
+			planStages(p, stages)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrentOpParams(t *testing.T) {
+	t.Parallel()
+
+	doc := wirebson.MustDocument(
+		"currentOp", int32(1),
+		"$ownOps", true,
+		"idleCursors", true,
+		"active", true,
+		"$or", wirebson.MustArray(
+			wirebson.MustDocument("op", "query"),
+			wirebson.MustDocument("op", "getmore"),
+		),
+		"$db", "admin",
+		"lsid", wirebson.MustDocument("id", wirebson.Binary{Subtype: wirebson.BinaryUUID}),
+	)
+
+	params, filter, err := getCurrentOpParams(doc)
+	require.NoError(t, err)
+
+	expected := &currentOpParams{
+		allUsers:    false,
+		idleCursors: true,
+	}
+	assert.Equal(t, expected, params)
+	assert.Equal(t, []string{"active", "$or"}, filter.FieldNames())
+
+	t.Run("All", func(t *testing.T) {
+		t.Parallel()
+
+		params, filter, err := getCurrentOpParams(wirebson.MustDocument("currentOp", int32(1), "$all", true))
+		require.NoError(t, err)
+
+		expected := &currentOpParams{
+			allUsers:        true,
+			idleConnections: true,
+			idleCursors:     true,
+			idleSessions:    true,
+		}
+		assert.Equal(t, expected, params)
+		assert.Zero(t, filter.Len())
+	})
+}
+
+func TestGetCurrentOpStageParams(t *testing.T) {
+	t.Parallel()
+
+	doc := wirebson.MustDocument(
+		"aggregate", float64(1),
+		"pipeline", wirebson.MustArray(
+			wirebson.MustDocument("$currentOp", wirebson.MustDocument("allUsers", true, "idleSessions", true)),
+			wirebson.MustDocument("$match", wirebson.MustDocument("active", true)),
+		),
+	)
+
+	params, stages, err := getCurrentOpStageParams(doc, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, &currentOpParams{allUsers: true, idleSessions: true}, params)
+	assert.Equal(t, 1, stages.Len())
+
+	_, _, err = getCurrentOpStageParams(doc, "test")
+	require.Error(t, err)
+
+	params, _, err = getCurrentOpStageParams(wirebson.MustDocument(
+		"aggregate", "coll",
+		"pipeline", wirebson.MustArray(wirebson.MustDocument("$match", wirebson.MustDocument())),
+	), "test")
+	require.NoError(t, err)
+	assert.Nil(t, params)
+}
+
+func TestPlanStages(t *testing.T) {
+	t.Parallel()
+
+	plan := wirebson.MustDocument(
+		"Plan", wirebson.MustDocument(
+			"Node Type", "Append",
+			"Plans", wirebson.MustArray(
+				wirebson.MustDocument("Node Type", "Seq Scan"),
+				wirebson.MustDocument("Node Type", "Index Scan", "Index Name", "a_1"),
+				wirebson.MustDocument("Node Type", "Seq Scan"),
+			),
+		),
+	)
+
+	var stages []string
+	planStages(plan, &stages)
+	assert.Equal(t, []string{"COLLSCAN", "IXSCAN { a_1 }"}, stages)
+}
+
+func TestEffectiveUsers(t *testing.T) {
+	t.Parallel()
+
+	expected := wirebson.MustArray(wirebson.MustDocument("user", "user@example.com", "db", "$external"))
+	assert.Equal(t, expected, effectiveUsers("user@example.com@$external"))
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// externalDB is the database of users authenticated by external mechanisms such as MONGODB-X509.
+//
+// DocumentDB does not support such users, so they are stored by FerretDB;
+// their roles are stored the same way as for other users, but with a prefixed key, see userRolesKey.
+const externalDB = "$external"
+
+// errExternalPassword returns an error for `$external` users with a password.
+func errExternalPassword(command string) error {
+	return mongoerrors.NewWithArgument(
+		mongoerrors.ErrBadValue,
+		"Cannot set the password for users defined on the '$external' database",
+		command,
+	)
+}
+
+// createExternalUser implements `createUser` command for the `$external` database.
+func (h *Handler) createExternalUser(ctx context.Context, doc *wirebson.Document, username string, roles []rbac.RoleName) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if username == "" {
+		msg := "User document needs 'user' field to be non-empty"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "createUser")
+	}
+
+	if doc.Get("pwd") != nil {
+		return nil, errExternalPassword("createUser")
+	}
+
+	err := h.Pool.CreateExternalUser(ctx, &documentdb.ExternalUserInfo{
+		Name: username,
+		ID:   uuid.New(),
+	})
+
+	if errors.Is(err, documentdb.ErrUserExists) {
+		msg := fmt.Sprintf("User %q already exists", username+"@"+externalDB)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrLocation51003, msg, "createUser")
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = h.setUserRoleNames(ctx, userRolesKey(username, externalDB), roles); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
+
+// updateExternalUser implements `updateUser` command for the `$external` database.
+// Only roles could be changed.
+func (h *Handler) updateExternalUser(ctx context.Context, doc *wirebson.Document, username string, roles []rbac.RoleName) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if doc.Get("pwd") != nil {
+		return nil, errExternalPassword("updateUser")
+	}
+
+	u, err := h.Pool.GetExternalUser(ctx, username)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if u == nil {
+		msg := fmt.Sprintf("Could not find user %q for db %q", username, externalDB)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrUserNotFound, msg, "updateUser")
+	}
+
+	if doc.Get("roles") != nil {
+		if err = h.setUserRoleNames(ctx, userRolesKey(username, externalDB), roles); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
+
+// dropExternalUser implements `dropUser` command for the `$external` database.
+func (h *Handler) dropExternalUser(ctx context.Context, username string) (*wire.OpMsg, error) {
+	dropped, err := h.Pool.DropExternalUser(ctx, username)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !dropped {
+		msg := fmt.Sprintf("User '%s@%s' not found", username, externalDB)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrUserNotFound, msg, "dropUser")
+	}
+
+	if err = h.deleteUserRoleNames(ctx, userRolesKey(username, externalDB)); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
+
+// dropAllExternalUsers implements `dropAllUsersFromDatabase` command for the `$external` database.
+func (h *Handler) dropAllExternalUsers(ctx context.Context) (*wire.OpMsg, error) {
+	users, err := h.Pool.ListExternalUsers(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var n int32
+
+	for _, u := range users {
+		var dropped bool
+		if dropped, err = h.Pool.DropExternalUser(ctx, u.Name); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !dropped {
+			continue
+		}
+
+		if err = h.deleteUserRoleNames(ctx, userRolesKey(u.Name, externalDB)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		n++
+	}
+
+	return wire.MustOpMsg("n", n, "ok", float64(1)), nil
+}
+
+// externalUsersInfo implements `usersInfo` command for the `$external` database.
+func (h *Handler) externalUsersInfo(ctx context.Context, doc *wirebson.Document) (*wire.OpMsg, error) {
+	names, all, err := getExternalUsersInfoNames(doc.Get("usersInfo"))
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*documentdb.ExternalUserInfo
+
+	if all {
+		if users, err = h.Pool.ListExternalUsers(ctx); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	} else {
+		for _, name := range names {
+			var u *documentdb.ExternalUserInfo
+			if u, err = h.Pool.GetExternalUser(ctx, name); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if u != nil {
+				users = append(users, u)
+			}
+		}
+	}
+
+	arr := wirebson.MakeArray(len(users))
+
+	for _, u := range users {
+		var roles []rbac.RoleName
+		if roles, _, err = h.getUserRoleNames(ctx, userRolesKey(u.Name, externalDB)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		must.NoError(arr.Add(wirebson.MustDocument(
+			"_id", externalDB+"."+u.Name,
+			"userId", wirebson.Binary{B: u.ID[:], Subtype: wirebson.BinaryUUID},
+			"user", u.Name,
+			"db", externalDB,
+			"roles", rbac.RoleNamesArray(roles),
+			"mechanisms", wirebson.MustArray("external"),
+		)))
+	}
+
+	return wire.MustOpMsg("users", arr, "ok", float64(1)), nil
+}
+
+// getExternalUsersInfoNames returns names of `$external` users requested by `usersInfo`,
+// or true if all users are requested.
+func getExternalUsersInfoNames(v any) ([]string, bool, error) {
+	switch v := v.(type) {
+	case int32, int64, float64:
+		return nil, true, nil
+
+	case string:
+		return []string{v}, false, nil
+
+	case *wirebson.Document:
+		user, _ := v.Get("user").(string)
+		if user == "" {
+			msg := "UserName must contain a field named: user"
+			return nil, false, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "usersInfo")
+		}
+
+		if db, _ := v.Get("db").(string); db != externalDB {
+			return nil, false, nil
+		}
+
+		return []string{user}, false, nil
+
+	case *wirebson.Array:
+		var res []string
+
+		for e := range v.Values() {
+			switch e.(type) {
+			case string, *wirebson.Document:
+			default:
+				msg := "User and role names must be either strings or objects"
+				return nil, false, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "usersInfo")
+			}
+
+			names, _, err := getExternalUsersInfoNames(e)
+			if err != nil {
+				return nil, false, err
+			}
+
+			res = append(res, names...)
+		}
+
+		return res, false, nil
+
+	default:
+		msg := "User and role names must be either strings or objects"
+		return nil, false, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "usersInfo")
+	}
+}
@@ -18,15 +18,23 @@ package handler
 import (
 	"context"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/compression"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/lockout"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/operation"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/oidc"
+	"github.com/FerretDB/FerretDB/v2/internal/util/plain"
 	"github.com/FerretDB/FerretDB/v2/internal/util/state"
 )
 
@@ -60,6 +68,10 @@ type Handler struct {
 
 	operations *operation.Registry
 	s          *session.Registry
+	privileges *privilegesCache
+	lockout    *lockout.Registry
+
+	credentialsVersion atomic.Uint64 // see CredentialsVersion
 }
 
 // NewOpts represents handler configuration.
@@ -69,14 +81,31 @@ type NewOpts struct {
 	Pool *documentdb.Pool
 	Auth bool
 
+	PLAINVerifier plain.Verifier // nil disables PLAIN mechanism
+	OIDCVerifier  *oidc.Verifier // nil disables MONGODB-OIDC mechanism
+
+	Audit *audit.Logger // nil disables audit log
+
+	RedactClientLogData bool // mask all client data values in logs, keeping only field names
+
+	Lockout lockout.Config // zero threshold disables SCRAM brute-force protection
+
+	MaxTimeDefault time.Duration // used by read commands without `maxTimeMS`; zero means no limit
+	MaxTimeLimit   time.Duration // caps `maxTimeMS` values; zero means no limit
+
+	CurrentOpPlanSummary bool // report `planSummary` in `currentOp` output, running EXPLAIN for operations
+
 	TCPHost     string
 	ReplSetName string
 
+	Compressors *compression.Compressors // nil disables OP_COMPRESSED
+
 	L             *slog.Logger
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
 
 	SessionCleanupInterval time.Duration
+	TransactionTimeout     time.Duration // defaults to 60s; checked every SessionCleanupInterval
 }
 
 // New returns a new handler.
@@ -91,6 +120,8 @@ func New(opts *NewOpts) (*Handler, error) {
 
 		operations: operation.NewRegistry(),
 		s:          session.NewRegistry(sessionTimeout, opts.L),
+		privileges: newPrivilegesCache(),
+		lockout:    lockout.NewRegistry(opts.Lockout, opts.L),
 	}
 
 	h.initCommands()
@@ -111,6 +142,13 @@ func (h *Handler) Run(ctx context.Context) {
 		sessionCleanupInterval = time.Minute
 	}
 
+	sessionTimeout := time.Duration(session.LogicalSessionTimeoutMinutes) * time.Minute
+
+	transactionTimeout := h.TransactionTimeout
+	if transactionTimeout == 0 {
+		transactionTimeout = time.Minute
+	}
+
 	ticker := time.NewTicker(sessionCleanupInterval)
 
 	defer ticker.Stop()
@@ -127,18 +165,79 @@ func (h *Handler) Run(ctx context.Context) {
 			for _, cursorID := range cursorIDs {
 				_ = h.Pool.KillCursor(ctx, cursorID)
 			}
+
+			for _, txn := range h.s.AbortExpiredTxns(ctx, transactionTimeout) {
+				_ = txn.Rollback(ctx)
+			}
+
+			h.s.RemoveCursors(h.Pool.CloseIdleCursors(ctx))
+
+			if err := h.Pool.ExpireSessions(ctx, sessionTimeout); err != nil {
+				h.L.WarnContext(ctx, "Failed to expire shared sessions", logging.Error(err))
+			}
 		}
 	}
 }
 
+// startOperation starts a new operation in the registry for the authenticated user.
+//
+// It returns the operation ID and a derived context that should be used by the handler;
+// that context records PostgreSQL backend PID of acquired connections
+// and is canceled by `killOp`.
+// It is caller's responsibility to call [operation.Registry.Stop].
+func (h *Handler) startOperation(ctx context.Context, op string) (context.Context, int32) {
+	ctx, opID := h.operations.Start(ctx, op, conninfo.Get(ctx))
+
+	ctx = documentdb.BackendPIDCtx(ctx, func(pid uint32, cancel func(context.Context) error) {
+		h.operations.SetBackend(opID, pid, cancel)
+	})
+
+	return ctx, opID
+}
+
+// addCursor registers the cursor created by the given user in the given session.
+//
+// Cursors shared between FerretDB instances are tracked by [documentdb.Pool] itself,
+// so they are not expired together with sessions of this instance.
+func (h *Handler) addCursor(ctx context.Context, userID session.UserID, sessionID uuid.UUID, cursorID int64) {
+	if !h.Pool.LocalCursor(cursorID) {
+		return
+	}
+
+	h.s.AddCursor(ctx, userID, sessionID, cursorID)
+}
+
+// cursorOwners returns cursor owners for the given user IDs, see [documentdb.CursorOwnerCtx].
+func cursorOwners(userIDs []session.UserID) []string {
+	res := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		res[i] = userID.String()
+	}
+
+	return res
+}
+
+// AddConn registers the client connection, so it could be reported by `currentOp`.
+// [Handler.RemoveConn] should be called when connection is closed.
+func (h *Handler) AddConn(ci *conninfo.ConnInfo) {
+	h.operations.AddConn(ci)
+}
+
+// RemoveConn unregisters the client connection.
+func (h *Handler) RemoveConn(ci *conninfo.ConnInfo) {
+	h.operations.RemoveConn(ci)
+}
+
 // Describe implements [prometheus.Collector].
 func (h *Handler) Describe(ch chan<- *prometheus.Desc) {
 	h.Pool.Describe(ch)
 	h.s.Describe(ch)
+	h.lockout.Describe(ch)
 }
 
 // Collect implements [prometheus.Collector].
 func (h *Handler) Collect(ch chan<- prometheus.Metric) {
 	h.Pool.Collect(ch)
 	h.s.Collect(ch)
+	h.lockout.Collect(ch)
 }
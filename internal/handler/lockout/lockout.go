@@ -0,0 +1,435 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lockout provides protection against brute-force authentication attempts.
+//
+// Failed attempts are tracked separately for usernames and peer addresses.
+// After each failed attempt, further attempts are blocked for the backoff time
+// that doubles with each consecutive failure.
+// After the threshold of consecutive failures is reached, attempts are blocked
+// for the lockout duration that doubles with each subsequent lockout.
+// Both are capped by the maximal duration; failures are forgotten after that time without new ones.
+package lockout
+
+import (
+	"log/slog"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "auth_lockout"
+)
+
+// Kinds of tracked entries used as metrics labels.
+const (
+	kindUser    = "user"
+	kindAddress = "address"
+)
+
+// sweepInterval is the minimal interval between removals of forgotten entries.
+const sweepInterval = time.Minute
+
+// Config represents [Registry] configuration.
+type Config struct {
+	// Threshold is the number of consecutive failed attempts that locks out the username or the peer address.
+	// Zero disables brute-force protection.
+	Threshold int
+
+	// Backoff is the time attempts are blocked after the first failure.
+	// It is doubled for each consecutive failure. Zero disables backoff.
+	Backoff time.Duration
+
+	// Duration is the first lockout duration.
+	// It is doubled for each subsequent lockout.
+	Duration time.Duration
+
+	// MaxDuration caps backoff and lockout durations.
+	// Failures are forgotten after that time without new ones.
+	MaxDuration time.Duration
+}
+
+// entry represents failed attempts for a single username or peer address.
+type entry struct {
+	blockedUntil time.Time
+	lastFailure  time.Time
+	failures     int  // consecutive failures since the last lockout
+	lockouts     int  // lockouts since the entry was created or forgotten
+	locked       bool // true if blockedUntil is set by lockout, false if by backoff
+}
+
+// blocked returns true if attempts are blocked at the given time.
+func (e *entry) blocked(now time.Time) bool {
+	return now.Before(e.blockedUntil)
+}
+
+// forgotten returns true if the entry should be forgotten at the given time.
+func (e *entry) forgotten(now time.Time, maxDuration time.Duration) bool {
+	last := e.lastFailure
+	if e.blockedUntil.After(last) {
+		last = e.blockedUntil
+	}
+
+	return now.Sub(last) > maxDuration
+}
+
+// Stats represents brute-force protection statistics.
+type Stats struct {
+	LockedUsers      int
+	LockedAddresses  int
+	FailedAttempts   int64
+	Lockouts         int64
+	RejectedAttempts int64
+}
+
+// Registry tracks failed authentication attempts.
+//
+//nolint:vet // for readability
+type Registry struct {
+	config Config
+	l      *slog.Logger
+
+	m         sync.Mutex
+	users     map[string]*entry
+	addrs     map[netip.Addr]*entry
+	lastSweep time.Time
+	stats     Stats
+
+	failed   *prometheus.CounterVec
+	lockouts *prometheus.CounterVec
+	rejected prometheus.Counter
+	locked   *prometheus.Desc
+}
+
+// NewRegistry creates a new registry.
+//
+// It is disabled if threshold is not positive.
+func NewRegistry(config Config, l *slog.Logger) *Registry {
+	if config.MaxDuration < config.Duration {
+		config.MaxDuration = config.Duration
+	}
+
+	return &Registry{
+		config: config,
+		l:      logging.WithName(l, "lockout"),
+		users:  map[string]*entry{},
+		addrs:  map[netip.Addr]*entry{},
+
+		failed: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "failed_attempts_total",
+				Help:      "Total number of failed authentication attempts.",
+			},
+			[]string{"kind"},
+		),
+		lockouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "lockouts_total",
+				Help:      "Total number of lockouts.",
+			},
+			[]string{"kind"},
+		),
+		rejected: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "rejected_attempts_total",
+				Help:      "Total number of authentication attempts rejected due to backoff or lockout.",
+			},
+		),
+		locked: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "locked"),
+			"The current number of locked out usernames and addresses.",
+			[]string{"kind"}, nil,
+		),
+	}
+}
+
+// Enabled returns true if brute-force protection is enabled.
+func (r *Registry) Enabled() bool {
+	return r.config.Threshold > 0
+}
+
+// Blocked returns true if the authentication attempt for the given username
+// from the given peer address should be rejected.
+//
+// Empty username and invalid address (for Unix domain sockets) are not checked.
+func (r *Registry) Blocked(username string, addr netip.Addr) bool {
+	return r.blocked(username, addr, time.Now())
+}
+
+// blocked implements [Registry.Blocked] at the given time.
+func (r *Registry) blocked(username string, addr netip.Addr, now time.Time) bool {
+	if !r.Enabled() {
+		return false
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var res bool
+
+	if e := r.users[username]; username != "" && e != nil && e.blocked(now) {
+		res = true
+	}
+
+	if e := r.addrs[addr]; addr.IsValid() && e != nil && e.blocked(now) {
+		res = true
+	}
+
+	if res {
+		r.stats.RejectedAttempts++
+		r.rejected.Inc()
+	}
+
+	return res
+}
+
+// Failure records the failed authentication attempt.
+//
+// Attempts made while the username or the address is blocked are not counted for it.
+func (r *Registry) Failure(username string, addr netip.Addr) {
+	r.failure(username, addr, time.Now())
+}
+
+// failure implements [Registry.Failure] at the given time.
+func (r *Registry) failure(username string, addr netip.Addr, now time.Time) {
+	if !r.Enabled() {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.stats.FailedAttempts++
+
+	if username != "" {
+		e := r.users[username]
+		if e == nil {
+			e = new(entry)
+			r.users[username] = e
+		}
+
+		if r.record(e, now, kindUser) {
+			r.l.Warn("Username is locked out", slog.String("username", username), slog.Time("until", e.blockedUntil))
+		}
+	}
+
+	if addr.IsValid() {
+		e := r.addrs[addr]
+		if e == nil {
+			e = new(entry)
+			r.addrs[addr] = e
+		}
+
+		if r.record(e, now, kindAddress) {
+			r.l.Warn("Address is locked out", slog.String("address", addr.String()), slog.Time("until", e.blockedUntil))
+		}
+	}
+
+	if now.Sub(r.lastSweep) > sweepInterval {
+		r.sweep(now)
+	}
+}
+
+// record records the failure for the entry and returns true if it was locked out.
+//
+// The caller must hold the lock.
+func (r *Registry) record(e *entry, now time.Time, kind string) bool {
+	if e.blocked(now) {
+		return false
+	}
+
+	if e.forgotten(now, r.config.MaxDuration) {
+		*e = entry{}
+	}
+
+	r.failed.WithLabelValues(kind).Inc()
+
+	e.lastFailure = now
+	e.failures++
+
+	if e.failures >= r.config.Threshold {
+		e.blockedUntil = now.Add(r.double(r.config.Duration, e.lockouts))
+		e.failures = 0
+		e.lockouts++
+		e.locked = true
+
+		r.stats.Lockouts++
+		r.lockouts.WithLabelValues(kind).Inc()
+
+		return true
+	}
+
+	if r.config.Backoff > 0 {
+		e.blockedUntil = now.Add(r.double(r.config.Backoff, e.failures-1))
+		e.locked = false
+	}
+
+	return false
+}
+
+// double returns the base duration doubled n times, capped by the maximal duration.
+func (r *Registry) double(base time.Duration, n int) time.Duration {
+	res := base
+	for range n {
+		if res >= r.config.MaxDuration {
+			break
+		}
+
+		res *= 2
+	}
+
+	return min(res, r.config.MaxDuration)
+}
+
+// sweep removes forgotten entries.
+//
+// The caller must hold the lock.
+func (r *Registry) sweep(now time.Time) {
+	for k, e := range r.users {
+		if e.forgotten(now, r.config.MaxDuration) {
+			delete(r.users, k)
+		}
+	}
+
+	for k, e := range r.addrs {
+		if e.forgotten(now, r.config.MaxDuration) {
+			delete(r.addrs, k)
+		}
+	}
+
+	r.lastSweep = now
+}
+
+// Success records the successful authentication for the given username
+// and forgets its failed attempts.
+//
+// Failed attempts for the peer address are not forgotten,
+// as it could be shared by many users.
+func (r *Registry) Success(username string) {
+	if !r.Enabled() {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.users, username)
+}
+
+// UnlockUser forgets failed attempts for the given username.
+// It returns true if the username was locked out.
+func (r *Registry) UnlockUser(username string) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	e := r.users[username]
+	delete(r.users, username)
+
+	return e != nil && e.locked && e.blocked(time.Now())
+}
+
+// UnlockAddr forgets failed attempts for the given peer address.
+// It returns true if the address was locked out.
+func (r *Registry) UnlockAddr(addr netip.Addr) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	e := r.addrs[addr]
+	delete(r.addrs, addr)
+
+	return e != nil && e.locked && e.blocked(time.Now())
+}
+
+// UnlockAll forgets all failed attempts.
+// It returns the numbers of usernames and addresses that were locked out.
+func (r *Registry) UnlockAll() (int, int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	users, addrs := r.lockedCounts(time.Now())
+
+	r.users = map[string]*entry{}
+	r.addrs = map[netip.Addr]*entry{}
+
+	return users, addrs
+}
+
+// lockedCounts returns the numbers of currently locked out usernames and addresses.
+//
+// The caller must hold the lock.
+func (r *Registry) lockedCounts(now time.Time) (int, int) {
+	var users, addrs int
+
+	for _, e := range r.users {
+		if e.locked && e.blocked(now) {
+			users++
+		}
+	}
+
+	for _, e := range r.addrs {
+		if e.locked && e.blocked(now) {
+			addrs++
+		}
+	}
+
+	return users, addrs
+}
+
+// Stats returns brute-force protection statistics.
+func (r *Registry) Stats() *Stats {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	res := r.stats
+	res.LockedUsers, res.LockedAddresses = r.lockedCounts(time.Now())
+
+	return &res
+}
+
+// Describe implements [prometheus.Collector].
+func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
+	r.failed.Describe(ch)
+	r.lockouts.Describe(ch)
+	r.rejected.Describe(ch)
+	ch <- r.locked
+}
+
+// Collect implements [prometheus.Collector].
+func (r *Registry) Collect(ch chan<- prometheus.Metric) {
+	r.failed.Collect(ch)
+	r.lockouts.Collect(ch)
+	r.rejected.Collect(ch)
+
+	stats := r.Stats()
+	ch <- prometheus.MustNewConstMetric(r.locked, prometheus.GaugeValue, float64(stats.LockedUsers), kindUser)
+	ch <- prometheus.MustNewConstMetric(r.locked, prometheus.GaugeValue, float64(stats.LockedAddresses), kindAddress)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Registry)(nil)
+)
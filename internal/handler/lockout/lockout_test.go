@@ -0,0 +1,146 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lockout
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ferretdbtestutil "github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(Config{
+		Threshold:   3,
+		Backoff:     time.Second,
+		Duration:    time.Minute,
+		MaxDuration: 3 * time.Minute,
+	}, ferretdbtestutil.Logger(t))
+
+	addr := netip.MustParseAddr("192.0.2.1")
+	otherAddr := netip.MustParseAddr("192.0.2.2")
+	now := time.Now()
+
+	// exponential backoff
+	r.failure("user", addr, now)
+	assert.True(t, r.blocked("user", otherAddr, now.Add(999*time.Millisecond)))
+	assert.True(t, r.blocked("other", addr, now.Add(999*time.Millisecond)))
+	assert.False(t, r.blocked("other", otherAddr, now))
+	assert.False(t, r.blocked("user", addr, now.Add(time.Second)))
+
+	now = now.Add(time.Second)
+	r.failure("user", addr, now)
+	assert.True(t, r.blocked("user", addr, now.Add(1999*time.Millisecond)))
+	assert.False(t, r.blocked("user", addr, now.Add(2*time.Second)))
+
+	// attempts while blocked are not counted
+	r.failure("user", addr, now.Add(time.Second))
+
+	// lockout
+	now = now.Add(2 * time.Second)
+	r.failure("user", addr, now)
+	assert.True(t, r.blocked("user", otherAddr, now.Add(59*time.Second)))
+	assert.False(t, r.blocked("user", otherAddr, now.Add(time.Minute)))
+
+	stats := r.Stats()
+	assert.Equal(t, int64(4), stats.FailedAttempts)
+	assert.Equal(t, int64(2), stats.Lockouts)
+	assert.Equal(t, int64(4), stats.RejectedAttempts)
+
+	// the second lockout is twice as long
+	now = now.Add(time.Minute)
+	for i := range 3 {
+		r.failure("user", otherAddr, now.Add(time.Duration(i)*10*time.Second))
+	}
+
+	now = now.Add(20 * time.Second)
+	assert.True(t, r.blocked("user", netip.Addr{}, now.Add(119*time.Second)))
+	assert.False(t, r.blocked("user", netip.Addr{}, now.Add(2*time.Minute)))
+
+	// unlock
+	assert.True(t, r.UnlockUser("user"))
+	assert.False(t, r.UnlockUser("user"))
+	assert.False(t, r.blocked("user", netip.Addr{}, now))
+
+	// forgotten after max duration without failures
+	r.failure("user", netip.Addr{}, now)
+	r.failure("user", netip.Addr{}, now.Add(4*time.Minute))
+	assert.False(t, r.blocked("user", netip.Addr{}, now.Add(4*time.Minute+time.Second)))
+
+	// success forgets username, but not address
+	r.Success("user")
+	r.failure("other", addr, now.Add(10*time.Minute))
+	r.failure("other", addr, now.Add(10*time.Minute+5*time.Second))
+	r.Success("other")
+	r.failure("third", addr, now.Add(10*time.Minute+10*time.Second))
+	assert.True(t, r.blocked("", addr, now.Add(10*time.Minute+11*time.Second)))
+	assert.False(t, r.blocked("other", netip.Addr{}, now.Add(10*time.Minute+11*time.Second)))
+}
+
+func TestRegistryDisabled(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(Config{}, ferretdbtestutil.Logger(t))
+	require.False(t, r.Enabled())
+
+	addr := netip.MustParseAddr("192.0.2.1")
+	for range 100 {
+		r.Failure("user", addr)
+	}
+
+	assert.False(t, r.Blocked("user", addr))
+	assert.Equal(t, &Stats{}, r.Stats())
+}
+
+func TestRegistryMetrics(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(Config{
+		Threshold:   1,
+		Duration:    time.Hour,
+		MaxDuration: time.Hour,
+	}, ferretdbtestutil.Logger(t))
+
+	r.Failure("user", netip.MustParseAddr("192.0.2.1"))
+	r.Failure("other", netip.Addr{})
+
+	expected := `
+		# HELP ferretdb_auth_lockout_locked The current number of locked out usernames and addresses.
+		# TYPE ferretdb_auth_lockout_locked gauge
+		ferretdb_auth_lockout_locked{kind="address"} 1
+		ferretdb_auth_lockout_locked{kind="user"} 2
+		# HELP ferretdb_auth_lockout_lockouts_total Total number of lockouts.
+		# TYPE ferretdb_auth_lockout_lockouts_total counter
+		ferretdb_auth_lockout_lockouts_total{kind="address"} 1
+		ferretdb_auth_lockout_lockouts_total{kind="user"} 2
+	`
+	assert.NoError(t, testutil.CollectAndCompare(
+		r, strings.NewReader(expected),
+		"ferretdb_auth_lockout_locked", "ferretdb_auth_lockout_lockouts_total",
+	))
+
+	users, addrs := r.UnlockAll()
+	assert.Equal(t, 2, users)
+	assert.Equal(t, 1, addrs)
+	assert.Equal(t, 0, r.Stats().LockedUsers)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// maxTimeDefaultCommands contains read commands that use [NewOpts.MaxTimeDefault]
+// (or [NewOpts.MaxTimeLimit]) when `maxTimeMS` is not set.
+var maxTimeDefaultCommands = map[string]struct{}{
+	"aggregate": {},
+	"count":     {},
+	"distinct":  {},
+	"find":      {},
+}
+
+// getMaxTimeParam returns the value of `maxTimeMS` field, or zero if it is not set.
+func getMaxTimeParam(doc *wirebson.Document) (time.Duration, error) {
+	v := doc.Get("maxTimeMS")
+	if v == nil {
+		return 0, nil
+	}
+
+	var ms float64
+
+	switch v := v.(type) {
+	case int32:
+		ms = float64(v)
+	case int64:
+		ms = float64(v)
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, "maxTimeMS has non-integral value", "maxTimeMS")
+		}
+
+		ms = v
+	default:
+		msg := fmt.Sprintf("maxTimeMS must be a number, got %s", aliasFromType(v))
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "maxTimeMS")
+	}
+
+	if ms < 0 || ms > math.MaxInt32 {
+		msg := fmt.Sprintf("%.0f value for maxTimeMS is out of range [0, %d]", ms, math.MaxInt32)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "maxTimeMS")
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// withMaxTime returns a command handler that runs the given handler with the time limit
+// set by `maxTimeMS` field, [NewOpts.MaxTimeDefault], and [NewOpts.MaxTimeLimit].
+//
+// The limit is applied to the passed context and to PostgreSQL `statement_timeout`
+// of connections acquired by the handler; see [documentdb.MaxTimeCtx].
+// Cursors created by the handler keep it for their `getMore` commands.
+func (h *Handler) withMaxTime(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	// getMore uses the limit of the command that created the cursor
+	if command == "getMore" {
+		return cmdHandler
+	}
+
+	_, useDefault := maxTimeDefaultCommands[command]
+
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		doc, err := msg.RawSection0().Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		d, err := getMaxTimeParam(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if d == 0 && useDefault {
+			d = h.MaxTimeDefault
+		}
+
+		if h.MaxTimeLimit > 0 && (d > h.MaxTimeLimit || (d == 0 && useDefault)) {
+			d = h.MaxTimeLimit
+		}
+
+		if d == 0 {
+			return cmdHandler(connCtx, msg)
+		}
+
+		ctx, cancel := documentdb.MaxTimeCtx(connCtx, d)
+		defer cancel()
+
+		return cmdHandler(ctx, msg)
+	}
+}
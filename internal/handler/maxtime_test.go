@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMaxTimeParam(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v        any
+		expected time.Duration
+		err      string
+	}{
+		"Int32": {
+			v:        int32(1500),
+			expected: 1500 * time.Millisecond,
+		},
+		"Int64": {
+			v:        int64(10),
+			expected: 10 * time.Millisecond,
+		},
+		"Double": {
+			v:        float64(42),
+			expected: 42 * time.Millisecond,
+		},
+		"Zero": {
+			v: int32(0),
+		},
+		"NonIntegral": {
+			v:   1.5,
+			err: "maxTimeMS has non-integral value",
+		},
+		"Negative": {
+			v:   int32(-1),
+			err: "-1 value for maxTimeMS is out of range [0, 2147483647]",
+		},
+		"TooLarge": {
+			v:   int64(1 << 31),
+			err: "2147483648 value for maxTimeMS is out of range [0, 2147483647]",
+		},
+		"String": {
+			v:   "1",
+			err: "maxTimeMS must be a number, got string",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := getMaxTimeParam(wirebson.MustDocument("find", "test", "maxTimeMS", tc.v))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, d)
+		})
+	}
+
+	d, err := getMaxTimeParam(wirebson.MustDocument("find", "test"))
+	require.NoError(t, err)
+	assert.Zero(t, d)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// MsgAbortTransaction implements `abortTransaction` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgAbortTransaction(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	txnNumber, err := getRequiredParam[int64](doc, "txnNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	userID, sessionID, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := h.s.AbortTxn(userID, sessionID, txnNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// the transaction is aborted even if rollback fails because the connection is closed anyway
+	if err = txn.Rollback(connCtx); err != nil {
+		h.L.WarnContext(connCtx, "Failed to roll back transaction", logging.Error(err))
+	}
+
+	return wire.MustOpMsg(
+		"ok", float64(1),
+	), nil
+}
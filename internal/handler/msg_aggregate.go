@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 // MsgAggregate implements `aggregate` command.
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("query")
+	connCtx, opID := h.startOperation(connCtx, "query")
 	defer h.operations.Stop(opID)
 
 	spec, err := msg.RawDocument()
@@ -53,12 +57,43 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
-	page, cursorID, err := h.Pool.Aggregate(connCtx, dbName, spec)
+	connCtx = documentdb.CursorOwnerCtx(connCtx, userID.String(), sessionID)
+
+	changeStream, err := getChangeStreamParams(doc, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	currentOp, stages, err := getCurrentOpStageParams(doc, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentOp != nil {
+		if spec, err = h.currentOpSpec(connCtx, doc, currentOp, stages); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	var page wirebson.RawDocument
+	var cursorID int64
+
+	if changeStream != nil {
+		if documentdb.GetTxn(connCtx) != nil {
+			msg := "Operation not permitted in transaction :: caused by :: $changeStream"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrOperationNotSupportedInTransaction, msg, "$changeStream")
+		}
+
+		page, cursorID, err = h.Pool.ChangeStream(connCtx, changeStream)
+	} else {
+		page, cursorID, err = h.Pool.Aggregate(connCtx, dbName, spec)
+	}
+
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	h.s.AddCursor(connCtx, userID, sessionID, cursorID)
+	h.addCursor(connCtx, userID, sessionID, cursorID)
 
 	if msg, err = wire.NewOpMsg(page); err != nil {
 		return nil, lazyerrors.Error(err)
@@ -66,3 +101,36 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 
 	return msg, nil
 }
+
+// currentOpSpec returns `aggregate` command spec with the `$currentOp` stage
+// replaced by the `$documents` stage with in-progress operations.
+func (h *Handler) currentOpSpec(ctx context.Context, doc *wirebson.Document, params *currentOpParams, stages *wirebson.Array) (wirebson.RawDocument, error) { //nolint:lll // for readability
+	inProgress, err := h.currentOp(ctx, params)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	pipeline := wirebson.MakeArray(stages.Len() + 1)
+	must.NoError(pipeline.Add(wirebson.MustDocument("$documents", inProgress)))
+
+	for v := range stages.Values() {
+		must.NoError(pipeline.Add(v))
+	}
+
+	res := wirebson.MakeDocument(doc.Len())
+
+	for k, v := range doc.All() {
+		if k == "pipeline" {
+			v = pipeline
+		}
+
+		must.NoError(res.Add(k, v))
+	}
+
+	spec, err := res.Encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return spec, nil
+}
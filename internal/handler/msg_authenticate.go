@@ -0,0 +1,143 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// mechanismX509 is the name of the authentication mechanism that uses TLS client certificates.
+const mechanismX509 = "MONGODB-X509"
+
+// MsgAuthenticate implements `authenticate` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgAuthenticate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.authenticate(connCtx, doc, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	must.NoError(res.Add("ok", float64(1)))
+
+	return wire.NewOpMsg(res)
+}
+
+// authenticate authenticates the connection as the `$external` user
+// with the subject of the verified TLS client certificate.
+// It returns the document containing the authenticated user used for the response.
+func (h *Handler) authenticate(ctx context.Context, doc *wirebson.Document, dbName string) (res *wirebson.Document, err error) { //nolint:lll // for readability
+	if !h.Auth {
+		h.L.WarnContext(ctx, "authenticate is called when authentication is disabled")
+	}
+
+	mechanism, err := getRequiredParam[string](doc, "mechanism")
+	if err != nil {
+		return nil, err
+	}
+
+	username, _ := doc.Get("user").(string)
+
+	defer func() {
+		h.auditAuthenticate(ctx, username, dbName, mechanism, err)
+	}()
+
+	if mechanism != mechanismX509 {
+		msg := fmt.Sprintf("Received authentication for mechanism %s which is not enabled", mechanism)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrMechanismUnavailable, msg, "mechanism")
+	}
+
+	if dbName != externalDB {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrProtocolError,
+			"X.509 authentication must always use the $external database.",
+			"authenticate",
+		)
+	}
+
+	cert := conninfo.Get(ctx).ClientCert
+	if cert == nil {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"No verified subject name available from client",
+			"authenticate",
+		)
+	}
+
+	subject := cert.Subject.String()
+
+	if username != "" && username != subject {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"There is no x.509 client certificate matching the user.",
+			"authenticate",
+		)
+	}
+
+	username = subject
+
+	u, err := h.Pool.GetExternalUser(ctx, subject)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if u == nil {
+		h.L.DebugContext(ctx, "authenticate: no such user", slog.String("username", subject))
+
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"Authentication failed.",
+			"authenticate",
+		)
+	}
+
+	conninfo.Get(ctx).SetExternalUser(subject, mechanism, nil)
+
+	h.L.DebugContext(ctx, "authenticate: passed", slog.String("username", subject))
+
+	return wirebson.MustDocument(
+		"dbname", externalDB,
+		"user", subject,
+	), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgCloneCollectionAsCapped implements `cloneCollectionAsCapped` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgCloneCollectionAsCapped(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	to, ok := doc.Get("toCollection").(string)
+	if !ok {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrLocation40414,
+			fmt.Sprintf("BSON field '%s.toCollection' is missing but a required field", command),
+			command,
+		)
+	}
+
+	if !collectionNameRe.MatchString(to) || !utf8.ValidString(to) {
+		msg := fmt.Sprintf("Invalid collection name: %s", to)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidNamespace, msg, command)
+	}
+
+	size, err := getCappedSizeParam(doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.Pool.CloneCollectionAsCapped(connCtx, dbName, from, to, &documentdb.CappedInfo{Size: size})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wire.MustOpMsg(
+		"ok", float64(1),
+	), nil
+}
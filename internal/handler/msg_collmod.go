@@ -20,6 +20,7 @@ import (
 	"github.com/FerretDB/wire"
 
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
 
@@ -52,7 +53,13 @@ func (h *Handler) MsgCollMod(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, err
 	}
 
-	conn, err := h.Pool.Acquire()
+	// view definition is replaced as a whole
+	if (doc.Get("viewOn") == nil) != (doc.Get("pipeline") == nil) {
+		msg := "collMod on a view must specify both 'viewOn' and 'pipeline'"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "collMod")
+	}
+
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
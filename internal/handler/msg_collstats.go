@@ -21,9 +21,9 @@ import (
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 
-	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 // MsgCollStats implements `collStats` command.
@@ -78,17 +78,42 @@ func (h *Handler) MsgCollStats(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		}
 	}
 
-	conn, err := h.Pool.Acquire()
+	page, err := h.Pool.CollStats(connCtx, dbName, collection, scale)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
-	defer conn.Release()
 
-	page, err := documentdb_api.CollStats(connCtx, conn.Conn(), h.L, dbName, collection, scale)
+	cappedInfo, err := h.Pool.CappedCollections(connCtx, dbName)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	if capped := cappedInfo[collection]; capped != nil {
+		var res *wirebson.Document
+
+		if res, err = page.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// keep `ok` the last field
+		okV := res.Get("ok")
+		res.Remove("ok")
+
+		res.Remove("capped")
+		must.NoError(res.Add("capped", true))
+		must.NoError(res.Add("maxSize", capped.Size))
+
+		if capped.Max > 0 {
+			must.NoError(res.Add("max", capped.Max))
+		}
+
+		must.NoError(res.Add("ok", okV))
+
+		if page, err = res.Encode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
 	if msg, err = wire.NewOpMsg(page); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
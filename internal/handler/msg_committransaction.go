@@ -0,0 +1,77 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgCommitTransaction implements `commitTransaction` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgCommitTransaction(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	txnNumber, err := getRequiredParam[int64](doc, "txnNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	userID, sessionID, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := h.s.CommitTxn(userID, sessionID, txnNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// nil means that the transaction was already committed, and the client retries the commit
+	if txn == nil {
+		return wire.MustOpMsg(
+			"ok", float64(1),
+		), nil
+	}
+
+	if err = txn.Commit(connCtx); err != nil {
+		h.s.SetTxnAborted(userID, sessionID, txnNumber)
+
+		// write conflicts already have a label that allows drivers to retry the whole transaction
+		e := mongoerrors.Make(connCtx, err, "commitTransaction", h.L)
+		if e.Code == int32(mongoerrors.ErrWriteConflict) {
+			return nil, e
+		}
+
+		return nil, e.WithLabels(mongoerrors.LabelUnknownTransactionCommitResult)
+	}
+
+	return wire.MustOpMsg(
+		"ok", float64(1),
+	), nil
+}
@@ -55,7 +55,7 @@ func (h *Handler) MsgCompact(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 		}
 	}
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
@@ -38,19 +39,59 @@ func (h *Handler) MsgConnectionStatus(connCtx context.Context, msg *wire.OpMsg)
 		return nil, err
 	}
 
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var showPrivileges bool
+
+	if v := doc.Get("showPrivileges"); v != nil {
+		if showPrivileges, err = getBoolParam("showPrivileges", v); err != nil {
+			return nil, err
+		}
+	}
+
 	users := wirebson.MakeArray(1)
+	roles := wirebson.MakeArray(0)
+	privileges := wirebson.MakeArray(0)
 
-	if u := conninfo.Get(connCtx).Conv().Username(); u != "" {
-		must.NoError(users.Add(must.NotFail(wirebson.NewDocument(
+	ci := conninfo.Get(connCtx)
+
+	if u := ci.Username(); u != "" {
+		user := must.NotFail(wirebson.NewDocument(
 			"user", u,
-		))))
+		))
+
+		if extUser, _ := ci.ExternalUser(); extUser != "" {
+			must.NoError(user.Add("db", externalDB))
+		}
+
+		must.NoError(users.Add(user))
+
+		up, err := h.getConnPrivileges(connCtx)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for _, r := range up.roles {
+			must.NoError(roles.Add(r.Name.Document()))
+		}
+
+		privileges = rbac.PrivilegesArray(up.privileges)
+	}
+
+	authInfo := must.NotFail(wirebson.NewDocument(
+		"authenticatedUsers", users,
+		"authenticatedUserRoles", roles,
+	))
+
+	if showPrivileges {
+		must.NoError(authInfo.Add("authenticatedUserPrivileges", privileges))
 	}
 
 	res := must.NotFail(wirebson.NewDocument(
-		"authInfo", must.NotFail(wirebson.NewDocument(
-			"authenticatedUsers", users,
-			"authenticatedUserRoles", must.NotFail(wirebson.NewArray()),
-		)),
+		"authInfo", authInfo,
 		"ok", float64(1),
 	))
 
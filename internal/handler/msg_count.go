@@ -27,7 +27,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgCount(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("query")
+	connCtx, opID := h.startOperation(connCtx, "query")
 	defer h.operations.Stop(opID)
 
 	spec, err := msg.RawDocument()
@@ -53,7 +53,7 @@ func (h *Handler) MsgCount(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMs
 	collection, _ := doc.Get(doc.Command()).(string)
 	h.operations.Update(opID, dbName, collection, doc)
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
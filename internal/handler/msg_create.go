@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"unicode/utf8"
 
 	"github.com/FerretDB/wire"
@@ -32,6 +33,16 @@ import (
 // collectionNameRe validates collection names.
 var collectionNameRe = regexp.MustCompile("^[^\\.$\x00][^$\x00]{0,234}$")
 
+// createSpecOptions contains `create` options that are handled by DocumentDB's `create_collection_view`.
+// Any other non-generic option is not implemented.
+var createSpecOptions = []string{
+	"viewOn",
+	"pipeline",
+	"validator",
+	"validationLevel",
+	"validationAction",
+}
+
 // MsgCreate implements `create` command.
 //
 // The passed context is canceled when the client connection is closed.
@@ -66,18 +77,65 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidNamespace, msg, "create")
 	}
 
-	conn, err := h.Pool.Acquire()
+	var withOptions bool
+
+	for k := range doc.Fields() {
+		switch {
+		case k == "create" || slices.Contains(genericFields, k):
+		case k == "capped" || k == "size" || k == "max":
+		case slices.Contains(createSpecOptions, k):
+			withOptions = true
+		default:
+			msg := fmt.Sprintf("create option %q is not implemented yet", k)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "create")
+		}
+	}
+
+	if doc.Get("pipeline") != nil && doc.Get("viewOn") == nil {
+		msg := "'pipeline' requires 'viewOn' to also be specified"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "create")
+	}
+
+	capped, err := getCreateCappedParams(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	defer conn.Release()
 
-	_, err = documentdb_api.CreateCollection(connCtx, conn.Conn(), h.L, dbName, collectionName)
+	if withOptions {
+		// capped collections are handled by FerretDB itself
+		if capped != nil {
+			doc.Remove("capped")
+			doc.Remove("size")
+			doc.Remove("max")
+
+			if spec, err = doc.Encode(); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		// views and validators are created from the whole command
+		_, err = documentdb_api.CreateCollectionView(connCtx, conn.Conn(), h.L, dbName, spec)
+	} else {
+		_, err = documentdb_api.CreateCollection(connCtx, conn.Conn(), h.L, dbName, collectionName)
+	}
+
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	if capped != nil {
+		if err = h.Pool.SetCapped(connCtx, dbName, collectionName, capped); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
 	res := wirebson.MustDocument(
 		"ok", float64(1),
 	)
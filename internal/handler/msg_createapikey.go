@@ -0,0 +1,98 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/util/apikey"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgCreateAPIKey implements FerretDB-specific `createAPIKey` command.
+//
+// It creates a new Data API key for the user given by the `createAPIKey` field.
+// The key value is returned only once; only its hash is stored.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgCreateAPIKey(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := doc.Command()
+
+	if err = checkAdminDB(doc, command); err != nil {
+		return nil, err
+	}
+
+	username, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := getOptionalParam(doc, "description", "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = h.getExistingUserRoleNames(connCtx, command, username, "admin"); err != nil {
+		return nil, err
+	}
+
+	key, err := apikey.New()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	info := &documentdb.APIKeyInfo{
+		ID:          key.ID,
+		Username:    username,
+		Description: description,
+		Hash:        key.Hash(),
+	}
+
+	if err = h.Pool.CreateAPIKey(connCtx, info); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.L.InfoContext(
+		connCtx, "API key created",
+		slog.String("by", conninfo.Get(connCtx).Username()),
+		slog.String("username", username), slog.String("key_id", key.ID),
+	)
+
+	return wire.MustOpMsg(
+		"keyId", key.ID,
+		"key", key.Value(),
+		"user", username,
+		"ok", float64(1),
+	), nil
+}
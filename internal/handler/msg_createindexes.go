@@ -61,7 +61,7 @@ func (h *Handler) MsgCreateIndexes(connCtx context.Context, msg *wire.OpMsg) (*w
 		)
 	}
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgCreateRole implements `createRole` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgCreateRole(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	roleName, err := getRequiredParam[string](doc, "createRole")
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if roleName == "" {
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, "Role name must be non-empty", "createRole")
+	}
+
+	if rbac.IsBuiltin(roleName) {
+		msg := "Cannot create roles with the same name as a built-in role"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "createRole")
+	}
+
+	privilegesV, err := getRequiredParamAny(doc, "privileges")
+	if err != nil {
+		return nil, err
+	}
+
+	privileges, err := rbac.ParsePrivileges(privilegesV)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesV, err := getRequiredParamAny(doc, "roles")
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := rbac.ParseRoleNames(rolesV, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateRole(dbName, privileges, roles); err != nil {
+		return nil, err
+	}
+
+	if err = h.checkRolesExist(connCtx, roles); err != nil {
+		return nil, err
+	}
+
+	if err = h.checkRolesGrantable(connCtx, "createRole", roles); err != nil {
+		return nil, err
+	}
+
+	role := &rbac.Role{
+		Name:       rbac.RoleName{Role: roleName, DB: dbName},
+		Privileges: privileges,
+		Roles:      roles,
+	}
+
+	err = h.Pool.CreateRole(connCtx, roleInfo(role))
+	if errors.Is(err, documentdb.ErrRoleExists) {
+		msg := fmt.Sprintf("Role %q already exists", role.Name.String())
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrLocation51002, msg, "createRole")
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.privileges.reset()
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
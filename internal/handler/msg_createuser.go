@@ -19,14 +19,19 @@ import (
 	"log/slog"
 
 	"github.com/FerretDB/wire"
-	"github.com/FerretDB/wire/wirebson"
-	"github.com/jackc/pgx/v5"
 
-	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
+// documentDBUserRoles contains DocumentDB roles of all users created by FerretDB.
+var documentDBUserRoles = []rbac.RoleName{
+	{Role: "clusterAdmin", DB: "admin"},
+	{Role: "readWriteAnyDatabase", DB: "admin"},
+}
+
 // MsgCreateUser implements `createUser` command.
 //
 // The passed context is canceled when the client connection is closed.
@@ -45,32 +50,72 @@ func (h *Handler) MsgCreateUser(connCtx context.Context, msg *wire.OpMsg) (*wire
 		return nil, lazyerrors.Error(err)
 	}
 
-	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/913
+	username, err := getRequiredParam[string](doc, "createUser")
+	if err != nil {
+		return nil, err
+	}
+
+	// DocumentDB always stores SCRAM-SHA-256 credentials, other mechanisms are handled by FerretDB
+	mechanisms, err := getMechanismsParam(doc, "createUser")
+	if err != nil {
+		return nil, err
+	}
+
 	doc.Remove("mechanisms")
 
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []rbac.RoleName
+
+	if rolesV := doc.Get("roles"); rolesV != nil {
+		if roles, err = rbac.ParseRoleNames(rolesV, dbName); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = h.checkRolesExist(connCtx, roles); err != nil {
+		return nil, err
+	}
+
+	if err = h.checkRolesGrantable(connCtx, "createUser", roles); err != nil {
+		return nil, err
+	}
+
+	if dbName == externalDB {
+		return h.createExternalUser(connCtx, doc, username, roles)
+	}
+
+	// DocumentDB supports only a few fixed roles, so privileges are checked by FerretDB itself
+	// using roles stored separately; DocumentDB always gets the same broad roles.
 	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/911
-	roles, _ := doc.Get("roles").(*wirebson.Array)
-	if roles == nil || roles.Len() == 0 {
-		roles = wirebson.MustArray(
-			wirebson.MustDocument("role", "clusterAdmin", "db", "admin"),
-			wirebson.MustDocument("role", "readWriteAnyDatabase", "db", "admin"),
-		)
-
-		h.L.WarnContext(
-			connCtx, "Adding default roles",
-			slog.Any("user", doc.Get(doc.Command())), slog.String("roles", roles.LogMessage()),
-		)
-
-		must.NoError(doc.Replace("roles", roles))
-		spec = must.NotFail(doc.Encode())
+	documentDBRoles := must.NotFail(rbac.RoleNamesArray(documentDBUserRoles).Encode())
+
+	h.L.DebugContext(
+		connCtx, "Replacing roles for DocumentDB",
+		slog.String("user", username), slog.String("roles", documentDBRoles.LogMessage()),
+	)
+
+	doc.Remove("roles")
+	must.NoError(doc.Add("roles", documentDBRoles))
+	spec = must.NotFail(doc.Encode())
+
+	var creds *documentdb.UserCredentials
+
+	if mechanisms != nil {
+		pwd, _ := doc.Get("pwd").(string)
+
+		if creds, err = newUserCredentials(username, pwd, mechanisms); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
-	var res wirebson.RawDocument
+	defer h.privileges.reset()
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
-		res, err = documentdb_api.CreateUser(connCtx, conn, h.L, spec)
-		return err
-	})
+	// roles and credentials are stored together with the user, see getUserRoleNames
+	res, err := h.Pool.CreateUser(connCtx, spec, username, must.NotFail(rbac.RoleNamesArray(roles).Encode()), creds)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
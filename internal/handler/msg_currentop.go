@@ -16,20 +16,16 @@ package handler
 
 import (
 	"context"
-	"time"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
-	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 // MsgCurrentOp implements `currentOp` command.
 //
 // The passed context is canceled when the client connection is closed.
-//
-// TODO https://github.com/FerretDB/FerretDB/issues/3974
 func (h *Handler) MsgCurrentOp(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
 	spec, err := msg.RawDocument()
 	if err != nil {
@@ -40,35 +36,28 @@ func (h *Handler) MsgCurrentOp(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
-	ops := h.operations.Operations()
-	inProgress := wirebson.MakeArray(len(ops))
-
-	for _, op := range ops {
-		since := time.Since(op.CurrentOpTime)
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
-		var ns string
-		if op.DB != "" && op.Collection != "" {
-			ns = op.DB + "." + op.Collection
-		}
+	params, filter, err := getCurrentOpParams(doc)
+	if err != nil {
+		return nil, err
+	}
 
-		opCommand := op.Command
-		if opCommand == nil {
-			opCommand = must.NotFail(wirebson.NewDocument())
-		}
+	inProgress, err := h.currentOp(connCtx, params)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
-		doc := wirebson.MustDocument(
-			"type", "op",
-			"active", op.Active,
-			"currentOpTime", time.Now().Format(time.RFC3339),
-			"opid", op.OpID,
-			"secs_running", int64(since.Truncate(time.Second).Seconds()),
-			"microsecs_running", since.Microseconds(),
-			"op", op.Op,
-			"ns", ns,
-			"command", opCommand,
-		)
+	// let DocumentDB match documents the same way as for the stored documents
+	if filter.Len() > 0 && inProgress.Len() > 0 {
+		stages := wirebson.MustArray(wirebson.MustDocument("$match", filter))
 
-		must.NoError(inProgress.Add(doc))
+		if inProgress, err = h.Pool.DocumentsPipeline(connCtx, "admin", inProgress, stages, inProgress.Len()); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	return wire.MustOpMsg(
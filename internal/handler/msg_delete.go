@@ -30,7 +30,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgDelete(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("remove")
+	connCtx, opID := h.startOperation(connCtx, "remove")
 	defer h.operations.Stop(opID)
 
 	spec, seq := msg.RawSections()
@@ -55,7 +55,7 @@ func (h *Handler) MsgDelete(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 
 	var res wirebson.RawDocument
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+	err = h.Pool.WithConn(connCtx, func(conn *pgx.Conn) error {
 		res, _, err = documentdb_api.Delete(connCtx, conn, h.L, dbName, spec, seq)
 		return err
 	})
@@ -29,7 +29,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgDistinct(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("query")
+	connCtx, opID := h.startOperation(connCtx, "query")
 	defer h.operations.Stop(opID)
 
 	spec, err := msg.RawDocument()
@@ -71,7 +71,7 @@ func (h *Handler) MsgDistinct(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 		)
 	}
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -65,7 +65,7 @@ func (h *Handler) MsgDrop(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 	// Should we manually close all cursors for the collection?
 	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/17
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
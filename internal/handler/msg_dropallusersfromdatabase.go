@@ -48,7 +48,11 @@ func (h *Handler) MsgDropAllUsersFromDatabase(connCtx context.Context, msg *wire
 		return nil, err
 	}
 
-	conn, err := h.Pool.Acquire()
+	if dbName == externalDB {
+		return h.dropAllExternalUsers(connCtx)
+	}
+
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -103,6 +107,18 @@ func (h *Handler) MsgDropAllUsersFromDatabase(connCtx context.Context, msg *wire
 			return nil, lazyerrors.Error(err)
 		}
 
+		if err = h.deleteUserRoleNames(connCtx, username); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err = h.setUserCredentials(connCtx, username, nil); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err = h.Pool.DropUserAPIKeys(connCtx, username); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
 		n++
 	}
 
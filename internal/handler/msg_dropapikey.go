@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgDropAPIKey implements FerretDB-specific `dropAPIKey` command.
+//
+// It removes the Data API key with the ID given by the `dropAPIKey` field.
+// The Data API could still accept the key until its authentication cache entry expires.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgDropAPIKey(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := doc.Command()
+
+	if err = checkAdminDB(doc, command); err != nil {
+		return nil, err
+	}
+
+	id, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	// invalidate cached authentications with that key
+	defer h.credentialsVersion.Add(1)
+
+	dropped, err := h.Pool.DropAPIKey(connCtx, id)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !dropped {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrBadValue,
+			fmt.Sprintf("API key %q not found", id),
+			command,
+		)
+	}
+
+	h.L.InfoContext(
+		connCtx, "API key dropped",
+		slog.String("by", conninfo.Get(connCtx).Username()), slog.String("key_id", id),
+	)
+
+	return wire.MustOpMsg(
+		"ok", float64(1),
+	), nil
+}
@@ -51,7 +51,7 @@ func (h *Handler) MsgDropDatabase(connCtx context.Context, msg *wire.OpMsg) (*wi
 	// Should we manually close all cursors for the database?
 	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/17
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
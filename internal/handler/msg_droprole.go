@@ -0,0 +1,79 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgDropRole implements `dropRole` command.
+//
+// Roles that inherit the dropped role and users that were granted it are not updated;
+// the dropped role is ignored during privileges resolution.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgDropRole(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	roleName, err := getRequiredParam[string](doc, "dropRole")
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	name := rbac.RoleName{Role: roleName, DB: dbName}
+
+	if rbac.Builtin(name) != nil {
+		msg := fmt.Sprintf("Cannot drop built-in role: %s", name)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidRoleModification, msg, "dropRole")
+	}
+
+	dropped, err := h.Pool.DropRole(connCtx, dbName, roleName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.privileges.reset()
+
+	if !dropped {
+		msg := fmt.Sprintf("Could not find role: %s", name)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrRoleNotFound, msg, "dropRole")
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
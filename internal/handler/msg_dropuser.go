@@ -54,6 +54,10 @@ func (h *Handler) MsgDropUser(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 		return nil, err
 	}
 
+	if dbName == externalDB {
+		return h.dropExternalUser(connCtx, user)
+	}
+
 	dropSpec := must.NotFail(must.NotFail(wirebson.NewDocument(
 		"dropUser", user,
 		"$db", dbName,
@@ -61,7 +65,7 @@ func (h *Handler) MsgDropUser(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 
 	var res wirebson.RawDocument
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+	err = h.Pool.WithConn(connCtx, func(conn *pgx.Conn) error {
 		// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/859
 		res, err = documentdb_api.DropUser(connCtx, conn, h.L, dropSpec)
 		return err
@@ -70,5 +74,17 @@ func (h *Handler) MsgDropUser(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = h.deleteUserRoleNames(connCtx, user); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = h.setUserCredentials(connCtx, user, nil); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = h.Pool.DropUserAPIKeys(connCtx, user); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	return wire.NewOpMsg(res)
 }
@@ -36,7 +36,8 @@ func (h *Handler) MsgEndSessions(connCtx context.Context, msg *wire.OpMsg) (*wir
 		return nil, lazyerrors.Error(err)
 	}
 
-	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+	userID, _, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
+	if err != nil {
 		return nil, err
 	}
 
@@ -47,6 +48,10 @@ func (h *Handler) MsgEndSessions(connCtx context.Context, msg *wire.OpMsg) (*wir
 
 	h.s.EndSessions(connCtx, ids)
 
+	if err = h.Pool.EndSessions(connCtx, userID.String(), ids); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	return wire.MustOpMsg(
 		"ok", float64(1),
 	), nil
@@ -37,7 +37,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("command")
+	connCtx, opID := h.startOperation(connCtx, "command")
 	defer h.operations.Stop(opID)
 
 	spec, err := msg.RawDocument()
@@ -89,42 +89,7 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 
 	h.operations.Update(opID, dbName, collection, doc)
 
-	var f string
-
-	switch cmd {
-	case "aggregate":
-		f = "documentdb_api_catalog.bson_aggregation_pipeline"
-	case "count":
-		f = "documentdb_api_catalog.bson_aggregation_count"
-	case "find":
-		f = "documentdb_api_catalog.bson_aggregation_find"
-	default:
-		return nil, mongoerrors.NewWithArgument(
-			mongoerrors.ErrNotImplemented,
-			fmt.Sprintf("explain for %s command is not supported", cmd),
-			"explain",
-		)
-	}
-
-	q := fmt.Sprintf(`
-		EXPLAIN (FORMAT JSON)
-			SELECT document
-		FROM %s($1, $2::bytea)`,
-		f,
-	)
-
-	conn, err := h.Pool.Acquire()
-	if err != nil {
-		return nil, lazyerrors.Error(err)
-	}
-	defer conn.Release()
-
-	var dest []byte
-	if err = conn.Conn().QueryRow(connCtx, q, dbName, explainSpec).Scan(&dest); err != nil {
-		return nil, lazyerrors.Error(mongoerrors.Make(connCtx, err, "", h.L))
-	}
-
-	queryPlan, err := unmarshalExplain(dest)
+	queryPlan, err := h.explainPlan(connCtx, dbName, cmd, explainSpec)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -176,6 +141,51 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 	return wire.NewOpMsg(reply)
 }
 
+// explainPlan returns PostgreSQL query plan of the given command.
+func (h *Handler) explainPlan(ctx context.Context, dbName, cmd string, spec wirebson.RawDocument) (*wirebson.Document, error) {
+	var f string
+
+	switch cmd {
+	case "aggregate":
+		f = "documentdb_api_catalog.bson_aggregation_pipeline"
+	case "count":
+		f = "documentdb_api_catalog.bson_aggregation_count"
+	case "find":
+		f = "documentdb_api_catalog.bson_aggregation_find"
+	default:
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrNotImplemented,
+			fmt.Sprintf("explain for %s command is not supported", cmd),
+			"explain",
+		)
+	}
+
+	q := fmt.Sprintf(`
+		EXPLAIN (FORMAT JSON)
+			SELECT document
+		FROM %s($1, $2::bytea)`,
+		f,
+	)
+
+	conn, err := h.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer conn.Release()
+
+	var dest []byte
+	if err = conn.Conn().QueryRow(ctx, q, dbName, spec).Scan(&dest); err != nil {
+		return nil, lazyerrors.Error(mongoerrors.Make(ctx, err, "", h.L))
+	}
+
+	queryPlan, err := unmarshalExplain(dest)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return queryPlan, nil
+}
+
 // unmarshalExplain unmarshalls the plan from EXPLAIN postgreSQL command.
 func unmarshalExplain(b []byte) (*wirebson.Document, error) {
 	var plans []map[string]any
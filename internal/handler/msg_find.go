@@ -18,7 +18,9 @@ import (
 	"context"
 
 	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
 
@@ -26,7 +28,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("query")
+	connCtx, opID := h.startOperation(connCtx, "query")
 	defer h.operations.Stop(opID)
 
 	spec, err := msg.RawDocument()
@@ -53,12 +55,27 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		return nil, err
 	}
 
-	page, cursorID, err := h.Pool.Find(connCtx, dbName, spec)
+	connCtx = documentdb.CursorOwnerCtx(connCtx, userID.String(), sessionID)
+
+	tailable, err := getTailableFindParams(doc, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	var page wirebson.RawDocument
+	var cursorID int64
+
+	if tailable != nil {
+		page, cursorID, err = h.Pool.TailableFind(connCtx, tailable)
+	} else {
+		page, cursorID, err = h.Pool.Find(connCtx, dbName, spec)
+	}
+
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	h.s.AddCursor(connCtx, userID, sessionID, cursorID)
+	h.addCursor(connCtx, userID, sessionID, cursorID)
 
 	if msg, err = wire.NewOpMsg(page); err != nil {
 		return nil, lazyerrors.Error(err)
@@ -20,6 +20,7 @@ import (
 
 	"github.com/FerretDB/wire"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
@@ -28,7 +29,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgGetMore(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("getmore")
+	connCtx, opID := h.startOperation(connCtx, "getmore")
 	defer h.operations.Stop(opID)
 
 	spec, err := msg.RawDocument()
@@ -67,6 +68,9 @@ func (h *Handler) MsgGetMore(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, err
 	}
 
+	// shared cursors are validated by the pool
+	connCtx = documentdb.CursorOwnerCtx(connCtx, userID.String(), sessionID)
+
 	page, err := h.Pool.GetMore(connCtx, dbName, spec, cursorID)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -77,6 +77,11 @@ func (h *Handler) MsgGetParameter(connCtx context.Context, msg *wire.OpMsg) (*wi
 			"settableAtRuntime", true,
 			"settableAtStartup", true,
 		)),
+		"redactClientLogData", must.NotFail(wirebson.NewDocument(
+			"value", h.RedactClientLogData,
+			"settableAtRuntime", false,
+			"settableAtStartup", true,
+		)),
 		// parameters are alphabetically ordered
 	))
 
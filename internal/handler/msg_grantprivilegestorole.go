@@ -0,0 +1,80 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgGrantPrivilegesToRole implements `grantPrivilegesToRole` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgGrantPrivilegesToRole(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	roleName, err := getRequiredParam[string](doc, "grantPrivilegesToRole")
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	privilegesV, err := getRequiredParamAny(doc, "privileges")
+	if err != nil {
+		return nil, err
+	}
+
+	privileges, err := rbac.ParsePrivileges(privilegesV)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := h.getUserDefinedRole(connCtx, "grantPrivilegesToRole", rbac.RoleName{Role: roleName, DB: dbName})
+	if err != nil {
+		return nil, err
+	}
+
+	role.Privileges = rbac.GrantPrivileges(role.Privileges, privileges)
+
+	if err = validateRole(dbName, role.Privileges, role.Roles); err != nil {
+		return nil, err
+	}
+
+	if err = h.updateRole(connCtx, "grantPrivilegesToRole", role); err != nil {
+		return nil, err
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
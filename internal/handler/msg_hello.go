@@ -23,6 +23,7 @@ import (
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
@@ -85,7 +86,11 @@ func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, tcpHost,
 		panic(fmt.Sprintf("unexpected command: %q", doc.Command()))
 	}
 
-	if name != "" {
+	if name == "" {
+		// FerretDB routes all queries like mongos does (see [Handler.MsgShardCollection]),
+		// so drivers should pass read preferences to it instead of selecting servers themselves
+		must.NoError(res.Add("msg", "isdbgrid"))
+	} else {
 		// That does not work for TLS-only setups, IPv6 addresses, etc.
 		// The proper solution is to support `replSetInitiate` command.
 		// TODO https://github.com/FerretDB/FerretDB/issues/3936
@@ -106,7 +111,36 @@ func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, tcpHost,
 	must.NoError(res.Add("minWireVersion", minWireVersion))
 	must.NoError(res.Add("maxWireVersion", maxWireVersion))
 	must.NoError(res.Add("readOnly", false))
-	must.NoError(res.Add("saslSupportedMechs", wirebson.MustArray("SCRAM-SHA-256")))
+
+	mechs, err := h.getSASLSupportedMechs(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if mechs != nil {
+		must.NoError(res.Add("saslSupportedMechs", mechs))
+	}
+
+	compressors, err := getCompressors(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	negotiated := h.Compressors.Negotiate(compressors)
+
+	// keep compressors negotiated before if the client did not send the field
+	if compressors != nil {
+		conninfo.Get(ctx).SetCompressors(negotiated)
+	}
+
+	if len(negotiated) > 0 {
+		arr := wirebson.MakeArray(len(negotiated))
+		for _, c := range negotiated {
+			must.NoError(arr.Add(c))
+		}
+
+		must.NoError(res.Add("compression", arr))
+	}
 
 	authV := doc.Get("speculativeAuthenticate")
 	if authV == nil {
@@ -129,19 +163,27 @@ func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, tcpHost,
 		return nil, lazyerrors.Error(err)
 	}
 
-	if _, err = getRequiredParam[string](auth, "db"); err != nil {
+	authDB, err := getRequiredParam[string](auth, "db")
+	if err != nil {
 		h.L.DebugContext(ctx, "No `db` in `speculativeAuthenticate`", logging.Error(err))
 		must.NoError(res.Add("ok", float64(1)))
 
 		return res, nil
 	}
 
-	authRes, err := h.saslStart(ctx, auth)
+	var authRes *wirebson.Document
+
+	if auth.Get("authenticate") != nil {
+		authRes, err = h.authenticate(ctx, auth, authDB)
+	} else {
+		authRes, err = h.saslStart(ctx, auth, authDB)
+	}
+
 	if err != nil {
 		h.L.DebugContext(ctx, "Speculative authentication failed", logging.Error(err))
 
 		// unsuccessful speculative authentication leave `speculativeAuthenticate` field unset
-		// and let `saslStart` return an error
+		// and let `saslStart` or `authenticate` return an error
 		must.NoError(res.Add("ok", float64(1)))
 
 		return res, nil
@@ -154,3 +196,42 @@ func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, tcpHost,
 
 	return res, nil
 }
+
+// getCompressors returns compressors listed by the client in the `compression` field of `hello`.
+func getCompressors(doc *wirebson.Document) ([]string, error) {
+	v := doc.Get("compression")
+	if v == nil {
+		return nil, nil
+	}
+
+	arrV, ok := v.(wirebson.AnyArray)
+	if !ok {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrTypeMismatch,
+			fmt.Sprintf("BSON field 'compression' is the wrong type '%s', expected type 'array'", aliasFromType(v)),
+			doc.Command(),
+		)
+	}
+
+	arr, err := arrV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := make([]string, 0, arr.Len())
+
+	for v := range arr.Values() {
+		name, ok := v.(string)
+		if !ok {
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrTypeMismatch,
+				fmt.Sprintf("'compression' is the wrong type '%s', expected type 'string'", aliasFromType(v)),
+				doc.Command(),
+			)
+		}
+
+		res = append(res, name)
+	}
+
+	return res, nil
+}
@@ -67,7 +67,17 @@ func checkClientMetadata(ctx context.Context, doc *wirebson.Document) error {
 		)
 	}
 
-	connInfo.SetMetadataRecv()
+	// unexpected types are left for the caller's validation
+	metadata := wirebson.MakeDocument(0)
+
+	if d, ok := c.(wirebson.AnyDocument); ok {
+		var err error
+		if metadata, err = d.Decode(); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	connInfo.SetMetadata(metadata)
 
 	return nil
 }
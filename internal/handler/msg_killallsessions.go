@@ -57,6 +57,10 @@ func (h *Handler) MsgKillAllSessions(connCtx context.Context, msg *wire.OpMsg) (
 			_ = h.Pool.KillCursor(connCtx, cursorID)
 		}
 
+		if err = h.Pool.EndUserSessions(connCtx, nil); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
 		return wire.MustOpMsg(
 			"ok", float64(1),
 		), nil
@@ -68,6 +72,10 @@ func (h *Handler) MsgKillAllSessions(connCtx context.Context, msg *wire.OpMsg) (
 		_ = h.Pool.KillCursor(connCtx, cursorID)
 	}
 
+	if err = h.Pool.EndUserSessions(connCtx, cursorOwners(userIDs)); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	return wire.MustOpMsg(
 		"ok", float64(1),
 	), nil
@@ -127,16 +127,28 @@ func (h *Handler) MsgKillAllSessionsByPattern(connCtx context.Context, msg *wire
 	if allSessions {
 		cursorIDs := h.s.DeleteAllSessions()
 		allCursorIDs = append(allCursorIDs, cursorIDs...)
+
+		if err = h.Pool.EndUserSessions(connCtx, nil); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	if len(userIDs) > 0 {
 		cursorIDs := h.s.DeleteSessionsByUserIDs(userIDs)
 		allCursorIDs = append(allCursorIDs, cursorIDs...)
+
+		if err = h.Pool.EndUserSessions(connCtx, cursorOwners(userIDs)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	for userID, sessionIDs := range lsids {
 		cursorIDs := h.s.DeleteSessionsByIDs(userID, sessionIDs)
 		allCursorIDs = append(allCursorIDs, cursorIDs...)
+
+		if err = h.Pool.EndSessions(connCtx, userID.String(), sessionIDs); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	for _, cursorID := range allCursorIDs {
@@ -22,6 +22,7 @@ import (
 	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
@@ -53,13 +54,16 @@ func (h *Handler) MsgKillCursors(connCtx context.Context, msg *wire.OpMsg) (*wir
 		return nil, err
 	}
 
-	username := conninfo.Get(connCtx).Conv().Username()
+	username := conninfo.Get(connCtx).Username()
 
-	userID, _, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
+	userID, sessionID, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
 	if err != nil {
 		return nil, err
 	}
 
+	// shared cursors of other users are not killed
+	connCtx = documentdb.CursorOwnerCtx(connCtx, userID.String(), sessionID)
+
 	cursorsV, err := getRequiredParamAny(document, "cursors")
 	if err != nil {
 		return nil, err
@@ -0,0 +1,162 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// errOperationKilled is the cause of the operation's context cancellation by `killOp`.
+var errOperationKilled = errors.New("operation was killed by killOp")
+
+// MsgKillOp implements `killOp` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgKillOp(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName != "admin" {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			"killOp may only be run against the admin database.",
+			command,
+		)
+	}
+
+	v, err := getRequiredParamAny(doc, "op")
+	if err != nil {
+		return nil, err
+	}
+
+	opID, err := getOpIDParam(v)
+	if err != nil {
+		return nil, err
+	}
+
+	res := wire.MustOpMsg(
+		"info", "attempting to kill op",
+		"ok", float64(1),
+	)
+
+	op, ok := h.operations.Get(opID)
+	if !ok {
+		return res, nil
+	}
+
+	ci := conninfo.Get(connCtx)
+	username := ci.Username()
+
+	// users can always kill their own operations;
+	// namespaced IDs are compared, so external users can't kill operations of same-named users
+	if op.UserID != ci.UserID() {
+		var allowed bool
+
+		if allowed, err = h.hasPrivilege(connCtx, "killop", "", ""); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !allowed {
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrUnauthorized,
+				fmt.Sprintf("not authorized on %s to execute command %s", dbName, command),
+				command,
+			)
+		}
+	}
+
+	pid, ok, err := h.operations.Kill(connCtx, opID, errOperationKilled)
+	if !ok {
+		return res, nil
+	}
+
+	h.L.InfoContext(
+		connCtx, "Killed operation",
+		slog.Int("opid", int(opID)), slog.String("username", username), slog.Int("backend_pid", int(pid)),
+	)
+
+	if err != nil {
+		h.L.WarnContext(connCtx, "Failed to cancel backend", slog.Int("backend_pid", int(pid)), logging.Error(err))
+	}
+
+	return res, nil
+}
+
+// getOpIDParam returns operation ID from the `op` parameter.
+func getOpIDParam(v any) (int32, error) {
+	var res int64
+
+	switch v := v.(type) {
+	case int32:
+		res = int64(v)
+	case int64:
+		res = v
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, mongoerrors.NewWithArgument(
+				mongoerrors.ErrBadValue,
+				fmt.Sprintf("Expected field \"op\" to have a whole number value, got %v", v),
+				"op",
+			)
+		}
+
+		res = int64(v)
+	default:
+		return 0, mongoerrors.NewWithArgument(
+			mongoerrors.ErrTypeMismatch,
+			fmt.Sprintf("Expected field \"op\" to have numeric type, but found %s", aliasFromType(v)),
+			"op",
+		)
+	}
+
+	if res < math.MinInt32 || res > math.MaxInt32 {
+		return 0, mongoerrors.NewWithArgument(
+			mongoerrors.ErrBadValue,
+			fmt.Sprintf("Invalid \"op\" field value: %d", res),
+			"op",
+		)
+	}
+
+	return int32(res), nil
+}
@@ -56,17 +56,39 @@ func (h *Handler) MsgKillSessions(connCtx context.Context, msg *wire.OpMsg) (*wi
 			_ = h.Pool.KillCursor(connCtx, cursorID)
 		}
 
+		if err = h.Pool.EndUserSessions(connCtx, []string{userID.String()}); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
 		return wire.MustOpMsg(
 			"ok", float64(1),
 		), nil
 	}
 
-	cursorIDs := h.s.DeleteSessionsByIDs(userID, ids)
+	// only users with killAnySession could kill sessions of other users
+	anyUser, err := h.hasPrivilege(connCtx, "killAnySession", "", "")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var cursorIDs []int64
+	owner := userID.String()
+
+	if anyUser {
+		cursorIDs = h.s.DeleteAnySessionsByIDs(ids)
+		owner = ""
+	} else {
+		cursorIDs = h.s.DeleteSessionsByIDs(userID, ids)
+	}
 
 	for _, cursorID := range cursorIDs {
 		_ = h.Pool.KillCursor(connCtx, cursorID)
 	}
 
+	if err = h.Pool.EndSessions(connCtx, owner, ids); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	return wire.MustOpMsg(
 		"ok", float64(1),
 	), nil
@@ -0,0 +1,77 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// MsgListAPIKeys implements FerretDB-specific `listAPIKeys` command.
+//
+// It returns Data API keys of the user given by the `user` field, or of all users.
+// Key values are not returned.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgListAPIKeys(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDB(doc, doc.Command()); err != nil {
+		return nil, err
+	}
+
+	username, err := getOptionalParam(doc, "user", "")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := h.Pool.ListAPIKeys(connCtx, username)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	keys := wirebson.MakeArray(len(list))
+
+	for _, k := range list {
+		must.NoError(keys.Add(wirebson.MustDocument(
+			"keyId", k.ID,
+			"user", k.Username,
+			"description", k.Description,
+			"created", k.Created,
+		)))
+	}
+
+	return wire.MustOpMsg(
+		"keys", keys,
+		"ok", float64(1),
+	), nil
+}
@@ -21,11 +21,18 @@ import (
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 // MsgListCollections implements `listCollections` command.
 //
+// If authentication is enabled, users without the `listCollections` action
+// could list names of collections they have privileges on
+// by setting both `authorizedCollections` and `nameOnly` to true.
+//
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgListCollections(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
 	spec, err := msg.RawDocument()
@@ -49,15 +56,21 @@ func (h *Handler) MsgListCollections(connCtx context.Context, msg *wire.OpMsg) (
 		return nil, err
 	}
 
-	page, cursorID, err := h.Pool.ListCollections(connCtx, dbName, spec)
+	connCtx = documentdb.CursorOwnerCtx(connCtx, userID.String(), sessionID)
+
+	authorizedOnly, privileges, err := h.listAuthorizedCollectionsOnly(connCtx, doc, dbName)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, err
 	}
 
-	h.s.AddCursor(connCtx, userID, sessionID, cursorID)
-
-	// Sort the first page as a partial workaround for
+	// all collections are returned in the first batch,
+	// so they could be sorted, filtered and annotated as a whole;
+	// DocumentDB does not sort them, see
 	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/822
+	page, err := h.Pool.ListCollections(connCtx, dbName, spec)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
 	resp, err := page.DecodeDeep()
 	if err != nil {
@@ -74,6 +87,44 @@ func (h *Handler) MsgListCollections(connCtx context.Context, msg *wire.OpMsg) (
 		return an < bn
 	}))
 
+	if authorizedOnly {
+		authorized := wirebson.MakeArray(firstBatch.Len())
+
+		for v := range firstBatch.Values() {
+			if name := v.(*wirebson.Document).Get("name").(string); rbac.HasAny(privileges, dbName, name) {
+				must.NoError(authorized.Add(v))
+			}
+		}
+
+		firstBatch = authorized
+		must.NoError(cursor.Replace("firstBatch", firstBatch))
+	}
+
+	cappedInfo, err := h.Pool.CappedCollections(connCtx, dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for v := range firstBatch.Values() {
+		coll := v.(*wirebson.Document)
+		name := coll.Get("name").(string)
+
+		if capped := cappedInfo[name]; capped != nil {
+			options, _ := coll.Get("options").(*wirebson.Document)
+			if options == nil {
+				options = wirebson.MakeDocument(3)
+				must.NoError(coll.Add("options", options))
+			}
+
+			must.NoError(options.Add("capped", true))
+			must.NoError(options.Add("size", capped.Size))
+
+			if capped.Max > 0 {
+				must.NoError(options.Add("max", capped.Max))
+			}
+		}
+	}
+
 	page, err = resp.Encode()
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -85,3 +136,41 @@ func (h *Handler) MsgListCollections(connCtx context.Context, msg *wire.OpMsg) (
 
 	return msg, nil
 }
+
+// listAuthorizedCollectionsOnly returns true and privileges of the authenticated user
+// if `listCollections` should return only collections the user has privileges on.
+//
+// That is the case only if both `authorizedCollections` and `nameOnly` are true,
+// and the user does not have the `listCollections` action on the database;
+// otherwise, the action was already checked by [Handler.checkPrivileges].
+func (h *Handler) listAuthorizedCollectionsOnly(ctx context.Context, doc *wirebson.Document, dbName string) (bool, []rbac.Privilege, error) { //nolint:lll // for readability
+	var authorized, nameOnly bool
+	var err error
+
+	if v := doc.Get("authorizedCollections"); v != nil {
+		if authorized, err = getBoolParam("authorizedCollections", v); err != nil {
+			return false, nil, err
+		}
+	}
+
+	if v := doc.Get("nameOnly"); v != nil {
+		if nameOnly, err = getBoolParam("nameOnly", v); err != nil {
+			return false, nil, err
+		}
+	}
+
+	if !h.Auth || !authorized || !nameOnly {
+		return false, nil, nil
+	}
+
+	up, err := h.getConnPrivileges(ctx)
+	if err != nil {
+		return false, nil, lazyerrors.Error(err)
+	}
+
+	if rbac.Allowed(up.privileges, "listCollections", dbName, "") {
+		return false, nil, nil
+	}
+
+	return true, up.privileges, nil
+}
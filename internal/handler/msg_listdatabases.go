@@ -16,21 +16,25 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"slices"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 	"golang.org/x/exp/maps"
 
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 // MsgListDatabases implements `listDatabases` command.
 //
-// The passed context is canceled when the client connection is closed.
+// If authentication is enabled, users without the `listDatabases` action
+// (or with `authorizedDatabases` set to true) see only databases they have privileges on.
 //
-// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/26
+// The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgListDatabases(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
 	spec, err := msg.RawDocument()
 	if err != nil {
@@ -41,6 +45,57 @@ func (h *Handler) MsgListDatabases(connCtx context.Context, msg *wire.OpMsg) (*w
 		return nil, err
 	}
 
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := doc.Command()
+
+	filter := wirebson.MakeDocument(0)
+
+	if v := doc.Get("filter"); v != nil {
+		filterV, ok := v.(wirebson.AnyDocument)
+		if !ok {
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.filter' is the wrong type '%s', expected type 'object'",
+					command, aliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		if filter, err = filterV.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	var nameOnly bool
+
+	if v := doc.Get("nameOnly"); v != nil {
+		if nameOnly, err = getBoolParam("nameOnly", v); err != nil {
+			return nil, err
+		}
+	}
+
+	authorized, err := h.listAuthorizedDatabasesOnly(connCtx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var privileges []rbac.Privilege
+
+	if authorized {
+		var up *userPrivileges
+		if up, err = h.getConnPrivileges(connCtx); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		privileges = up.privileges
+	}
+
 	list, err := h.Pool.ListDatabases(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -52,17 +107,83 @@ func (h *Handler) MsgListDatabases(connCtx context.Context, msg *wire.OpMsg) (*w
 	databases := wirebson.MakeArray(len(names))
 
 	for _, name := range names {
-		d := must.NotFail(wirebson.NewDocument(
-			"name", name,
-		))
+		if authorized && !rbac.HasAny(privileges, name, "") {
+			continue
+		}
+
+		d := wirebson.MustDocument("name", name)
+
+		if !nameOnly {
+			info := list[name]
+			must.NoError(d.Add("sizeOnDisk", info.Size))
+			must.NoError(d.Add("empty", info.Collections == 0))
+		}
 
 		must.NoError(databases.Add(d))
 	}
 
-	res := must.NotFail(wirebson.NewDocument(
-		"databases", databases,
-		"ok", float64(1),
-	))
+	// let DocumentDB match documents the same way as for the stored documents
+	if filter.Len() > 0 && databases.Len() > 0 {
+		stages := wirebson.MustArray(wirebson.MustDocument("$match", filter))
+
+		if databases, err = h.Pool.DocumentsPipeline(connCtx, "admin", databases, stages, databases.Len()); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	res := wirebson.MustDocument("databases", databases)
+
+	if !nameOnly {
+		var totalSize int64
+
+		for v := range databases.Values() {
+			var d *wirebson.Document
+			if d, err = v.(wirebson.AnyDocument).Decode(); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			size, _ := d.Get("sizeOnDisk").(int64)
+			totalSize += size
+		}
+
+		must.NoError(res.Add("totalSize", totalSize))
+		must.NoError(res.Add("totalSizeMb", totalSize/1024/1024))
+	}
+
+	must.NoError(res.Add("ok", float64(1)))
 
 	return wire.NewOpMsg(must.NotFail(res.Encode()))
 }
+
+// listAuthorizedDatabasesOnly returns true if `listDatabases` should return
+// only databases the authenticated user has privileges on.
+//
+// If `authorizedDatabases` is not set, that depends on the `listDatabases` action;
+// if it is set to false, the action was already checked by [Handler.checkPrivileges].
+func (h *Handler) listAuthorizedDatabasesOnly(ctx context.Context, doc *wirebson.Document) (bool, error) {
+	v := doc.Get("authorizedDatabases")
+
+	var authorized bool
+
+	if v != nil {
+		var err error
+		if authorized, err = getBoolParam("authorizedDatabases", v); err != nil {
+			return false, err
+		}
+	}
+
+	if !h.Auth {
+		return false, nil
+	}
+
+	if v != nil {
+		return authorized, nil
+	}
+
+	allowed, err := h.hasPrivilege(ctx, "listDatabases", "", "")
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return !allowed, nil
+}
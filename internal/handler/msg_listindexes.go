@@ -19,6 +19,7 @@ import (
 
 	"github.com/FerretDB/wire"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
 
@@ -47,12 +48,14 @@ func (h *Handler) MsgListIndexes(connCtx context.Context, msg *wire.OpMsg) (*wir
 		return nil, err
 	}
 
+	connCtx = documentdb.CursorOwnerCtx(connCtx, userID.String(), sessionID)
+
 	page, cursorID, err := h.Pool.ListIndexes(connCtx, dbName, spec)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	h.s.AddCursor(connCtx, userID, sessionID, cursorID)
+	h.addCursor(connCtx, userID, sessionID, cursorID)
 
 	if msg, err = wire.NewOpMsg(page); err != nil {
 		return nil, lazyerrors.Error(err)
@@ -39,7 +39,7 @@ func (h *Handler) MsgPing(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		return nil, err
 	}
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+	err = h.Pool.WithConn(connCtx, func(conn *pgx.Conn) error {
 		_, err = documentdb_api.BinaryExtendedVersion(connCtx, conn, h.L)
 		return err
 	})
@@ -36,7 +36,7 @@ func (h *Handler) MsgRefreshSessions(connCtx context.Context, msg *wire.OpMsg) (
 		return nil, lazyerrors.Error(err)
 	}
 
-	_, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec)
+	userID, _, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +48,10 @@ func (h *Handler) MsgRefreshSessions(connCtx context.Context, msg *wire.OpMsg) (
 
 	h.s.CreateOrUpdateSessions(connCtx, ids)
 
+	if err = h.Pool.RefreshSessions(connCtx, userID.String(), ids); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	return wire.MustOpMsg(
 		"ok", float64(1),
 	), nil
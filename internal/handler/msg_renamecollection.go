@@ -133,7 +133,7 @@ func (h *Handler) MsgRenameCollection(connCtx context.Context, msg *wire.OpMsg)
 		)
 	}
 
-	conn, err := h.Pool.Acquire()
+	conn, err := h.Pool.Acquire(connCtx)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -0,0 +1,77 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"slices"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgRevokeRolesFromUser implements `revokeRolesFromUser` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgRevokeRolesFromUser(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	username, err := getRequiredParam[string](doc, "revokeRolesFromUser")
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	rolesV, err := getRequiredParamAny(doc, "roles")
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := rbac.ParseRoleNames(rolesV, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := h.getExistingUserRoleNames(connCtx, "revokeRolesFromUser", username, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	names = slices.DeleteFunc(names, func(n rbac.RoleName) bool { return slices.Contains(roles, n) })
+
+	if err = h.setUserRoleNames(connCtx, userRolesKey(username, dbName), names); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
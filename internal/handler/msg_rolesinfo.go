@@ -0,0 +1,168 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// MsgRolesInfo implements `rolesInfo` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgRolesInfo(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	var showPrivileges, showBuiltinRoles bool
+
+	if v := doc.Get("showPrivileges"); v != nil {
+		if v == "asUserFragment" {
+			msg := "rolesInfo option showPrivileges: \"asUserFragment\" is not implemented yet"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "rolesInfo")
+		}
+
+		if showPrivileges, err = getBoolParam("showPrivileges", v); err != nil {
+			return nil, err
+		}
+	}
+
+	if v := doc.Get("showBuiltinRoles"); v != nil {
+		if showBuiltinRoles, err = getBoolParam("showBuiltinRoles", v); err != nil {
+			return nil, err
+		}
+	}
+
+	lookup := h.lookupRole(connCtx)
+
+	var roles []*rbac.Role
+
+	switch v := doc.Get("rolesInfo").(type) {
+	case int32, int64, float64:
+		if showBuiltinRoles {
+			roles = rbac.BuiltinRoles(dbName)
+		}
+
+		infos, err := h.Pool.ListRoles(connCtx, dbName)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for _, info := range infos {
+			role, err := roleFromInfo(info)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			roles = append(roles, role)
+		}
+
+	case string, wirebson.AnyDocument, wirebson.AnyArray:
+		if _, ok := v.(wirebson.AnyArray); !ok {
+			v = must.NotFail(wirebson.NewArray(v))
+		}
+
+		names, err := rbac.ParseRoleNames(v, dbName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			role, err := rbac.GetRole(name, lookup)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if role != nil {
+				roles = append(roles, role)
+			}
+		}
+
+	default:
+		msg := fmt.Sprintf("rolesInfo argument must be a number, string, object, or array, not %T", v)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "rolesInfo")
+	}
+
+	res := wirebson.MakeArray(len(roles))
+
+	for _, role := range roles {
+		var roleDoc *wirebson.Document
+		if roleDoc, err = roleInfoDocument(role, lookup, showPrivileges); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		must.NoError(res.Add(roleDoc))
+	}
+
+	return wire.MustOpMsg(
+		"roles", res,
+		"ok", float64(1),
+	), nil
+}
+
+// roleInfoDocument returns role information document in `rolesInfo` format.
+func roleInfoDocument(role *rbac.Role, lookup rbac.LookupFunc, showPrivileges bool) (*wirebson.Document, error) {
+	inherited, inheritedPrivileges, err := rbac.Resolve(role.Roles, lookup)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	inheritedRoles := make([]rbac.RoleName, len(inherited))
+	for i, r := range inherited {
+		inheritedRoles[i] = r.Name
+	}
+
+	res := wirebson.MustDocument(
+		"role", role.Name.Role,
+		"db", role.Name.DB,
+		"isBuiltin", role.Builtin,
+		"roles", rbac.RoleNamesArray(role.Roles),
+		"inheritedRoles", rbac.RoleNamesArray(inheritedRoles),
+	)
+
+	if showPrivileges {
+		must.NoError(res.Add("privileges", rbac.PrivilegesArray(role.Privileges)))
+		must.NoError(res.Add(
+			"inheritedPrivileges",
+			rbac.PrivilegesArray(rbac.GrantPrivileges(role.Privileges, inheritedPrivileges)),
+		))
+	}
+
+	return res, nil
+}
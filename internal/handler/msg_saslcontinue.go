@@ -16,6 +16,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/FerretDB/wire"
@@ -27,6 +28,8 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/oidc"
+	"github.com/FerretDB/FerretDB/v2/internal/util/scram"
 )
 
 // MsgSASLContinue implements `saslContinue` command.
@@ -59,7 +62,7 @@ func (h *Handler) MsgSASLContinue(connCtx context.Context, msg *wire.OpMsg) (*wi
 	return msg, nil
 }
 
-// saslContinue continues and finishes SCRAM conversation.
+// saslContinue continues and finishes SCRAM or MONGODB-OIDC conversation.
 // It returns the document containing authentication payload used for the response.
 func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wirebson.Document, error) {
 	if !h.Auth {
@@ -71,7 +74,36 @@ func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wi
 		return nil, lazyerrors.Error(err)
 	}
 
+	if conninfo.Get(ctx).PendingMechanism() == oidc.Mechanism && h.OIDCVerifier != nil {
+		return h.saslContinueOIDC(ctx, payload)
+	}
+
 	conv := conninfo.Get(ctx).Conv()
+	succeeded := conv.Succeed()
+
+	dbName, _ := doc.Get("$db").(string)
+	if dbName == "" {
+		dbName = "admin"
+	}
+
+	res, err := h.saslContinueSCRAM(ctx, conv, payload)
+	if err != nil {
+		h.auditAuthenticate(ctx, conv.Username(), dbName, conv.Mechanism(), err)
+		h.recordSCRAMFailure(ctx, conv.Username(), err)
+
+		return nil, err
+	}
+
+	if !succeeded && conv.Succeed() {
+		h.auditAuthenticate(ctx, conv.Username(), dbName, conv.Mechanism(), nil)
+		h.lockout.Success(conv.Username())
+	}
+
+	return res, nil
+}
+
+// saslContinueSCRAM continues and finishes SCRAM conversation.
+func (h *Handler) saslContinueSCRAM(ctx context.Context, conv *scram.Conv, payload wirebson.Binary) (*wirebson.Document, error) { //nolint:lll // for readability
 	steps := conninfo.Get(ctx).DecrementSteps()
 
 	if conv == nil || steps < 0 {
@@ -100,8 +132,7 @@ func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wi
 	authMsg, clientProof, err := conv.ClientFinal(string(payload.B))
 	h.L.DebugContext(
 		ctx, "saslContinue: client final",
-		slog.String("payload", string(payload.B)), slog.String("username", username),
-		slog.String("auth_msg", authMsg), slog.String("client_proof", clientProof), logging.Error(err),
+		slog.String("username", username), logging.Error(err),
 	)
 	if err != nil {
 		conninfo.Get(ctx).SetConv(nil)
@@ -115,30 +146,45 @@ func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wi
 
 	var res wirebson.RawDocument
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
-		res, err = documentdb_api_internal.AuthenticateWithScramSha256(ctx, conn, h.L, username, authMsg, clientProof)
-		return err
-	})
-	if err != nil {
-		conninfo.Get(ctx).SetConv(nil)
-		return nil, lazyerrors.Error(err)
+	if conv.Mechanism() == scram.SHA1 {
+		var creds *scram.Credentials
+		if creds, err = h.getSHA1Credentials(ctx, username); err != nil {
+			conninfo.Get(ctx).SetConv(nil)
+			return nil, lazyerrors.Error(err)
+		}
+
+		// credentials could be removed after saslStart
+		if creds == nil {
+			conninfo.Get(ctx).SetConv(nil)
+
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrAuthenticationFailed,
+				"Authentication failed.",
+				"saslContinue",
+			)
+		}
+
+		res = creds.AuthenticateSHA1(authMsg, clientProof)
+	} else {
+		err = h.Pool.WithConn(ctx, func(conn *pgx.Conn) error {
+			res, err = documentdb_api_internal.AuthenticateWithScramSha256(ctx, conn, h.L, username, authMsg, clientProof)
+			return err
+		})
+		if err != nil {
+			conninfo.Get(ctx).SetConv(nil)
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
-	resDoc, err := res.DecodeDeep()
-	h.L.DebugContext(
-		ctx, "saslContinue: authentication",
-		slog.Any("res", logging.LazyString(resDoc.LogMessage)), logging.Error(err),
-	)
+	_, err = res.DecodeDeep()
+	h.L.DebugContext(ctx, "saslContinue: authentication", logging.Error(err))
 	if err != nil {
 		conninfo.Get(ctx).SetConv(nil)
 		return nil, lazyerrors.Error(err)
 	}
 
 	payloadS, err := conv.ServerFinal(res)
-	h.L.DebugContext(
-		ctx, "saslContinue: server final",
-		slog.String("payload", payloadS), logging.Error(err),
-	)
+	h.L.DebugContext(ctx, "saslContinue: server final", logging.Error(err))
 	if err != nil {
 		conninfo.Get(ctx).SetConv(nil)
 
@@ -156,3 +202,14 @@ func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wi
 		"ok", float64(1),
 	), nil
 }
+
+// recordSCRAMFailure records failed SCRAM authentication for brute-force protection.
+// Errors other than authentication failures (for example, protocol errors) are not recorded.
+func (h *Handler) recordSCRAMFailure(ctx context.Context, username string, err error) {
+	var mErr *mongoerrors.Error
+	if !errors.As(err, &mErr) || mErr.Code != int32(mongoerrors.ErrAuthenticationFailed) {
+		return
+	}
+
+	h.lockout.Failure(username, conninfo.Get(ctx).Peer.Addr())
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
@@ -29,6 +30,8 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/oidc"
+	"github.com/FerretDB/FerretDB/v2/internal/util/plain"
 	"github.com/FerretDB/FerretDB/v2/internal/util/scram"
 )
 
@@ -50,7 +53,12 @@ func (h *Handler) MsgSASLStart(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
-	res, err := h.saslStart(connCtx, doc)
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.saslStart(connCtx, doc, dbName)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -60,9 +68,9 @@ func (h *Handler) MsgSASLStart(connCtx context.Context, msg *wire.OpMsg) (*wire.
 	return wire.NewOpMsg(res)
 }
 
-// saslStart starts SCRAM conversation.
+// saslStart starts SCRAM or MONGODB-OIDC conversation, or performs PLAIN authentication.
 // It returns the document containing authentication payload used for the response.
-func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wirebson.Document, error) {
+func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document, dbName string) (*wirebson.Document, error) {
 	if !h.Auth {
 		h.L.WarnContext(ctx, "saslStart is called when authentication is disabled")
 	}
@@ -72,18 +80,43 @@ func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wireb
 		return nil, lazyerrors.Error(err)
 	}
 
-	if mechanism != "SCRAM-SHA-256" {
+	if mechanism == plain.Mechanism && h.PLAINVerifier != nil {
+		return h.saslStartPLAIN(ctx, doc, dbName)
+	}
+
+	if mechanism == oidc.Mechanism && h.OIDCVerifier != nil {
+		return h.saslStartOIDC(ctx, doc, dbName)
+	}
+
+	if mechanism != scram.SHA1 && mechanism != scram.SHA256 {
 		msg := fmt.Sprintf(
 			"Received authentication for mechanism %s which is not enabled",
 			mechanism,
 		)
 
-		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrMechanismUnavailable, msg, "mechanism")
+		err = mongoerrors.NewWithArgument(mongoerrors.ErrMechanismUnavailable, msg, "mechanism")
+		h.auditAuthenticate(ctx, "", dbName, mechanism, err)
+
+		return nil, err
+	}
+
+	res, username, err := h.saslStartSCRAM(ctx, doc, mechanism)
+	if err != nil {
+		h.auditAuthenticate(ctx, username, dbName, mechanism, err)
+		h.recordSCRAMFailure(ctx, username, err)
+
+		return nil, err
 	}
 
+	return res, nil
+}
+
+// saslStartSCRAM starts SCRAM conversation.
+// It also returns the username sent by the client, or empty string if it was not parsed.
+func (h *Handler) saslStartSCRAM(ctx context.Context, doc *wirebson.Document, mechanism string) (*wirebson.Document, string, error) { //nolint:lll // for readability
 	payload, err := getRequiredParam[wirebson.Binary](doc, "payload")
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, "", lazyerrors.Error(err)
 	}
 
 	optionsV, err := getOptionalParamAny(doc, "options", wirebson.MustDocument())
@@ -91,12 +124,12 @@ func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wireb
 	optionsDoc, ok := optionsV.(wirebson.AnyDocument)
 	if !ok {
 		msg := fmt.Sprintf("BSON field 'saslStart.options' is the wrong type '%T', expected type 'object'", optionsV)
-		return nil, lazyerrors.Error(mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "options"))
+		return nil, "", lazyerrors.Error(mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "options"))
 	}
 
 	options, err := optionsDoc.Decode()
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, "", lazyerrors.Error(err)
 	}
 
 	skipEmptyExchange, err := getOptionalParam(options, "skipEmptyExchange", false)
@@ -106,7 +139,7 @@ func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wireb
 			slog.String("options", optionsDoc.LogMessage()), logging.Error(err),
 		)
 
-		return nil, mongoerrors.NewWithArgument(
+		return nil, "", mongoerrors.NewWithArgument(
 			mongoerrors.ErrAuthenticationFailed,
 			"Authentication failed.",
 			"saslStart",
@@ -121,28 +154,71 @@ func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wireb
 
 	conninfo.Get(ctx).SetSteps(steps)
 
-	conv := scram.NewConv(h.L)
+	conv := scram.NewConv(mechanism, h.L)
 	username, err := conv.ClientFirst(string(payload.B))
 	h.L.DebugContext(
 		ctx, "saslStart: client first",
-		slog.String("payload", string(payload.B)), slog.String("username", username), logging.Error(err),
+		slog.String("username", username), logging.Error(err),
 	)
 	if err != nil {
-		return nil, mongoerrors.NewWithArgument(
+		return nil, username, mongoerrors.NewWithArgument(
 			mongoerrors.ErrAuthenticationFailed,
 			"Authentication failed.",
 			"saslStart",
 		)
 	}
 
-	var res wirebson.RawDocument
+	if h.lockout.Blocked(username, conninfo.Get(ctx).Peer.Addr()) {
+		h.L.WarnContext(ctx, "saslStart: attempt rejected by brute-force protection", slog.String("username", username))
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
-		res, err = documentdb_api_internal.ScramSha256GetSaltAndIterations(ctx, conn, h.L, username)
-		return err
-	})
+		return nil, username, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"Authentication failed.",
+			"saslStart",
+		)
+	}
+
+	mechanisms, err := h.getUserMechanisms(ctx, username)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, username, lazyerrors.Error(err)
+	}
+
+	if mechanisms != nil && !slices.Contains(mechanisms, mechanism) {
+		msg := fmt.Sprintf(
+			"Unable to use %s based authentication for user without any %s credentials registered",
+			mechanism, mechanism,
+		)
+
+		return nil, username, mongoerrors.NewWithArgument(mongoerrors.ErrMechanismUnavailable, msg, "mechanism")
+	}
+
+	var res wirebson.RawDocument
+
+	if mechanism == scram.SHA1 {
+		var creds *scram.Credentials
+		if creds, err = h.getSHA1Credentials(ctx, username); err != nil {
+			return nil, username, lazyerrors.Error(err)
+		}
+
+		if creds == nil {
+			h.L.DebugContext(ctx, "saslStart: no SCRAM-SHA-1 credentials", slog.String("username", username))
+
+			return nil, username, mongoerrors.NewWithArgument(
+				mongoerrors.ErrAuthenticationFailed,
+				"Authentication failed.",
+				"saslStart",
+			)
+		}
+
+		res = creds.SaltAndIterations()
+	} else {
+		err = h.Pool.WithConn(ctx, func(conn *pgx.Conn) error {
+			res, err = documentdb_api_internal.ScramSha256GetSaltAndIterations(ctx, conn, h.L, username)
+			return err
+		})
+		if err != nil {
+			return nil, username, lazyerrors.Error(err)
+		}
 	}
 
 	resDoc, err := res.DecodeDeep()
@@ -151,16 +227,13 @@ func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wireb
 		slog.Any("res", logging.LazyString(resDoc.LogMessage)), logging.Error(err),
 	)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, username, lazyerrors.Error(err)
 	}
 
 	payloadS, err := conv.ServerFirst(res)
-	h.L.DebugContext(
-		ctx, "saslStart: server first",
-		slog.String("payload", payloadS), logging.Error(err),
-	)
+	h.L.DebugContext(ctx, "saslStart: server first", logging.Error(err))
 	if err != nil {
-		return nil, mongoerrors.NewWithArgument(
+		return nil, username, mongoerrors.NewWithArgument(
 			mongoerrors.ErrAuthenticationFailed,
 			"Authentication failed.",
 			"saslStart",
@@ -175,5 +248,5 @@ func (h *Handler) saslStart(ctx context.Context, doc *wirebson.Document) (*wireb
 		"conversationId", int32(1),
 		"done", false,
 		"payload", wirebson.Binary{B: []byte(payloadS)},
-	), nil
+	), username, nil
 }
@@ -79,13 +79,22 @@ func (h *Handler) MsgServerStatus(connCtx context.Context, msg *wire.OpMsg) (*wi
 		must.NoError(buildEnvironment.Add(k, info.BuildEnvironment[k]))
 	}
 
+	lockoutStats := h.lockout.Stats()
+	cursorStats := h.Pool.CursorStats()
+
 	state := h.StateProvider.Get()
 	uptime := time.Since(state.Start)
 
+	// report mongos-like deployment like `hello` does
+	process := "mongos"
+	if h.ReplSetName != "" {
+		process = filepath.Base(exec)
+	}
+
 	res := must.NotFail(wirebson.NewDocument(
 		"host", host,
 		"version", info.MongoDBVersion,
-		"process", filepath.Base(exec),
+		"process", process,
 		"pid", int64(os.Getpid()),
 		"uptime", uptime.Seconds(),
 		"uptimeMillis", uptime.Milliseconds(),
@@ -96,6 +105,23 @@ func (h *Handler) MsgServerStatus(connCtx context.Context, msg *wire.OpMsg) (*wi
 		)),
 		"metrics", must.NotFail(wirebson.NewDocument(
 			"commands", metricsDoc,
+			"cursor", must.NotFail(wirebson.NewDocument(
+				"timedOut", cursorStats.TimedOut,
+				"totalOpened", cursorStats.TotalOpened,
+				"open", must.NotFail(wirebson.NewDocument(
+					"noTimeout", int64(cursorStats.NoTimeout),
+					"pinned", int64(cursorStats.Pinned),
+					"total", int64(cursorStats.Open),
+				)),
+			)),
+		)),
+		"authLockout", must.NotFail(wirebson.NewDocument(
+			"enabled", h.lockout.Enabled(),
+			"lockedUsers", int64(lockoutStats.LockedUsers),
+			"lockedAddresses", int64(lockoutStats.LockedAddresses),
+			"failedAttempts", lockoutStats.FailedAttempts,
+			"lockouts", lockoutStats.Lockouts,
+			"rejectedAttempts", lockoutStats.RejectedAttempts,
 		)),
 		"catalogStats", must.NotFail(wirebson.NewDocument(
 			"collections", int32(0),
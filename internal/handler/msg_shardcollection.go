@@ -0,0 +1,106 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgShardCollection implements `shardCollection` command.
+//
+// Collections are distributed by DocumentDB on Citus workers.
+// FerretDB itself routes all queries like mongos does,
+// so `hello` and `serverStatus` report a mongos-like deployment
+// (unless the replica set name is configured) regardless of sharding.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgShardCollection(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	ns, err := getShardingNamespaceParam(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := h.Pool.Acquire(connCtx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer conn.Release()
+
+	if _, err = documentdb_api.ShardCollection1(connCtx, conn.Conn(), h.L, spec); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wire.MustOpMsg(
+		"collectionsharded", ns,
+		"ok", float64(1),
+	), nil
+}
+
+// getShardingNamespaceParam returns the namespace of the collection from the command's first field.
+// It also checks that the sharding command is run against the `admin` database.
+func getShardingNamespaceParam(doc *wirebson.Document) (string, error) {
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return "", err
+	}
+
+	if dbName != "admin" {
+		return "", mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			fmt.Sprintf("%s may only be run against the admin database.", command),
+			command,
+		)
+	}
+
+	ns, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return "", err
+	}
+
+	if db, coll, ok := strings.Cut(ns, "."); !ok || db == "" || coll == "" {
+		return "", mongoerrors.NewWithArgument(
+			mongoerrors.ErrInvalidNamespace,
+			fmt.Sprintf("Invalid namespace specified '%s'", ns),
+			command,
+		)
+	}
+
+	return ns, nil
+}
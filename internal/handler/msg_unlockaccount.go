@@ -0,0 +1,108 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgUnlockAccount implements FerretDB-specific `unlockAccount` command.
+//
+// It unlocks the username given by the `user` field and/or the peer address given by the `address` field
+// that were locked out by brute-force protection.
+// If neither is given, all usernames and addresses are unlocked.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgUnlockAccount(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := doc.Command()
+
+	if err = checkAdminDB(doc, command); err != nil {
+		return nil, err
+	}
+
+	username, err := getOptionalParam(doc, "user", "")
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := getOptionalParam(doc, "address", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var addr netip.Addr
+
+	if address != "" {
+		if addr, err = netip.ParseAddr(address); err != nil {
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrBadValue,
+				fmt.Sprintf("Invalid address %q", address),
+				command,
+			)
+		}
+	}
+
+	var users, addrs int
+
+	switch {
+	case username == "" && address == "":
+		users, addrs = h.lockout.UnlockAll()
+
+	default:
+		if username != "" && h.lockout.UnlockUser(username) {
+			users++
+		}
+
+		if address != "" && h.lockout.UnlockAddr(addr) {
+			addrs++
+		}
+	}
+
+	h.L.InfoContext(
+		connCtx, "Unlocking accounts locked out by brute-force protection",
+		slog.String("by", conninfo.Get(connCtx).Username()),
+		slog.String("username", username), slog.String("address", address),
+		slog.Int("users", users), slog.Int("addresses", addrs),
+	)
+
+	return wire.MustOpMsg(
+		"unlockedUsers", int32(users),
+		"unlockedAddresses", int32(addrs),
+		"ok", float64(1),
+	), nil
+}
@@ -30,7 +30,7 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgUpdate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-	opID := h.operations.Start("update")
+	connCtx, opID := h.startOperation(connCtx, "update")
 	defer h.operations.Stop(opID)
 
 	spec, seq := msg.RawSections()
@@ -55,7 +55,7 @@ func (h *Handler) MsgUpdate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 
 	var res wirebson.RawDocument
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+	err = h.Pool.WithConn(connCtx, func(conn *pgx.Conn) error {
 		res, _, err = documentdb_api.Update(connCtx, conn, h.L, dbName, spec, seq)
 		return err
 	})
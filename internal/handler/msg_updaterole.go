@@ -0,0 +1,95 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// MsgUpdateRole implements `updateRole` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgUpdateRole(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	spec, err := msg.RawDocument()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, spec); err != nil {
+		return nil, err
+	}
+
+	doc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	roleName, err := getRequiredParam[string](doc, "updateRole")
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	privilegesV, rolesV := doc.Get("privileges"), doc.Get("roles")
+	if privilegesV == nil && rolesV == nil {
+		msg := "Must specify at least one field to update in updateRole"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "updateRole")
+	}
+
+	role, err := h.getUserDefinedRole(connCtx, "updateRole", rbac.RoleName{Role: roleName, DB: dbName})
+	if err != nil {
+		return nil, err
+	}
+
+	if privilegesV != nil {
+		if role.Privileges, err = rbac.ParsePrivileges(privilegesV); err != nil {
+			return nil, err
+		}
+	}
+
+	if rolesV != nil {
+		if role.Roles, err = rbac.ParseRoleNames(rolesV, dbName); err != nil {
+			return nil, err
+		}
+
+		if err = h.checkRolesExist(connCtx, role.Roles); err != nil {
+			return nil, err
+		}
+
+		if err = h.checkRolesGrantable(connCtx, "updateRole", role.Roles); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = validateRole(dbName, role.Privileges, role.Roles); err != nil {
+		return nil, err
+	}
+
+	if err = h.updateRole(connCtx, "updateRole", role); err != nil {
+		return nil, err
+	}
+
+	return wire.MustOpMsg("ok", float64(1)), nil
+}
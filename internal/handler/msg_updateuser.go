@@ -16,14 +16,20 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/scram"
 )
 
 // MsgUpdateUser implements `updateUser` command.
@@ -57,10 +63,6 @@ func (h *Handler) MsgUpdateUser(connCtx context.Context, msg *wire.OpMsg) (*wire
 		must.NoError(updateSpec.Add("customData", customData))
 	}
 
-	if roles := doc.Get("roles"); roles != nil {
-		must.NoError(updateSpec.Add("roles", roles))
-	}
-
 	if userPassword := doc.Get("pwd"); userPassword != nil {
 		must.NoError(updateSpec.Add("pwd", userPassword))
 	}
@@ -69,10 +71,6 @@ func (h *Handler) MsgUpdateUser(connCtx context.Context, msg *wire.OpMsg) (*wire
 		must.NoError(updateSpec.Add("authenticationRestrictions", authRestrictions))
 	}
 
-	if mechanisms := doc.Get("mechanisms"); mechanisms != nil {
-		must.NoError(updateSpec.Add("mechanisms", mechanisms))
-	}
-
 	if passwordDigestor := doc.Get("passwordDigestor"); passwordDigestor != nil {
 		must.NoError(updateSpec.Add("passwordDigestor", passwordDigestor))
 	}
@@ -82,18 +80,133 @@ func (h *Handler) MsgUpdateUser(connCtx context.Context, msg *wire.OpMsg) (*wire
 		return nil, err
 	}
 
-	must.NoError(updateSpec.Add("$db", dbName))
+	var roles []rbac.RoleName
 
-	var res wirebson.RawDocument
+	// roles are stored by FerretDB, see MsgCreateUser
+	rolesV := doc.Get("roles")
+	if rolesV != nil {
+		if roles, err = rbac.ParseRoleNames(rolesV, dbName); err != nil {
+			return nil, err
+		}
+
+		if err = h.checkRolesExist(connCtx, roles); err != nil {
+			return nil, err
+		}
+
+		if err = h.checkRolesGrantable(connCtx, "updateUser", roles); err != nil {
+			return nil, err
+		}
+	}
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
-		// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/859
-		res, err = documentdb_api.UpdateUser(connCtx, conn, h.L, must.NotFail(updateSpec.Encode()))
-		return err
-	})
+	if dbName == externalDB {
+		return h.updateExternalUser(connCtx, doc, user, roles)
+	}
+
+	if rolesV != nil {
+		if _, err = h.getExistingUserRoleNames(connCtx, "updateUser", user, dbName); err != nil {
+			return nil, err
+		}
+	}
+
+	// DocumentDB always stores SCRAM-SHA-256 credentials, other mechanisms are handled by FerretDB
+	mechanisms, err := getMechanismsParam(doc, "updateUser")
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, err
+	}
+
+	creds, updateCreds, err := h.getUpdatedUserCredentials(connCtx, doc, user, dbName, mechanisms)
+	if err != nil {
+		return nil, err
+	}
+
+	res := must.NotFail(wirebson.MustDocument("ok", float64(1)).Encode())
+
+	// skip DocumentDB if there is nothing else to update there
+	if updateSpec.Len() > 1 || (rolesV == nil && mechanisms == nil) {
+		must.NoError(updateSpec.Add("$db", dbName))
+
+		err = h.Pool.WithConn(connCtx, func(conn *pgx.Conn) error {
+			// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/859
+			res, err = documentdb_api.UpdateUser(connCtx, conn, h.L, must.NotFail(updateSpec.Encode()))
+			return err
+		})
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if rolesV != nil {
+		if err = h.setUserRoleNames(connCtx, user, roles); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if updateCreds {
+		if err = h.setUserCredentials(connCtx, user, creds); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	return wire.NewOpMsg(res)
 }
+
+// getUpdatedUserCredentials returns credentials of the user to be stored by FerretDB after `updateUser`.
+// It returns false if they should not be changed.
+//
+// Like MongoDB, mechanisms could be changed without a new password
+// only if they are a subset of existing mechanisms.
+func (h *Handler) getUpdatedUserCredentials(ctx context.Context, doc *wirebson.Document, user, dbName string, mechanisms []string) (*documentdb.UserCredentials, bool, error) { //nolint:lll // for readability
+	pwd, hasPwd := doc.Get("pwd").(string)
+	if !hasPwd && mechanisms == nil {
+		return nil, false, nil
+	}
+
+	current, err := h.getUserMechanisms(ctx, user)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if current == nil {
+		msg := fmt.Sprintf("Could not find user %q for db %q", user, dbName)
+		return nil, false, mongoerrors.NewWithArgument(mongoerrors.ErrUserNotFound, msg, "updateUser")
+	}
+
+	if mechanisms == nil {
+		mechanisms = current
+	}
+
+	if hasPwd {
+		creds, err := newUserCredentials(user, pwd, mechanisms)
+		if err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		return creds, true, nil
+	}
+
+	for _, m := range mechanisms {
+		if !slices.Contains(current, m) {
+			msg := "mechanisms field must be a subset of previously set mechanisms"
+			return nil, false, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "updateUser")
+		}
+	}
+
+	if slices.Equal(mechanisms, defaultMechanisms) {
+		return nil, true, nil
+	}
+
+	existing, err := h.Pool.GetUserCredentials(ctx, user)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	creds := &documentdb.UserCredentials{
+		Mechanisms: mechanisms,
+	}
+
+	if existing != nil && slices.Contains(mechanisms, scram.SHA1) {
+		creds.SCRAMSHA1 = existing.SCRAMSHA1
+	}
+
+	return creds, true, nil
+}
@@ -22,7 +22,9 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
 // MsgUsersInfo implements `usersInfo` command.
@@ -38,9 +40,18 @@ func (h *Handler) MsgUsersInfo(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
+	specDoc, err := spec.DecodeDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if dbName, _ := specDoc.Get("$db").(string); dbName == externalDB {
+		return h.externalUsersInfo(connCtx, specDoc)
+	}
+
 	var res wirebson.RawDocument
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+	err = h.Pool.WithConn(connCtx, func(conn *pgx.Conn) error {
 		res, err = documentdb_api.UsersInfo(connCtx, conn, h.L, spec)
 		return err
 	})
@@ -48,5 +59,33 @@ func (h *Handler) MsgUsersInfo(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
-	return wire.NewOpMsg(res)
+	doc, err := res.DecodeDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// replace DocumentDB roles and mechanisms with ones stored by FerretDB, see MsgCreateUser
+	if users, _ := doc.Get("users").(*wirebson.Array); users != nil {
+		for v := range users.Values() {
+			user, _ := v.(*wirebson.Document)
+			if user == nil {
+				continue
+			}
+
+			username, _ := user.Get("user").(string)
+
+			var names []rbac.RoleName
+			if names, _, err = h.getUserRoleNames(connCtx, username); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			must.NoError(user.Replace("roles", rbac.RoleNamesArray(names)))
+
+			if err = h.replaceUserCredentials(connCtx, user, username); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+	}
+
+	return wire.NewOpMsg(must.NotFail(doc.Encode()))
 }
@@ -0,0 +1,152 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/oidc"
+)
+
+// saslStartOIDC starts MONGODB-OIDC conversation.
+//
+// If the payload contains the access token, the connection is authenticated immediately
+// (that's what drivers do for machine workflows and cached tokens).
+// Otherwise, the identity provider information is returned, and the token is expected in `saslContinue`.
+func (h *Handler) saslStartOIDC(ctx context.Context, doc *wirebson.Document, dbName string) (*wirebson.Document, error) {
+	if dbName != externalDB {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrProtocolError,
+			"MONGODB-OIDC authentication must always use the $external database.",
+			"saslStart",
+		)
+	}
+
+	payload, err := getRequiredParam[wirebson.Binary](doc, "payload")
+	if err != nil {
+		return nil, err
+	}
+
+	step, err := decodeOIDCPayload(payload.B, "saslStart")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := step.Get("jwt").(string); ok {
+		return h.authenticateOIDC(ctx, step, "saslStart")
+	}
+
+	conninfo.Get(ctx).SetPendingMechanism(oidc.Mechanism)
+
+	info := must.NotFail(wirebson.NewDocument("issuer", h.OIDCVerifier.Issuer()))
+	if clientID := h.OIDCVerifier.ClientID(); clientID != "" {
+		must.NoError(info.Add("clientId", clientID))
+	}
+
+	return wirebson.MustDocument(
+		"conversationId", int32(1),
+		"done", false,
+		"payload", wirebson.Binary{B: must.NotFail(info.Encode())},
+	), nil
+}
+
+// saslContinueOIDC finishes MONGODB-OIDC conversation started by saslStartOIDC.
+func (h *Handler) saslContinueOIDC(ctx context.Context, payload wirebson.Binary) (*wirebson.Document, error) {
+	step, err := decodeOIDCPayload(payload.B, "saslContinue")
+	if err != nil {
+		conninfo.Get(ctx).SetConv(nil)
+		return nil, err
+	}
+
+	res, err := h.authenticateOIDC(ctx, step, "saslContinue")
+	if err != nil {
+		conninfo.Get(ctx).SetConv(nil)
+		return nil, err
+	}
+
+	must.NoError(res.Add("ok", float64(1)))
+
+	return res, nil
+}
+
+// authenticateOIDC verifies the access token from the client's payload and authenticates the connection.
+//
+// Users are not required to exist in the `$external` database;
+// groups from the token are mapped to roles in the `admin` database, see getConnPrivileges.
+func (h *Handler) authenticateOIDC(ctx context.Context, step *wirebson.Document, command string) (*wirebson.Document, error) {
+	token, _ := step.Get("jwt").(string)
+
+	identity, err := h.OIDCVerifier.Verify(token)
+	if err != nil {
+		h.L.DebugContext(ctx, command+": invalid OIDC token", logging.Error(err))
+
+		err = mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"Authentication failed.",
+			command,
+		)
+		h.auditAuthenticate(ctx, "", externalDB, oidc.Mechanism, err)
+
+		return nil, err
+	}
+
+	ci := conninfo.Get(ctx)
+	ci.SetExternalUser(identity.Username, oidc.Mechanism, identity.Groups)
+	ci.SetExpires(identity.Expires)
+
+	h.auditAuthenticate(ctx, identity.Username, externalDB, oidc.Mechanism, nil)
+
+	h.L.DebugContext(
+		ctx, command+": MONGODB-OIDC passed",
+		slog.String("username", identity.Username), slog.String("groups", strings.Join(identity.Groups, ",")),
+		slog.Time("expires", identity.Expires),
+	)
+
+	return wirebson.MustDocument(
+		"conversationId", int32(1),
+		"done", true,
+		"payload", wirebson.Binary{},
+	), nil
+}
+
+// decodeOIDCPayload decodes BSON document sent by the client in the MONGODB-OIDC conversation.
+func decodeOIDCPayload(b []byte, command string) (*wirebson.Document, error) {
+	var doc *wirebson.Document
+	var err error
+
+	if len(b) > 0 {
+		doc, err = wirebson.RawDocument(b).Decode()
+	} else {
+		doc = wirebson.MakeDocument(0)
+	}
+
+	if err != nil {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrBadValue,
+			"Invalid MONGODB-OIDC payload",
+			command,
+		)
+	}
+
+	return doc, nil
+}
@@ -16,10 +16,13 @@
 package operation
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/FerretDB/wire/wirebson"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
 )
 
@@ -28,19 +31,42 @@ type Operation struct {
 	// the order of the fields is weird to reduce size
 	Command       *wirebson.Document
 	CurrentOpTime time.Time
+	Conn          *conninfo.ConnInfo // client connection that started the operation
+	cancel        context.CancelCauseFunc
+	backend       *backend
 	token         *resource.Token
 	Op            string
 	DB            string
 	Collection    string
+	Username      string // empty if authentication is disabled
+	UserID        string // see [conninfo.ConnInfo.UserID]; empty if authentication is disabled
+	PlanSummary   string // empty if unknown
+	BackendPID    uint32 // PostgreSQL backend PID of the held connection, 0 if none
 	OpID          int32
 	Active        bool
+	Killed        bool
+}
+
+// backend stores PostgreSQL backend PID of the connection held by the operation
+// and the function that cancels the query running on it.
+//
+// It is shared by copies of [Operation].
+type backend struct {
+	m      sync.Mutex
+	cancel func(context.Context) error // nil if the operation does not hold a connection
+	pid    uint32
 }
 
 // newOperation creates a new operation.
-func newOperation(id int32, op string) *Operation {
+func newOperation(id int32, op string, ci *conninfo.ConnInfo, cancel context.CancelCauseFunc) *Operation {
 	o := &Operation{
+		Conn:          ci,
+		cancel:        cancel,
+		backend:       new(backend),
 		token:         resource.NewToken(),
 		Op:            op,
+		Username:      ci.Username(),
+		UserID:        ci.UserID(),
 		Active:        true,
 		CurrentOpTime: time.Now(),
 		OpID:          id,
@@ -51,7 +77,8 @@ func newOperation(id int32, op string) *Operation {
 	return o
 }
 
-// close untracks the operation.
+// close cancels the operation's context and untracks the operation.
 func (o *Operation) close() {
+	o.cancel(context.Canceled)
 	resource.Untrack(o, o.token)
 }
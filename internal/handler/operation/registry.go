@@ -16,6 +16,7 @@ package operation
 
 import (
 	"cmp"
+	"context"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -23,13 +24,16 @@ import (
 	"github.com/FerretDB/wire/wirebson"
 	"golang.org/x/exp/maps"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/util/redact"
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
 )
 
-// Registry stores operations.
+// Registry stores operations and client connections.
 type Registry struct {
 	rw         sync.RWMutex
 	operations map[int32]*Operation
+	conns      map[int64]*conninfo.ConnInfo
 
 	nextOperationID atomic.Int32
 
@@ -40,6 +44,7 @@ type Registry struct {
 func NewRegistry() *Registry {
 	res := &Registry{
 		operations: map[int32]*Operation{},
+		conns:      map[int64]*conninfo.ConnInfo{},
 		token:      resource.NewToken(),
 	}
 
@@ -48,17 +53,20 @@ func NewRegistry() *Registry {
 	return res
 }
 
-// Start starts a new operation and returns the operation ID.
-func (r *Registry) Start(op string) int32 {
+// Start starts a new operation of the given client connection and returns the operation ID
+// and a derived context that is canceled by [Registry.Kill] or [Registry.Stop].
+func (r *Registry) Start(ctx context.Context, op string, ci *conninfo.ConnInfo) (context.Context, int32) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
 	id := r.nextOperationID.Add(1)
-	o := newOperation(id, op)
+	o := newOperation(id, op, ci, cancel)
 
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
 	r.operations[id] = o
 
-	return id
+	return ctx, id
 }
 
 // Stop ends an operation.
@@ -73,10 +81,103 @@ func (r *Registry) Stop(id int32) {
 	o.close()
 }
 
+// SetBackend sets PostgreSQL backend PID of the connection held by the given operation
+// and the function that cancels the query running on it.
+// Zero PID and nil function mean that the operation does not hold a connection.
+//
+// It waits for the cancellation started by [Registry.Kill] to finish,
+// so the connection is not released while it could be canceled.
+//
+// If the operation does not exist, it does nothing.
+func (r *Registry) SetBackend(id int32, pid uint32, cancel func(context.Context) error) {
+	r.rw.Lock()
+
+	o, ok := r.operations[id]
+	if !ok {
+		r.rw.Unlock()
+		return
+	}
+
+	o.BackendPID = pid
+	b := o.backend
+
+	r.rw.Unlock()
+
+	b.m.Lock()
+	b.pid, b.cancel = pid, cancel
+	b.m.Unlock()
+}
+
+// SetPlanSummary sets the plan summary of the given operation.
+//
+// If the operation does not exist, it does nothing.
+func (r *Registry) SetPlanSummary(id int32, planSummary string) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	o, ok := r.operations[id]
+	if !ok {
+		return
+	}
+
+	o.PlanSummary = planSummary
+}
+
+// Get returns the operation with the given ID.
+func (r *Registry) Get(id int32) (Operation, bool) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	o, ok := r.operations[id]
+	if !ok {
+		return Operation{}, false
+	}
+
+	return *o, true
+}
+
+// Kill cancels the context of the given operation with the given cause,
+// then cancels the query running on the PostgreSQL backend of the connection it holds, if any.
+// The operation is removed from the registry by [Registry.Stop] as usual.
+//
+// The query is canceled while the operation still holds the connection (see [Registry.SetBackend]),
+// because it might not check the context.
+// The returned error is the error of that cancellation.
+//
+// It returns PostgreSQL backend PID of that connection (or 0), and false if the operation does not exist.
+func (r *Registry) Kill(ctx context.Context, id int32, cause error) (uint32, bool, error) {
+	r.rw.Lock()
+
+	o, ok := r.operations[id]
+	if !ok {
+		r.rw.Unlock()
+		return 0, false, nil
+	}
+
+	o.Killed = true
+	o.cancel(cause)
+
+	b := o.backend
+
+	r.rw.Unlock()
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.cancel == nil {
+		return 0, true, nil
+	}
+
+	return b.pid, true, b.cancel(ctx)
+}
+
 // Update sets additional information of the given operation.
+// Secrets in the command document are masked.
 //
 // If the operation does not exist, it does nothing.
 func (r *Registry) Update(id int32, db, collection string, command *wirebson.Document) {
+	command = redact.Secrets(command.Command(), command)
+
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
@@ -109,6 +210,36 @@ func (r *Registry) Operations() []Operation {
 	return res
 }
 
+// AddConn adds the client connection to the registry.
+func (r *Registry) AddConn(ci *conninfo.ConnInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.conns[ci.ID] = ci
+}
+
+// RemoveConn removes the client connection from the registry.
+func (r *Registry) RemoveConn(ci *conninfo.ConnInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	delete(r.conns, ci.ID)
+}
+
+// Conns returns all client connections sorted by ID.
+func (r *Registry) Conns() []*conninfo.ConnInfo {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := maps.Values(r.conns)
+
+	slices.SortFunc(res, func(a, b *conninfo.ConnInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return res
+}
+
 // Close closes the operation registry.
 func (r *Registry) Close() {
 	r.rw.Lock()
@@ -119,6 +250,7 @@ func (r *Registry) Close() {
 	}
 
 	r.operations = nil
+	r.conns = nil
 
 	resource.Untrack(r, r.token)
 }
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/util/redact"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	t.Cleanup(r.Close)
+
+	ci1, ci2 := conninfo.New(), conninfo.New()
+
+	r.AddConn(ci2)
+	r.AddConn(ci1)
+	assert.Equal(t, []*conninfo.ConnInfo{ci1, ci2}, r.Conns())
+
+	ctx1, id1 := r.Start(context.Background(), "query", ci1)
+	ctx2, id2 := r.Start(context.Background(), "insert", ci2)
+
+	var canceled int
+	r.SetBackend(id1, 42, func(context.Context) error {
+		canceled++
+		return nil
+	})
+
+	op, ok := r.Get(id1)
+	require.True(t, ok)
+	assert.Equal(t, "query", op.Op)
+	assert.Same(t, ci1, op.Conn)
+	assert.Equal(t, uint32(42), op.BackendPID)
+	assert.False(t, op.Killed)
+
+	assert.Len(t, r.Operations(), 2)
+
+	r.Update(id2, "admin", "", wirebson.MustDocument("updateUser", "user", "pwd", "secret"))
+
+	op, ok = r.Get(id2)
+	require.True(t, ok)
+	assert.Equal(t, "admin", op.DB)
+	assert.Equal(t, redact.Placeholder, op.Command.Get("pwd"))
+
+	t.Run("Kill", func(t *testing.T) {
+		cause := errors.New("killed")
+
+		pid, ok, err := r.Kill(context.Background(), id1, cause)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, uint32(42), pid)
+		assert.Equal(t, 1, canceled)
+
+		assert.ErrorIs(t, ctx1.Err(), context.Canceled)
+		assert.Equal(t, cause, context.Cause(ctx1))
+
+		op, ok = r.Get(id1)
+		require.True(t, ok)
+		assert.True(t, op.Killed)
+
+		r.Stop(id1)
+
+		_, ok = r.Get(id1)
+		assert.False(t, ok)
+
+		_, ok, err = r.Kill(context.Background(), id1, cause)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		require.NoError(t, ctx2.Err())
+
+		r.Stop(id2)
+
+		assert.ErrorIs(t, ctx2.Err(), context.Canceled)
+		assert.Empty(t, r.Operations())
+	})
+
+	t.Run("RemoveConn", func(t *testing.T) {
+		r.RemoveConn(ci1)
+		assert.Equal(t, []*conninfo.ConnInfo{ci2}, r.Conns())
+	})
+}
+
+func TestUserID(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	t.Cleanup(r.Close)
+
+	// same-named external and global users should have different IDs
+	ext, global := conninfo.New(), conninfo.New()
+	ext.SetExternalUser("user", "PLAIN", nil)
+	global.SetUser("user")
+
+	_, extID := r.Start(context.Background(), "query", ext)
+	_, globalID := r.Start(context.Background(), "query", global)
+
+	extOp, ok := r.Get(extID)
+	require.True(t, ok)
+
+	globalOp, ok := r.Get(globalID)
+	require.True(t, ok)
+
+	assert.Equal(t, extOp.Username, globalOp.Username)
+	assert.Equal(t, "user@$external", extOp.UserID)
+	assert.Equal(t, "user@admin", globalOp.UserID)
+}
@@ -25,6 +25,24 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
 
+// genericFields contains fields that drivers could add to any command.
+var genericFields = []string{
+	"$db",
+	"$clusterTime",
+	"$readPreference",
+	"lsid",
+	"txnNumber",
+	"autocommit",
+	"startTransaction",
+	"apiVersion",
+	"apiStrict",
+	"apiDeprecationErrors",
+	"comment",
+	"maxTimeMS",
+	"readConcern",
+	"writeConcern",
+}
+
 // getRequiredParamAny returns doc's first value for the given key
 // or protocol error for missing key.
 func getRequiredParamAny(doc wirebson.AnyDocument, key string) (any, error) {
@@ -97,6 +115,24 @@ func getOptionalParam[T wirebson.ScalarType](doc wirebson.AnyDocument, key strin
 	return res, nil
 }
 
+// checkAdminDB returns protocol error if the command is not run against the `admin` database.
+func checkAdminDB(doc *wirebson.Document, command string) error {
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return err
+	}
+
+	if dbName != "admin" {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			fmt.Sprintf("%s may only be run against the admin database.", command),
+			command,
+		)
+	}
+
+	return nil
+}
+
 // getBoolParam returns bool value of v.
 // Non-zero double, long, and int values return true.
 // Zero values for those types, as well as nulls and missing fields, return false.
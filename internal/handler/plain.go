@@ -0,0 +1,105 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/plain"
+)
+
+// saslStartPLAIN authenticates the connection as the `$external` user
+// with the username and password verified by the configured [plain.Verifier].
+//
+// Users are not required to exist in the `$external` database;
+// groups returned by the verifier are mapped to roles in the `admin` database, see getConnPrivileges.
+func (h *Handler) saslStartPLAIN(ctx context.Context, doc *wirebson.Document, dbName string) (res *wirebson.Document, err error) { //nolint:lll // for readability
+	var username string
+
+	defer func() {
+		h.auditAuthenticate(ctx, username, dbName, plain.Mechanism, err)
+	}()
+
+	if dbName != externalDB {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrProtocolError,
+			"PLAIN authentication must always use the $external database.",
+			"saslStart",
+		)
+	}
+
+	ci := conninfo.Get(ctx)
+
+	// the password is sent in clear text
+	if !ci.TLS && ci.Peer.IsValid() {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"PLAIN authentication is only allowed over TLS or Unix domain socket connections",
+			"saslStart",
+		)
+	}
+
+	payload, err := getRequiredParam[wirebson.Binary](doc, "payload")
+	if err != nil {
+		return nil, err
+	}
+
+	username, password, err := plain.ParsePayload(payload.B)
+	if err != nil {
+		h.L.DebugContext(ctx, "saslStart: invalid PLAIN payload", logging.Error(err))
+
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"Authentication failed.",
+			"saslStart",
+		)
+	}
+
+	groups, err := h.PLAINVerifier.Verify(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, plain.ErrInvalidCredentials) {
+			h.L.DebugContext(ctx, "saslStart: invalid PLAIN credentials", slog.String("username", username))
+		} else {
+			h.L.WarnContext(ctx, "saslStart: PLAIN verifier failed", slog.String("username", username), logging.Error(err))
+		}
+
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrAuthenticationFailed,
+			"Authentication failed.",
+			"saslStart",
+		)
+	}
+
+	ci.SetExternalUser(username, plain.Mechanism, groups)
+
+	h.L.DebugContext(
+		ctx, "saslStart: PLAIN passed",
+		slog.String("username", username), slog.String("groups", strings.Join(groups, ",")),
+	)
+
+	return wirebson.MustDocument(
+		"conversationId", int32(1),
+		"done", true,
+		"payload", wirebson.Binary{},
+	), nil
+}
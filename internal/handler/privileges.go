@@ -0,0 +1,897 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// privilegesCacheTTL is the time resolved user privileges are cached for.
+//
+// Changes made by this FerretDB instance invalidate the cache immediately;
+// changes made by other instances become visible after that time.
+const privilegesCacheTTL = 10 * time.Second
+
+// userPrivileges represents resolved roles and privileges of the user.
+type userPrivileges struct {
+	roles      []*rbac.Role
+	privileges []rbac.Privilege
+	expires    time.Time
+}
+
+// privilegesCache caches resolved user privileges.
+type privilegesCache struct {
+	rw sync.RWMutex
+	m  map[string]*userPrivileges
+}
+
+// newPrivilegesCache creates a new privileges cache.
+func newPrivilegesCache() *privilegesCache {
+	return &privilegesCache{
+		m: map[string]*userPrivileges{},
+	}
+}
+
+// get returns cached privileges of the user, or nil.
+func (c *privilegesCache) get(username string) *userPrivileges {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	up := c.m[username]
+	if up == nil || time.Now().After(up.expires) {
+		return nil
+	}
+
+	return up
+}
+
+// set caches privileges of the user.
+func (c *privilegesCache) set(username string, up *userPrivileges) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	up.expires = time.Now().Add(privilegesCacheTTL)
+	c.m[username] = up
+}
+
+// reset clears the cache.
+//
+// It should be called after any change of users or roles.
+func (c *privilegesCache) reset() {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	clear(c.m)
+}
+
+// lookupRole returns a function that fetches user-defined roles.
+func (h *Handler) lookupRole(ctx context.Context) rbac.LookupFunc {
+	return func(name rbac.RoleName) (*rbac.Role, error) {
+		info, err := h.Pool.GetRole(ctx, name.DB, name.Role)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if info == nil {
+			return nil, nil
+		}
+
+		return roleFromInfo(info)
+	}
+}
+
+// roleFromInfo converts stored role to [rbac.Role].
+func roleFromInfo(info *documentdb.RoleInfo) (*rbac.Role, error) {
+	privileges, err := rbac.ParsePrivileges(info.Privileges)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	roles, err := rbac.ParseRoleNames(info.Roles, info.DB)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &rbac.Role{
+		Name:       rbac.RoleName{Role: info.Name, DB: info.DB},
+		Privileges: privileges,
+		Roles:      roles,
+	}, nil
+}
+
+// roleInfo converts [rbac.Role] to stored role.
+func roleInfo(role *rbac.Role) *documentdb.RoleInfo {
+	return &documentdb.RoleInfo{
+		DB:         role.Name.DB,
+		Name:       role.Name.Role,
+		Privileges: must.NotFail(rbac.PrivilegesArray(role.Privileges).Encode()),
+		Roles:      must.NotFail(rbac.RoleNamesArray(role.Roles).Encode()),
+	}
+}
+
+// getUserRoleNames returns names of roles directly granted to the user.
+//
+// Roles are taken from FerretDB's own storage. Users created by older versions
+// or directly in DocumentDB use roles stored by DocumentDB,
+// unless those are the roles FerretDB grants to all users it creates (see [documentDBUserRoles]);
+// such users get no roles instead, because they are expected to have roles in FerretDB's storage.
+// PostgreSQL superusers unknown to DocumentDB get the `root` role.
+// The second returned value is false if the user does not exist.
+func (h *Handler) getUserRoleNames(ctx context.Context, username string) ([]rbac.RoleName, bool, error) {
+	raw, ok, err := h.Pool.GetUserRoles(ctx, username)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if ok {
+		var res []rbac.RoleName
+		if res, err = rbac.ParseRoleNames(raw, "admin"); err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		return res, true, nil
+	}
+
+	user, err := h.getDocumentDBUser(ctx, username)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if user != nil {
+		var res []rbac.RoleName
+		if res, err = rbac.ParseRoleNames(user.Get("roles"), "admin"); err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		if isDocumentDBUserRoles(res) {
+			h.L.WarnContext(ctx, "User has no roles stored by FerretDB", slog.String("username", username))
+			return nil, true, nil
+		}
+
+		return res, true, nil
+	}
+
+	superuser, err := h.Pool.IsSuperuser(ctx, username)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if superuser {
+		return []rbac.RoleName{{Role: "root", DB: "admin"}}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// isDocumentDBUserRoles returns true if the given roles are [documentDBUserRoles] in any order.
+func isDocumentDBUserRoles(roles []rbac.RoleName) bool {
+	if len(roles) != len(documentDBUserRoles) {
+		return false
+	}
+
+	for _, r := range documentDBUserRoles {
+		if !slices.Contains(roles, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getDocumentDBUser returns the user document as returned by DocumentDB's `usersInfo`,
+// or nil if the user does not exist.
+func (h *Handler) getDocumentDBUser(ctx context.Context, username string) (*wirebson.Document, error) {
+	spec := must.NotFail(wirebson.MustDocument(
+		"usersInfo", username,
+		"$db", "admin",
+	).Encode())
+
+	var res wirebson.RawDocument
+
+	err := h.Pool.WithConn(ctx, func(conn *pgx.Conn) error {
+		var err error
+		res, err = documentdb_api.UsersInfo(ctx, conn, h.L, spec)
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := res.DecodeDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	users, _ := doc.Get("users").(*wirebson.Array)
+	if users == nil || users.Len() == 0 {
+		return nil, nil
+	}
+
+	user, _ := users.Get(0).(*wirebson.Document)
+
+	return user, nil
+}
+
+// getUserPrivileges returns resolved roles and privileges of the user
+// with the given key in FerretDB's roles storage, see [userRolesKey].
+//
+// Groups provided by the external identity source are mapped to roles in the `admin` database.
+func (h *Handler) getUserPrivileges(ctx context.Context, key string, groups []string) (*userPrivileges, error) {
+	cacheKey := key
+	if len(groups) > 0 {
+		cacheKey += "\x00" + strings.Join(groups, "\x00")
+	}
+
+	if up := h.privileges.get(cacheKey); up != nil {
+		return up, nil
+	}
+
+	names, _, err := h.getUserRoleNames(ctx, key)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, g := range groups {
+		if name := (rbac.RoleName{Role: g, DB: "admin"}); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	roles, privileges, err := rbac.Resolve(names, h.lookupRole(ctx))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	up := &userPrivileges{
+		roles:      roles,
+		privileges: privileges,
+	}
+	h.privileges.set(cacheKey, up)
+
+	return up, nil
+}
+
+// getConnPrivileges returns resolved roles and privileges of the user authenticated on the connection.
+func (h *Handler) getConnPrivileges(ctx context.Context) (*userPrivileges, error) {
+	ci := conninfo.Get(ctx)
+
+	if extUser, _ := ci.ExternalUser(); extUser != "" {
+		return h.getUserPrivileges(ctx, userRolesKey(extUser, externalDB), ci.ExternalGroups())
+	}
+
+	return h.getUserPrivileges(ctx, ci.Username(), nil)
+}
+
+// explainActions maps commands that could be explained to actions they require.
+var explainActions = map[string]string{
+	"aggregate":     "find",
+	"count":         "find",
+	"delete":        "remove",
+	"distinct":      "find",
+	"find":          "find",
+	"findAndModify": "update",
+	"findandmodify": "update",
+	"update":        "update",
+}
+
+// commandTarget represents an action that command performs on the database and collection.
+type commandTarget struct {
+	action     string
+	db         string
+	collection string
+}
+
+// commandTargets returns actions that the given command performs.
+func commandTargets(command string, cmd *command, doc *wirebson.Document) []commandTarget {
+	dbName, _ := doc.Get("$db").(string)
+
+	target := commandTarget{
+		action: cmd.action,
+		db:     dbName,
+	}
+
+	if !rbac.IsUserAdminAction(target.action) {
+		target.collection, _ = doc.Get(command).(string)
+	}
+
+	switch command {
+	case "aggregate":
+		if params, _ := getChangeStreamParams(doc, dbName); params != nil {
+			target.action = "changeStream"
+		}
+
+		if params, _, _ := getCurrentOpStageParams(doc, dbName); params != nil {
+			// users can always see their own operations
+			if !params.allUsers {
+				return nil
+			}
+
+			target.action = "inprog"
+		}
+
+		return append([]commandTarget{target}, pipelineTargets(dbName, doc.Get("pipeline"))...)
+
+	case "findAndModify", "findandmodify":
+		// the command returns the document, so it also requires find
+		res := []commandTarget{{action: "find", db: target.db, collection: target.collection}}
+
+		if getBoolField(doc, "remove") {
+			res = append(res, commandTarget{action: "remove", db: target.db, collection: target.collection})
+		} else {
+			res = append(res, commandTarget{action: "update", db: target.db, collection: target.collection})
+		}
+
+		if getBoolField(doc, "upsert") {
+			res = append(res, commandTarget{action: "insert", db: target.db, collection: target.collection})
+		}
+
+		return res
+
+	case "explain":
+		// explain requires privileges of the explained command
+		explained := decodeAnyDocument(doc.Get(command))
+		if explained == nil || explained.Len() == 0 {
+			break
+		}
+
+		return explainTargets(dbName, explained)
+
+	case "create":
+		// views require find on the source collection and collections read by the pipeline
+		viewOn, _ := doc.Get("viewOn").(string)
+		if viewOn == "" {
+			break
+		}
+
+		res := []commandTarget{target, {action: "find", db: dbName, collection: viewOn}}
+
+		return append(res, pipelineTargets(dbName, doc.Get("pipeline"))...)
+
+	case "update":
+		updates, _ := doc.Get("updates").(wirebson.AnyArray)
+		if updates == nil {
+			break
+		}
+
+		arr, err := updates.Decode()
+		if err != nil {
+			break
+		}
+
+		for v := range arr.Values() {
+			u := decodeAnyDocument(v)
+			if u == nil {
+				continue
+			}
+
+			if getBoolField(u, "upsert") {
+				return []commandTarget{target, {action: "insert", db: target.db, collection: target.collection}}
+			}
+		}
+
+	case "renameCollection":
+		from, _ := doc.Get(command).(string)
+		to, _ := doc.Get("to").(string)
+
+		fromDB, fromCollection := rbac.ParseNamespace(from)
+		toDB, toCollection := rbac.ParseNamespace(to)
+
+		target.db, target.collection = fromDB, fromCollection
+
+		if fromDB != toDB {
+			return []commandTarget{
+				{action: "find", db: fromDB, collection: fromCollection},
+				{action: "dropCollection", db: fromDB, collection: fromCollection},
+				{action: "insert", db: toDB, collection: toCollection},
+				{action: "createCollection", db: toDB, collection: toCollection},
+			}
+		}
+
+	case "cloneCollectionAsCapped":
+		from, _ := doc.Get(command).(string)
+		to, _ := doc.Get("toCollection").(string)
+
+		return []commandTarget{
+			{action: "find", db: dbName, collection: from},
+			{action: "insert", db: dbName, collection: to},
+			{action: "createCollection", db: dbName, collection: to},
+		}
+
+	case "shardCollection", "reshardCollection", "unshardCollection":
+		ns, _ := doc.Get(command).(string)
+		target.db, target.collection = rbac.ParseNamespace(ns)
+
+	case "currentOp":
+		// users can always see their own operations
+		if getBoolField(doc, "$ownOps") {
+			return nil
+		}
+
+	case "listDatabases":
+		// users without the action could list databases they have privileges on, see MsgListDatabases
+		authorized := getBoolField(doc, "authorizedDatabases")
+
+		if doc.Get("authorizedDatabases") == nil || authorized {
+			return nil
+		}
+
+	case "listCollections":
+		// users without the action could list names of collections they have privileges on, see MsgListCollections
+		authorized := getBoolField(doc, "authorizedCollections")
+		nameOnly := getBoolField(doc, "nameOnly")
+
+		if authorized && nameOnly {
+			return nil
+		}
+
+	case "killOp":
+		// users can always kill their own operations; killing others' is checked by the handler
+		return nil
+
+	case "dropUser", "grantRolesToUser", "revokeRolesFromUser":
+		target.db = userDB(dbName)
+
+	case "updateUser":
+		// roles are checked by the handler itself, see checkRolesGrantable
+		var res []commandTarget
+
+		for field, action := range map[string]string{
+			"pwd":        "changePassword",
+			"customData": "changeCustomData",
+			"roles":      "revokeRole",
+		} {
+			if doc.Get(field) != nil {
+				res = append(res, commandTarget{action: action, db: userDB(dbName)})
+			}
+		}
+
+		if len(res) == 0 {
+			return []commandTarget{{action: target.action, db: userDB(dbName)}}
+		}
+
+		return res
+	}
+
+	return []commandTarget{target}
+}
+
+// userDB returns the database that the existing user given by the command's `$db` belongs to.
+//
+// Users other than external ones are global, so commands that change or drop them
+// require privileges on the `admin` database whatever `$db` is.
+// Otherwise, a user administrator of any database could change the password of any user.
+func userDB(dbName string) string {
+	if dbName == externalDB {
+		return externalDB
+	}
+
+	return "admin"
+}
+
+// explainTargets returns actions that the explained command performs.
+func explainTargets(dbName string, explained *wirebson.Document) []commandTarget {
+	explained.Remove("$db")
+	must.NoError(explained.Add("$db", dbName))
+
+	name := explained.Command()
+
+	action, ok := explainActions[name]
+	if !ok {
+		action = "find"
+	}
+
+	return commandTargets(name, &command{action: action}, explained)
+}
+
+// pipelineTargets returns actions that the given aggregation pipeline performs
+// on collections other than the source one: writes by `$out` and `$merge` stages,
+// and reads by `$lookup`, `$graphLookup`, and `$unionWith` stages, including nested pipelines.
+func pipelineTargets(dbName string, v any) []commandTarget {
+	pipeline, _ := v.(wirebson.AnyArray)
+	if pipeline == nil {
+		return nil
+	}
+
+	stages, err := pipeline.Decode()
+	if err != nil {
+		return nil
+	}
+
+	var res []commandTarget
+
+	for v := range stages.Values() {
+		stage := decodeAnyDocument(v)
+		if stage == nil {
+			continue
+		}
+
+		name := stage.Command()
+		arg := stage.Get(name)
+
+		switch name {
+		case "$out":
+			db, collection := stageNamespace(dbName, arg)
+			res = append(res,
+				commandTarget{action: "insert", db: db, collection: collection},
+				commandTarget{action: "remove", db: db, collection: collection},
+			)
+
+		case "$merge":
+			if d := decodeAnyDocument(arg); d != nil {
+				arg = d.Get("into")
+			}
+
+			db, collection := stageNamespace(dbName, arg)
+			res = append(res,
+				commandTarget{action: "insert", db: db, collection: collection},
+				commandTarget{action: "update", db: db, collection: collection},
+			)
+
+		case "$lookup", "$graphLookup":
+			d := decodeAnyDocument(arg)
+			if d == nil {
+				continue
+			}
+
+			// $lookup with $documents pipeline does not read any collection
+			if from := d.Get("from"); from != nil {
+				db, collection := stageNamespace(dbName, from)
+				res = append(res, commandTarget{action: "find", db: db, collection: collection})
+			}
+
+			res = append(res, pipelineTargets(dbName, d.Get("pipeline"))...)
+
+		case "$unionWith":
+			collection, _ := arg.(string)
+
+			if d := decodeAnyDocument(arg); d != nil {
+				collection, _ = d.Get("coll").(string)
+				res = append(res, pipelineTargets(dbName, d.Get("pipeline"))...)
+			}
+
+			if collection != "" {
+				res = append(res, commandTarget{action: "find", db: dbName, collection: collection})
+			}
+
+		case "$facet":
+			d := decodeAnyDocument(arg)
+			if d == nil {
+				continue
+			}
+
+			for _, p := range d.All() {
+				res = append(res, pipelineTargets(dbName, p)...)
+			}
+		}
+	}
+
+	return res
+}
+
+// stageNamespace returns the database and collection of the aggregation stage argument
+// that is either a collection name or a `{db: <db>, coll: <collection>}` document.
+func stageNamespace(dbName string, v any) (string, string) {
+	if collection, ok := v.(string); ok {
+		return dbName, collection
+	}
+
+	d := decodeAnyDocument(v)
+	if d == nil {
+		return dbName, ""
+	}
+
+	db, _ := d.Get("db").(string)
+	if db == "" {
+		db = dbName
+	}
+
+	collection, _ := d.Get("coll").(string)
+
+	return db, collection
+}
+
+// getBoolField returns bool value of the document field like [getBoolParam],
+// or false if the field is missing or has invalid type.
+func getBoolField(doc *wirebson.Document, key string) bool {
+	v := doc.Get(key)
+	if v == nil {
+		return false
+	}
+
+	res, _ := getBoolParam(key, v)
+
+	return res
+}
+
+// decodeWithSequences returns the decoded section 0 of the message
+// with documents of kind 1 sections added as arrays named after section identifiers.
+func decodeWithSequences(msg *wire.OpMsg) (*wirebson.Document, error) {
+	doc, err := msg.RawSection0().Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, s := range msg.Sections() {
+		if s.Kind != 1 {
+			continue
+		}
+
+		arr := wirebson.MakeArray(len(s.Documents()))
+		for _, d := range s.Documents() {
+			if err = arr.Add(d); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if err = doc.Add(s.Identifier, arr); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	return doc, nil
+}
+
+// decodeAnyDocument returns the decoded document, or nil if v is not a valid document.
+func decodeAnyDocument(v any) *wirebson.Document {
+	ad, _ := v.(wirebson.AnyDocument)
+	if ad == nil {
+		return nil
+	}
+
+	d, err := ad.Decode()
+	if err != nil {
+		return nil
+	}
+
+	return d
+}
+
+// checkPrivileges returns error if the authenticated user is not allowed to execute the command.
+func (h *Handler) checkPrivileges(ctx context.Context, command string, cmd *command, msg *wire.OpMsg) error {
+	if cmd.action == "" {
+		return nil
+	}
+
+	username := conninfo.Get(ctx).Username()
+
+	doc, err := decodeWithSequences(msg)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	up, err := h.getConnPrivileges(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, t := range commandTargets(command, cmd, doc) {
+		if rbac.Allowed(up.privileges, t.action, t.db, t.collection) {
+			continue
+		}
+
+		h.L.WarnContext(
+			ctx, "checkPrivileges: not authorized",
+			slog.String("username", username), slog.String("command", command),
+			slog.String("action", t.action), slog.String("db", t.db), slog.String("collection", t.collection),
+		)
+
+		dbName, _ := doc.Get("$db").(string)
+
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			fmt.Sprintf("not authorized on %s to execute command %s", dbName, command),
+			command,
+		)
+	}
+
+	return nil
+}
+
+// hasPrivilege returns true if the authenticated user is allowed to perform the given action.
+// It always returns true if authentication is disabled.
+func (h *Handler) hasPrivilege(ctx context.Context, action, db, collection string) (bool, error) {
+	if !h.Auth {
+		return true, nil
+	}
+
+	up, err := h.getConnPrivileges(ctx)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return rbac.Allowed(up.privileges, action, db, collection), nil
+}
+
+// checkRolesGrantable returns error if the authenticated user is not allowed to grant given roles.
+//
+// That prevents privilege escalation by granting roles from other databases.
+func (h *Handler) checkRolesGrantable(ctx context.Context, command string, roles []rbac.RoleName) error {
+	if !h.Auth {
+		return nil
+	}
+
+	up, err := h.getConnPrivileges(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, r := range roles {
+		if rbac.Allowed(up.privileges, "grantRole", r.DB, "") {
+			continue
+		}
+
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			fmt.Sprintf("not authorized on %s to grant role %s", r.DB, r),
+			command,
+		)
+	}
+
+	return nil
+}
+
+// checkRolesExist returns error if any of the given roles does not exist.
+func (h *Handler) checkRolesExist(ctx context.Context, roles []rbac.RoleName) error {
+	lookup := h.lookupRole(ctx)
+
+	for _, name := range roles {
+		role, err := rbac.GetRole(name, lookup)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if role == nil {
+			return mongoerrors.NewWithArgument(
+				mongoerrors.ErrRoleNotFound,
+				fmt.Sprintf("Could not find role: %s", name),
+				"roles",
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateRole returns error if the role defined in the given database has invalid privileges or roles.
+func validateRole(dbName string, privileges []rbac.Privilege, roles []rbac.RoleName) error {
+	if dbName == "admin" {
+		return nil
+	}
+
+	for _, p := range privileges {
+		if p.Resource.Cluster || p.Resource.AnyResource || p.Resource.DB != dbName {
+			msg := fmt.Sprintf(
+				"Roles on the '%s' database cannot be granted privileges that target other databases or the cluster",
+				dbName,
+			)
+
+			return mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "privileges")
+		}
+	}
+
+	for _, r := range roles {
+		if r.DB != dbName {
+			msg := fmt.Sprintf("Roles on the '%s' database cannot inherit from roles on other databases", dbName)
+			return mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "roles")
+		}
+	}
+
+	return nil
+}
+
+// getUserDefinedRole returns existing user-defined role, or error if it is built-in or does not exist.
+func (h *Handler) getUserDefinedRole(ctx context.Context, command string, name rbac.RoleName) (*rbac.Role, error) {
+	if rbac.Builtin(name) != nil {
+		msg := fmt.Sprintf("Cannot modify built-in role: %s", name)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidRoleModification, msg, command)
+	}
+
+	role, err := h.lookupRole(ctx)(name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if role == nil {
+		msg := fmt.Sprintf("Could not find role: %s", name)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrRoleNotFound, msg, command)
+	}
+
+	return role, nil
+}
+
+// getExistingUserRoleNames returns names of roles directly granted to the user,
+// or error if the user does not exist.
+func (h *Handler) getExistingUserRoleNames(ctx context.Context, command, username, dbName string) ([]rbac.RoleName, error) {
+	names, ok, err := h.getUserRoleNames(ctx, userRolesKey(username, dbName))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !ok {
+		msg := fmt.Sprintf("Could not find user %q for db %q", username, dbName)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrUserNotFound, msg, command)
+	}
+
+	return names, nil
+}
+
+// userRolesKey returns the key of the user in FerretDB's roles storage.
+//
+// Users of the `$external` database are prefixed to avoid clashes with DocumentDB users and PostgreSQL roles.
+func userRolesKey(username, dbName string) string {
+	if dbName == externalDB {
+		return externalDB + "." + username
+	}
+
+	return username
+}
+
+// setUserRoleNames stores names of roles directly granted to the user.
+func (h *Handler) setUserRoleNames(ctx context.Context, username string, names []rbac.RoleName) error {
+	defer h.privileges.reset()
+
+	if err := h.Pool.SetUserRoles(ctx, username, must.NotFail(rbac.RoleNamesArray(names).Encode())); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// deleteUserRoleNames removes stored names of roles granted to the dropped user.
+func (h *Handler) deleteUserRoleNames(ctx context.Context, username string) error {
+	defer h.privileges.reset()
+
+	if err := h.Pool.DeleteUserRoles(ctx, username); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// updateRole stores changed user-defined role.
+func (h *Handler) updateRole(ctx context.Context, command string, role *rbac.Role) error {
+	defer h.privileges.reset()
+
+	updated, err := h.Pool.UpdateRole(ctx, roleInfo(role))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !updated {
+		msg := fmt.Sprintf("Could not find role: %s", role.Name)
+		return mongoerrors.NewWithArgument(mongoerrors.ErrRoleNotFound, msg, command)
+	}
+
+	return nil
+}
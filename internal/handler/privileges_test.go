@@ -0,0 +1,212 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/rbac"
+)
+
+func TestCommandTargetsAggregate(t *testing.T) {
+	t.Parallel()
+
+	raw, err := wirebson.MustDocument(
+		"aggregate", "src",
+		"pipeline", wirebson.MustArray(
+			wirebson.MustDocument("$lookup", wirebson.MustDocument(
+				"from", "foreign",
+				"pipeline", wirebson.MustArray(
+					wirebson.MustDocument("$unionWith", "union"),
+				),
+			)),
+			wirebson.MustDocument("$facet", wirebson.MustDocument(
+				"f", wirebson.MustArray(
+					wirebson.MustDocument("$graphLookup", wirebson.MustDocument("from", "graph")),
+				),
+			)),
+			wirebson.MustDocument("$merge", wirebson.MustDocument(
+				"into", wirebson.MustDocument("db", "other", "coll", "merged"),
+			)),
+			wirebson.MustDocument("$out", "out"),
+		),
+		"$db", "test",
+	).Encode()
+	require.NoError(t, err)
+
+	doc, err := raw.Decode()
+	require.NoError(t, err)
+
+	actual := commandTargets("aggregate", &command{action: "find"}, doc)
+	expected := []commandTarget{
+		{action: "find", db: "test", collection: "src"},
+		{action: "find", db: "test", collection: "foreign"},
+		{action: "find", db: "test", collection: "union"},
+		{action: "find", db: "test", collection: "graph"},
+		{action: "insert", db: "other", collection: "merged"},
+		{action: "update", db: "other", collection: "merged"},
+		{action: "insert", db: "test", collection: "out"},
+		{action: "remove", db: "test", collection: "out"},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestCommandTargetsUpsert(t *testing.T) {
+	t.Parallel()
+
+	spec, err := wirebson.MustDocument("update", "c", "$db", "test").Encode()
+	require.NoError(t, err)
+
+	u, err := wirebson.MustDocument("q", wirebson.MustDocument(), "u", wirebson.MustDocument(), "upsert", true).Encode()
+	require.NoError(t, err)
+
+	// flags, section 0, and section 1 with the single document
+	b := binary.LittleEndian.AppendUint32(nil, 0)
+	b = append(b, 0)
+	b = append(b, spec...)
+	b = append(b, 1)
+	b = binary.LittleEndian.AppendUint32(b, uint32(4+len("updates")+1+len(u)))
+	b = append(b, "updates"...)
+	b = append(b, 0)
+	b = append(b, u...)
+
+	var msg wire.OpMsg
+	require.NoError(t, msg.UnmarshalBinaryNocopy(b))
+
+	doc, err := decodeWithSequences(&msg)
+	require.NoError(t, err)
+
+	actual := commandTargets("update", &command{action: "update"}, doc)
+	expected := []commandTarget{
+		{action: "update", db: "test", collection: "c"},
+		{action: "insert", db: "test", collection: "c"},
+	}
+	assert.Equal(t, expected, actual)
+
+	doc = wirebson.MustDocument("findAndModify", "c", "remove", true, "$db", "test")
+
+	actual = commandTargets("findAndModify", &command{action: "update"}, doc)
+	expected = []commandTarget{
+		{action: "find", db: "test", collection: "c"},
+		{action: "remove", db: "test", collection: "c"},
+	}
+	assert.Equal(t, expected, actual)
+
+	doc = wirebson.MustDocument("findAndModify", "c", "upsert", true, "$db", "test")
+
+	actual = commandTargets("findAndModify", &command{action: "update"}, doc)
+	expected = []commandTarget{
+		{action: "find", db: "test", collection: "c"},
+		{action: "update", db: "test", collection: "c"},
+		{action: "insert", db: "test", collection: "c"},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestCommandTargetsExplain(t *testing.T) {
+	t.Parallel()
+
+	doc := wirebson.MustDocument(
+		"explain", wirebson.MustDocument(
+			"aggregate", "src",
+			"pipeline", wirebson.MustArray(wirebson.MustDocument("$unionWith", "union")),
+		),
+		"$db", "test",
+	)
+
+	actual := commandTargets("explain", &command{action: "find"}, doc)
+	expected := []commandTarget{
+		{action: "find", db: "test", collection: "src"},
+		{action: "find", db: "test", collection: "union"},
+	}
+	assert.Equal(t, expected, actual)
+
+	doc = wirebson.MustDocument("explain", wirebson.MustDocument("delete", "c"), "$db", "test")
+
+	actual = commandTargets("explain", &command{action: "find"}, doc)
+	expected = []commandTarget{{action: "remove", db: "test", collection: "c"}}
+	assert.Equal(t, expected, actual)
+}
+
+func TestCommandTargetsCreateView(t *testing.T) {
+	t.Parallel()
+
+	doc := wirebson.MustDocument(
+		"create", "view",
+		"viewOn", "src",
+		"pipeline", wirebson.MustArray(wirebson.MustDocument("$lookup", wirebson.MustDocument("from", "foreign"))),
+		"$db", "test",
+	)
+
+	actual := commandTargets("create", &command{action: "createCollection"}, doc)
+	expected := []commandTarget{
+		{action: "createCollection", db: "test", collection: "view"},
+		{action: "find", db: "test", collection: "src"},
+		{action: "find", db: "test", collection: "foreign"},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestCommandTargetsUsers(t *testing.T) {
+	t.Parallel()
+
+	// user administrator of db1 should not be able to take over global users
+	privileges := rbac.Builtin(rbac.RoleName{Role: "userAdmin", DB: "db1"}).Privileges
+
+	for name, tc := range map[string]struct {
+		doc      *wirebson.Document
+		cmd      *command
+		expected []commandTarget
+	}{
+		"ChangePassword": {
+			doc:      wirebson.MustDocument("updateUser", "root", "pwd", "password", "$db", "db1"),
+			cmd:      &command{action: "updateUser"},
+			expected: []commandTarget{{action: "changePassword", db: "admin"}},
+		},
+		"DropUser": {
+			doc:      wirebson.MustDocument("dropUser", "root", "$db", "db1"),
+			cmd:      &command{action: "dropUser"},
+			expected: []commandTarget{{action: "dropUser", db: "admin"}},
+		},
+		"RevokeRoles": {
+			doc:      wirebson.MustDocument("revokeRolesFromUser", "root", "roles", wirebson.MustArray("root"), "$db", "db1"),
+			cmd:      &command{action: "revokeRole"},
+			expected: []commandTarget{{action: "revokeRole", db: "admin"}},
+		},
+		"External": {
+			doc:      wirebson.MustDocument("dropUser", "ext", "$db", externalDB),
+			cmd:      &command{action: "dropUser"},
+			expected: []commandTarget{{action: "dropUser", db: externalDB}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			command := tc.doc.Command()
+			actual := commandTargets(command, tc.cmd, tc.doc)
+			assert.Equal(t, tc.expected, actual)
+
+			for _, target := range actual {
+				assert.False(t, rbac.Allowed(privileges, target.action, target.db, target.collection))
+			}
+		})
+	}
+}
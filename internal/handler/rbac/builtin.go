@@ -0,0 +1,237 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"maps"
+	"slices"
+)
+
+// Actions of built-in roles.
+var (
+	readActions = []string{
+		"changeStream",
+		"collStats",
+		"dbStats",
+		"find",
+		"killCursors",
+		"listCollections",
+		"listIndexes",
+	}
+
+	readWriteActions = append(slices.Clone(readActions),
+		"convertToCapped",
+		"createCollection",
+		"createIndex",
+		"dropCollection",
+		"dropIndex",
+		"insert",
+		"remove",
+		"renameCollectionSameDB",
+		"update",
+	)
+
+	dbAdminActions = []string{
+		"collMod",
+		"collStats",
+		"compact",
+		"convertToCapped",
+		"createCollection",
+		"createIndex",
+		"dbStats",
+		"dropCollection",
+		"dropDatabase",
+		"dropIndex",
+		"listCollections",
+		"listIndexes",
+		"renameCollectionSameDB",
+		"validate",
+	}
+
+	userAdminActions = []string{
+		"changeCustomData",
+		"changePassword",
+		"createRole",
+		"createUser",
+		"dropRole",
+		"dropUser",
+		"grantRole",
+		"revokeRole",
+		"viewRole",
+		"viewUser",
+	}
+
+	clusterManagerActions = []string{
+		"enableSharding",
+		"reshardCollection",
+		"unshardCollection",
+	}
+
+	clusterMonitorActions = []string{
+		"getCmdLineOpts",
+		"getLog",
+		"getParameter",
+		"hostInfo",
+		"inprog",
+		"listDatabases",
+		"serverStatus",
+		"setFreeMonitoring",
+	}
+)
+
+// builtinRoles contains built-in roles constructors.
+//
+// Database roles are available in every database and grant privileges on that database.
+// Roles marked as adminOnly are available only in the `admin` database.
+var builtinRoles = map[string]struct {
+	privileges func(db string) []Privilege
+	adminOnly  bool
+}{
+	"read": {
+		privileges: func(db string) []Privilege {
+			return []Privilege{{Resource: Resource{DB: db}, Actions: readActions}}
+		},
+	},
+	"readWrite": {
+		privileges: func(db string) []Privilege {
+			return []Privilege{{Resource: Resource{DB: db}, Actions: readWriteActions}}
+		},
+	},
+	"dbAdmin": {
+		privileges: func(db string) []Privilege {
+			return []Privilege{{Resource: Resource{DB: db}, Actions: dbAdminActions}}
+		},
+	},
+	"userAdmin": {
+		privileges: func(db string) []Privilege {
+			return []Privilege{{Resource: Resource{DB: db}, Actions: userAdminActions}}
+		},
+	},
+	"dbOwner": {
+		privileges: func(db string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{DB: db}, Actions: readWriteActions},
+				{Resource: Resource{DB: db}, Actions: dbAdminActions},
+				{Resource: Resource{DB: db}, Actions: userAdminActions},
+			}
+		},
+	},
+	"clusterMonitor": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{Cluster: true}, Actions: clusterMonitorActions},
+				{Resource: Resource{}, Actions: []string{"collStats", "dbStats", "listCollections", "listIndexes"}},
+			}
+		},
+		adminOnly: true,
+	},
+	"clusterManager": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{Cluster: true}, Actions: []string{"killAnySession", "listDatabases"}},
+				{Resource: Resource{}, Actions: clusterManagerActions},
+			}
+		},
+		adminOnly: true,
+	},
+	"clusterAdmin": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{
+					Resource: Resource{Cluster: true},
+					Actions:  append(slices.Clone(clusterMonitorActions), "killAnySession", "killop", "unlockAccount"),
+				},
+				{Resource: Resource{}, Actions: []string{"collStats", "dbStats", "dropDatabase", "listCollections", "listIndexes"}},
+				{Resource: Resource{}, Actions: clusterManagerActions},
+			}
+		},
+		adminOnly: true,
+	},
+	"readAnyDatabase": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{}, Actions: readActions},
+				{Resource: Resource{Cluster: true}, Actions: []string{"listDatabases"}},
+			}
+		},
+		adminOnly: true,
+	},
+	"readWriteAnyDatabase": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{}, Actions: readWriteActions},
+				{Resource: Resource{Cluster: true}, Actions: []string{"listDatabases"}},
+			}
+		},
+		adminOnly: true,
+	},
+	"dbAdminAnyDatabase": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{}, Actions: dbAdminActions},
+				{Resource: Resource{Cluster: true}, Actions: []string{"listDatabases"}},
+			}
+		},
+		adminOnly: true,
+	},
+	"userAdminAnyDatabase": {
+		privileges: func(string) []Privilege {
+			return []Privilege{
+				{Resource: Resource{}, Actions: userAdminActions},
+				{Resource: Resource{Cluster: true}, Actions: []string{"listDatabases", "manageAPIKeys", "unlockAccount"}},
+			}
+		},
+		adminOnly: true,
+	},
+	"root": {
+		privileges: func(string) []Privilege {
+			return []Privilege{{Resource: Resource{AnyResource: true}, Actions: []string{AnyAction}}}
+		},
+		adminOnly: true,
+	},
+}
+
+// Builtin returns built-in role with the given name, or nil if there is no such role.
+func Builtin(name RoleName) *Role {
+	b, ok := builtinRoles[name.Role]
+	if !ok || (b.adminOnly && name.DB != "admin") {
+		return nil
+	}
+
+	return &Role{
+		Name:       name,
+		Privileges: b.privileges(name.DB),
+		Builtin:    true,
+	}
+}
+
+// IsBuiltin returns true if the role name is reserved for a built-in role in any database.
+func IsBuiltin(role string) bool {
+	_, ok := builtinRoles[role]
+	return ok
+}
+
+// BuiltinRoles returns all built-in roles available in the given database, sorted by name.
+func BuiltinRoles(db string) []*Role {
+	var res []*Role
+
+	for _, name := range slices.Sorted(maps.Keys(builtinRoles)) {
+		if role := Builtin(RoleName{Role: name, DB: db}); role != nil {
+			res = append(res, role)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,468 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rbac implements MongoDB-compatible role-based access control:
+// privileges, built-in and user-defined roles, and their resolution.
+//
+// Roles and users storage is not a part of this package.
+package rbac
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// AnyAction is a special action that matches all actions.
+const AnyAction = "anyAction"
+
+// Resource represents a privilege resource.
+//
+// Empty DB or Collection matches any database or collection.
+type Resource struct {
+	DB          string
+	Collection  string
+	Cluster     bool
+	AnyResource bool
+}
+
+// Privilege represents a set of actions allowed on the resource.
+type Privilege struct {
+	Resource Resource
+	Actions  []string
+}
+
+// RoleName identifies a role.
+type RoleName struct {
+	Role string
+	DB   string
+}
+
+// String returns role name in MongoDB format.
+func (rn RoleName) String() string {
+	return rn.Role + "@" + rn.DB
+}
+
+// Document returns role name as a BSON document.
+func (rn RoleName) Document() *wirebson.Document {
+	return wirebson.MustDocument("role", rn.Role, "db", rn.DB)
+}
+
+// Role represents a built-in or user-defined role.
+type Role struct {
+	Name       RoleName
+	Privileges []Privilege
+	Roles      []RoleName
+	Builtin    bool
+}
+
+// LookupFunc returns user-defined role with the given name, or nil if it does not exist.
+type LookupFunc func(name RoleName) (*Role, error)
+
+// ClusterActions contains actions that are granted on the cluster resource.
+var ClusterActions = []string{
+	"getCmdLineOpts",
+	"getLog",
+	"getParameter",
+	"hostInfo",
+	"inprog",
+	"killAnySession",
+	"killop",
+	"listDatabases",
+	"manageAPIKeys",
+	"serverStatus",
+	"setFreeMonitoring",
+	"unlockAccount",
+}
+
+// IsClusterAction returns true if the action is granted on the cluster resource.
+func IsClusterAction(action string) bool {
+	return slices.Contains(ClusterActions, action)
+}
+
+// IsUserAdminAction returns true if the action manages users or roles.
+// Such actions are granted on the database, not on collections.
+func IsUserAdminAction(action string) bool {
+	return slices.Contains(userAdminActions, action)
+}
+
+// matches returns true if the resource matches the given database and collection.
+func (r *Resource) matches(action, db, collection string) bool {
+	if r.AnyResource {
+		return true
+	}
+
+	if IsClusterAction(action) {
+		return r.Cluster
+	}
+
+	if r.Cluster {
+		return false
+	}
+
+	return (r.DB == "" || r.DB == db) && (r.Collection == "" || r.Collection == collection)
+}
+
+// Allowed returns true if any of privileges allows the action on the given database and collection.
+// Empty collection means the database itself.
+func Allowed(privileges []Privilege, action, db, collection string) bool {
+	for _, p := range privileges {
+		if !p.Resource.matches(action, db, collection) {
+			continue
+		}
+
+		if slices.Contains(p.Actions, action) || slices.Contains(p.Actions, AnyAction) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAny returns true if any of privileges allows any action on the given database and collection.
+// Empty collection means any collection in the database. Cluster privileges are ignored.
+//
+// It is used to filter databases and collections visible to the user.
+func HasAny(privileges []Privilege, db, collection string) bool {
+	for _, p := range privileges {
+		r := p.Resource
+
+		switch {
+		case r.AnyResource:
+			return true
+		case r.Cluster:
+			continue
+		case r.DB != "" && r.DB != db:
+			continue
+		case collection == "" || r.Collection == "" || r.Collection == collection:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns all roles (including inherited ones) and all privileges of the given roles.
+// Roles that do not exist are ignored.
+func Resolve(roles []RoleName, lookup LookupFunc) ([]*Role, []Privilege, error) {
+	var resRoles []*Role
+	var resPrivileges []Privilege
+
+	seen := map[RoleName]struct{}{}
+	queue := slices.Clone(roles)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		seen[name] = struct{}{}
+
+		role, err := GetRole(name, lookup)
+		if err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		if role == nil {
+			continue
+		}
+
+		resRoles = append(resRoles, role)
+		resPrivileges = append(resPrivileges, role.Privileges...)
+		queue = append(queue, role.Roles...)
+	}
+
+	return resRoles, resPrivileges, nil
+}
+
+// GetRole returns built-in or user-defined role with the given name, or nil if it does not exist.
+func GetRole(name RoleName, lookup LookupFunc) (*Role, error) {
+	if role := Builtin(name); role != nil {
+		return role, nil
+	}
+
+	role, err := lookup(name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return role, nil
+}
+
+// ParseRoleNames parses roles array.
+// Elements could be either role name strings (in the default database) or `{role, db}` documents.
+func ParseRoleNames(v any, defaultDB string) ([]RoleName, error) {
+	arrV, ok := v.(wirebson.AnyArray)
+	if !ok {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrTypeMismatch,
+			fmt.Sprintf("roles field must be an array, not %T", v),
+			"roles",
+		)
+	}
+
+	arr, err := arrV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := make([]RoleName, 0, arr.Len())
+
+	for v := range arr.Values() {
+		switch v := v.(type) {
+		case string:
+			res = append(res, RoleName{Role: v, DB: defaultDB})
+
+		case wirebson.AnyDocument:
+			var doc *wirebson.Document
+			if doc, err = v.Decode(); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			role, _ := doc.Get("role").(string)
+			db, _ := doc.Get("db").(string)
+
+			if role == "" || db == "" {
+				return nil, mongoerrors.NewWithArgument(
+					mongoerrors.ErrBadValue,
+					"role names must have non-empty 'role' and 'db' fields",
+					"roles",
+				)
+			}
+
+			res = append(res, RoleName{Role: role, DB: db})
+
+		default:
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrBadValue,
+				fmt.Sprintf("role names must be either strings or objects, not %T", v),
+				"roles",
+			)
+		}
+	}
+
+	return res, nil
+}
+
+// RoleNamesArray returns role names as a BSON array of `{role, db}` documents.
+func RoleNamesArray(names []RoleName) *wirebson.Array {
+	res := wirebson.MakeArray(len(names))
+
+	for _, n := range names {
+		must.NoError(res.Add(n.Document()))
+	}
+
+	return res
+}
+
+// ParsePrivileges parses privileges array.
+func ParsePrivileges(v any) ([]Privilege, error) {
+	arrV, ok := v.(wirebson.AnyArray)
+	if !ok {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrTypeMismatch,
+			fmt.Sprintf("privileges field must be an array, not %T", v),
+			"privileges",
+		)
+	}
+
+	arr, err := arrV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := make([]Privilege, 0, arr.Len())
+
+	for v := range arr.Values() {
+		docV, ok := v.(wirebson.AnyDocument)
+		if !ok {
+			return nil, invalidPrivilege("privileges must be objects")
+		}
+
+		var doc *wirebson.Document
+		if doc, err = docV.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var p Privilege
+
+		if p.Resource, err = parseResource(doc.Get("resource")); err != nil {
+			return nil, err
+		}
+
+		actionsV, ok := doc.Get("actions").(wirebson.AnyArray)
+		if !ok {
+			return nil, invalidPrivilege("privilege must have 'actions' array")
+		}
+
+		var actions *wirebson.Array
+		if actions, err = actionsV.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for a := range actions.Values() {
+			action, ok := a.(string)
+			if !ok || action == "" {
+				return nil, invalidPrivilege("actions must be non-empty strings")
+			}
+
+			p.Actions = append(p.Actions, action)
+		}
+
+		if len(p.Actions) == 0 {
+			return nil, invalidPrivilege("privilege must have at least one action")
+		}
+
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+// parseResource parses privilege resource document.
+func parseResource(v any) (Resource, error) {
+	docV, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		return Resource{}, invalidPrivilege("privilege must have 'resource' object")
+	}
+
+	doc, err := docV.Decode()
+	if err != nil {
+		return Resource{}, lazyerrors.Error(err)
+	}
+
+	if cluster, _ := doc.Get("cluster").(bool); cluster {
+		return Resource{Cluster: true}, nil
+	}
+
+	if anyResource, _ := doc.Get("anyResource").(bool); anyResource {
+		return Resource{AnyResource: true}, nil
+	}
+
+	db, ok := doc.Get("db").(string)
+	if !ok {
+		return Resource{}, invalidPrivilege("resource must have 'db' and 'collection' string fields")
+	}
+
+	collection, ok := doc.Get("collection").(string)
+	if !ok {
+		return Resource{}, invalidPrivilege("resource must have 'db' and 'collection' string fields")
+	}
+
+	return Resource{DB: db, Collection: collection}, nil
+}
+
+// invalidPrivilege returns an error for invalid privilege specification.
+func invalidPrivilege(msg string) error {
+	return mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "privileges")
+}
+
+// Document returns resource as a BSON document.
+func (r *Resource) Document() *wirebson.Document {
+	switch {
+	case r.AnyResource:
+		return wirebson.MustDocument("anyResource", true)
+	case r.Cluster:
+		return wirebson.MustDocument("cluster", true)
+	default:
+		return wirebson.MustDocument("db", r.DB, "collection", r.Collection)
+	}
+}
+
+// Document returns privilege as a BSON document.
+func (p *Privilege) Document() *wirebson.Document {
+	actions := wirebson.MakeArray(len(p.Actions))
+	for _, a := range p.Actions {
+		must.NoError(actions.Add(a))
+	}
+
+	return wirebson.MustDocument(
+		"resource", p.Resource.Document(),
+		"actions", actions,
+	)
+}
+
+// PrivilegesArray returns privileges as a BSON array.
+func PrivilegesArray(privileges []Privilege) *wirebson.Array {
+	res := wirebson.MakeArray(len(privileges))
+
+	for _, p := range privileges {
+		must.NoError(res.Add(p.Document()))
+	}
+
+	return res
+}
+
+// GrantPrivileges returns privileges with given privileges added.
+// Actions for the same resource are merged.
+func GrantPrivileges(privileges, grant []Privilege) []Privilege {
+	res := slices.Clone(privileges)
+
+	for _, g := range grant {
+		i := slices.IndexFunc(res, func(p Privilege) bool { return p.Resource == g.Resource })
+		if i < 0 {
+			res = append(res, Privilege{Resource: g.Resource, Actions: slices.Clone(g.Actions)})
+			continue
+		}
+
+		actions := slices.Clone(res[i].Actions)
+
+		for _, a := range g.Actions {
+			if !slices.Contains(actions, a) {
+				actions = append(actions, a)
+			}
+		}
+
+		res[i] = Privilege{Resource: g.Resource, Actions: actions}
+	}
+
+	return res
+}
+
+// RevokePrivileges returns privileges with given privileges removed.
+// Privileges without actions left are removed completely.
+func RevokePrivileges(privileges, revoke []Privilege) []Privilege {
+	res := make([]Privilege, 0, len(privileges))
+
+	for _, p := range privileges {
+		actions := slices.Clone(p.Actions)
+
+		for _, r := range revoke {
+			if r.Resource == p.Resource {
+				actions = slices.DeleteFunc(actions, func(a string) bool { return slices.Contains(r.Actions, a) })
+			}
+		}
+
+		if len(actions) > 0 {
+			res = append(res, Privilege{Resource: p.Resource, Actions: actions})
+		}
+	}
+
+	return res
+}
+
+// ParseNamespace splits `db.collection` namespace.
+func ParseNamespace(ns string) (string, string) {
+	db, collection, _ := strings.Cut(ns, ".")
+	return db, collection
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowed(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		role       RoleName
+		action     string
+		db         string
+		collection string
+		expected   bool
+	}{
+		"ReadFind":            {RoleName{"read", "test"}, "find", "test", "coll", true},
+		"ReadInsert":          {RoleName{"read", "test"}, "insert", "test", "coll", false},
+		"ReadOtherDB":         {RoleName{"read", "test"}, "find", "other", "coll", false},
+		"ReadWriteInsert":     {RoleName{"readWrite", "test"}, "insert", "test", "coll", true},
+		"ReadWriteDropDB":     {RoleName{"readWrite", "test"}, "dropDatabase", "test", "", false},
+		"DBAdminDropDB":       {RoleName{"dbAdmin", "test"}, "dropDatabase", "test", "", true},
+		"UserAdminCreateUser": {RoleName{"userAdmin", "test"}, "createUser", "test", "", true},
+		"ClusterMonitor":      {RoleName{"clusterMonitor", "admin"}, "serverStatus", "admin", "", true},
+		"ClusterMonitorFind":  {RoleName{"clusterMonitor", "admin"}, "find", "test", "coll", false},
+		"ClusterManagerShard": {RoleName{"clusterManager", "admin"}, "enableSharding", "test", "coll", true},
+		"ClusterManagerFind":  {RoleName{"clusterManager", "admin"}, "find", "test", "coll", false},
+		"ClusterManagerKill":  {RoleName{"clusterManager", "admin"}, "killAnySession", "admin", "", true},
+		"ClusterMonitorKill":  {RoleName{"clusterMonitor", "admin"}, "killAnySession", "admin", "", false},
+		"ClusterMonitorFM":    {RoleName{"clusterMonitor", "admin"}, "setFreeMonitoring", "admin", "", true},
+		"UserAdminKill":       {RoleName{"userAdminAnyDatabase", "admin"}, "killAnySession", "admin", "", false},
+		"ClusterAdminShard":   {RoleName{"clusterAdmin", "admin"}, "reshardCollection", "test", "coll", true},
+		"ReadCluster":         {RoleName{"read", "admin"}, "serverStatus", "admin", "", false},
+		"Root":                {RoleName{"root", "admin"}, "dropDatabase", "test", "", true},
+		"RootNotAdmin":        {RoleName{"root", "test"}, "find", "test", "coll", false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, privileges, err := Resolve([]RoleName{tc.role}, func(RoleName) (*Role, error) { return nil, nil })
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, Allowed(privileges, tc.action, tc.db, tc.collection))
+		})
+	}
+}
+
+func TestHasAny(t *testing.T) {
+	t.Parallel()
+
+	privileges := []Privilege{
+		{Resource: Resource{Cluster: true}, Actions: []string{"listDatabases"}},
+		{Resource: Resource{DB: "test", Collection: ""}, Actions: []string{"find"}},
+		{Resource: Resource{DB: "other", Collection: "coll"}, Actions: []string{"insert"}},
+		{Resource: Resource{DB: "", Collection: "system.js"}, Actions: []string{"find"}},
+	}
+
+	assert.True(t, HasAny(privileges, "test", ""))
+	assert.True(t, HasAny(privileges, "test", "coll"))
+	assert.True(t, HasAny(privileges, "other", ""))
+	assert.True(t, HasAny(privileges, "other", "coll"))
+	assert.False(t, HasAny(privileges, "other", "another"))
+	assert.True(t, HasAny(privileges, "third", ""))
+	assert.True(t, HasAny(privileges, "third", "system.js"))
+	assert.False(t, HasAny(privileges, "third", "coll"))
+
+	assert.True(t, HasAny([]Privilege{{Resource: Resource{AnyResource: true}, Actions: []string{"find"}}}, "x", "y"))
+	assert.False(t, HasAny(privileges[:1], "test", ""))
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	userDefined := map[RoleName]*Role{
+		{"a", "test"}: {
+			Name: RoleName{"a", "test"},
+			Privileges: []Privilege{
+				{Resource: Resource{DB: "test", Collection: "a"}, Actions: []string{"insert"}},
+			},
+			Roles: []RoleName{{"b", "test"}},
+		},
+		{"b", "test"}: {
+			Name:  RoleName{"b", "test"},
+			Roles: []RoleName{{"a", "test"}, {"read", "test"}, {"missing", "test"}},
+		},
+	}
+
+	lookup := func(name RoleName) (*Role, error) {
+		return userDefined[name], nil
+	}
+
+	roles, privileges, err := Resolve([]RoleName{{"a", "test"}}, lookup)
+	require.NoError(t, err)
+
+	names := make([]RoleName, len(roles))
+	for i, r := range roles {
+		names[i] = r.Name
+	}
+
+	assert.Equal(t, []RoleName{{"a", "test"}, {"b", "test"}, {"read", "test"}}, names)
+
+	assert.True(t, Allowed(privileges, "insert", "test", "a"))
+	assert.False(t, Allowed(privileges, "insert", "test", "b"))
+	assert.True(t, Allowed(privileges, "find", "test", "b"))
+}
+
+func TestGrantRevokePrivileges(t *testing.T) {
+	t.Parallel()
+
+	coll := Resource{DB: "test", Collection: "coll"}
+	db := Resource{DB: "test"}
+
+	privileges := []Privilege{{Resource: coll, Actions: []string{"find"}}}
+
+	privileges = GrantPrivileges(privileges, []Privilege{
+		{Resource: coll, Actions: []string{"find", "insert"}},
+		{Resource: db, Actions: []string{"dbStats"}},
+	})
+
+	expected := []Privilege{
+		{Resource: coll, Actions: []string{"find", "insert"}},
+		{Resource: db, Actions: []string{"dbStats"}},
+	}
+	assert.Equal(t, expected, privileges)
+
+	privileges = RevokePrivileges(privileges, []Privilege{
+		{Resource: coll, Actions: []string{"find"}},
+		{Resource: db, Actions: []string{"dbStats"}},
+	})
+
+	expected = []Privilege{{Resource: coll, Actions: []string{"insert"}}}
+	assert.Equal(t, expected, privileges)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	names, err := ParseRoleNames(wirebson.MustArray("read", wirebson.MustDocument("role", "root", "db", "admin")), "test")
+	require.NoError(t, err)
+	assert.Equal(t, []RoleName{{"read", "test"}, {"root", "admin"}}, names)
+
+	_, err = ParseRoleNames(wirebson.MustArray(int32(1)), "test")
+	require.Error(t, err)
+
+	privileges := []Privilege{
+		{Resource: Resource{DB: "test", Collection: ""}, Actions: []string{"find"}},
+		{Resource: Resource{Cluster: true}, Actions: []string{"serverStatus"}},
+	}
+
+	actual, err := ParsePrivileges(PrivilegesArray(privileges))
+	require.NoError(t, err)
+	assert.Equal(t, privileges, actual)
+
+	_, err = ParsePrivileges(wirebson.MustArray(wirebson.MustDocument("resource", wirebson.MustDocument("cluster", true))))
+	require.Error(t, err)
+}
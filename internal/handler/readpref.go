@@ -0,0 +1,211 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// readPreferenceCommands contains read commands that could be routed to PostgreSQL replicas.
+var readPreferenceCommands = map[string]struct{}{
+	"aggregate": {},
+	"count":     {},
+	"distinct":  {},
+	"find":      {},
+}
+
+// writeStages contains aggregation stages that modify data or require the primary.
+var writeStages = map[string]struct{}{
+	"$changeStream": {},
+	"$merge":        {},
+	"$out":          {},
+}
+
+// minMaxStaleness is the minimal non-zero value of `maxStalenessSeconds`.
+const minMaxStaleness = 90 * time.Second
+
+// getReadPreference returns the mode and `maxStalenessSeconds` of `$readPreference` field.
+// Zero staleness means no limit.
+func getReadPreference(doc *wirebson.Document) (documentdb.ReadMode, time.Duration, error) {
+	v := doc.Get("$readPreference")
+	if v == nil {
+		return documentdb.ReadPrimary, 0, nil
+	}
+
+	rp, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf("BSON field '$readPreference' is the wrong type '%s', expected type 'object'", aliasFromType(v))
+		return "", 0, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "$readPreference")
+	}
+
+	rpDoc, err := rp.Decode()
+	if err != nil {
+		return "", 0, lazyerrors.Error(err)
+	}
+
+	mode := documentdb.ReadPrimary
+
+	if v = rpDoc.Get("mode"); v != nil {
+		s, _ := v.(string)
+
+		switch m := documentdb.ReadMode(s); m {
+		case documentdb.ReadPrimary, documentdb.ReadPrimaryPreferred, documentdb.ReadSecondary,
+			documentdb.ReadSecondaryPreferred, documentdb.ReadNearest:
+			mode = m
+		default:
+			msg := fmt.Sprintf(
+				"Could not parse $readPreference mode '%s'. Only the modes 'primary', 'primaryPreferred', "+
+					"'secondary', 'secondaryPreferred', and 'nearest' are supported.",
+				s,
+			)
+
+			return "", 0, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "$readPreference")
+		}
+	}
+
+	var seconds float64
+
+	switch v := rpDoc.Get("maxStalenessSeconds").(type) {
+	case nil:
+	case int32:
+		seconds = float64(v)
+	case int64:
+		seconds = float64(v)
+	case float64:
+		seconds = v
+	default:
+		msg := fmt.Sprintf("maxStalenessSeconds must be a number, got %s", aliasFromType(v))
+		return "", 0, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "$readPreference")
+	}
+
+	// -1 means no limit
+	if seconds == -1 {
+		seconds = 0
+	}
+
+	if seconds < 0 {
+		msg := "maxStalenessSeconds value can not be negative"
+		return "", 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "$readPreference")
+	}
+
+	if seconds == 0 {
+		return mode, 0, nil
+	}
+
+	if mode == documentdb.ReadPrimary {
+		msg := "mode primary does not allow for maxStalenessSeconds"
+		return "", 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "$readPreference")
+	}
+
+	maxStaleness := time.Duration(seconds * float64(time.Second))
+	if maxStaleness < minMaxStaleness {
+		msg := fmt.Sprintf("maxStalenessSeconds value can not be less than %.0f", minMaxStaleness.Seconds())
+		return "", 0, mongoerrors.NewWithArgument(mongoerrors.ErrMaxStalenessOutOfRange, msg, "$readPreference")
+	}
+
+	return mode, maxStaleness, nil
+}
+
+// readOnlyAggregate returns true if the aggregation pipeline of the given `aggregate` command
+// could be run on a PostgreSQL replica.
+func readOnlyAggregate(doc *wirebson.Document) (bool, error) {
+	// database-level aggregations like $currentOp report the state of the server itself
+	if _, ok := doc.Get("aggregate").(string); !ok {
+		return false, nil
+	}
+
+	pipeline, _ := doc.Get("pipeline").(wirebson.AnyArray)
+	if pipeline == nil {
+		return true, nil
+	}
+
+	stages, err := pipeline.Decode()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	for v := range stages.Values() {
+		stage, _ := v.(wirebson.AnyDocument)
+		if stage == nil {
+			continue
+		}
+
+		stageDoc, err := stage.Decode()
+		if err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		if _, ok := writeStages[stageDoc.Command()]; ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// withReadPreference returns a command handler that runs the given read command
+// on the PostgreSQL server selected by `$readPreference` field;
+// see [documentdb.Pool.ReadPreferenceCtx].
+//
+// Other commands, aggregations with write stages, and commands inside transactions
+// always use the primary.
+func (h *Handler) withReadPreference(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if _, ok := readPreferenceCommands[command]; !ok {
+		return cmdHandler
+	}
+
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		if documentdb.GetTxn(connCtx) != nil {
+			return cmdHandler(connCtx, msg)
+		}
+
+		doc, err := msg.RawSection0().Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		mode, maxStaleness, err := getReadPreference(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if command == "aggregate" {
+			var readOnly bool
+			if readOnly, err = readOnlyAggregate(doc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if !readOnly {
+				mode, maxStaleness = documentdb.ReadPrimary, 0
+			}
+		}
+
+		ctx, err := h.Pool.ReadPreferenceCtx(connCtx, mode, maxStaleness)
+		if err != nil {
+			return nil, err
+		}
+
+		return cmdHandler(ctx, msg)
+	}
+}
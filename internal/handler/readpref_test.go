@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+)
+
+func TestGetReadPreference(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v            any
+		mode         documentdb.ReadMode
+		maxStaleness time.Duration
+		err          string
+	}{
+		"Secondary": {
+			v:    wirebson.MustDocument("mode", "secondary"),
+			mode: documentdb.ReadSecondary,
+		},
+		"MaxStaleness": {
+			v:            wirebson.MustDocument("mode", "nearest", "maxStalenessSeconds", int32(120)),
+			mode:         documentdb.ReadNearest,
+			maxStaleness: 2 * time.Minute,
+		},
+		"NoMaxStaleness": {
+			v:    wirebson.MustDocument("mode", "secondaryPreferred", "maxStalenessSeconds", int64(-1)),
+			mode: documentdb.ReadSecondaryPreferred,
+		},
+		"NoMode": {
+			v:    wirebson.MustDocument(),
+			mode: documentdb.ReadPrimary,
+		},
+		"UnknownMode": {
+			v:   wirebson.MustDocument("mode", "any"),
+			err: "Could not parse $readPreference mode 'any'.",
+		},
+		"Negative": {
+			v:   wirebson.MustDocument("mode", "secondary", "maxStalenessSeconds", int32(-2)),
+			err: "maxStalenessSeconds value can not be negative",
+		},
+		"TooSmall": {
+			v:   wirebson.MustDocument("mode", "secondary", "maxStalenessSeconds", int32(10)),
+			err: "maxStalenessSeconds value can not be less than 90",
+		},
+		"Primary": {
+			v:   wirebson.MustDocument("mode", "primary", "maxStalenessSeconds", int32(120)),
+			err: "mode primary does not allow for maxStalenessSeconds",
+		},
+		"WrongType": {
+			v:   "secondary",
+			err: "BSON field '$readPreference' is the wrong type 'string', expected type 'object'",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mode, maxStaleness, err := getReadPreference(wirebson.MustDocument("find", "test", "$readPreference", tc.v))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.mode, mode)
+			assert.Equal(t, tc.maxStaleness, maxStaleness)
+		})
+	}
+
+	mode, maxStaleness, err := getReadPreference(wirebson.MustDocument("find", "test"))
+	require.NoError(t, err)
+	assert.Equal(t, documentdb.ReadPrimary, mode)
+	assert.Zero(t, maxStaleness)
+}
+
+func TestReadOnlyAggregate(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		doc      *wirebson.Document
+		expected bool
+	}{
+		"Match": {
+			doc: wirebson.MustDocument(
+				"aggregate", "test",
+				"pipeline", wirebson.MustArray(wirebson.MustDocument("$match", wirebson.MustDocument())),
+			),
+			expected: true,
+		},
+		"Out": {
+			doc: wirebson.MustDocument(
+				"aggregate", "test",
+				"pipeline", wirebson.MustArray(
+					wirebson.MustDocument("$match", wirebson.MustDocument()),
+					wirebson.MustDocument("$out", "other"),
+				),
+			),
+		},
+		"ChangeStream": {
+			doc: wirebson.MustDocument(
+				"aggregate", "test",
+				"pipeline", wirebson.MustArray(wirebson.MustDocument("$changeStream", wirebson.MustDocument())),
+			),
+		},
+		"Database": {
+			doc: wirebson.MustDocument(
+				"aggregate", int32(1),
+				"pipeline", wirebson.MustArray(wirebson.MustDocument("$currentOp", wirebson.MustDocument())),
+			),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := readOnlyAggregate(tc.doc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// retryableWriteCommands contains commands that drivers send as retryable writes.
+var retryableWriteCommands = map[string]struct{}{
+	"delete":        {},
+	"findAndModify": {},
+	"insert":        {},
+	"update":        {},
+}
+
+// retryableWriteParams represents retryable write parameters of the command.
+type retryableWriteParams struct {
+	txnNumber int64
+	stmtID    int32
+}
+
+// getRetryableWriteParams returns retryable write parameters of the command,
+// or nil if the command is not a retryable write.
+//
+// Commands with `autocommit` field are parts of multi-document transactions, not retryable writes.
+func getRetryableWriteParams(doc *wirebson.Document) (*retryableWriteParams, error) {
+	if doc.Get("txnNumber") == nil || doc.Get("autocommit") != nil {
+		return nil, nil
+	}
+
+	txnNumber, err := getRequiredParam[int64](doc, "txnNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	stmtID, err := getOptionalParam(doc, "stmtId", int32(0))
+	if err != nil {
+		return nil, err
+	}
+
+	// mongos splits batches into several commands with the same transaction number,
+	// each starting with its own statement ID
+	if v := doc.Get("stmtIds"); v != nil {
+		arr, ok := v.(wirebson.AnyArray)
+		if !ok {
+			msg := fmt.Sprintf("BSON field 'stmtIds' is the wrong type '%s', expected type 'array'", aliasFromType(v))
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "stmtIds")
+		}
+
+		var stmtIDs *wirebson.Array
+
+		if stmtIDs, err = arr.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if stmtIDs.Len() > 0 {
+			first, ok := stmtIDs.Get(0).(int32)
+			if !ok {
+				msg := fmt.Sprintf(
+					"BSON field 'stmtIds' is the wrong type '%s', expected type 'int'",
+					aliasFromType(stmtIDs.Get(0)),
+				)
+
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "stmtIds")
+			}
+
+			stmtID = first
+		}
+	}
+
+	return &retryableWriteParams{
+		txnNumber: txnNumber,
+		stmtID:    stmtID,
+	}, nil
+}
+
+// withRetryableWrite returns a command handler that deduplicates retryable writes:
+// if the command has `txnNumber` field outside of multi-document transaction,
+// its result is stored in the session and returned for retries without executing the command again.
+//
+// Errors after which the write could be safely retried get [mongoerrors.LabelRetryableWriteError] label.
+func (h *Handler) withRetryableWrite(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if _, ok := retryableWriteCommands[command]; !ok {
+		return cmdHandler
+	}
+
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		spec := msg.RawSection0()
+
+		doc, err := spec.Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		params, err := getRetryableWriteParams(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if params == nil {
+			return cmdHandler(connCtx, msg)
+		}
+
+		if doc.Get("lsid") == nil {
+			msg := "Transaction numbers are only allowed on a replica set member or mongos"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrIllegalOperation, msg, command)
+		}
+
+		userID, sessionID, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		stored, finish, err := h.s.StartRetryableWrite(connCtx, userID, sessionID, params.txnNumber, params.stmtID)
+		if err != nil {
+			return nil, err
+		}
+
+		if stored != nil {
+			h.L.DebugContext(
+				connCtx, "Returning stored result of retried write",
+				slog.String("session_id", sessionID.String()),
+				slog.Int64("txn_number", params.txnNumber), slog.Int("stmt_id", int(params.stmtID)),
+			)
+
+			var res *wire.OpMsg
+			if res, err = wire.NewOpMsg(stored); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			return res, nil
+		}
+
+		res, err := cmdHandler(connCtx, msg)
+		if err != nil {
+			finish(nil)
+
+			if isRetryableWriteError(err) {
+				return nil, mongoerrors.Make(connCtx, err, command, h.L).WithLabels(mongoerrors.LabelRetryableWriteError)
+			}
+
+			return nil, err
+		}
+
+		raw, err := res.RawDocument()
+		if err != nil {
+			finish(nil)
+			return nil, lazyerrors.Error(err)
+		}
+
+		finish(raw)
+
+		return res, nil
+	}
+}
+
+// isRetryableWriteError returns true if the write failed because of the PostgreSQL connection problem,
+// so the driver could retry it, possibly after reconnecting.
+func isRetryableWriteError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgerrcode.AdminShutdown, pgerrcode.CrashShutdown, pgerrcode.CannotConnectNow:
+			return true
+		default:
+			return pgerrcode.IsConnectionException(pgErr.Code)
+		}
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	return pgconn.SafeToRetry(err)
+}
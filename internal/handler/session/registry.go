@@ -282,6 +282,19 @@ func (r *Registry) deleteCursor(userID UserID, cursorID int64) bool {
 	return true
 }
 
+// RemoveCursors removes cursors that were already closed, for example, due to timeout.
+// Cursors that do not exist are ignored.
+func (r *Registry) RemoveCursors(cursorIDs []int64) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	for _, cursorID := range cursorIDs {
+		if owner, ok := r.cursors[cursorID]; ok {
+			r.deleteCursor(owner.userID, cursorID)
+		}
+	}
+}
+
 // CreateOrUpdateSessions updates the last used time of the sessions.
 // If a session does not exist, a new session is created implicitly.
 func (r *Registry) CreateOrUpdateSessions(ctx context.Context, sessionIDs []uuid.UUID) {
@@ -327,6 +340,46 @@ func (r *Registry) createOrUpdateSessions(ctx context.Context, userID UserID, se
 	}
 }
 
+// Session represents information about a session for `currentOp`.
+type Session struct {
+	LastUsed  time.Time
+	CursorIDs []int64
+	UserID    UserID
+	ID        uuid.UUID // empty for cursors created without lsid
+}
+
+// Sessions returns all sessions that are not ended, sorted by the last use time.
+// Cursors created without lsid are returned in a session with an empty ID.
+func (r *Registry) Sessions() []Session {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	var res []Session
+
+	for userID, sessions := range r.sessions {
+		for sessionID, s := range sessions {
+			if s.ended {
+				continue
+			}
+
+			cursorIDs := slices.Sorted(maps.Keys(s.cursorIDs))
+
+			res = append(res, Session{
+				LastUsed:  s.lastUsed,
+				CursorIDs: cursorIDs,
+				UserID:    userID,
+				ID:        sessionID,
+			})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Session) int {
+		return a.LastUsed.Compare(b.LastUsed)
+	})
+
+	return res
+}
+
 // DeleteAllSessions removes all sessions of all users and
 // returns all cursors of removed sessions.
 func (r *Registry) DeleteAllSessions() []int64 {
@@ -378,6 +431,22 @@ func (r *Registry) DeleteSessionsByIDs(userID UserID, sessionIDs []uuid.UUID) []
 	return r.deleteSessions(userID, sessionIDs, "killed")
 }
 
+// DeleteAnySessionsByIDs removes sessions of any user and returns cursors of the deleted sessions.
+// If a session does not exist, it does nothing.
+func (r *Registry) DeleteAnySessionsByIDs(sessionIDs []uuid.UUID) []int64 {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var cursorIDs []int64
+
+	for _, userID := range slices.Collect(maps.Keys(r.sessions)) {
+		userCursorIDs := r.deleteSessions(userID, sessionIDs, "killed")
+		cursorIDs = append(cursorIDs, userCursorIDs...)
+	}
+
+	return cursorIDs
+}
+
 // deleteSessions removes given sessions of the given user and returns cursors of the deleted sessions.
 // The `reason` parameter is used for the label of the Prometheus metrics.
 //
@@ -0,0 +1,139 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// retryableWrites contains results of retryable write statements
+// executed with the last transaction number of a session.
+type retryableWrites struct {
+	stmts     map[int32]*retryableStmt // stmtId -> statement
+	txnNumber int64
+}
+
+// retryableStmt contains the result of a retryable write statement.
+type retryableStmt struct {
+	done chan struct{}        // closed when the statement is executed
+	res  wirebson.RawDocument // nil if the statement failed
+}
+
+// StartRetryableWrite checks whether the write statement with the given transaction number
+// and statement ID was already executed in the session.
+// If the session does not exist, a new session is created implicitly.
+//
+// If the statement was executed, its stored result is returned;
+// the caller should reply with it instead of executing the statement again.
+// If the same statement is being executed concurrently (for example, the driver retried it after a network error
+// while the first attempt is still running), it waits for that execution to finish first.
+//
+// Otherwise, it returns nil result and a function that the caller must call once the statement is executed,
+// with the command's result or nil if the command failed and could be retried.
+//
+// It returns an error if the transaction number is older than the last one of the session.
+func (r *Registry) StartRetryableWrite(ctx context.Context, userID UserID, sessionID uuid.UUID, txnNumber int64, stmtID int32) (wirebson.RawDocument, func(wirebson.RawDocument), error) { //nolint:lll // for readability
+	for {
+		r.rw.Lock()
+		res, finish, wait, err := r.startRetryableWrite(ctx, userID, sessionID, txnNumber, stmtID)
+		r.rw.Unlock()
+
+		if wait == nil {
+			return res, finish, err
+		}
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, nil, lazyerrors.Error(ctx.Err())
+		}
+	}
+}
+
+// startRetryableWrite implements [Registry.StartRetryableWrite].
+// It returns a non-nil channel if the statement is being executed, and the caller should wait for it.
+//
+// It does not hold RWMutex, hence caller should hold RWMutex.
+func (r *Registry) startRetryableWrite(ctx context.Context, userID UserID, sessionID uuid.UUID, txnNumber int64, stmtID int32) (wirebson.RawDocument, func(wirebson.RawDocument), <-chan struct{}, error) { //nolint:lll // for readability
+	r.createOrUpdateSessions(ctx, userID, []uuid.UUID{sessionID})
+
+	s := r.sessions[userID][sessionID]
+
+	last := int64(-1)
+	if s.txn != nil {
+		last = s.txn.txnNumber
+	}
+
+	if s.retryable != nil {
+		last = max(last, s.retryable.txnNumber)
+	}
+
+	if txnNumber < last || (s.txn != nil && txnNumber == s.txn.txnNumber) {
+		msg := fmt.Sprintf(
+			"Retryable write with txnNumber %d is prohibited on session %s "+
+				"because a newer retryable write or transaction with txnNumber %d has already started on this session.",
+			txnNumber, sessionID, last,
+		)
+
+		return nil, nil, nil, mongoerrors.NewWithArgument(mongoerrors.ErrTransactionTooOld, msg, "txnNumber")
+	}
+
+	w := s.retryable
+	if w == nil || w.txnNumber < txnNumber {
+		w = &retryableWrites{
+			stmts:     map[int32]*retryableStmt{},
+			txnNumber: txnNumber,
+		}
+		s.retryable = w
+	}
+
+	stmt := w.stmts[stmtID]
+	if stmt != nil {
+		select {
+		case <-stmt.done:
+			// failed statements are removed, so the result is always present
+			return stmt.res, nil, nil, nil
+		default:
+			return nil, nil, stmt.done, nil
+		}
+	}
+
+	stmt = &retryableStmt{
+		done: make(chan struct{}),
+	}
+	w.stmts[stmtID] = stmt
+
+	finish := func(res wirebson.RawDocument) {
+		r.rw.Lock()
+		defer r.rw.Unlock()
+
+		stmt.res = res
+
+		if res == nil && w.stmts[stmtID] == stmt {
+			delete(w.stmts, stmtID)
+		}
+
+		close(stmt.done)
+	}
+
+	return nil, finish, nil, nil
+}
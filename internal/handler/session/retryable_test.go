@@ -0,0 +1,102 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestRetryableWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	r := NewRegistry(time.Minute, testutil.Logger(t))
+	t.Cleanup(r.Stop)
+
+	userID := GetUIDFromUsername("", "")
+	sessionID := uuid.New()
+
+	raw := must.NotFail(wirebson.MustDocument("n", int32(1), "ok", float64(1)).Encode())
+
+	stored, finish, err := r.StartRetryableWrite(ctx, userID, sessionID, 1, 0)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+	require.NotNil(t, finish)
+
+	retried := make(chan wirebson.RawDocument)
+
+	go func() {
+		// waits for the first execution
+		res, _, _ := r.StartRetryableWrite(ctx, userID, sessionID, 1, 0)
+		retried <- res
+	}()
+
+	finish(raw)
+	assert.Equal(t, raw, <-retried)
+
+	t.Run("Failed", func(t *testing.T) {
+		stored, finish, err = r.StartRetryableWrite(ctx, userID, sessionID, 1, 1)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		finish(nil)
+
+		// failed statement is executed again
+		stored, finish, err = r.StartRetryableWrite(ctx, userID, sessionID, 1, 1)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		finish(raw)
+	})
+
+	t.Run("TooOld", func(t *testing.T) {
+		stored, finish, err = r.StartRetryableWrite(ctx, userID, sessionID, 2, 0)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		finish(raw)
+
+		_, _, err = r.StartRetryableWrite(ctx, userID, sessionID, 1, 0)
+
+		var e *mongoerrors.Error
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, int32(mongoerrors.ErrTransactionTooOld), e.Code)
+
+		_, err = r.StartTxn(ctx, userID, sessionID, 2, nil)
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, int32(mongoerrors.ErrTransactionTooOld), e.Code)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		r.DeleteSessionsByIDs(userID, []uuid.UUID{sessionID})
+
+		stored, finish, err = r.StartRetryableWrite(ctx, userID, sessionID, 2, 0)
+		require.NoError(t, err)
+		assert.Nil(t, stored)
+
+		finish(nil)
+	})
+}
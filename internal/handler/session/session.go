@@ -26,6 +26,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
@@ -45,6 +46,8 @@ func (s UserID) String() string {
 // sessionInfo contains information of a session.
 type sessionInfo struct {
 	cursorIDs map[int64]struct{}
+	txn       *txnInfo         // the last transaction, if any
+	retryable *retryableWrites // results of the last retryable writes, if any
 	created   time.Time
 	lastUsed  time.Time
 	ended     bool
@@ -67,9 +70,20 @@ func newSessionInfo() *sessionInfo {
 	return s
 }
 
-// close untracks the session information.
+// close rolls back the in-progress transaction, if any, and untracks the session information.
+//
+// Rollback happens in the background to avoid waiting for the transaction's
+// current operation while the registry is locked.
 func (s *sessionInfo) close() {
+	if s.txn != nil && s.txn.state == txnInProgress {
+		go func(txn *documentdb.Txn) {
+			_ = txn.Rollback(context.Background())
+		}(s.txn.txn)
+	}
+
 	s.cursorIDs = nil
+	s.txn = nil
+	s.retryable = nil
 	resource.Untrack(s, s.token)
 }
 
@@ -130,13 +144,8 @@ func getSessionUUID(spec wirebson.RawDocument) (uuid.UUID, error) {
 // getUserID gets the username from conninfo and returns the hash of <username>@<database>.
 // If there is no logged-in user, it returns a hash of an empty string.
 func getUserID(ctx context.Context) UserID {
-	var username, db string
-
-	if conv := conninfo.Get(ctx).Conv(); conv != nil {
-		// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/864
-		username = conv.Username()
-		db = "admin"
-	}
+	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/864
+	username, db := conninfo.Get(ctx).UserDB()
 
 	return GetUIDFromUsername(db, username)
 }
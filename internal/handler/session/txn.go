@@ -0,0 +1,258 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+)
+
+// txnState represents the state of a multi-document transaction.
+type txnState int
+
+const (
+	txnInProgress txnState = iota
+	txnCommitted
+	txnAborted
+)
+
+// txnInfo contains information of the last multi-document transaction of a session.
+type txnInfo struct {
+	txn       *documentdb.Txn
+	started   time.Time
+	txnNumber int64
+	state     txnState
+}
+
+// StartTxn stores a new in-progress transaction with the given transaction number for the session.
+// If the session does not exist, a new session is created implicitly.
+//
+// If the session has an older in-progress transaction, it is marked as aborted and returned,
+// so the caller could roll it back.
+// It returns an error if the transaction number is not greater than the last one.
+func (r *Registry) StartTxn(ctx context.Context, userID UserID, sessionID uuid.UUID, txnNumber int64, txn *documentdb.Txn) (*documentdb.Txn, error) { //nolint:lll // for readability
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.createOrUpdateSessions(ctx, userID, []uuid.UUID{sessionID})
+
+	s := r.sessions[userID][sessionID]
+
+	if w := s.retryable; w != nil && txnNumber <= w.txnNumber {
+		msg := fmt.Sprintf(
+			"Cannot start transaction %d on session %s because a newer retryable write %d has already started",
+			txnNumber, sessionID, w.txnNumber,
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTransactionTooOld, msg, "startTransaction")
+	}
+
+	var prev *documentdb.Txn
+
+	if t := s.txn; t != nil {
+		if txnNumber <= t.txnNumber {
+			msg := fmt.Sprintf(
+				"Cannot start transaction %d on session %s because a newer transaction %d has already started",
+				txnNumber, sessionID, t.txnNumber,
+			)
+
+			if txnNumber == t.txnNumber {
+				msg = fmt.Sprintf(
+					"Transaction with { txnNumber: %d } has been already started on session %s",
+					txnNumber, sessionID,
+				)
+			}
+
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTransactionTooOld, msg, "startTransaction")
+		}
+
+		if t.state == txnInProgress {
+			prev = t.txn
+		}
+	}
+
+	s.txn = &txnInfo{
+		txn:       txn,
+		started:   time.Now(),
+		txnNumber: txnNumber,
+		state:     txnInProgress,
+	}
+
+	return prev, nil
+}
+
+// GetTxn returns the in-progress transaction with the given transaction number of the session.
+//
+// It returns an error with [mongoerrors.LabelTransientTransactionError] label
+// if there is no such transaction or if it was aborted.
+func (r *Registry) GetTxn(userID UserID, sessionID uuid.UUID, txnNumber int64) (*documentdb.Txn, error) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	t, err := r.getTxn(userID, sessionID, txnNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.state {
+	case txnInProgress:
+		return t.txn, nil
+
+	case txnCommitted:
+		msg := fmt.Sprintf(
+			"Transaction %d has been committed; cannot continue it on session %s",
+			txnNumber, sessionID,
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTransactionCommitted, msg, "txnNumber")
+
+	default:
+		return nil, noSuchTxn(sessionID, txnNumber)
+	}
+}
+
+// CommitTxn marks the transaction with the given transaction number as committed,
+// and returns it so the caller could commit it.
+//
+// If the transaction was already committed, it returns nil and no error;
+// that allows drivers to retry `commitTransaction`.
+func (r *Registry) CommitTxn(userID UserID, sessionID uuid.UUID, txnNumber int64) (*documentdb.Txn, error) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	t, err := r.getTxn(userID, sessionID, txnNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.state {
+	case txnInProgress:
+		t.state = txnCommitted
+		return t.txn, nil
+
+	case txnCommitted:
+		return nil, nil
+
+	default:
+		return nil, noSuchTxn(sessionID, txnNumber)
+	}
+}
+
+// AbortTxn marks the transaction with the given transaction number as aborted,
+// and returns it so the caller could roll it back.
+//
+// It returns an error if the transaction was already committed or aborted.
+func (r *Registry) AbortTxn(userID UserID, sessionID uuid.UUID, txnNumber int64) (*documentdb.Txn, error) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	t, err := r.getTxn(userID, sessionID, txnNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.state {
+	case txnInProgress:
+		t.state = txnAborted
+		return t.txn, nil
+
+	case txnCommitted:
+		msg := fmt.Sprintf("Transaction %d has been committed; cannot abort it on session %s", txnNumber, sessionID)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTransactionCommitted, msg, "abortTransaction")
+
+	default:
+		return nil, noSuchTxn(sessionID, txnNumber)
+	}
+}
+
+// SetTxnAborted marks the transaction with the given transaction number as aborted regardless of its state.
+// It is used when commit fails.
+// If there is no such transaction, it does nothing.
+func (r *Registry) SetTxnAborted(userID UserID, sessionID uuid.UUID, txnNumber int64) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if t, _ := r.getTxn(userID, sessionID, txnNumber); t != nil {
+		t.state = txnAborted
+	}
+}
+
+// AbortExpiredTxns marks in-progress transactions that were started more than timeout ago as aborted,
+// and returns them so the caller could roll them back.
+func (r *Registry) AbortExpiredTxns(ctx context.Context, timeout time.Duration) []*documentdb.Txn {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var res []*documentdb.Txn
+
+	for userID, sessions := range r.sessions {
+		for sessionID, s := range sessions {
+			t := s.txn
+			if t == nil || t.state != txnInProgress || time.Since(t.started) <= timeout {
+				continue
+			}
+
+			r.l.InfoContext(
+				ctx, "Aborting expired transaction",
+				slog.String("user_id", userID.String()), slog.String("session_id", sessionID.String()),
+				slog.Int64("txn_number", t.txnNumber),
+			)
+
+			t.state = txnAborted
+			res = append(res, t.txn)
+		}
+	}
+
+	return res
+}
+
+// getTxn returns the transaction information with the given transaction number.
+//
+// It does not hold RWMutex, hence caller should hold RWMutex.
+func (r *Registry) getTxn(userID UserID, sessionID uuid.UUID, txnNumber int64) (*txnInfo, error) {
+	s := r.sessions[userID][sessionID]
+	if s == nil || s.txn == nil || s.txn.txnNumber < txnNumber {
+		return nil, noSuchTxn(sessionID, txnNumber)
+	}
+
+	if s.txn.txnNumber > txnNumber {
+		msg := fmt.Sprintf(
+			"Cannot continue transaction %d on session %s because a newer transaction %d has started",
+			txnNumber, sessionID, s.txn.txnNumber,
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTransactionTooOld, msg, "txnNumber")
+	}
+
+	return s.txn, nil
+}
+
+// noSuchTxn returns NoSuchTransaction error with the label that allows drivers to retry the whole transaction.
+func noSuchTxn(sessionID uuid.UUID, txnNumber int64) error {
+	msg := fmt.Sprintf(
+		"Transaction with { txnNumber: %d } has been aborted or does not exist on session %s",
+		txnNumber, sessionID,
+	)
+
+	return mongoerrors.NewWithArgument(mongoerrors.ErrNoSuchTransaction, msg, "txnNumber").
+		WithLabels(mongoerrors.LabelTransientTransactionError)
+}
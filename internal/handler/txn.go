@@ -0,0 +1,203 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// txnCommands contains commands that could be run inside multi-document transactions.
+var txnCommands = map[string]struct{}{
+	"abortTransaction":  {},
+	"aggregate":         {},
+	"commitTransaction": {},
+	"delete":            {},
+	"distinct":          {},
+	"find":              {},
+	"findAndModify":     {},
+	"getMore":           {},
+	"insert":            {},
+	"killCursors":       {},
+	"update":            {},
+}
+
+// txnParams represents multi-document transaction parameters of the command.
+type txnParams struct {
+	txnNumber        int64
+	startTransaction bool
+}
+
+// getTxnParams returns multi-document transaction parameters of the command,
+// or nil if the command is not a part of a multi-document transaction.
+func getTxnParams(doc *wirebson.Document) (*txnParams, error) {
+	command := doc.Command()
+
+	v := doc.Get("autocommit")
+	if v == nil {
+		if doc.Get("startTransaction") != nil {
+			msg := "'autocommit' field must be specified with 'startTransaction'"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+		}
+
+		return nil, nil
+	}
+
+	if autocommit, ok := v.(bool); !ok || autocommit {
+		msg := "autocommit must be false"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+	}
+
+	if _, ok := txnCommands[command]; !ok {
+		msg := fmt.Sprintf("Cannot run '%s' in a multi-document transaction.", command)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrOperationNotSupportedInTransaction, msg, command)
+	}
+
+	txnNumber, err := getRequiredParam[int64](doc, "txnNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	var startTransaction bool
+
+	if v = doc.Get("startTransaction"); v != nil {
+		if startTransaction, err = getBoolParam("startTransaction", v); err != nil {
+			return nil, err
+		}
+
+		if !startTransaction {
+			msg := "startTransaction must be true"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+		}
+	}
+
+	return &txnParams{
+		txnNumber:        txnNumber,
+		startTransaction: startTransaction,
+	}, nil
+}
+
+// withTxn returns a command handler that runs the given handler inside the multi-document transaction
+// if the command has `autocommit: false` field.
+//
+// Connections acquired from the pool by the handler are replaced with the transaction's connection.
+// The transaction is aborted if the handler returns an error.
+func (h *Handler) withTxn(command string, cmdHandler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) { //nolint:lll // for readability
+	return func(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		spec := msg.RawSection0()
+
+		doc, err := spec.Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		params, err := getTxnParams(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if params == nil {
+			return cmdHandler(connCtx, msg)
+		}
+
+		if doc.Get("lsid") == nil {
+			msg := "Transaction numbers are only allowed on a replica set member or mongos"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrIllegalOperation, msg, command)
+		}
+
+		if command == "commitTransaction" || command == "abortTransaction" {
+			return cmdHandler(connCtx, msg)
+		}
+
+		userID, sessionID, err := h.s.CreateOrUpdateByLSID(connCtx, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		var txn *documentdb.Txn
+
+		if params.startTransaction {
+			if txn, err = h.startTxn(connCtx, userID, sessionID, params.txnNumber); err != nil {
+				return nil, err
+			}
+		} else {
+			if txn, err = h.s.GetTxn(userID, sessionID, params.txnNumber); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := cmdHandler(documentdb.TxnCtx(connCtx, txn), msg)
+		if err != nil {
+			h.abortTxn(connCtx, userID, sessionID, params.txnNumber)
+
+			var e *mongoerrors.Error
+			if errors.As(err, &e) && e.Code == int32(mongoerrors.ErrWriteConflict) {
+				return nil, e.WithLabels(mongoerrors.LabelTransientTransactionError)
+			}
+
+			return nil, err
+		}
+
+		return res, nil
+	}
+}
+
+// startTxn begins a new transaction and stores it in the session registry.
+// The previous in-progress transaction of the session, if any, is rolled back.
+func (h *Handler) startTxn(ctx context.Context, userID session.UserID, sessionID uuid.UUID, txnNumber int64) (*documentdb.Txn, error) { //nolint:lll // for readability
+	txn, err := h.Pool.BeginTxn(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	prev, err := h.s.StartTxn(ctx, userID, sessionID, txnNumber, txn)
+	if err != nil {
+		_ = txn.Rollback(ctx)
+		return nil, err
+	}
+
+	if prev != nil {
+		if err = prev.Rollback(ctx); err != nil {
+			h.L.WarnContext(ctx, "Failed to roll back previous transaction", logging.Error(err))
+		}
+	}
+
+	return txn, nil
+}
+
+// abortTxn marks the transaction as aborted in the session registry and rolls it back.
+func (h *Handler) abortTxn(ctx context.Context, userID session.UserID, sessionID uuid.UUID, txnNumber int64) {
+	txn, err := h.s.AbortTxn(userID, sessionID, txnNumber)
+	if err != nil {
+		h.L.DebugContext(ctx, "Transaction is not in progress", logging.Error(err))
+		return
+	}
+
+	if err = txn.Rollback(ctx); err != nil {
+		h.L.WarnContext(ctx, "Failed to roll back transaction", slog.Int64("txn_number", txnNumber), logging.Error(err))
+	}
+}
@@ -29,8 +29,10 @@ func _() {
 	_ = x[ErrRoleNotFound-31]
 	_ = x[ErrCannotBackfillArray-34]
 	_ = x[ErrConflictingUpdateOperators-40]
+	_ = x[ErrInvalidRoleModification-42]
 	_ = x[ErrCursorNotFound-43]
 	_ = x[ErrNamespaceExists-48]
+	_ = x[ErrMaxTimeMSExpired-50]
 	_ = x[ErrDollarPrefixedFieldName-52]
 	_ = x[ErrCanNotBeTypeArray-53]
 	_ = x[ErrNotSingleValueField-54]
@@ -48,32 +50,44 @@ func _() {
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
 	_ = x[ErrNotExactValueField-111]
+	_ = x[ErrWriteConflict-112]
 	_ = x[ErrCommandNotSupported-115]
 	_ = x[ErrNamespaceNotSharded-118]
 	_ = x[ErrDocumentFailedValidation-121]
+	_ = x[ErrFailedToSatisfyReadPreference-133]
 	_ = x[ErrExceededMemoryLimit-146]
 	_ = x[ErrDurationOverflow-159]
+	_ = x[ErrMaxStalenessOutOfRange-160]
 	_ = x[ErrViewDepthLimitExceeded-165]
 	_ = x[ErrCommandNotSupportedOnView-166]
 	_ = x[ErrOptionNotSupportedOnView-167]
+	_ = x[ErrQueryPlanKilled-175]
 	_ = x[ErrAmbiguousIndexKeyPattern-181]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidUUID-207]
 	_ = x[ErrQueryFeatureNotAllowed-224]
+	_ = x[ErrTransactionTooOld-225]
 	_ = x[ErrMaxSubPipelineDepthExceeded-232]
 	_ = x[ErrNotImplemented-238]
 	_ = x[ErrConversionFailure-241]
+	_ = x[ErrNoSuchTransaction-251]
+	_ = x[ErrTransactionCommitted-256]
+	_ = x[ErrInvalidResumeToken-260]
 	_ = x[ErrOperationNotSupportedInTransaction-263]
 	_ = x[ErrIndexBuildAborted-276]
+	_ = x[ErrChangeStreamFatalError-280]
+	_ = x[ErrChangeStreamHistoryLost-286]
 	_ = x[ErrUnableToFindIndex-291]
 	_ = x[ErrMechanismUnavailable-334]
 	_ = x[ErrUnsupportedOpQueryCommand-352]
 	_ = x[ErrCollectionUUIDMismatch-361]
+	_ = x[ErrReauthenticationRequired-391]
 	_ = x[ErrUserCountLimitExceeded-8000]
 	_ = x[ErrLocation10065-10065]
 	_ = x[ErrBsonObjectTooLarge-10334]
 	_ = x[ErrDuplicateKey-11000]
+	_ = x[ErrInterrupted-11601]
 	_ = x[ErrBackgroundOperationInProgressForNamespace-12587]
 	_ = x[ErrLocation13026-13026]
 	_ = x[ErrLocation13027-13027]
@@ -369,6 +383,7 @@ func _() {
 	_ = x[ErrLocation50759-50759]
 	_ = x[ErrLocation50840-50840]
 	_ = x[ErrLocation50989-50989]
+	_ = x[ErrLocation51002-51002]
 	_ = x[ErrLocation51003-51003]
 	_ = x[ErrLocation51024-51024]
 	_ = x[ErrLocation51044-51044]
@@ -534,7 +549,7 @@ func _() {
 	_ = x[ErrLocation8993000-8993000]
 }
 
-const _Code_name = "UnsetInternalErrorBadValueGraphContainsCycleFailedToParseUserNotFoundUnsupportedFormatUnauthorizedTypeMismatchOverflowInvalidLengthProtocolErrorAuthenticationFailedIllegalOperationAlreadyInitializedNamespaceNotFoundIndexNotFoundPathNotViableRoleNotFoundCannotBackfillArrayConflictingUpdateOperatorsCursorNotFoundNamespaceExistsDollarPrefixedFieldNameCanNotBeTypeArrayNotSingleValueFieldLocation55EmptyFieldNameDottedFieldNameCommandNotFoundShardKeyNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedNotExactValueFieldCommandNotSupportedNamespaceNotShardedDocumentFailedValidationExceededMemoryLimitDurationOverflowViewDepthLimitExceededCommandNotSupportedOnViewOptionNotSupportedOnViewAmbiguousIndexKeyPatternClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionInvalidUUIDQueryFeatureNotAllowedMaxSubPipelineDepthExceededNotImplementedConversionFailureOperationNotSupportedInTransactionIndexBuildAbortedUnableToFindIndexMechanismUnavailableUnsupportedOpQueryCommandCollectionUUIDMismatchUserCountLimitExceededLocation10065BsonObjectTooLargeDuplicateKeyBackgroundOperationInProgressForNamespaceLocation13026Location13027Location13068Location13111MergeStageNoMatchingDocumentDbAlreadyExistsLocation13548Location15947Location15952Location15955Location15957Location15958Location15959Location15972Location15976Location15981Location15998Location16004Location16006Location16007Location16020Location16034Location16035Location16410Location16411Location16433DollarAddNumericOrDateTypesDollarModByZeroProhibitedDollarModOnlyNumericDollarAddOnlyOneDateLocation16702Location16747Location16748Location16749Location16755Location16764HashedIndexDoNotSupportArrayValuesLocation16800Location16801Location16804Location16874Location16875Location16876Location16878Location16879Location16880Location16882Location16883Location16979Location16990Location16994Location17040Location17041Location17042Location17043Location17044Location17045Location17046Location17047Location17048Location17049Location17053DollarCondMissingIfParameterDollarCondMissingThenParameterDollarCondMissingElseParameterDollarCondBadParameterDollarSizeRequiresArrayExactlyOneTextIndexLocation17261Location17276Location17308Location17310DocumentAfterUpdateLargerThanMaxSizeDocumentToUpsertLargerThanMaxSizeLocation18533Location18534Location18535Location18536Location18537Location18628Location18629Location28625Location28646Location28647Location28648Location28650Location28651Location28656Location28657Location28664RangeArgumentExpressionArgsOutOfRangeDollarAbsCantTakeLongMinValueArrayOperatorElemAtFirstArgMustBeArrayDollarArrayElemAtSecondArgArgMustBeNumericDollarArrayElemAtSecondArgArgMustBe32BitDollarSqrtGreaterOrEqualToZeroDollarSliceInvalidInputDollarSliceInvalidTypeSecondArgDollarSliceInvalidValueSecondArgDollarSliceInvalidTypeThirdArgDollarSliceInvalidValueThirdArgDollarSliceInvalidSignThirdArgLocation28745Location28746Location28747Location28748Location28749DollarLogArgumentMustBeNumericDollarLogBaseMustBeNumericDollarLogNumberMustBePositiveDollarLogBaseMustBeGreaterThanOneDollarLog10MustBePositiveNumberDollarPowBaseMustBeNumericDollarPowExponentMustBeNumericDollarPowExponentInvalidForZeroBaseLocation28765DollarLnMustBePositiveNumberLocation28769Location28803Location28808Location28809Location28810Location28811Location28812Location28818Location28822Location31002Location31022Location31023Location31024KeyCannotContainNullByteLocation31034Location31095Location31109Location31119Location31120Location31138Location31170Location31249Location31250Location31253Location31254Location31256Location31271Location31276Location31308Location31325Location31393Location31395Location31441Location31465Location34435Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location34471Location34473DollarSwitchRequiresObjectDollarSwitchRequiresArrayForBranchesDollarSwitchRequiresObjectForEachBranchDollarSwitchUnknownArgumentForBranchDollarSwitchRequiresCaseExpressionForBranchDollarSwitchRequiresThenExpressionForBranchDollarSwitchNoMatchingBranchAndNoDefaultDollarSwitchBadArgumentDollarSwitchRequiresAtLeastOneBranchLocation40075Location40076Location40077Location40078Location40079Location40080DollarInRequiresArrayLocation40085Location40086Location40087Location40090Location40091Location40092Location40093Location40094Location40096Location40097Location40100Location40101Location40102Location40103Location40104Location40105Location40147Location40156Location40158Location40160Location40169Location40177Location40181Location40185Location40191Location40192Location40193Location40194Location40195Location40196Location40197Location40198Location40199Location40200Location40201Location40202Location40218Location40228Location40229Location40234Location40235Location40236Location40237Location40238Location40272Location40319Location40321Location40323UnrecognizedCommandLocation40352DollarArrayToObjectRequiresArrayDollarObjectToArrayRequiresObjectDollarArrayToObjectAllMustBeObjectsDollarArrayToObjectIncorrectNumberOfKeysDollarArrayToObjectRequiresObjectWithKAndVDollarArrayToObjectObjectKeyMustBeStringDollarArrayToObjectArrayKeyMustBeStringDollarArrayToObjectAllMustBeArraysDollarArrayToObjectIncorrectArrayLengthDollarArrayToObjectBadInputTypeFormatDollarMergeObjectsInvalidTypeLocation40414UnknownBsonFieldLocation40485Location40489Location40515Location40516Location40517Location40518Location40519Location40520Location40521Location40522Location40523Location40524Location40525Location40533Location40535Location40536Location40539Location40540Location40541Location40542Location40600Location40601Location40602Location40603Location40621ChangeStreamBadResumeTokenLocation40684Location50687Location50692Location50694Location50695Location50696Location50699Location50700Location50723Location50752Location50759Location50840Location50989Location51003Location51024Location51044Location51045Location51047Location51074Location51075DollarRoundOverflowInt64DollarRoundFirstArgMustBeNumericDollarRoundPrecisionMustBeIntegralDollarRoundPrecisionOutOfRangeLocation51091Location51103Location51104Location51105Location51106Location51107Location51108Location51109Location51110Location51111Location51132Location51134Location51151Location51156Location51178Location51183Location51185Location51186Location51187Location51191Location51246Location51247Location51276Location51743Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location327391Location327392Location605001DollarIfNullRequiresAtLeastTwoArgsLocation2942500Location2942501Location2942502Location2942503Location2942504Location2942505Location2942506DollarRandNonEmptyArgumentLocation3041701Location3041702Location3041703IntermediateResultTooLargeDollarSetFieldRequiresObjectDollarSetFieldUnknownArgumentLocation4161102Location4161103Location4161104Location4161105Location4161106Location4161107Location4161108Location4161109Location4890500Location4940400Location4940401Location5107200Location5107201Location5166301Location5166302Location5166303Location5166304Location5166305Location5166307Location5166400Location5166401Location5166402Location5166403Location5166404Location5166405Location5166406Location5339900Location5339901Location5339902Location5371601Location5371602Location5371603Location5423900Location5423901Location5423902Location5429413Location5429414Location5429513Location5439007Location5439008Location5439009Location5439010Location5439012Location5439013Location5439014Location5439015Location5439016Location5439017Location5439018Location5490710Location5624900Location5624901Location5626500Location5654600Location5654601Location5654602Location5687301Location5687302Location5687400Location5687401Location5733201Location5733401Location5733402Location5733403Location5733406Location5733408Location5733409Location5739101Location5746102Location5787801Location5787900Location5787901Location5787902Location5787903Location5787906Location5787907Location5787908Location5788001Location5788002Location5788003Location5788004Location5788005Location5788200Location5788604Location5858203Location5876900Location5897900Location5946802Location5976500Location6007200Location6045000Location6050106Location6050202Location6050204Location6053600Location6586400Location7429703Location7436100Location7750301Location7750302Location7750303Location8993000"
+const _Code_name = "UnsetInternalErrorBadValueGraphContainsCycleFailedToParseUserNotFoundUnsupportedFormatUnauthorizedTypeMismatchOverflowInvalidLengthProtocolErrorAuthenticationFailedIllegalOperationAlreadyInitializedNamespaceNotFoundIndexNotFoundPathNotViableRoleNotFoundCannotBackfillArrayConflictingUpdateOperatorsInvalidRoleModificationCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameCanNotBeTypeArrayNotSingleValueFieldLocation55EmptyFieldNameDottedFieldNameCommandNotFoundShardKeyNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedNotExactValueFieldWriteConflictCommandNotSupportedNamespaceNotShardedDocumentFailedValidationFailedToSatisfyReadPreferenceExceededMemoryLimitDurationOverflowMaxStalenessOutOfRangeViewDepthLimitExceededCommandNotSupportedOnViewOptionNotSupportedOnViewQueryPlanKilledAmbiguousIndexKeyPatternClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionInvalidUUIDQueryFeatureNotAllowedTransactionTooOldMaxSubPipelineDepthExceededNotImplementedConversionFailureNoSuchTransactionTransactionCommittedInvalidResumeTokenOperationNotSupportedInTransactionIndexBuildAbortedChangeStreamFatalErrorChangeStreamHistoryLostUnableToFindIndexMechanismUnavailableUnsupportedOpQueryCommandCollectionUUIDMismatchReauthenticationRequiredUserCountLimitExceededLocation10065BsonObjectTooLargeDuplicateKeyInterruptedBackgroundOperationInProgressForNamespaceLocation13026Location13027Location13068Location13111MergeStageNoMatchingDocumentDbAlreadyExistsLocation13548Location15947Location15952Location15955Location15957Location15958Location15959Location15972Location15976Location15981Location15998Location16004Location16006Location16007Location16020Location16034Location16035Location16410Location16411Location16433DollarAddNumericOrDateTypesDollarModByZeroProhibitedDollarModOnlyNumericDollarAddOnlyOneDateLocation16702Location16747Location16748Location16749Location16755Location16764HashedIndexDoNotSupportArrayValuesLocation16800Location16801Location16804Location16874Location16875Location16876Location16878Location16879Location16880Location16882Location16883Location16979Location16990Location16994Location17040Location17041Location17042Location17043Location17044Location17045Location17046Location17047Location17048Location17049Location17053DollarCondMissingIfParameterDollarCondMissingThenParameterDollarCondMissingElseParameterDollarCondBadParameterDollarSizeRequiresArrayExactlyOneTextIndexLocation17261Location17276Location17308Location17310DocumentAfterUpdateLargerThanMaxSizeDocumentToUpsertLargerThanMaxSizeLocation18533Location18534Location18535Location18536Location18537Location18628Location18629Location28625Location28646Location28647Location28648Location28650Location28651Location28656Location28657Location28664RangeArgumentExpressionArgsOutOfRangeDollarAbsCantTakeLongMinValueArrayOperatorElemAtFirstArgMustBeArrayDollarArrayElemAtSecondArgArgMustBeNumericDollarArrayElemAtSecondArgArgMustBe32BitDollarSqrtGreaterOrEqualToZeroDollarSliceInvalidInputDollarSliceInvalidTypeSecondArgDollarSliceInvalidValueSecondArgDollarSliceInvalidTypeThirdArgDollarSliceInvalidValueThirdArgDollarSliceInvalidSignThirdArgLocation28745Location28746Location28747Location28748Location28749DollarLogArgumentMustBeNumericDollarLogBaseMustBeNumericDollarLogNumberMustBePositiveDollarLogBaseMustBeGreaterThanOneDollarLog10MustBePositiveNumberDollarPowBaseMustBeNumericDollarPowExponentMustBeNumericDollarPowExponentInvalidForZeroBaseLocation28765DollarLnMustBePositiveNumberLocation28769Location28803Location28808Location28809Location28810Location28811Location28812Location28818Location28822Location31002Location31022Location31023Location31024KeyCannotContainNullByteLocation31034Location31095Location31109Location31119Location31120Location31138Location31170Location31249Location31250Location31253Location31254Location31256Location31271Location31276Location31308Location31325Location31393Location31395Location31441Location31465Location34435Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location34471Location34473DollarSwitchRequiresObjectDollarSwitchRequiresArrayForBranchesDollarSwitchRequiresObjectForEachBranchDollarSwitchUnknownArgumentForBranchDollarSwitchRequiresCaseExpressionForBranchDollarSwitchRequiresThenExpressionForBranchDollarSwitchNoMatchingBranchAndNoDefaultDollarSwitchBadArgumentDollarSwitchRequiresAtLeastOneBranchLocation40075Location40076Location40077Location40078Location40079Location40080DollarInRequiresArrayLocation40085Location40086Location40087Location40090Location40091Location40092Location40093Location40094Location40096Location40097Location40100Location40101Location40102Location40103Location40104Location40105Location40147Location40156Location40158Location40160Location40169Location40177Location40181Location40185Location40191Location40192Location40193Location40194Location40195Location40196Location40197Location40198Location40199Location40200Location40201Location40202Location40218Location40228Location40229Location40234Location40235Location40236Location40237Location40238Location40272Location40319Location40321Location40323UnrecognizedCommandLocation40352DollarArrayToObjectRequiresArrayDollarObjectToArrayRequiresObjectDollarArrayToObjectAllMustBeObjectsDollarArrayToObjectIncorrectNumberOfKeysDollarArrayToObjectRequiresObjectWithKAndVDollarArrayToObjectObjectKeyMustBeStringDollarArrayToObjectArrayKeyMustBeStringDollarArrayToObjectAllMustBeArraysDollarArrayToObjectIncorrectArrayLengthDollarArrayToObjectBadInputTypeFormatDollarMergeObjectsInvalidTypeLocation40414UnknownBsonFieldLocation40485Location40489Location40515Location40516Location40517Location40518Location40519Location40520Location40521Location40522Location40523Location40524Location40525Location40533Location40535Location40536Location40539Location40540Location40541Location40542Location40600Location40601Location40602Location40603Location40621ChangeStreamBadResumeTokenLocation40684Location50687Location50692Location50694Location50695Location50696Location50699Location50700Location50723Location50752Location50759Location50840Location50989Location51002Location51003Location51024Location51044Location51045Location51047Location51074Location51075DollarRoundOverflowInt64DollarRoundFirstArgMustBeNumericDollarRoundPrecisionMustBeIntegralDollarRoundPrecisionOutOfRangeLocation51091Location51103Location51104Location51105Location51106Location51107Location51108Location51109Location51110Location51111Location51132Location51134Location51151Location51156Location51178Location51183Location51185Location51186Location51187Location51191Location51246Location51247Location51276Location51743Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location327391Location327392Location605001DollarIfNullRequiresAtLeastTwoArgsLocation2942500Location2942501Location2942502Location2942503Location2942504Location2942505Location2942506DollarRandNonEmptyArgumentLocation3041701Location3041702Location3041703IntermediateResultTooLargeDollarSetFieldRequiresObjectDollarSetFieldUnknownArgumentLocation4161102Location4161103Location4161104Location4161105Location4161106Location4161107Location4161108Location4161109Location4890500Location4940400Location4940401Location5107200Location5107201Location5166301Location5166302Location5166303Location5166304Location5166305Location5166307Location5166400Location5166401Location5166402Location5166403Location5166404Location5166405Location5166406Location5339900Location5339901Location5339902Location5371601Location5371602Location5371603Location5423900Location5423901Location5423902Location5429413Location5429414Location5429513Location5439007Location5439008Location5439009Location5439010Location5439012Location5439013Location5439014Location5439015Location5439016Location5439017Location5439018Location5490710Location5624900Location5624901Location5626500Location5654600Location5654601Location5654602Location5687301Location5687302Location5687400Location5687401Location5733201Location5733401Location5733402Location5733403Location5733406Location5733408Location5733409Location5739101Location5746102Location5787801Location5787900Location5787901Location5787902Location5787903Location5787906Location5787907Location5787908Location5788001Location5788002Location5788003Location5788004Location5788005Location5788200Location5788604Location5858203Location5876900Location5897900Location5946802Location5976500Location6007200Location6045000Location6050106Location6050202Location6050204Location6053600Location6586400Location7429703Location7436100Location7750301Location7750302Location7750303Location8993000"
 
 var _Code_map = map[Code]string{
 	0:       _Code_name[0:5],
@@ -558,509 +573,524 @@ var _Code_map = map[Code]string{
 	31:      _Code_name[241:253],
 	34:      _Code_name[253:272],
 	40:      _Code_name[272:298],
-	43:      _Code_name[298:312],
-	48:      _Code_name[312:327],
-	52:      _Code_name[327:350],
-	53:      _Code_name[350:367],
-	54:      _Code_name[367:386],
-	55:      _Code_name[386:396],
-	56:      _Code_name[396:410],
-	57:      _Code_name[410:425],
-	59:      _Code_name[425:440],
-	61:      _Code_name[440:456],
-	66:      _Code_name[456:470],
-	67:      _Code_name[470:487],
-	68:      _Code_name[487:505],
-	72:      _Code_name[505:519],
-	73:      _Code_name[519:535],
-	85:      _Code_name[535:555],
-	86:      _Code_name[555:576],
-	96:      _Code_name[576:591],
-	111:     _Code_name[591:609],
-	115:     _Code_name[609:628],
-	118:     _Code_name[628:647],
-	121:     _Code_name[647:671],
-	146:     _Code_name[671:690],
-	159:     _Code_name[690:706],
-	165:     _Code_name[706:728],
-	166:     _Code_name[728:753],
-	167:     _Code_name[753:777],
-	181:     _Code_name[777:801],
-	186:     _Code_name[801:830],
-	197:     _Code_name[830:861],
-	207:     _Code_name[861:872],
-	224:     _Code_name[872:894],
-	232:     _Code_name[894:921],
-	238:     _Code_name[921:935],
-	241:     _Code_name[935:952],
-	263:     _Code_name[952:986],
-	276:     _Code_name[986:1003],
-	291:     _Code_name[1003:1020],
-	334:     _Code_name[1020:1040],
-	352:     _Code_name[1040:1065],
-	361:     _Code_name[1065:1087],
-	8000:    _Code_name[1087:1109],
-	10065:   _Code_name[1109:1122],
-	10334:   _Code_name[1122:1140],
-	11000:   _Code_name[1140:1152],
-	12587:   _Code_name[1152:1193],
-	13026:   _Code_name[1193:1206],
-	13027:   _Code_name[1206:1219],
-	13068:   _Code_name[1219:1232],
-	13111:   _Code_name[1232:1245],
-	13113:   _Code_name[1245:1273],
-	13297:   _Code_name[1273:1288],
-	13548:   _Code_name[1288:1301],
-	15947:   _Code_name[1301:1314],
-	15952:   _Code_name[1314:1327],
-	15955:   _Code_name[1327:1340],
-	15957:   _Code_name[1340:1353],
-	15958:   _Code_name[1353:1366],
-	15959:   _Code_name[1366:1379],
-	15972:   _Code_name[1379:1392],
-	15976:   _Code_name[1392:1405],
-	15981:   _Code_name[1405:1418],
-	15998:   _Code_name[1418:1431],
-	16004:   _Code_name[1431:1444],
-	16006:   _Code_name[1444:1457],
-	16007:   _Code_name[1457:1470],
-	16020:   _Code_name[1470:1483],
-	16034:   _Code_name[1483:1496],
-	16035:   _Code_name[1496:1509],
-	16410:   _Code_name[1509:1522],
-	16411:   _Code_name[1522:1535],
-	16433:   _Code_name[1535:1548],
-	16554:   _Code_name[1548:1575],
-	16610:   _Code_name[1575:1600],
-	16611:   _Code_name[1600:1620],
-	16612:   _Code_name[1620:1640],
-	16702:   _Code_name[1640:1653],
-	16747:   _Code_name[1653:1666],
-	16748:   _Code_name[1666:1679],
-	16749:   _Code_name[1679:1692],
-	16755:   _Code_name[1692:1705],
-	16764:   _Code_name[1705:1718],
-	16766:   _Code_name[1718:1752],
-	16800:   _Code_name[1752:1765],
-	16801:   _Code_name[1765:1778],
-	16804:   _Code_name[1778:1791],
-	16874:   _Code_name[1791:1804],
-	16875:   _Code_name[1804:1817],
-	16876:   _Code_name[1817:1830],
-	16878:   _Code_name[1830:1843],
-	16879:   _Code_name[1843:1856],
-	16880:   _Code_name[1856:1869],
-	16882:   _Code_name[1869:1882],
-	16883:   _Code_name[1882:1895],
-	16979:   _Code_name[1895:1908],
-	16990:   _Code_name[1908:1921],
-	16994:   _Code_name[1921:1934],
-	17040:   _Code_name[1934:1947],
-	17041:   _Code_name[1947:1960],
-	17042:   _Code_name[1960:1973],
-	17043:   _Code_name[1973:1986],
-	17044:   _Code_name[1986:1999],
-	17045:   _Code_name[1999:2012],
-	17046:   _Code_name[2012:2025],
-	17047:   _Code_name[2025:2038],
-	17048:   _Code_name[2038:2051],
-	17049:   _Code_name[2051:2064],
-	17053:   _Code_name[2064:2077],
-	17080:   _Code_name[2077:2105],
-	17081:   _Code_name[2105:2135],
-	17082:   _Code_name[2135:2165],
-	17083:   _Code_name[2165:2187],
-	17124:   _Code_name[2187:2210],
-	17194:   _Code_name[2210:2229],
-	17261:   _Code_name[2229:2242],
-	17276:   _Code_name[2242:2255],
-	17308:   _Code_name[2255:2268],
-	17310:   _Code_name[2268:2281],
-	17419:   _Code_name[2281:2317],
-	17420:   _Code_name[2317:2350],
-	18533:   _Code_name[2350:2363],
-	18534:   _Code_name[2363:2376],
-	18535:   _Code_name[2376:2389],
-	18536:   _Code_name[2389:2402],
-	18537:   _Code_name[2402:2415],
-	18628:   _Code_name[2415:2428],
-	18629:   _Code_name[2428:2441],
-	28625:   _Code_name[2441:2454],
-	28646:   _Code_name[2454:2467],
-	28647:   _Code_name[2467:2480],
-	28648:   _Code_name[2480:2493],
-	28650:   _Code_name[2493:2506],
-	28651:   _Code_name[2506:2519],
-	28656:   _Code_name[2519:2532],
-	28657:   _Code_name[2532:2545],
-	28664:   _Code_name[2545:2558],
-	28667:   _Code_name[2558:2595],
-	28680:   _Code_name[2595:2624],
-	28689:   _Code_name[2624:2662],
-	28690:   _Code_name[2662:2704],
-	28691:   _Code_name[2704:2744],
-	28714:   _Code_name[2744:2774],
-	28724:   _Code_name[2774:2797],
-	28725:   _Code_name[2797:2828],
-	28726:   _Code_name[2828:2860],
-	28727:   _Code_name[2860:2890],
-	28728:   _Code_name[2890:2921],
-	28729:   _Code_name[2921:2951],
-	28745:   _Code_name[2951:2964],
-	28746:   _Code_name[2964:2977],
-	28747:   _Code_name[2977:2990],
-	28748:   _Code_name[2990:3003],
-	28749:   _Code_name[3003:3016],
-	28756:   _Code_name[3016:3046],
-	28757:   _Code_name[3046:3072],
-	28758:   _Code_name[3072:3101],
-	28759:   _Code_name[3101:3134],
-	28761:   _Code_name[3134:3165],
-	28762:   _Code_name[3165:3191],
-	28763:   _Code_name[3191:3221],
-	28764:   _Code_name[3221:3256],
-	28765:   _Code_name[3256:3269],
-	28766:   _Code_name[3269:3297],
-	28769:   _Code_name[3297:3310],
-	28803:   _Code_name[3310:3323],
-	28808:   _Code_name[3323:3336],
-	28809:   _Code_name[3336:3349],
-	28810:   _Code_name[3349:3362],
-	28811:   _Code_name[3362:3375],
-	28812:   _Code_name[3375:3388],
-	28818:   _Code_name[3388:3401],
-	28822:   _Code_name[3401:3414],
-	31002:   _Code_name[3414:3427],
-	31022:   _Code_name[3427:3440],
-	31023:   _Code_name[3440:3453],
-	31024:   _Code_name[3453:3466],
-	31032:   _Code_name[3466:3490],
-	31034:   _Code_name[3490:3503],
-	31095:   _Code_name[3503:3516],
-	31109:   _Code_name[3516:3529],
-	31119:   _Code_name[3529:3542],
-	31120:   _Code_name[3542:3555],
-	31138:   _Code_name[3555:3568],
-	31170:   _Code_name[3568:3581],
-	31249:   _Code_name[3581:3594],
-	31250:   _Code_name[3594:3607],
-	31253:   _Code_name[3607:3620],
-	31254:   _Code_name[3620:3633],
-	31256:   _Code_name[3633:3646],
-	31271:   _Code_name[3646:3659],
-	31276:   _Code_name[3659:3672],
-	31308:   _Code_name[3672:3685],
-	31325:   _Code_name[3685:3698],
-	31393:   _Code_name[3698:3711],
-	31395:   _Code_name[3711:3724],
-	31441:   _Code_name[3724:3737],
-	31465:   _Code_name[3737:3750],
-	34435:   _Code_name[3750:3763],
-	34443:   _Code_name[3763:3776],
-	34444:   _Code_name[3776:3789],
-	34445:   _Code_name[3789:3802],
-	34446:   _Code_name[3802:3815],
-	34447:   _Code_name[3815:3828],
-	34448:   _Code_name[3828:3841],
-	34449:   _Code_name[3841:3854],
-	34450:   _Code_name[3854:3867],
-	34451:   _Code_name[3867:3880],
-	34452:   _Code_name[3880:3893],
-	34453:   _Code_name[3893:3906],
-	34454:   _Code_name[3906:3919],
-	34455:   _Code_name[3919:3932],
-	34460:   _Code_name[3932:3945],
-	34461:   _Code_name[3945:3958],
-	34462:   _Code_name[3958:3971],
-	34463:   _Code_name[3971:3984],
-	34464:   _Code_name[3984:3997],
-	34465:   _Code_name[3997:4010],
-	34466:   _Code_name[4010:4023],
-	34467:   _Code_name[4023:4036],
-	34468:   _Code_name[4036:4049],
-	34471:   _Code_name[4049:4062],
-	34473:   _Code_name[4062:4075],
-	40060:   _Code_name[4075:4101],
-	40061:   _Code_name[4101:4137],
-	40062:   _Code_name[4137:4176],
-	40063:   _Code_name[4176:4212],
-	40064:   _Code_name[4212:4255],
-	40065:   _Code_name[4255:4298],
-	40066:   _Code_name[4298:4338],
-	40067:   _Code_name[4338:4361],
-	40068:   _Code_name[4361:4397],
-	40075:   _Code_name[4397:4410],
-	40076:   _Code_name[4410:4423],
-	40077:   _Code_name[4423:4436],
-	40078:   _Code_name[4436:4449],
-	40079:   _Code_name[4449:4462],
-	40080:   _Code_name[4462:4475],
-	40081:   _Code_name[4475:4496],
-	40085:   _Code_name[4496:4509],
-	40086:   _Code_name[4509:4522],
-	40087:   _Code_name[4522:4535],
-	40090:   _Code_name[4535:4548],
-	40091:   _Code_name[4548:4561],
-	40092:   _Code_name[4561:4574],
-	40093:   _Code_name[4574:4587],
-	40094:   _Code_name[4587:4600],
-	40096:   _Code_name[4600:4613],
-	40097:   _Code_name[4613:4626],
-	40100:   _Code_name[4626:4639],
-	40101:   _Code_name[4639:4652],
-	40102:   _Code_name[4652:4665],
-	40103:   _Code_name[4665:4678],
-	40104:   _Code_name[4678:4691],
-	40105:   _Code_name[4691:4704],
-	40147:   _Code_name[4704:4717],
-	40156:   _Code_name[4717:4730],
-	40158:   _Code_name[4730:4743],
-	40160:   _Code_name[4743:4756],
-	40169:   _Code_name[4756:4769],
-	40177:   _Code_name[4769:4782],
-	40181:   _Code_name[4782:4795],
-	40185:   _Code_name[4795:4808],
-	40191:   _Code_name[4808:4821],
-	40192:   _Code_name[4821:4834],
-	40193:   _Code_name[4834:4847],
-	40194:   _Code_name[4847:4860],
-	40195:   _Code_name[4860:4873],
-	40196:   _Code_name[4873:4886],
-	40197:   _Code_name[4886:4899],
-	40198:   _Code_name[4899:4912],
-	40199:   _Code_name[4912:4925],
-	40200:   _Code_name[4925:4938],
-	40201:   _Code_name[4938:4951],
-	40202:   _Code_name[4951:4964],
-	40218:   _Code_name[4964:4977],
-	40228:   _Code_name[4977:4990],
-	40229:   _Code_name[4990:5003],
-	40234:   _Code_name[5003:5016],
-	40235:   _Code_name[5016:5029],
-	40236:   _Code_name[5029:5042],
-	40237:   _Code_name[5042:5055],
-	40238:   _Code_name[5055:5068],
-	40272:   _Code_name[5068:5081],
-	40319:   _Code_name[5081:5094],
-	40321:   _Code_name[5094:5107],
-	40323:   _Code_name[5107:5120],
-	40324:   _Code_name[5120:5139],
-	40352:   _Code_name[5139:5152],
-	40386:   _Code_name[5152:5184],
-	40390:   _Code_name[5184:5217],
-	40391:   _Code_name[5217:5252],
-	40392:   _Code_name[5252:5292],
-	40393:   _Code_name[5292:5334],
-	40394:   _Code_name[5334:5374],
-	40395:   _Code_name[5374:5413],
-	40396:   _Code_name[5413:5447],
-	40397:   _Code_name[5447:5486],
-	40398:   _Code_name[5486:5523],
-	40400:   _Code_name[5523:5552],
-	40414:   _Code_name[5552:5565],
-	40415:   _Code_name[5565:5581],
-	40485:   _Code_name[5581:5594],
-	40489:   _Code_name[5594:5607],
-	40515:   _Code_name[5607:5620],
-	40516:   _Code_name[5620:5633],
-	40517:   _Code_name[5633:5646],
-	40518:   _Code_name[5646:5659],
-	40519:   _Code_name[5659:5672],
-	40520:   _Code_name[5672:5685],
-	40521:   _Code_name[5685:5698],
-	40522:   _Code_name[5698:5711],
-	40523:   _Code_name[5711:5724],
-	40524:   _Code_name[5724:5737],
-	40525:   _Code_name[5737:5750],
-	40533:   _Code_name[5750:5763],
-	40535:   _Code_name[5763:5776],
-	40536:   _Code_name[5776:5789],
-	40539:   _Code_name[5789:5802],
-	40540:   _Code_name[5802:5815],
-	40541:   _Code_name[5815:5828],
-	40542:   _Code_name[5828:5841],
-	40600:   _Code_name[5841:5854],
-	40601:   _Code_name[5854:5867],
-	40602:   _Code_name[5867:5880],
-	40603:   _Code_name[5880:5893],
-	40621:   _Code_name[5893:5906],
-	40647:   _Code_name[5906:5932],
-	40684:   _Code_name[5932:5945],
-	50687:   _Code_name[5945:5958],
-	50692:   _Code_name[5958:5971],
-	50694:   _Code_name[5971:5984],
-	50695:   _Code_name[5984:5997],
-	50696:   _Code_name[5997:6010],
-	50699:   _Code_name[6010:6023],
-	50700:   _Code_name[6023:6036],
-	50723:   _Code_name[6036:6049],
-	50752:   _Code_name[6049:6062],
-	50759:   _Code_name[6062:6075],
-	50840:   _Code_name[6075:6088],
-	50989:   _Code_name[6088:6101],
-	51003:   _Code_name[6101:6114],
-	51024:   _Code_name[6114:6127],
-	51044:   _Code_name[6127:6140],
-	51045:   _Code_name[6140:6153],
-	51047:   _Code_name[6153:6166],
-	51074:   _Code_name[6166:6179],
-	51075:   _Code_name[6179:6192],
-	51080:   _Code_name[6192:6216],
-	51081:   _Code_name[6216:6248],
-	51082:   _Code_name[6248:6282],
-	51083:   _Code_name[6282:6312],
-	51091:   _Code_name[6312:6325],
-	51103:   _Code_name[6325:6338],
-	51104:   _Code_name[6338:6351],
-	51105:   _Code_name[6351:6364],
-	51106:   _Code_name[6364:6377],
-	51107:   _Code_name[6377:6390],
-	51108:   _Code_name[6390:6403],
-	51109:   _Code_name[6403:6416],
-	51110:   _Code_name[6416:6429],
-	51111:   _Code_name[6429:6442],
-	51132:   _Code_name[6442:6455],
-	51134:   _Code_name[6455:6468],
-	51151:   _Code_name[6468:6481],
-	51156:   _Code_name[6481:6494],
-	51178:   _Code_name[6494:6507],
-	51183:   _Code_name[6507:6520],
-	51185:   _Code_name[6520:6533],
-	51186:   _Code_name[6533:6546],
-	51187:   _Code_name[6546:6559],
-	51191:   _Code_name[6559:6572],
-	51246:   _Code_name[6572:6585],
-	51247:   _Code_name[6585:6598],
-	51276:   _Code_name[6598:6611],
-	51743:   _Code_name[6611:6624],
-	51744:   _Code_name[6624:6637],
-	51745:   _Code_name[6637:6650],
-	51746:   _Code_name[6650:6663],
-	51747:   _Code_name[6663:6676],
-	51748:   _Code_name[6676:6689],
-	51749:   _Code_name[6689:6702],
-	51750:   _Code_name[6702:6715],
-	51751:   _Code_name[6715:6728],
-	327391:  _Code_name[6728:6742],
-	327392:  _Code_name[6742:6756],
-	605001:  _Code_name[6756:6770],
-	1257300: _Code_name[6770:6804],
-	2942500: _Code_name[6804:6819],
-	2942501: _Code_name[6819:6834],
-	2942502: _Code_name[6834:6849],
-	2942503: _Code_name[6849:6864],
-	2942504: _Code_name[6864:6879],
-	2942505: _Code_name[6879:6894],
-	2942506: _Code_name[6894:6909],
-	3040501: _Code_name[6909:6935],
-	3041701: _Code_name[6935:6950],
-	3041702: _Code_name[6950:6965],
-	3041703: _Code_name[6965:6980],
-	4031700: _Code_name[6980:7006],
-	4161100: _Code_name[7006:7034],
-	4161101: _Code_name[7034:7063],
-	4161102: _Code_name[7063:7078],
-	4161103: _Code_name[7078:7093],
-	4161104: _Code_name[7093:7108],
-	4161105: _Code_name[7108:7123],
-	4161106: _Code_name[7123:7138],
-	4161107: _Code_name[7138:7153],
-	4161108: _Code_name[7153:7168],
-	4161109: _Code_name[7168:7183],
-	4890500: _Code_name[7183:7198],
-	4940400: _Code_name[7198:7213],
-	4940401: _Code_name[7213:7228],
-	5107200: _Code_name[7228:7243],
-	5107201: _Code_name[7243:7258],
-	5166301: _Code_name[7258:7273],
-	5166302: _Code_name[7273:7288],
-	5166303: _Code_name[7288:7303],
-	5166304: _Code_name[7303:7318],
-	5166305: _Code_name[7318:7333],
-	5166307: _Code_name[7333:7348],
-	5166400: _Code_name[7348:7363],
-	5166401: _Code_name[7363:7378],
-	5166402: _Code_name[7378:7393],
-	5166403: _Code_name[7393:7408],
-	5166404: _Code_name[7408:7423],
-	5166405: _Code_name[7423:7438],
-	5166406: _Code_name[7438:7453],
-	5339900: _Code_name[7453:7468],
-	5339901: _Code_name[7468:7483],
-	5339902: _Code_name[7483:7498],
-	5371601: _Code_name[7498:7513],
-	5371602: _Code_name[7513:7528],
-	5371603: _Code_name[7528:7543],
-	5423900: _Code_name[7543:7558],
-	5423901: _Code_name[7558:7573],
-	5423902: _Code_name[7573:7588],
-	5429413: _Code_name[7588:7603],
-	5429414: _Code_name[7603:7618],
-	5429513: _Code_name[7618:7633],
-	5439007: _Code_name[7633:7648],
-	5439008: _Code_name[7648:7663],
-	5439009: _Code_name[7663:7678],
-	5439010: _Code_name[7678:7693],
-	5439012: _Code_name[7693:7708],
-	5439013: _Code_name[7708:7723],
-	5439014: _Code_name[7723:7738],
-	5439015: _Code_name[7738:7753],
-	5439016: _Code_name[7753:7768],
-	5439017: _Code_name[7768:7783],
-	5439018: _Code_name[7783:7798],
-	5490710: _Code_name[7798:7813],
-	5624900: _Code_name[7813:7828],
-	5624901: _Code_name[7828:7843],
-	5626500: _Code_name[7843:7858],
-	5654600: _Code_name[7858:7873],
-	5654601: _Code_name[7873:7888],
-	5654602: _Code_name[7888:7903],
-	5687301: _Code_name[7903:7918],
-	5687302: _Code_name[7918:7933],
-	5687400: _Code_name[7933:7948],
-	5687401: _Code_name[7948:7963],
-	5733201: _Code_name[7963:7978],
-	5733401: _Code_name[7978:7993],
-	5733402: _Code_name[7993:8008],
-	5733403: _Code_name[8008:8023],
-	5733406: _Code_name[8023:8038],
-	5733408: _Code_name[8038:8053],
-	5733409: _Code_name[8053:8068],
-	5739101: _Code_name[8068:8083],
-	5746102: _Code_name[8083:8098],
-	5787801: _Code_name[8098:8113],
-	5787900: _Code_name[8113:8128],
-	5787901: _Code_name[8128:8143],
-	5787902: _Code_name[8143:8158],
-	5787903: _Code_name[8158:8173],
-	5787906: _Code_name[8173:8188],
-	5787907: _Code_name[8188:8203],
-	5787908: _Code_name[8203:8218],
-	5788001: _Code_name[8218:8233],
-	5788002: _Code_name[8233:8248],
-	5788003: _Code_name[8248:8263],
-	5788004: _Code_name[8263:8278],
-	5788005: _Code_name[8278:8293],
-	5788200: _Code_name[8293:8308],
-	5788604: _Code_name[8308:8323],
-	5858203: _Code_name[8323:8338],
-	5876900: _Code_name[8338:8353],
-	5897900: _Code_name[8353:8368],
-	5946802: _Code_name[8368:8383],
-	5976500: _Code_name[8383:8398],
-	6007200: _Code_name[8398:8413],
-	6045000: _Code_name[8413:8428],
-	6050106: _Code_name[8428:8443],
-	6050202: _Code_name[8443:8458],
-	6050204: _Code_name[8458:8473],
-	6053600: _Code_name[8473:8488],
-	6586400: _Code_name[8488:8503],
-	7429703: _Code_name[8503:8518],
-	7436100: _Code_name[8518:8533],
-	7750301: _Code_name[8533:8548],
-	7750302: _Code_name[8548:8563],
-	7750303: _Code_name[8563:8578],
-	8993000: _Code_name[8578:8593],
+	42:      _Code_name[298:321],
+	43:      _Code_name[321:335],
+	48:      _Code_name[335:350],
+	50:      _Code_name[350:366],
+	52:      _Code_name[366:389],
+	53:      _Code_name[389:406],
+	54:      _Code_name[406:425],
+	55:      _Code_name[425:435],
+	56:      _Code_name[435:449],
+	57:      _Code_name[449:464],
+	59:      _Code_name[464:479],
+	61:      _Code_name[479:495],
+	66:      _Code_name[495:509],
+	67:      _Code_name[509:526],
+	68:      _Code_name[526:544],
+	72:      _Code_name[544:558],
+	73:      _Code_name[558:574],
+	85:      _Code_name[574:594],
+	86:      _Code_name[594:615],
+	96:      _Code_name[615:630],
+	111:     _Code_name[630:648],
+	112:     _Code_name[648:661],
+	115:     _Code_name[661:680],
+	118:     _Code_name[680:699],
+	121:     _Code_name[699:723],
+	133:     _Code_name[723:752],
+	146:     _Code_name[752:771],
+	159:     _Code_name[771:787],
+	160:     _Code_name[787:809],
+	165:     _Code_name[809:831],
+	166:     _Code_name[831:856],
+	167:     _Code_name[856:880],
+	175:     _Code_name[880:895],
+	181:     _Code_name[895:919],
+	186:     _Code_name[919:948],
+	197:     _Code_name[948:979],
+	207:     _Code_name[979:990],
+	224:     _Code_name[990:1012],
+	225:     _Code_name[1012:1029],
+	232:     _Code_name[1029:1056],
+	238:     _Code_name[1056:1070],
+	241:     _Code_name[1070:1087],
+	251:     _Code_name[1087:1104],
+	256:     _Code_name[1104:1124],
+	260:     _Code_name[1124:1142],
+	263:     _Code_name[1142:1176],
+	276:     _Code_name[1176:1193],
+	280:     _Code_name[1193:1215],
+	286:     _Code_name[1215:1238],
+	291:     _Code_name[1238:1255],
+	334:     _Code_name[1255:1275],
+	352:     _Code_name[1275:1300],
+	361:     _Code_name[1300:1322],
+	391:     _Code_name[1322:1346],
+	8000:    _Code_name[1346:1368],
+	10065:   _Code_name[1368:1381],
+	10334:   _Code_name[1381:1399],
+	11000:   _Code_name[1399:1411],
+	11601:   _Code_name[1411:1422],
+	12587:   _Code_name[1422:1463],
+	13026:   _Code_name[1463:1476],
+	13027:   _Code_name[1476:1489],
+	13068:   _Code_name[1489:1502],
+	13111:   _Code_name[1502:1515],
+	13113:   _Code_name[1515:1543],
+	13297:   _Code_name[1543:1558],
+	13548:   _Code_name[1558:1571],
+	15947:   _Code_name[1571:1584],
+	15952:   _Code_name[1584:1597],
+	15955:   _Code_name[1597:1610],
+	15957:   _Code_name[1610:1623],
+	15958:   _Code_name[1623:1636],
+	15959:   _Code_name[1636:1649],
+	15972:   _Code_name[1649:1662],
+	15976:   _Code_name[1662:1675],
+	15981:   _Code_name[1675:1688],
+	15998:   _Code_name[1688:1701],
+	16004:   _Code_name[1701:1714],
+	16006:   _Code_name[1714:1727],
+	16007:   _Code_name[1727:1740],
+	16020:   _Code_name[1740:1753],
+	16034:   _Code_name[1753:1766],
+	16035:   _Code_name[1766:1779],
+	16410:   _Code_name[1779:1792],
+	16411:   _Code_name[1792:1805],
+	16433:   _Code_name[1805:1818],
+	16554:   _Code_name[1818:1845],
+	16610:   _Code_name[1845:1870],
+	16611:   _Code_name[1870:1890],
+	16612:   _Code_name[1890:1910],
+	16702:   _Code_name[1910:1923],
+	16747:   _Code_name[1923:1936],
+	16748:   _Code_name[1936:1949],
+	16749:   _Code_name[1949:1962],
+	16755:   _Code_name[1962:1975],
+	16764:   _Code_name[1975:1988],
+	16766:   _Code_name[1988:2022],
+	16800:   _Code_name[2022:2035],
+	16801:   _Code_name[2035:2048],
+	16804:   _Code_name[2048:2061],
+	16874:   _Code_name[2061:2074],
+	16875:   _Code_name[2074:2087],
+	16876:   _Code_name[2087:2100],
+	16878:   _Code_name[2100:2113],
+	16879:   _Code_name[2113:2126],
+	16880:   _Code_name[2126:2139],
+	16882:   _Code_name[2139:2152],
+	16883:   _Code_name[2152:2165],
+	16979:   _Code_name[2165:2178],
+	16990:   _Code_name[2178:2191],
+	16994:   _Code_name[2191:2204],
+	17040:   _Code_name[2204:2217],
+	17041:   _Code_name[2217:2230],
+	17042:   _Code_name[2230:2243],
+	17043:   _Code_name[2243:2256],
+	17044:   _Code_name[2256:2269],
+	17045:   _Code_name[2269:2282],
+	17046:   _Code_name[2282:2295],
+	17047:   _Code_name[2295:2308],
+	17048:   _Code_name[2308:2321],
+	17049:   _Code_name[2321:2334],
+	17053:   _Code_name[2334:2347],
+	17080:   _Code_name[2347:2375],
+	17081:   _Code_name[2375:2405],
+	17082:   _Code_name[2405:2435],
+	17083:   _Code_name[2435:2457],
+	17124:   _Code_name[2457:2480],
+	17194:   _Code_name[2480:2499],
+	17261:   _Code_name[2499:2512],
+	17276:   _Code_name[2512:2525],
+	17308:   _Code_name[2525:2538],
+	17310:   _Code_name[2538:2551],
+	17419:   _Code_name[2551:2587],
+	17420:   _Code_name[2587:2620],
+	18533:   _Code_name[2620:2633],
+	18534:   _Code_name[2633:2646],
+	18535:   _Code_name[2646:2659],
+	18536:   _Code_name[2659:2672],
+	18537:   _Code_name[2672:2685],
+	18628:   _Code_name[2685:2698],
+	18629:   _Code_name[2698:2711],
+	28625:   _Code_name[2711:2724],
+	28646:   _Code_name[2724:2737],
+	28647:   _Code_name[2737:2750],
+	28648:   _Code_name[2750:2763],
+	28650:   _Code_name[2763:2776],
+	28651:   _Code_name[2776:2789],
+	28656:   _Code_name[2789:2802],
+	28657:   _Code_name[2802:2815],
+	28664:   _Code_name[2815:2828],
+	28667:   _Code_name[2828:2865],
+	28680:   _Code_name[2865:2894],
+	28689:   _Code_name[2894:2932],
+	28690:   _Code_name[2932:2974],
+	28691:   _Code_name[2974:3014],
+	28714:   _Code_name[3014:3044],
+	28724:   _Code_name[3044:3067],
+	28725:   _Code_name[3067:3098],
+	28726:   _Code_name[3098:3130],
+	28727:   _Code_name[3130:3160],
+	28728:   _Code_name[3160:3191],
+	28729:   _Code_name[3191:3221],
+	28745:   _Code_name[3221:3234],
+	28746:   _Code_name[3234:3247],
+	28747:   _Code_name[3247:3260],
+	28748:   _Code_name[3260:3273],
+	28749:   _Code_name[3273:3286],
+	28756:   _Code_name[3286:3316],
+	28757:   _Code_name[3316:3342],
+	28758:   _Code_name[3342:3371],
+	28759:   _Code_name[3371:3404],
+	28761:   _Code_name[3404:3435],
+	28762:   _Code_name[3435:3461],
+	28763:   _Code_name[3461:3491],
+	28764:   _Code_name[3491:3526],
+	28765:   _Code_name[3526:3539],
+	28766:   _Code_name[3539:3567],
+	28769:   _Code_name[3567:3580],
+	28803:   _Code_name[3580:3593],
+	28808:   _Code_name[3593:3606],
+	28809:   _Code_name[3606:3619],
+	28810:   _Code_name[3619:3632],
+	28811:   _Code_name[3632:3645],
+	28812:   _Code_name[3645:3658],
+	28818:   _Code_name[3658:3671],
+	28822:   _Code_name[3671:3684],
+	31002:   _Code_name[3684:3697],
+	31022:   _Code_name[3697:3710],
+	31023:   _Code_name[3710:3723],
+	31024:   _Code_name[3723:3736],
+	31032:   _Code_name[3736:3760],
+	31034:   _Code_name[3760:3773],
+	31095:   _Code_name[3773:3786],
+	31109:   _Code_name[3786:3799],
+	31119:   _Code_name[3799:3812],
+	31120:   _Code_name[3812:3825],
+	31138:   _Code_name[3825:3838],
+	31170:   _Code_name[3838:3851],
+	31249:   _Code_name[3851:3864],
+	31250:   _Code_name[3864:3877],
+	31253:   _Code_name[3877:3890],
+	31254:   _Code_name[3890:3903],
+	31256:   _Code_name[3903:3916],
+	31271:   _Code_name[3916:3929],
+	31276:   _Code_name[3929:3942],
+	31308:   _Code_name[3942:3955],
+	31325:   _Code_name[3955:3968],
+	31393:   _Code_name[3968:3981],
+	31395:   _Code_name[3981:3994],
+	31441:   _Code_name[3994:4007],
+	31465:   _Code_name[4007:4020],
+	34435:   _Code_name[4020:4033],
+	34443:   _Code_name[4033:4046],
+	34444:   _Code_name[4046:4059],
+	34445:   _Code_name[4059:4072],
+	34446:   _Code_name[4072:4085],
+	34447:   _Code_name[4085:4098],
+	34448:   _Code_name[4098:4111],
+	34449:   _Code_name[4111:4124],
+	34450:   _Code_name[4124:4137],
+	34451:   _Code_name[4137:4150],
+	34452:   _Code_name[4150:4163],
+	34453:   _Code_name[4163:4176],
+	34454:   _Code_name[4176:4189],
+	34455:   _Code_name[4189:4202],
+	34460:   _Code_name[4202:4215],
+	34461:   _Code_name[4215:4228],
+	34462:   _Code_name[4228:4241],
+	34463:   _Code_name[4241:4254],
+	34464:   _Code_name[4254:4267],
+	34465:   _Code_name[4267:4280],
+	34466:   _Code_name[4280:4293],
+	34467:   _Code_name[4293:4306],
+	34468:   _Code_name[4306:4319],
+	34471:   _Code_name[4319:4332],
+	34473:   _Code_name[4332:4345],
+	40060:   _Code_name[4345:4371],
+	40061:   _Code_name[4371:4407],
+	40062:   _Code_name[4407:4446],
+	40063:   _Code_name[4446:4482],
+	40064:   _Code_name[4482:4525],
+	40065:   _Code_name[4525:4568],
+	40066:   _Code_name[4568:4608],
+	40067:   _Code_name[4608:4631],
+	40068:   _Code_name[4631:4667],
+	40075:   _Code_name[4667:4680],
+	40076:   _Code_name[4680:4693],
+	40077:   _Code_name[4693:4706],
+	40078:   _Code_name[4706:4719],
+	40079:   _Code_name[4719:4732],
+	40080:   _Code_name[4732:4745],
+	40081:   _Code_name[4745:4766],
+	40085:   _Code_name[4766:4779],
+	40086:   _Code_name[4779:4792],
+	40087:   _Code_name[4792:4805],
+	40090:   _Code_name[4805:4818],
+	40091:   _Code_name[4818:4831],
+	40092:   _Code_name[4831:4844],
+	40093:   _Code_name[4844:4857],
+	40094:   _Code_name[4857:4870],
+	40096:   _Code_name[4870:4883],
+	40097:   _Code_name[4883:4896],
+	40100:   _Code_name[4896:4909],
+	40101:   _Code_name[4909:4922],
+	40102:   _Code_name[4922:4935],
+	40103:   _Code_name[4935:4948],
+	40104:   _Code_name[4948:4961],
+	40105:   _Code_name[4961:4974],
+	40147:   _Code_name[4974:4987],
+	40156:   _Code_name[4987:5000],
+	40158:   _Code_name[5000:5013],
+	40160:   _Code_name[5013:5026],
+	40169:   _Code_name[5026:5039],
+	40177:   _Code_name[5039:5052],
+	40181:   _Code_name[5052:5065],
+	40185:   _Code_name[5065:5078],
+	40191:   _Code_name[5078:5091],
+	40192:   _Code_name[5091:5104],
+	40193:   _Code_name[5104:5117],
+	40194:   _Code_name[5117:5130],
+	40195:   _Code_name[5130:5143],
+	40196:   _Code_name[5143:5156],
+	40197:   _Code_name[5156:5169],
+	40198:   _Code_name[5169:5182],
+	40199:   _Code_name[5182:5195],
+	40200:   _Code_name[5195:5208],
+	40201:   _Code_name[5208:5221],
+	40202:   _Code_name[5221:5234],
+	40218:   _Code_name[5234:5247],
+	40228:   _Code_name[5247:5260],
+	40229:   _Code_name[5260:5273],
+	40234:   _Code_name[5273:5286],
+	40235:   _Code_name[5286:5299],
+	40236:   _Code_name[5299:5312],
+	40237:   _Code_name[5312:5325],
+	40238:   _Code_name[5325:5338],
+	40272:   _Code_name[5338:5351],
+	40319:   _Code_name[5351:5364],
+	40321:   _Code_name[5364:5377],
+	40323:   _Code_name[5377:5390],
+	40324:   _Code_name[5390:5409],
+	40352:   _Code_name[5409:5422],
+	40386:   _Code_name[5422:5454],
+	40390:   _Code_name[5454:5487],
+	40391:   _Code_name[5487:5522],
+	40392:   _Code_name[5522:5562],
+	40393:   _Code_name[5562:5604],
+	40394:   _Code_name[5604:5644],
+	40395:   _Code_name[5644:5683],
+	40396:   _Code_name[5683:5717],
+	40397:   _Code_name[5717:5756],
+	40398:   _Code_name[5756:5793],
+	40400:   _Code_name[5793:5822],
+	40414:   _Code_name[5822:5835],
+	40415:   _Code_name[5835:5851],
+	40485:   _Code_name[5851:5864],
+	40489:   _Code_name[5864:5877],
+	40515:   _Code_name[5877:5890],
+	40516:   _Code_name[5890:5903],
+	40517:   _Code_name[5903:5916],
+	40518:   _Code_name[5916:5929],
+	40519:   _Code_name[5929:5942],
+	40520:   _Code_name[5942:5955],
+	40521:   _Code_name[5955:5968],
+	40522:   _Code_name[5968:5981],
+	40523:   _Code_name[5981:5994],
+	40524:   _Code_name[5994:6007],
+	40525:   _Code_name[6007:6020],
+	40533:   _Code_name[6020:6033],
+	40535:   _Code_name[6033:6046],
+	40536:   _Code_name[6046:6059],
+	40539:   _Code_name[6059:6072],
+	40540:   _Code_name[6072:6085],
+	40541:   _Code_name[6085:6098],
+	40542:   _Code_name[6098:6111],
+	40600:   _Code_name[6111:6124],
+	40601:   _Code_name[6124:6137],
+	40602:   _Code_name[6137:6150],
+	40603:   _Code_name[6150:6163],
+	40621:   _Code_name[6163:6176],
+	40647:   _Code_name[6176:6202],
+	40684:   _Code_name[6202:6215],
+	50687:   _Code_name[6215:6228],
+	50692:   _Code_name[6228:6241],
+	50694:   _Code_name[6241:6254],
+	50695:   _Code_name[6254:6267],
+	50696:   _Code_name[6267:6280],
+	50699:   _Code_name[6280:6293],
+	50700:   _Code_name[6293:6306],
+	50723:   _Code_name[6306:6319],
+	50752:   _Code_name[6319:6332],
+	50759:   _Code_name[6332:6345],
+	50840:   _Code_name[6345:6358],
+	50989:   _Code_name[6358:6371],
+	51002:   _Code_name[6371:6384],
+	51003:   _Code_name[6384:6397],
+	51024:   _Code_name[6397:6410],
+	51044:   _Code_name[6410:6423],
+	51045:   _Code_name[6423:6436],
+	51047:   _Code_name[6436:6449],
+	51074:   _Code_name[6449:6462],
+	51075:   _Code_name[6462:6475],
+	51080:   _Code_name[6475:6499],
+	51081:   _Code_name[6499:6531],
+	51082:   _Code_name[6531:6565],
+	51083:   _Code_name[6565:6595],
+	51091:   _Code_name[6595:6608],
+	51103:   _Code_name[6608:6621],
+	51104:   _Code_name[6621:6634],
+	51105:   _Code_name[6634:6647],
+	51106:   _Code_name[6647:6660],
+	51107:   _Code_name[6660:6673],
+	51108:   _Code_name[6673:6686],
+	51109:   _Code_name[6686:6699],
+	51110:   _Code_name[6699:6712],
+	51111:   _Code_name[6712:6725],
+	51132:   _Code_name[6725:6738],
+	51134:   _Code_name[6738:6751],
+	51151:   _Code_name[6751:6764],
+	51156:   _Code_name[6764:6777],
+	51178:   _Code_name[6777:6790],
+	51183:   _Code_name[6790:6803],
+	51185:   _Code_name[6803:6816],
+	51186:   _Code_name[6816:6829],
+	51187:   _Code_name[6829:6842],
+	51191:   _Code_name[6842:6855],
+	51246:   _Code_name[6855:6868],
+	51247:   _Code_name[6868:6881],
+	51276:   _Code_name[6881:6894],
+	51743:   _Code_name[6894:6907],
+	51744:   _Code_name[6907:6920],
+	51745:   _Code_name[6920:6933],
+	51746:   _Code_name[6933:6946],
+	51747:   _Code_name[6946:6959],
+	51748:   _Code_name[6959:6972],
+	51749:   _Code_name[6972:6985],
+	51750:   _Code_name[6985:6998],
+	51751:   _Code_name[6998:7011],
+	327391:  _Code_name[7011:7025],
+	327392:  _Code_name[7025:7039],
+	605001:  _Code_name[7039:7053],
+	1257300: _Code_name[7053:7087],
+	2942500: _Code_name[7087:7102],
+	2942501: _Code_name[7102:7117],
+	2942502: _Code_name[7117:7132],
+	2942503: _Code_name[7132:7147],
+	2942504: _Code_name[7147:7162],
+	2942505: _Code_name[7162:7177],
+	2942506: _Code_name[7177:7192],
+	3040501: _Code_name[7192:7218],
+	3041701: _Code_name[7218:7233],
+	3041702: _Code_name[7233:7248],
+	3041703: _Code_name[7248:7263],
+	4031700: _Code_name[7263:7289],
+	4161100: _Code_name[7289:7317],
+	4161101: _Code_name[7317:7346],
+	4161102: _Code_name[7346:7361],
+	4161103: _Code_name[7361:7376],
+	4161104: _Code_name[7376:7391],
+	4161105: _Code_name[7391:7406],
+	4161106: _Code_name[7406:7421],
+	4161107: _Code_name[7421:7436],
+	4161108: _Code_name[7436:7451],
+	4161109: _Code_name[7451:7466],
+	4890500: _Code_name[7466:7481],
+	4940400: _Code_name[7481:7496],
+	4940401: _Code_name[7496:7511],
+	5107200: _Code_name[7511:7526],
+	5107201: _Code_name[7526:7541],
+	5166301: _Code_name[7541:7556],
+	5166302: _Code_name[7556:7571],
+	5166303: _Code_name[7571:7586],
+	5166304: _Code_name[7586:7601],
+	5166305: _Code_name[7601:7616],
+	5166307: _Code_name[7616:7631],
+	5166400: _Code_name[7631:7646],
+	5166401: _Code_name[7646:7661],
+	5166402: _Code_name[7661:7676],
+	5166403: _Code_name[7676:7691],
+	5166404: _Code_name[7691:7706],
+	5166405: _Code_name[7706:7721],
+	5166406: _Code_name[7721:7736],
+	5339900: _Code_name[7736:7751],
+	5339901: _Code_name[7751:7766],
+	5339902: _Code_name[7766:7781],
+	5371601: _Code_name[7781:7796],
+	5371602: _Code_name[7796:7811],
+	5371603: _Code_name[7811:7826],
+	5423900: _Code_name[7826:7841],
+	5423901: _Code_name[7841:7856],
+	5423902: _Code_name[7856:7871],
+	5429413: _Code_name[7871:7886],
+	5429414: _Code_name[7886:7901],
+	5429513: _Code_name[7901:7916],
+	5439007: _Code_name[7916:7931],
+	5439008: _Code_name[7931:7946],
+	5439009: _Code_name[7946:7961],
+	5439010: _Code_name[7961:7976],
+	5439012: _Code_name[7976:7991],
+	5439013: _Code_name[7991:8006],
+	5439014: _Code_name[8006:8021],
+	5439015: _Code_name[8021:8036],
+	5439016: _Code_name[8036:8051],
+	5439017: _Code_name[8051:8066],
+	5439018: _Code_name[8066:8081],
+	5490710: _Code_name[8081:8096],
+	5624900: _Code_name[8096:8111],
+	5624901: _Code_name[8111:8126],
+	5626500: _Code_name[8126:8141],
+	5654600: _Code_name[8141:8156],
+	5654601: _Code_name[8156:8171],
+	5654602: _Code_name[8171:8186],
+	5687301: _Code_name[8186:8201],
+	5687302: _Code_name[8201:8216],
+	5687400: _Code_name[8216:8231],
+	5687401: _Code_name[8231:8246],
+	5733201: _Code_name[8246:8261],
+	5733401: _Code_name[8261:8276],
+	5733402: _Code_name[8276:8291],
+	5733403: _Code_name[8291:8306],
+	5733406: _Code_name[8306:8321],
+	5733408: _Code_name[8321:8336],
+	5733409: _Code_name[8336:8351],
+	5739101: _Code_name[8351:8366],
+	5746102: _Code_name[8366:8381],
+	5787801: _Code_name[8381:8396],
+	5787900: _Code_name[8396:8411],
+	5787901: _Code_name[8411:8426],
+	5787902: _Code_name[8426:8441],
+	5787903: _Code_name[8441:8456],
+	5787906: _Code_name[8456:8471],
+	5787907: _Code_name[8471:8486],
+	5787908: _Code_name[8486:8501],
+	5788001: _Code_name[8501:8516],
+	5788002: _Code_name[8516:8531],
+	5788003: _Code_name[8531:8546],
+	5788004: _Code_name[8546:8561],
+	5788005: _Code_name[8561:8576],
+	5788200: _Code_name[8576:8591],
+	5788604: _Code_name[8591:8606],
+	5858203: _Code_name[8606:8621],
+	5876900: _Code_name[8621:8636],
+	5897900: _Code_name[8636:8651],
+	5946802: _Code_name[8651:8666],
+	5976500: _Code_name[8666:8681],
+	6007200: _Code_name[8681:8696],
+	6045000: _Code_name[8696:8711],
+	6050106: _Code_name[8711:8726],
+	6050202: _Code_name[8726:8741],
+	6050204: _Code_name[8741:8756],
+	6053600: _Code_name[8756:8771],
+	6586400: _Code_name[8771:8786],
+	7429703: _Code_name[8786:8801],
+	7436100: _Code_name[8801:8816],
+	7750301: _Code_name[8816:8831],
+	7750302: _Code_name[8831:8846],
+	7750303: _Code_name[8846:8861],
+	8993000: _Code_name[8861:8876],
 }
 
 func (i Code) String() string {
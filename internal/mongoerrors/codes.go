@@ -25,8 +25,10 @@ const (
 	ErrRoleNotFound                                = Code(31)      // RoleNotFound
 	ErrCannotBackfillArray                         = Code(34)      // CannotBackfillArray
 	ErrConflictingUpdateOperators                  = Code(40)      // ConflictingUpdateOperators
+	ErrInvalidRoleModification                     = Code(42)      // InvalidRoleModification
 	ErrCursorNotFound                              = Code(43)      // CursorNotFound
 	ErrNamespaceExists                             = Code(48)      // NamespaceExists
+	ErrMaxTimeMSExpired                            = Code(50)      // MaxTimeMSExpired
 	ErrDollarPrefixedFieldName                     = Code(52)      // DollarPrefixedFieldName
 	ErrCanNotBeTypeArray                           = Code(53)      // CanNotBeTypeArray
 	ErrNotSingleValueField                         = Code(54)      // NotSingleValueField
@@ -44,32 +46,44 @@ const (
 	ErrIndexKeySpecsConflict                       = Code(86)      // IndexKeySpecsConflict
 	ErrOperationFailed                             = Code(96)      // OperationFailed
 	ErrNotExactValueField                          = Code(111)     // NotExactValueField
+	ErrWriteConflict                               = Code(112)     // WriteConflict
 	ErrCommandNotSupported                         = Code(115)     // CommandNotSupported
 	ErrNamespaceNotSharded                         = Code(118)     // NamespaceNotSharded
 	ErrDocumentFailedValidation                    = Code(121)     // DocumentFailedValidation
+	ErrFailedToSatisfyReadPreference               = Code(133)     // FailedToSatisfyReadPreference
 	ErrExceededMemoryLimit                         = Code(146)     // ExceededMemoryLimit
 	ErrDurationOverflow                            = Code(159)     // DurationOverflow
+	ErrMaxStalenessOutOfRange                      = Code(160)     // MaxStalenessOutOfRange
 	ErrViewDepthLimitExceeded                      = Code(165)     // ViewDepthLimitExceeded
 	ErrCommandNotSupportedOnView                   = Code(166)     // CommandNotSupportedOnView
 	ErrOptionNotSupportedOnView                    = Code(167)     // OptionNotSupportedOnView
+	ErrQueryPlanKilled                             = Code(175)     // QueryPlanKilled
 	ErrAmbiguousIndexKeyPattern                    = Code(181)     // AmbiguousIndexKeyPattern
 	ErrClientMetadataCannotBeMutated               = Code(186)     // ClientMetadataCannotBeMutated
 	ErrInvalidIndexSpecificationOption             = Code(197)     // InvalidIndexSpecificationOption
 	ErrInvalidUUID                                 = Code(207)     // InvalidUUID
 	ErrQueryFeatureNotAllowed                      = Code(224)     // QueryFeatureNotAllowed
+	ErrTransactionTooOld                           = Code(225)     // TransactionTooOld
 	ErrMaxSubPipelineDepthExceeded                 = Code(232)     // MaxSubPipelineDepthExceeded
 	ErrNotImplemented                              = Code(238)     // NotImplemented
 	ErrConversionFailure                           = Code(241)     // ConversionFailure
+	ErrNoSuchTransaction                           = Code(251)     // NoSuchTransaction
+	ErrTransactionCommitted                        = Code(256)     // TransactionCommitted
+	ErrInvalidResumeToken                          = Code(260)     // InvalidResumeToken
 	ErrOperationNotSupportedInTransaction          = Code(263)     // OperationNotSupportedInTransaction
 	ErrIndexBuildAborted                           = Code(276)     // IndexBuildAborted
+	ErrChangeStreamFatalError                      = Code(280)     // ChangeStreamFatalError
+	ErrChangeStreamHistoryLost                     = Code(286)     // ChangeStreamHistoryLost
 	ErrUnableToFindIndex                           = Code(291)     // UnableToFindIndex
 	ErrMechanismUnavailable                        = Code(334)     // MechanismUnavailable
 	ErrUnsupportedOpQueryCommand                   = Code(352)     // UnsupportedOpQueryCommand
 	ErrCollectionUUIDMismatch                      = Code(361)     // CollectionUUIDMismatch
+	ErrReauthenticationRequired                    = Code(391)     // ReauthenticationRequired
 	ErrUserCountLimitExceeded                      = Code(8000)    // UserCountLimitExceeded
 	ErrLocation10065                               = Code(10065)   // Location10065
 	ErrBsonObjectTooLarge                          = Code(10334)   // BsonObjectTooLarge
 	ErrDuplicateKey                                = Code(11000)   // DuplicateKey
+	ErrInterrupted                                 = Code(11601)   // Interrupted
 	ErrBackgroundOperationInProgressForNamespace   = Code(12587)   // BackgroundOperationInProgressForNamespace
 	ErrLocation13026                               = Code(13026)   // Location13026
 	ErrLocation13027                               = Code(13027)   // Location13027
@@ -365,6 +379,7 @@ const (
 	ErrLocation50759                               = Code(50759)   // Location50759
 	ErrLocation50840                               = Code(50840)   // Location50840
 	ErrLocation50989                               = Code(50989)   // Location50989
+	ErrLocation51002                               = Code(51002)   // Location51002
 	ErrLocation51003                               = Code(51003)   // Location51003
 	ErrLocation51024                               = Code(51024)   // Location51024
 	ErrLocation51044                               = Code(51044)   // Location51044
@@ -16,6 +16,7 @@ package mongoerrors
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
@@ -24,6 +25,19 @@ import (
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
+// Error labels used by drivers to decide whether an operation could be retried.
+const (
+	// LabelTransientTransactionError indicates that the whole transaction could be retried.
+	LabelTransientTransactionError = "TransientTransactionError"
+
+	// LabelUnknownTransactionCommitResult indicates that the outcome of the commit is unknown,
+	// and `commitTransaction` could be retried.
+	LabelUnknownTransactionCommitResult = "UnknownTransactionCommitResult"
+
+	// LabelRetryableWriteError indicates that the retryable write could be retried.
+	LabelRetryableWriteError = "RetryableWriteError"
+)
+
 // Error represents MongoDB command error.
 type Error struct {
 	// Command's argument, operator, or aggregation pipeline stage that caused an error.
@@ -68,6 +82,21 @@ func NewWithArgument(code Code, msg, argument string) *Error {
 	}
 }
 
+// WithLabels returns a copy of the error with given labels added.
+// Existing labels are preserved; duplicates are not added.
+func (e *Error) WithLabels(labels ...string) *Error {
+	res := *e
+	res.Labels = slices.Clone(e.Labels)
+
+	for _, l := range labels {
+		if !slices.Contains(res.Labels, l) {
+			res.Labels = append(res.Labels, l)
+		}
+	}
+
+	return &res
+}
+
 // Error implements error interface.
 //
 // We overload [mongo.CommandError]'s method to ensure that Error is always passed by pointer.
@@ -87,20 +116,31 @@ func (e *Error) GoString() string {
 
 // Msg returns this error as a OP_MSG message.
 func (e *Error) Msg() *wire.OpMsg {
-	return wire.MustOpMsg(
-		"ok", float64(0),
-		"errmsg", e.Message,
-		"code", int32(e.Code),
-		"codeName", e.Name,
-	)
+	return must.NotFail(wire.NewOpMsg(e.doc()))
 }
 
 // Reply returns this error as a OP_REPLY message.
 func (e *Error) Reply() *wire.OpReply {
-	return must.NotFail(wire.NewOpReply(wirebson.MustDocument(
+	return must.NotFail(wire.NewOpReply(e.doc()))
+}
+
+// doc returns this error as a document.
+func (e *Error) doc() *wirebson.Document {
+	doc := wirebson.MustDocument(
 		"ok", float64(0),
 		"errmsg", e.Message,
 		"code", int32(e.Code),
 		"codeName", e.Name,
-	)))
+	)
+
+	if len(e.Labels) > 0 {
+		labels := wirebson.MakeArray(len(e.Labels))
+		for _, l := range e.Labels {
+			must.NoError(labels.Add(l))
+		}
+
+		must.NoError(doc.Add("errorLabels", labels))
+	}
+
+	return doc
 }
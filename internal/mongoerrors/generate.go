@@ -50,18 +50,33 @@ var extraMongoErrors = map[string]int{
 	"Unauthorized":                  13,
 	"ProtocolError":                 17,
 	"AuthenticationFailed":          18,
+	"InvalidRoleModification":       42,
+	"MaxTimeMSExpired":              50,
 	"CommandNotFound":               59,
 	"OperationFailed":               96,
+	"WriteConflict":                 112,
+	"FailedToSatisfyReadPreference": 133,
+	"MaxStalenessOutOfRange":        160,
+	"QueryPlanKilled":               175,
 	"ClientMetadataCannotBeMutated": 186,
 	"InvalidUUID":                   207,
+	"TransactionTooOld":             225,
 	"NotImplemented":                238,
+	"NoSuchTransaction":             251,
+	"TransactionCommitted":          256,
+	"InvalidResumeToken":            260,
+	"ChangeStreamFatalError":        280,
+	"ChangeStreamHistoryLost":       286,
 	"MechanismUnavailable":          334,
 	"UnsupportedOpQueryCommand":     352,
+	"ReauthenticationRequired":      391,
+	"Interrupted":                   11601,
 	"Location16979":                 16979,
 	"Location40621":                 40621,
 	"Location50687":                 50687,
 	"Location50692":                 50692,
 	"Location50840":                 50840,
+	"Location51002":                 51002,
 	"Location5739101":               5739101,
 }
 
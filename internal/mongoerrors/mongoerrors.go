@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"strings"
 
 	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgerrcode"
@@ -71,6 +72,8 @@ func goString(err error) string {
 //
 // Nil panics (it never should be passed),
 // [*Error] (possibly wrapped) is returned unwrapped,
+// [context.Canceled] (possibly wrapped) is converted to [ErrInterrupted],
+// [context.DeadlineExceeded] (possibly wrapped) is converted to [ErrMaxTimeMSExpired],
 // [*pgconn.PgError] (possibly wrapped) is converted by mapping error code,
 // any other values are returned as [*Error] with [ErrInternalError] code.
 //
@@ -90,6 +93,32 @@ func Make(ctx context.Context, err error, arg string, l *slog.Logger) *Error {
 		return e
 	}
 
+	// operation was killed or client disconnected
+	if errors.Is(err, context.Canceled) {
+		return &Error{
+			Argument: arg,
+			CommandError: mongo.CommandError{
+				Code:    int32(ErrInterrupted),
+				Message: "operation was interrupted",
+				Name:    ErrInterrupted.String(),
+				Wrapped: err,
+			},
+		}
+	}
+
+	// maxTimeMS expired
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{
+			Argument: arg,
+			CommandError: mongo.CommandError{
+				Code:    int32(ErrMaxTimeMSExpired),
+				Message: "operation exceeded time limit",
+				Name:    ErrMaxTimeMSExpired.String(),
+				Wrapped: err,
+			},
+		}
+	}
+
 	var pg *pgconn.PgError
 	if !errors.As(err, &pg) {
 		l.WarnContext(ctx, "Unexpected error type", slog.String("arg", arg), slog.String("error", goString(err)))
@@ -110,6 +139,7 @@ func Make(ctx context.Context, err error, arg string, l *slog.Logger) *Error {
 	}
 
 	var code Code
+	var labels []string
 
 	switch pg.Code {
 	case pgerrcode.UndefinedFunction:
@@ -121,6 +151,18 @@ func Make(ctx context.Context, err error, arg string, l *slog.Logger) *Error {
 		// mainly for tests
 		l.ErrorContext(ctx, "Connection failure", slog.String("arg", arg), slog.String("error", goString(err)))
 		code = ErrInternalError
+
+	case pgerrcode.QueryCanceled:
+		// statement_timeout set for maxTimeMS, or pg_cancel_backend called by killOp
+		code = ErrInterrupted
+		if strings.Contains(pg.Message, "statement timeout") {
+			code = ErrMaxTimeMSExpired
+		}
+
+	case pgerrcode.SerializationFailure, pgerrcode.DeadlockDetected:
+		// concurrent multi-document transactions
+		code = ErrWriteConflict
+		labels = []string{LabelTransientTransactionError}
 	}
 
 	if len(pg.Code) == 5 && pg.Code[0] == 'M' {
@@ -155,6 +197,7 @@ func Make(ctx context.Context, err error, arg string, l *slog.Logger) *Error {
 			Code:    int32(code),
 			Message: pg.Message,
 			Name:    code.String(),
+			Labels:  labels,
 			Wrapped: err,
 		},
 	}
@@ -15,6 +15,7 @@
 package mongoerrors
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
@@ -22,6 +23,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
@@ -149,3 +151,52 @@ func TestMakeConnectError(t *testing.T) {
 		"Wrapped: &pgconn.ConnectError(" + strconv.Quote(err.Message) + ")}"
 	assert.Equal(t, expectedS, fmt.Sprintf("%#v", err))
 }
+
+func TestMakeWriteConflict(t *testing.T) {
+	ctx := testutil.Ctx(t)
+	l := testutil.Logger(t)
+
+	pg := &pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "40001",
+		Message:  "could not serialize access due to concurrent update",
+	}
+
+	err := Make(ctx, pg, "documentdb_api.update", l)
+	assert.Equal(t, int32(ErrWriteConflict), err.Code)
+	assert.Equal(t, []string{LabelTransientTransactionError}, err.Labels)
+
+	err = err.WithLabels(LabelTransientTransactionError, LabelUnknownTransactionCommitResult)
+	assert.Equal(t, []string{LabelTransientTransactionError, LabelUnknownTransactionCommitResult}, err.Labels)
+
+	doc, decodeErr := err.Msg().DecodeDeepDocument()
+	require.NoError(t, decodeErr)
+	assert.NotNil(t, doc.Get("errorLabels"))
+}
+
+func TestMakeMaxTimeMSExpired(t *testing.T) {
+	ctx := testutil.Ctx(t)
+	l := testutil.Logger(t)
+
+	err := Make(ctx, fmt.Errorf("query: %w", context.DeadlineExceeded), "find", l)
+	assert.Equal(t, int32(ErrMaxTimeMSExpired), err.Code)
+	assert.Equal(t, "MaxTimeMSExpired", err.Name)
+
+	pg := &pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "57014",
+		Message:  "canceling statement due to statement timeout",
+	}
+
+	err = Make(ctx, pg, "documentdb_api.find_cursor_first_page", l)
+	assert.Equal(t, int32(ErrMaxTimeMSExpired), err.Code)
+
+	pg = &pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "57014",
+		Message:  "canceling statement due to user request",
+	}
+
+	err = Make(ctx, pg, "documentdb_api.find_cursor_first_page", l)
+	assert.Equal(t, int32(ErrInterrupted), err.Code)
+}
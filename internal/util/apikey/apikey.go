@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apikey provides generation and verification of API keys.
+//
+// API key has the format `fdb_<id>_<secret>`.
+// The ID is used to find the stored key; only the hash of the secret is stored.
+// Secrets are random and long enough, so a fast hash function is used.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// prefix is used to recognize API keys (for example, by secret scanners).
+const prefix = "fdb_"
+
+// Lengths of random parts in bytes.
+const (
+	idLen     = 8
+	secretLen = 32
+)
+
+// ErrInvalid is returned by [Parse] for values that are not API keys.
+var ErrInvalid = errors.New("invalid API key format")
+
+// Key represents an API key.
+type Key struct {
+	ID     string
+	secret string
+}
+
+// New generates a new random API key.
+func New() (*Key, error) {
+	id := make([]byte, idLen)
+	if _, err := rand.Read(id); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	secret := make([]byte, secretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &Key{
+		ID:     hex.EncodeToString(id),
+		secret: base64.RawURLEncoding.EncodeToString(secret),
+	}, nil
+}
+
+// Parse parses the API key value returned by [Key.Value].
+func Parse(s string) (*Key, error) {
+	rest, ok := strings.CutPrefix(s, prefix)
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != hex.EncodedLen(idLen) || len(secret) != base64.RawURLEncoding.EncodedLen(secretLen) {
+		return nil, ErrInvalid
+	}
+
+	return &Key{
+		ID:     id,
+		secret: secret,
+	}, nil
+}
+
+// Value returns the full API key value that should be given to the client.
+// It contains the secret and should not be logged or stored.
+func (k *Key) Value() string {
+	return prefix + k.ID + "_" + k.secret
+}
+
+// Hash returns the hash of the secret that should be stored.
+func (k *Key) Hash() []byte {
+	h := sha256.Sum256([]byte(k.secret))
+	return h[:]
+}
+
+// Verify returns true if the secret matches the given stored hash.
+func (k *Key) Verify(hash []byte) bool {
+	return subtle.ConstantTimeCompare(k.Hash(), hash) == 1
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := New()
+	require.NoError(t, err)
+
+	other, err := New()
+	require.NoError(t, err)
+	assert.NotEqual(t, key.ID, other.ID)
+
+	value := key.Value()
+	assert.True(t, strings.HasPrefix(value, "fdb_"+key.ID+"_"))
+
+	parsed, err := Parse(value)
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+	assert.True(t, parsed.Verify(key.Hash()))
+	assert.False(t, parsed.Verify(other.Hash()))
+	assert.False(t, parsed.Verify(nil))
+
+	for _, s := range []string{
+		"",
+		key.ID,
+		strings.TrimPrefix(value, "fdb_"),
+		value[:len(value)-1],
+		"fdb_" + key.ID + "-" + strings.TrimPrefix(value, "fdb_"+key.ID+"_"),
+	} {
+		_, err = Parse(s)
+		assert.ErrorIs(t, err, ErrInvalid, "%q", s)
+	}
+}
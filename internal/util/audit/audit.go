@@ -0,0 +1,233 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit provides audit logging of security-relevant events
+// in the MongoDB audit event format.
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// Audit event types.
+const (
+	TypeAuthenticate             = "authenticate"
+	TypeAuthCheck                = "authCheck"
+	TypeCreateCollection         = "createCollection"
+	TypeDropCollection           = "dropCollection"
+	TypeDropDatabase             = "dropDatabase"
+	TypeRenameCollection         = "renameCollection"
+	TypeCreateIndex              = "createIndex"
+	TypeDropIndex                = "dropIndex"
+	TypeCreateUser               = "createUser"
+	TypeDropUser                 = "dropUser"
+	TypeDropAllUsersFromDatabase = "dropAllUsersFromDatabase"
+	TypeUpdateUser               = "updateUser"
+	TypeGrantRolesToUser         = "grantRolesToUser"
+	TypeRevokeRolesFromUser      = "revokeRolesFromUser"
+)
+
+// Event represents a single audit event.
+type Event struct {
+	Type   string
+	Time   time.Time
+	Local  Endpoint
+	Remote Endpoint
+	Users  []User
+	Roles  []Role
+
+	// Param contains event-specific details.
+	// Values should be encodable with [json.Marshal].
+	Param map[string]any
+
+	// Result is 0 for success, or MongoDB error code.
+	Result int32
+}
+
+// Endpoint represents the local or remote endpoint of the client connection.
+// Either IP and Port, or Unix is set.
+type Endpoint struct {
+	IP   string `json:"ip,omitempty"`
+	Port uint16 `json:"port,omitempty"`
+	Unix string `json:"unix,omitempty"`
+}
+
+// User represents an authenticated user.
+type User struct {
+	User string `json:"user"`
+	DB   string `json:"db"`
+}
+
+// Role represents a role of the authenticated user.
+type Role struct {
+	Role string `json:"role"`
+	DB   string `json:"db"`
+}
+
+// eventDate represents a date in MongoDB Extended JSON format.
+type eventDate struct {
+	Date string `json:"$date"`
+}
+
+// jsonEvent represents an event as it is recorded.
+type jsonEvent struct {
+	AType  string         `json:"atype"`
+	TS     eventDate      `json:"ts"`
+	Local  Endpoint       `json:"local"`
+	Remote Endpoint       `json:"remote"`
+	Users  []User         `json:"users"`
+	Roles  []Role         `json:"roles"`
+	Param  map[string]any `json:"param"`
+	Result int32          `json:"result"`
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (e *Event) MarshalJSON() ([]byte, error) {
+	je := jsonEvent{
+		AType:  e.Type,
+		TS:     eventDate{Date: e.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00")},
+		Local:  e.Local,
+		Remote: e.Remote,
+		Users:  e.Users,
+		Roles:  e.Roles,
+		Param:  e.Param,
+		Result: e.Result,
+	}
+
+	if je.Users == nil {
+		je.Users = []User{}
+	}
+
+	if je.Roles == nil {
+		je.Roles = []Role{}
+	}
+
+	if je.Param == nil {
+		je.Param = map[string]any{}
+	}
+
+	return json.Marshal(je)
+}
+
+// Destinations of audit events.
+const (
+	// DestinationFile writes events to the file, one JSON document per line.
+	DestinationFile = "file"
+
+	// DestinationSyslog writes events to the local syslog daemon, one JSON document per message.
+	DestinationSyslog = "syslog"
+)
+
+// NewOpts represents [New] options.
+type NewOpts struct {
+	// Destination is [DestinationFile] or [DestinationSyslog].
+	Destination string
+
+	// Path is the file path for [DestinationFile].
+	Path string
+
+	// Filter is an optional filter expression in JSON, see [ParseFilter].
+	Filter string
+
+	L *slog.Logger
+}
+
+// Logger records audit events.
+//
+//nolint:vet // for readability
+type Logger struct {
+	l      *slog.Logger
+	filter *Filter
+
+	m sync.Mutex
+	w io.WriteCloser
+}
+
+// New creates a new audit logger.
+func New(opts *NewOpts) (*Logger, error) {
+	filter, err := ParseFilter(opts.Filter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var w io.WriteCloser
+
+	switch opts.Destination {
+	case DestinationFile:
+		if opts.Path == "" {
+			return nil, lazyerrors.New("audit log path is not set")
+		}
+
+		if w, err = os.OpenFile(opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	case DestinationSyslog:
+		if w, err = newSyslogWriter(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	default:
+		return nil, lazyerrors.Errorf("unsupported audit destination %q", opts.Destination)
+	}
+
+	return &Logger{
+		l:      opts.L,
+		filter: filter,
+		w:      w,
+	}, nil
+}
+
+// Log records the event if it matches the filter.
+//
+// Errors are logged, but not returned, as they should not affect the client.
+func (l *Logger) Log(e *Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		l.l.Error("Failed to encode audit event", slog.String("atype", e.Type), logging.Error(err))
+		return
+	}
+
+	if !l.filter.Match(b) {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if _, err = l.w.Write(append(b, '\n')); err != nil {
+		l.l.Error("Failed to write audit event", slog.String("atype", e.Type), logging.Error(err))
+	}
+}
+
+// Close closes the underlying file or syslog connection.
+func (l *Logger) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	return l.w.Close()
+}
+
+// check interfaces
+var (
+	_ json.Marshaler = (*Event)(nil)
+)
@@ -0,0 +1,158 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	e := &Event{
+		Type:   TypeAuthenticate,
+		Time:   time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.FixedZone("", 3600)),
+		Local:  Endpoint{IP: "127.0.0.1", Port: 27017},
+		Remote: Endpoint{Unix: "/tmp/ferretdb.sock"},
+		Users:  []User{{User: "user", DB: "admin"}},
+		Param:  map[string]any{"user": "user", "db": "admin", "mechanism": "SCRAM-SHA-256"},
+		Result: 18,
+	}
+
+	b, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	expected := `{` +
+		`"atype":"authenticate",` +
+		`"ts":{"$date":"2024-05-01T11:30:00.123Z"},` +
+		`"local":{"ip":"127.0.0.1","port":27017},` +
+		`"remote":{"unix":"/tmp/ferretdb.sock"},` +
+		`"users":[{"user":"user","db":"admin"}],` +
+		`"roles":[],` +
+		`"param":{"db":"admin","mechanism":"SCRAM-SHA-256","user":"user"},` +
+		`"result":18` +
+		`}`
+	assert.JSONEq(t, expected, string(b))
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	event, err := json.Marshal(&Event{
+		Type:  TypeCreateIndex,
+		Users: []User{{User: "user", DB: "admin"}, {User: "other", DB: "test"}},
+		Param: map[string]any{"ns": "test.coll", "indexName": "v_1"},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		filter   string
+		expected bool
+	}{
+		"Empty":        {filter: ``, expected: true},
+		"EmptyDoc":     {filter: `{}`, expected: true},
+		"Eq":           {filter: `{"atype": "createIndex"}`, expected: true},
+		"NotEq":        {filter: `{"atype": "dropIndex"}`, expected: false},
+		"ExplicitEq":   {filter: `{"atype": {"$eq": "createIndex"}}`, expected: true},
+		"Ne":           {filter: `{"atype": {"$ne": "createIndex"}}`, expected: false},
+		"In":           {filter: `{"atype": {"$in": ["authenticate", "createIndex"]}}`, expected: true},
+		"Nin":          {filter: `{"atype": {"$nin": ["authenticate", "createIndex"]}}`, expected: false},
+		"Dotted":       {filter: `{"param.ns": "test.coll"}`, expected: true},
+		"Array":        {filter: `{"users.user": "other"}`, expected: true},
+		"ArrayMissing": {filter: `{"users.user": "missing"}`, expected: false},
+		"Exists":       {filter: `{"param.indexName": {"$exists": true}}`, expected: true},
+		"NotExists":    {filter: `{"param.user": {"$exists": false}}`, expected: true},
+		"Number":       {filter: `{"result": 0}`, expected: true},
+		"And":          {filter: `{"$and": [{"atype": "createIndex"}, {"result": 0}]}`, expected: true},
+		"Or":           {filter: `{"$or": [{"atype": "authenticate"}, {"result": 13}]}`, expected: false},
+		"Nor":          {filter: `{"$nor": [{"atype": "authenticate"}, {"result": 13}]}`, expected: true},
+		"Implicit": {
+			filter:   `{"atype": "createIndex", "param.ns": {"$in": ["test.coll"]}, "result": {"$ne": 0}}`,
+			expected: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := ParseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, f.Match(event))
+		})
+	}
+
+	for name, filter := range map[string]string{
+		"Invalid":         `{`,
+		"NotDocument":     `[]`,
+		"UnknownTop":      `{"$where": "true"}`,
+		"UnknownOperator": `{"atype": {"$regex": "auth"}}`,
+		"InNotArray":      `{"atype": {"$in": "auth"}}`,
+		"ExistsNotBool":   `{"atype": {"$exists": 1}}`,
+		"AndNotArray":     `{"$and": {"atype": "auth"}}`,
+		"AndEmpty":        `{"$and": []}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseFilter(filter)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLoggerFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.json")
+
+	l, err := New(&NewOpts{
+		Destination: DestinationFile,
+		Path:        path,
+		Filter:      `{"atype": {"$ne": "authCheck"}}`,
+		L:           testutil.Logger(t),
+	})
+	require.NoError(t, err)
+
+	l.Log(&Event{Type: TypeAuthenticate, Time: time.Now()})
+	l.Log(&Event{Type: TypeAuthCheck, Time: time.Now(), Result: 13})
+	l.Log(&Event{Type: TypeDropDatabase, Time: time.Now(), Param: map[string]any{"ns": "test"}})
+
+	require.NoError(t, l.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+
+	var atypes []string
+
+	for _, line := range lines {
+		var e map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		atypes = append(atypes, e["atype"].(string))
+	}
+
+	assert.Equal(t, []string{TypeAuthenticate, TypeDropDatabase}, atypes)
+}
@@ -0,0 +1,265 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// Filter selects audit events to be recorded.
+//
+// It is a query document in JSON, similar to MongoDB's `auditLog.filter`,
+// that is matched against the recorded event. For example:
+//
+//	{"atype": {"$in": ["authenticate", "authCheck"]}, "param.db": "admin"}
+//
+// Supported are implicit equality, dotted paths (arrays are traversed),
+// and `$eq`, `$ne`, `$in`, `$nin`, `$exists`, `$and`, `$or`, `$nor` operators.
+type Filter struct {
+	expr map[string]any
+}
+
+// ParseFilter parses and validates the filter expression.
+//
+// It returns nil filter that matches all events for the empty expression.
+func ParseFilter(s string) (*Filter, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var expr map[string]any
+	if err := json.Unmarshal([]byte(s), &expr); err != nil {
+		return nil, lazyerrors.Errorf("invalid audit filter: %w", err)
+	}
+
+	if err := validateExpr(expr); err != nil {
+		return nil, lazyerrors.Errorf("invalid audit filter: %w", err)
+	}
+
+	return &Filter{expr: expr}, nil
+}
+
+// validateExpr checks that the query expression uses only supported operators.
+func validateExpr(expr map[string]any) error {
+	for k, v := range expr {
+		switch k {
+		case "$and", "$or", "$nor":
+			arr, ok := v.([]any)
+			if !ok || len(arr) == 0 {
+				return lazyerrors.Errorf("%s must be a non-empty array", k)
+			}
+
+			for _, e := range arr {
+				sub, ok := e.(map[string]any)
+				if !ok {
+					return lazyerrors.Errorf("%s elements must be objects", k)
+				}
+
+				if err := validateExpr(sub); err != nil {
+					return err
+				}
+			}
+
+		default:
+			if strings.HasPrefix(k, "$") {
+				return lazyerrors.Errorf("unsupported operator %s", k)
+			}
+
+			ops, ok := operators(v)
+			if !ok {
+				continue
+			}
+
+			for op, arg := range ops {
+				switch op {
+				case "$eq", "$ne":
+				case "$in", "$nin":
+					if _, ok := arg.([]any); !ok {
+						return lazyerrors.Errorf("%s needs an array", op)
+					}
+				case "$exists":
+					if _, ok := arg.(bool); !ok {
+						return lazyerrors.Errorf("%s needs a boolean", op)
+					}
+				default:
+					return lazyerrors.Errorf("unsupported operator %s", op)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Match returns true if the encoded event matches the filter.
+// Nil filter matches all events.
+func (f *Filter) Match(event []byte) bool {
+	if f == nil {
+		return true
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(event, &doc); err != nil {
+		return false
+	}
+
+	return matchExpr(f.expr, doc)
+}
+
+// matchExpr returns true if the document matches the query expression.
+func matchExpr(expr, doc map[string]any) bool {
+	for k, v := range expr {
+		var ok bool
+
+		switch k {
+		case "$and":
+			ok = true
+			for _, e := range v.([]any) {
+				if !matchExpr(e.(map[string]any), doc) {
+					ok = false
+					break
+				}
+			}
+
+		case "$or", "$nor":
+			for _, e := range v.([]any) {
+				if matchExpr(e.(map[string]any), doc) {
+					ok = true
+					break
+				}
+			}
+
+			if k == "$nor" {
+				ok = !ok
+			}
+
+		default:
+			ok = matchCond(lookup(doc, strings.Split(k, ".")), v)
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchCond returns true if values found by the path match the condition.
+func matchCond(values []any, cond any) bool {
+	ops, ok := operators(cond)
+	if !ok {
+		return matchEq(values, cond)
+	}
+
+	for op, arg := range ops {
+		var res bool
+
+		switch op {
+		case "$eq":
+			res = matchEq(values, arg)
+		case "$ne":
+			res = !matchEq(values, arg)
+		case "$in", "$nin":
+			for _, a := range arg.([]any) {
+				if matchEq(values, a) {
+					res = true
+					break
+				}
+			}
+
+			if op == "$nin" {
+				res = !res
+			}
+		case "$exists":
+			res = (len(values) > 0) == arg.(bool)
+		}
+
+		if !res {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchEq returns true if any of values, or any element of array value, is equal to v.
+func matchEq(values []any, v any) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+
+		if arr, ok := value.([]any); ok {
+			for _, e := range arr {
+				if reflect.DeepEqual(e, v) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// lookup returns all values found by the dotted path.
+// Arrays on the path are traversed.
+func lookup(v any, path []string) []any {
+	if len(path) == 0 {
+		return []any{v}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		next, ok := v[path[0]]
+		if !ok {
+			return nil
+		}
+
+		return lookup(next, path[1:])
+
+	case []any:
+		var res []any
+		for _, e := range v {
+			res = append(res, lookup(e, path)...)
+		}
+
+		return res
+
+	default:
+		return nil
+	}
+}
+
+// operators returns the condition as operators expression
+// if it is an object with fields that all start with `$`.
+func operators(cond any) (map[string]any, bool) {
+	m, ok := cond.(map[string]any)
+	if !ok || len(m) == 0 {
+		return nil, false
+	}
+
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return nil, false
+		}
+	}
+
+	return m, true
+}
@@ -24,10 +24,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgx/v5/tracelog"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/devbuild"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/redact"
 )
 
 // pgxLogLevels maps pgx log levels to slog levels.
@@ -41,6 +43,11 @@ var pgxLogLevels = map[tracelog.LogLevel]slog.Level{
 	tracelog.LogLevelError: slog.LevelError,
 }
 
+// pgxSecretArgs contains indexes of query arguments with secrets, indexed by called function names.
+var pgxSecretArgs = map[string][]int{
+	"documentdb_api_internal.authenticate_with_scram_sha256": {1, 2},
+}
+
 // pgxLogger is a pgx's [tracelog.Logger] implementation that uses slog
 // with correct source code location.
 type pgxLogger struct {
@@ -96,6 +103,8 @@ func (sl *pgxLogger) Log(ctx context.Context, level tracelog.LogLevel, msg strin
 
 	msgAttrs := make([]slog.Attr, len(data))
 
+	sql, _ := data["sql"].(string)
+
 	for i, k := range dataKeys {
 		v := data[k]
 
@@ -107,7 +116,7 @@ func (sl *pgxLogger) Log(ctx context.Context, level tracelog.LogLevel, msg strin
 			for i, v := range v {
 				attrs[i] = slog.Attr{
 					Key:   strconv.Itoa(i),
-					Value: slog.AnyValue(v),
+					Value: slog.AnyValue(pgxRedactArg(sql, i, v)),
 				}
 			}
 
@@ -135,6 +144,31 @@ func (sl *pgxLogger) Log(ctx context.Context, level tracelog.LogLevel, msg strin
 	}
 }
 
+// pgxRedactArg returns the query argument with the given index with secrets masked.
+func pgxRedactArg(sql string, i int, arg any) any {
+	for f, indexes := range pgxSecretArgs {
+		if strings.Contains(sql, f) && slices.Contains(indexes, i) {
+			return redact.Placeholder
+		}
+	}
+
+	raw, ok := arg.(wirebson.RawDocument)
+	if !ok {
+		return arg
+	}
+
+	doc, err := raw.Decode()
+	if err != nil {
+		return arg
+	}
+
+	if redacted := redact.Secrets(doc.Command(), doc); redacted != doc {
+		return redacted
+	}
+
+	return arg
+}
+
 // check interfaces
 var (
 	_ tracelog.Logger = (*pgxLogger)(nil)
@@ -20,10 +20,14 @@ import (
 	"log/slog"
 	"testing"
 
+	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/redact"
 )
 
 func TestPgxLogger(t *testing.T) {
@@ -60,3 +64,19 @@ func TestPgxLogger(t *testing.T) {
 
 	assert.Regexp(t, `failed to connect`, buf.String())
 }
+
+func TestPgxRedactArg(t *testing.T) {
+	t.Parallel()
+
+	sql := "SELECT authenticate_with_scram_sha256::bytea FROM documentdb_api_internal.authenticate_with_scram_sha256($1, $2, $3)"
+	assert.Equal(t, "user", pgxRedactArg(sql, 0, "user"))
+	assert.Equal(t, redact.Placeholder, pgxRedactArg(sql, 2, "proof"))
+
+	spec := must.NotFail(wirebson.MustDocument("createUser", "user", "pwd", "secret").Encode())
+	doc := pgxRedactArg("SELECT documentdb_api.create_user($1::bytea)", 0, spec).(*wirebson.Document)
+	assert.Equal(t, "user", doc.Get("createUser"))
+	assert.Equal(t, redact.Placeholder, doc.Get("pwd"))
+
+	spec = must.NotFail(wirebson.MustDocument("find", "coll").Encode())
+	assert.Equal(t, spec, pgxRedactArg("SELECT documentdb_api.find_cursor_first_page($1, $2::bytea)", 1, spec))
+}
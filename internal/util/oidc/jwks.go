@@ -0,0 +1,244 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// jwksTimeout is the timeout for fetching JWKS over HTTP.
+const jwksTimeout = 10 * time.Second
+
+// maxJWKSSize is the maximal accepted size of JWKS.
+const maxJWKSSize = 1024 * 1024
+
+// jwk represents a single parsed JSON Web Key, see RFC 7517.
+type jwk struct {
+	key crypto.PublicKey
+	kty string
+	kid string
+	alg string
+}
+
+// rawJWK represents JSON Web Key fields used by FerretDB.
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS reads JWKS from the file or HTTP(S) URL.
+func loadJWKS(ctx context.Context, src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		b, err := os.ReadFile(src)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return b, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jwksTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer res.Body.Close() //nolint:errcheck // we are only interested in reading the body
+
+	if res.StatusCode != http.StatusOK {
+		return nil, lazyerrors.Errorf("unexpected status %s", res.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxJWKSSize))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return b, nil
+}
+
+// parseJWKS parses JWKS and returns supported signing keys.
+func parseJWKS(b []byte) ([]*jwk, error) {
+	var set struct {
+		Keys []rawJWK `json:"keys"`
+	}
+
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res []*jwk
+
+	for i, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+
+		key, err := parseJWK(&raw)
+		if err != nil {
+			return nil, lazyerrors.Errorf("key %d (kid %q): %w", i, raw.Kid, err)
+		}
+
+		if key == nil {
+			continue
+		}
+
+		res = append(res, &jwk{
+			key: key,
+			kty: raw.Kty,
+			kid: raw.Kid,
+			alg: raw.Alg,
+		})
+	}
+
+	if len(res) == 0 {
+		return nil, lazyerrors.New("no supported signing keys")
+	}
+
+	return res, nil
+}
+
+// parseJWK returns the public key of the supported type, or nil.
+func parseJWK(raw *rawJWK) (crypto.PublicKey, error) {
+	switch raw.Kty {
+	case "RSA":
+		n, err := decodeBigInt(raw.N)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		e, err := decodeBigInt(raw.E)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, lazyerrors.New("invalid RSA exponent")
+		}
+
+		if n.BitLen() < 2048 {
+			return nil, lazyerrors.Errorf("RSA key is too short: %d bits", n.BitLen())
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+
+		switch raw.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, lazyerrors.Errorf("unsupported curve %q", raw.Crv)
+		}
+
+		size := (curve.Params().BitSize + 7) / 8
+
+		x, err := base64.RawURLEncoding.DecodeString(raw.X)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(raw.Y)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if len(x) != size || len(y) != size {
+			return nil, lazyerrors.New("invalid EC point size")
+		}
+
+		// check that the point is on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err = ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+
+	case "OKP":
+		if raw.Crv != "Ed25519" {
+			return nil, lazyerrors.Errorf("unsupported curve %q", raw.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(raw.X)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, lazyerrors.New("invalid Ed25519 key size")
+		}
+
+		return ed25519.PublicKey(x), nil
+
+	default:
+		// symmetric and other keys are ignored
+		return nil, nil
+	}
+}
+
+// decodeBigInt decodes base64url-encoded big-endian unsigned integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(b) == 0 {
+		return nil, lazyerrors.New("empty integer")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256" // register hash functions
+	_ "crypto/sha512" // register hash functions
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// maxTokenLength is the maximal accepted length of the token.
+const maxTokenLength = 64 * 1024
+
+// jwtHeader represents the JOSE header of the token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwsAlgorithm represents a supported JWS algorithm, see RFC 7518 (section 3.1).
+type jwsAlgorithm struct {
+	kty  string
+	hash crypto.Hash
+	pss  bool
+}
+
+// jwsAlgorithms contains supported JWS algorithms.
+// `none` and HMAC algorithms are intentionally absent.
+var jwsAlgorithms = map[string]jwsAlgorithm{
+	"RS256": {kty: "RSA", hash: crypto.SHA256},
+	"RS384": {kty: "RSA", hash: crypto.SHA384},
+	"RS512": {kty: "RSA", hash: crypto.SHA512},
+	"PS256": {kty: "RSA", hash: crypto.SHA256, pss: true},
+	"PS384": {kty: "RSA", hash: crypto.SHA384, pss: true},
+	"PS512": {kty: "RSA", hash: crypto.SHA512, pss: true},
+	"ES256": {kty: "EC", hash: crypto.SHA256},
+	"ES384": {kty: "EC", hash: crypto.SHA384},
+	"ES512": {kty: "EC", hash: crypto.SHA512},
+	"EdDSA": {kty: "OKP"},
+}
+
+// verifyJWT verifies the signature of JWS Compact Serialization of the token
+// with one of the given keys and returns its claims.
+//
+// Claims themselves are not validated.
+func verifyJWT(token string, keys []*jwk) (map[string]any, error) {
+	if len(token) > maxTokenLength {
+		return nil, invalidTokenf("token is too long")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidTokenf("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidTokenf("malformed header: %s", err)
+	}
+
+	alg, ok := jwsAlgorithms[header.Alg]
+	if !ok {
+		return nil, invalidTokenf("unsupported algorithm %q", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidTokenf("malformed signature: %s", err)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+
+	var verified bool
+
+	for _, k := range keys {
+		if header.Kid != "" && k.kid != header.Kid {
+			continue
+		}
+
+		if k.kty != alg.kty || (k.alg != "" && k.alg != header.Alg) {
+			continue
+		}
+
+		if verifySignature(alg, k.key, signed, sig) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, invalidTokenf("signature verification failed (kid %q, alg %q)", header.Kid, header.Alg)
+	}
+
+	var claims map[string]any
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidTokenf("malformed claims: %s", err)
+	}
+
+	return claims, nil
+}
+
+// verifySignature returns true if the signature is valid.
+func verifySignature(alg jwsAlgorithm, key crypto.PublicKey, signed, sig []byte) bool {
+	if alg.kty == "OKP" {
+		k, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(k, signed, sig)
+	}
+
+	h := alg.hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg.pss {
+			opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+			return rsa.VerifyPSS(k, alg.hash, digest, sig, opts) == nil
+		}
+
+		return rsa.VerifyPKCS1v15(k, alg.hash, digest, sig) == nil
+
+	case *ecdsa.PublicKey:
+		// JWS uses fixed-size R || S instead of ASN.1
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+
+		return ecdsa.Verify(k, digest, r, s)
+
+	default:
+		return false
+	}
+}
+
+// decodeSegment decodes base64url-encoded JSON segment of the token.
+func decodeSegment(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
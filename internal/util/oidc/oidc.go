@@ -0,0 +1,286 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oidc provides MONGODB-OIDC authentication with JWT access tokens
+// verified against a locally configured JSON Web Key Set (JWKS).
+//
+// Only signed tokens are supported; OpenID Connect discovery is not used.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// Mechanism is the name of the MONGODB-OIDC authentication mechanism.
+const Mechanism = "MONGODB-OIDC"
+
+// ErrInvalidToken is returned by [Verifier.Verify] if the token is not valid.
+var ErrInvalidToken = errors.New("invalid token")
+
+// defaultRefreshInterval is the default JWKS refresh interval.
+const defaultRefreshInterval = time.Hour
+
+// clockSkew is the allowed difference between clocks of the issuer and FerretDB.
+const clockSkew = time.Minute
+
+// Config represents [Verifier] configuration.
+type Config struct {
+	// Issuer is the expected value of the `iss` claim.
+	Issuer string
+
+	// Audience is the value that the `aud` claim should contain.
+	Audience string
+
+	// JWKS is a file path or `http(s)://` URL of the JSON Web Key Set.
+	JWKS string
+
+	// RefreshInterval is the interval of JWKS reloading; defaults to 1h.
+	RefreshInterval time.Duration
+
+	// UsernameClaim is the name of the claim used as a username; defaults to `sub`.
+	UsernameClaim string
+
+	// GroupsClaim is the name of the claim with the user's groups.
+	// If empty, groups are not used.
+	GroupsClaim string
+
+	// GroupRoles maps group names to names of roles in the `admin` database.
+	// Groups without mapping are ignored.
+	GroupRoles map[string]string
+
+	// ClientID is an optional OAuth client ID returned to clients that request IdP information.
+	ClientID string
+
+	L *slog.Logger
+}
+
+// Identity represents the user authenticated with the token.
+type Identity struct {
+	Username string
+	Groups   []string // names of roles mapped from the groups claim, see [Config.GroupRoles]
+	Expires  time.Time
+}
+
+// Verifier verifies access tokens.
+//
+//nolint:vet // for readability
+type Verifier struct {
+	config Config
+
+	rw   sync.RWMutex
+	keys []*jwk
+}
+
+// NewVerifier creates a new verifier and loads JWKS.
+func NewVerifier(config *Config) (*Verifier, error) {
+	if config.Issuer == "" {
+		return nil, lazyerrors.New("OIDC issuer is not set")
+	}
+
+	if config.Audience == "" {
+		return nil, lazyerrors.New("OIDC audience is not set")
+	}
+
+	if config.JWKS == "" {
+		return nil, lazyerrors.New("OIDC JWKS is not set")
+	}
+
+	v := &Verifier{
+		config: *config,
+	}
+
+	if v.config.RefreshInterval == 0 {
+		v.config.RefreshInterval = defaultRefreshInterval
+	}
+
+	if v.config.UsernameClaim == "" {
+		v.config.UsernameClaim = "sub"
+	}
+
+	if err := v.refresh(context.Background()); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return v, nil
+}
+
+// Run refreshes JWKS periodically until ctx is canceled.
+//
+// Refresh errors are logged; previously loaded keys are used in that case.
+func (v *Verifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := v.refresh(ctx); err != nil {
+				v.config.L.WarnContext(ctx, "Failed to refresh OIDC JWKS", logging.Error(err))
+				continue
+			}
+
+			v.config.L.DebugContext(ctx, "OIDC JWKS refreshed", slog.String("jwks", v.config.JWKS))
+		}
+	}
+}
+
+// refresh loads JWKS and replaces keys.
+func (v *Verifier) refresh(ctx context.Context) error {
+	b, err := loadJWKS(ctx, v.config.JWKS)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return lazyerrors.Errorf("%s: %w", v.config.JWKS, err)
+	}
+
+	v.rw.Lock()
+	v.keys = keys
+	v.rw.Unlock()
+
+	return nil
+}
+
+// Issuer returns the configured issuer.
+func (v *Verifier) Issuer() string {
+	return v.config.Issuer
+}
+
+// ClientID returns the configured client ID, or empty string.
+func (v *Verifier) ClientID() string {
+	return v.config.ClientID
+}
+
+// Verify verifies the token and returns the authenticated identity.
+//
+// It returns error wrapping [ErrInvalidToken] if the token is not valid.
+func (v *Verifier) Verify(token string) (*Identity, error) {
+	return v.verify(token, time.Now())
+}
+
+// verify verifies the token at the given time.
+func (v *Verifier) verify(token string, now time.Time) (*Identity, error) {
+	v.rw.RLock()
+	keys := v.keys
+	v.rw.RUnlock()
+
+	claims, err := verifyJWT(token, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+		return nil, invalidTokenf("unexpected issuer %q", iss)
+	}
+
+	if !audienceContains(claims["aud"], v.config.Audience) {
+		return nil, invalidTokenf("audience does not contain %q", v.config.Audience)
+	}
+
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return nil, invalidTokenf("no expiration time")
+	}
+
+	if now.After(exp.Add(clockSkew)) {
+		return nil, invalidTokenf("token expired at %s", exp.Format(time.RFC3339))
+	}
+
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Before(nbf.Add(-clockSkew)) {
+		return nil, invalidTokenf("token is not valid before %s", nbf.Format(time.RFC3339))
+	}
+
+	username, _ := claims[v.config.UsernameClaim].(string)
+	if username == "" {
+		return nil, invalidTokenf("no %q claim", v.config.UsernameClaim)
+	}
+
+	res := &Identity{
+		Username: username,
+		Expires:  exp,
+	}
+
+	if v.config.GroupsClaim != "" {
+		var groups []string
+
+		switch claim := claims[v.config.GroupsClaim].(type) {
+		case string:
+			groups = strings.Fields(claim)
+
+		case []any:
+			for _, g := range claim {
+				if s, ok := g.(string); ok && s != "" {
+					groups = append(groups, s)
+				}
+			}
+		}
+
+		// group names are controlled by the identity provider, not by FerretDB administrators,
+		// so only explicitly mapped groups are used
+		for _, g := range groups {
+			if role, ok := v.config.GroupRoles[g]; ok && !slices.Contains(res.Groups, role) {
+				res.Groups = append(res.Groups, role)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// audienceContains returns true if the `aud` claim value contains the given audience.
+func audienceContains(v any, audience string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == audience
+
+	case []any:
+		for _, a := range v {
+			if a == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// numericDate returns the time of the NumericDate claim value.
+func numericDate(v any) (time.Time, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(f), 0), true
+}
+
+// invalidTokenf returns a formatted error wrapping [ErrInvalidToken].
+func invalidTokenf(format string, args ...any) error {
+	args = append([]any{ErrInvalidToken}, args...)
+	return lazyerrors.Errorf("%w: "+format, args...)
+}
@@ -0,0 +1,348 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+// testKeys contains private keys used by tests.
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ec      *ecdsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+}
+
+// newTestKeys generates new keys.
+func newTestKeys(t testing.TB) *testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &testKeys{
+		rsa:     rsaKey,
+		ec:      ecKey,
+		ed25519: edKey,
+	}
+}
+
+// jwks returns JWKS with public keys.
+func (k *testKeys) jwks(t testing.TB) []byte {
+	t.Helper()
+
+	enc := base64.RawURLEncoding.EncodeToString
+
+	pad := func(i *big.Int) string {
+		b := make([]byte, 32)
+		return enc(i.FillBytes(b))
+	}
+
+	set := map[string]any{
+		"keys": []map[string]any{{
+			"kty": "RSA",
+			"kid": "rsa",
+			"use": "sig",
+			"n":   enc(k.rsa.N.Bytes()),
+			"e":   enc(big.NewInt(int64(k.rsa.E)).Bytes()),
+		}, {
+			"kty": "EC",
+			"kid": "ec",
+			"crv": "P-256",
+			"x":   pad(k.ec.X),
+			"y":   pad(k.ec.Y),
+		}, {
+			"kty": "OKP",
+			"kid": "ed25519",
+			"alg": "EdDSA",
+			"crv": "Ed25519",
+			"x":   enc(k.ed25519.Public().(ed25519.PublicKey)),
+		}, {
+			"kty": "oct",
+			"kid": "hmac",
+			"k":   enc([]byte("secret")),
+		}},
+	}
+
+	b, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	return b
+}
+
+// sign returns signed token.
+func (k *testKeys) sign(t testing.TB, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	enc := base64.RawURLEncoding.EncodeToString
+
+	header, err := json.Marshal(map[string]any{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := enc(header) + "." + enc(payload)
+
+	var sig []byte
+
+	switch alg {
+	case "RS256":
+		h := crypto.SHA256.New()
+		h.Write([]byte(signed))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, h.Sum(nil))
+
+	case "PS256":
+		h := crypto.SHA256.New()
+		h.Write([]byte(signed))
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+		sig, err = rsa.SignPSS(rand.Reader, k.rsa, crypto.SHA256, h.Sum(nil), opts)
+
+	case "ES256":
+		h := crypto.SHA256.New()
+		h.Write([]byte(signed))
+
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, h.Sum(nil))
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	case "EdDSA":
+		sig = ed25519.Sign(k.ed25519, []byte(signed))
+
+	case "none":
+		// no signature
+	}
+
+	require.NoError(t, err)
+
+	return signed + "." + enc(sig)
+}
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, keys.jwks(t), 0o600))
+
+	v, err := NewVerifier(&Config{
+		Issuer:      "https://issuer.example.com",
+		Audience:    "ferretdb",
+		JWKS:        path,
+		GroupsClaim: "groups",
+		GroupRoles:  map[string]string{"admins": "dbAdminAnyDatabase", "readers": "readAnyDatabase"},
+		L:           testutil.Logger(t),
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	claims := func(overrides map[string]any) map[string]any {
+		res := map[string]any{
+			"iss":    "https://issuer.example.com",
+			"aud":    []string{"other", "ferretdb"},
+			"sub":    "workload",
+			"exp":    now.Add(time.Hour).Unix(),
+			"nbf":    now.Add(-time.Minute).Unix(),
+			"groups": []string{"readAnyDatabase", "admins"},
+		}
+
+		for k, v := range overrides {
+			if v == nil {
+				delete(res, k)
+				continue
+			}
+
+			res[k] = v
+		}
+
+		return res
+	}
+
+	for name, tc := range map[string]struct {
+		token    string
+		expected *Identity
+	}{
+		"RS256": {
+			token: keys.sign(t, "RS256", "rsa", claims(nil)),
+		},
+		"PS256": {
+			token: keys.sign(t, "PS256", "rsa", claims(nil)),
+		},
+		"ES256": {
+			token: keys.sign(t, "ES256", "ec", claims(nil)),
+		},
+		"EdDSA": {
+			token: keys.sign(t, "EdDSA", "ed25519", claims(nil)),
+		},
+		"NoKid": {
+			token: keys.sign(t, "ES256", "", claims(nil)),
+		},
+		"StringAudience": {
+			token: keys.sign(t, "RS256", "rsa", claims(map[string]any{"aud": "ferretdb"})),
+		},
+		"StringGroups": {
+			token: keys.sign(t, "RS256", "rsa", claims(map[string]any{"groups": "readAnyDatabase admins"})),
+		},
+		"NoGroups": {
+			token: keys.sign(t, "RS256", "rsa", claims(map[string]any{"groups": nil})),
+			expected: &Identity{
+				Username: "workload",
+				Expires:  time.Unix(now.Add(time.Hour).Unix(), 0),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expected := tc.expected
+			if expected == nil {
+				expected = &Identity{
+					Username: "workload",
+					Groups:   []string{"dbAdminAnyDatabase"}, // unmapped groups are ignored
+					Expires:  time.Unix(now.Add(time.Hour).Unix(), 0),
+				}
+			}
+
+			actual, err := v.verify(tc.token, now)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	otherKeys := newTestKeys(t)
+
+	for name, token := range map[string]string{
+		"Malformed":       "not.a.token",
+		"None":            keys.sign(t, "none", "rsa", claims(nil)),
+		"WrongKey":        otherKeys.sign(t, "RS256", "rsa", claims(nil)),
+		"UnknownKid":      keys.sign(t, "RS256", "unknown", claims(nil)),
+		"KeyTypeMismatch": keys.sign(t, "ES256", "rsa", claims(nil)),
+		"AlgMismatch":     keys.sign(t, "ES256", "ed25519", claims(nil)),
+		"WrongIssuer":     keys.sign(t, "RS256", "rsa", claims(map[string]any{"iss": "https://evil.example.com"})),
+		"WrongAudience":   keys.sign(t, "RS256", "rsa", claims(map[string]any{"aud": "other"})),
+		"NoAudience":      keys.sign(t, "RS256", "rsa", claims(map[string]any{"aud": nil})),
+		"Expired":         keys.sign(t, "RS256", "rsa", claims(map[string]any{"exp": now.Add(-time.Hour).Unix()})),
+		"NoExpiration":    keys.sign(t, "RS256", "rsa", claims(map[string]any{"exp": nil})),
+		"NotYetValid":     keys.sign(t, "RS256", "rsa", claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})),
+		"NoSubject":       keys.sign(t, "RS256", "rsa", claims(map[string]any{"sub": nil})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := v.verify(token, now)
+			require.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	t.Run("UsernameClaim", func(t *testing.T) {
+		t.Parallel()
+
+		cv, err := NewVerifier(&Config{
+			Issuer:        "https://issuer.example.com",
+			Audience:      "ferretdb",
+			JWKS:          path,
+			UsernameClaim: "email",
+			L:             testutil.Logger(t),
+		})
+		require.NoError(t, err)
+
+		token := keys.sign(t, "RS256", "rsa", claims(map[string]any{"email": "workload@example.com"}))
+
+		actual, err := cv.verify(token, now)
+		require.NoError(t, err)
+		assert.Equal(t, "workload@example.com", actual.Username)
+		assert.Empty(t, actual.Groups)
+	})
+}
+
+func TestVerifierRefresh(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+
+	var jwks atomic.Pointer[[]byte]
+	jwks.Store(ptr(keys.jwks(t)))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(*jwks.Load())
+	}))
+	t.Cleanup(srv.Close)
+
+	v, err := NewVerifier(&Config{
+		Issuer:          "https://issuer.example.com",
+		Audience:        "ferretdb",
+		JWKS:            srv.URL,
+		RefreshInterval: 10 * time.Millisecond,
+		L:               testutil.Logger(t),
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	newKeys := newTestKeys(t)
+
+	token := newKeys.sign(t, "EdDSA", "ed25519", map[string]any{
+		"iss": "https://issuer.example.com",
+		"aud": "ferretdb",
+		"sub": "workload",
+		"exp": now.Add(time.Hour).Unix(),
+	})
+
+	_, err = v.verify(token, now)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// keys rotated by the issuer
+	jwks.Store(ptr(newKeys.jwks(t)))
+
+	ctx := testutil.Ctx(t)
+	go v.Run(ctx)
+
+	assert.Eventually(t, func() bool {
+		_, err = v.verify(token, now)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// ptr returns a pointer to the given value.
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// This file contains a minimal BER encoder and decoder sufficient for LDAP messages used by [LDAPVerifier].
+// Only definite lengths and single-byte tags are supported.
+
+// BER identifier octets used by LDAP.
+const (
+	berBoolean     = 0x01
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+	berSet         = 0x31
+
+	berApplication = 0x40
+	berContext     = 0x80
+	berConstructed = 0x20
+)
+
+// maxBERLength is the maximal length of a single BER element that is accepted.
+const maxBERLength = 16 * 1024 * 1024
+
+// berElement represents a single decoded BER element.
+type berElement struct {
+	tag     byte
+	content []byte
+}
+
+// berEncode returns BER encoding of the element with the given tag and content.
+func berEncode(tag byte, content []byte) []byte {
+	l := len(content)
+
+	var length []byte
+
+	switch {
+	case l < 0x80:
+		length = []byte{byte(l)}
+	default:
+		for n := l; n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+
+		length = append([]byte{0x80 | byte(len(length))}, length...)
+	}
+
+	res := make([]byte, 0, 1+len(length)+l)
+	res = append(res, tag)
+	res = append(res, length...)
+
+	return append(res, content...)
+}
+
+// berConcat returns BER encoding of the constructed element with the given tag and encoded children.
+func berConcat(tag byte, children ...[]byte) []byte {
+	var content []byte
+	for _, c := range children {
+		content = append(content, c...)
+	}
+
+	return berEncode(tag, content)
+}
+
+// berInt returns BER encoding of the integer with the given tag.
+func berInt(tag byte, v int) []byte {
+	var content []byte
+
+	for {
+		content = append([]byte{byte(v)}, content...)
+		v >>= 8
+
+		if (v == 0 && content[0]&0x80 == 0) || (v == -1 && content[0]&0x80 != 0) {
+			break
+		}
+	}
+
+	return berEncode(tag, content)
+}
+
+// berBool returns BER encoding of the boolean.
+func berBool(v bool) []byte {
+	if v {
+		return berEncode(berBoolean, []byte{0xff})
+	}
+
+	return berEncode(berBoolean, []byte{0x00})
+}
+
+// berString returns BER encoding of the string with the given tag.
+func berString(tag byte, s string) []byte {
+	return berEncode(tag, []byte(s))
+}
+
+// berRead reads a single BER element.
+func berRead(r *bufio.Reader) (*berElement, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	l := int(b)
+
+	if b&0x80 != 0 {
+		n := int(b & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, lazyerrors.Errorf("unsupported BER length encoding 0x%02x", b)
+		}
+
+		l = 0
+
+		for range n {
+			if b, err = r.ReadByte(); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			l = l<<8 | int(b)
+		}
+	}
+
+	if l > maxBERLength {
+		return nil, lazyerrors.Errorf("BER element is too large: %d", l)
+	}
+
+	content := make([]byte, l)
+	if _, err = io.ReadFull(r, content); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &berElement{tag: tag, content: content}, nil
+}
+
+// children returns decoded elements of the constructed element.
+func (e *berElement) children() ([]*berElement, error) {
+	var res []*berElement
+
+	r := bufio.NewReader(bytes.NewReader(e.content))
+
+	for {
+		c, err := berRead(r)
+		if err == io.EOF {
+			return res, nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res = append(res, c)
+	}
+}
+
+// int returns the value of the integer or enumerated element.
+func (e *berElement) int() (int, error) {
+	if len(e.content) == 0 || len(e.content) > 4 {
+		return 0, lazyerrors.Errorf("invalid BER integer length %d", len(e.content))
+	}
+
+	v := int(int8(e.content[0]))
+	for _, b := range e.content[1:] {
+		v = v<<8 | int(b)
+	}
+
+	return v, nil
+}
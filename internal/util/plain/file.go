@@ -0,0 +1,189 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // used by htpasswd's {SHA} format
+	"crypto/subtle"
+	"encoding/base64"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// fileUser represents a single user of [FileVerifier].
+type fileUser struct {
+	hash   string
+	groups []string
+}
+
+// FileVerifier is a [Verifier] that uses a static htpasswd-style file.
+//
+// Each non-empty line that does not start with `#` has the following format:
+//
+//	username:hash[:group1,group2,...]
+//
+// Hashes could be in bcrypt (`$2y$...`, as generated by `htpasswd -B`)
+// or `{SHA}` (base64-encoded SHA-1, as generated by `htpasswd -s`) formats.
+//
+// The file is reloaded if its modification time changes.
+type FileVerifier struct {
+	path string
+
+	rw      sync.RWMutex
+	modTime time.Time
+	users   map[string]*fileUser
+}
+
+// NewFileVerifier creates a new verifier for the given file.
+func NewFileVerifier(path string) (*FileVerifier, error) {
+	v := &FileVerifier{
+		path: path,
+	}
+
+	if err := v.reload(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return v, nil
+}
+
+// reload loads the file if it was changed.
+func (v *FileVerifier) reload() error {
+	fi, err := os.Stat(v.path)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	v.rw.RLock()
+	same := fi.ModTime().Equal(v.modTime)
+	v.rw.RUnlock()
+
+	if same {
+		return nil
+	}
+
+	b, err := os.ReadFile(v.path)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	users, err := parseFile(b)
+	if err != nil {
+		return lazyerrors.Errorf("%s: %w", v.path, err)
+	}
+
+	v.rw.Lock()
+	defer v.rw.Unlock()
+
+	v.modTime = fi.ModTime()
+	v.users = users
+
+	return nil
+}
+
+// parseFile parses the content of htpasswd-style file.
+func parseFile(b []byte) (map[string]*fileUser, error) {
+	res := map[string]*fileUser{}
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, lazyerrors.Errorf("line %d: invalid format", n)
+		}
+
+		username, hash := parts[0], parts[1]
+
+		if !strings.HasPrefix(hash, "$2") && !strings.HasPrefix(hash, "{SHA}") {
+			return nil, lazyerrors.Errorf("line %d: unsupported hash format", n)
+		}
+
+		if _, ok := res[username]; ok {
+			return nil, lazyerrors.Errorf("line %d: duplicate user %q", n, username)
+		}
+
+		u := &fileUser{
+			hash: hash,
+		}
+
+		if len(parts) == 3 {
+			for _, g := range strings.Split(parts[2], ",") {
+				if g = strings.TrimSpace(g); g != "" {
+					u.groups = append(u.groups, g)
+				}
+			}
+		}
+
+		res[username] = u
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// Verify implements [Verifier].
+func (v *FileVerifier) Verify(ctx context.Context, username, password string) ([]string, error) {
+	if err := v.reload(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	v.rw.RLock()
+	u := v.users[username]
+	v.rw.RUnlock()
+
+	if u == nil || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	if hash, ok := strings.CutPrefix(u.hash, "{SHA}"); ok {
+		h := sha1.Sum([]byte(password)) //nolint:gosec // used by htpasswd's {SHA} format
+		expected := base64.StdEncoding.EncodeToString(h[:])
+
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(expected)) != 1 {
+			return nil, ErrInvalidCredentials
+		}
+
+		return u.groups, nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.hash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return u.groups, nil
+}
+
+// check interfaces
+var (
+	_ Verifier = (*FileVerifier)(nil)
+)
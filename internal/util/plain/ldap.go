@@ -0,0 +1,417 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// LDAP protocol operations, see RFC 4511.
+const (
+	ldapBindRequest      = berApplication | berConstructed | 0
+	ldapBindResponse     = berApplication | berConstructed | 1
+	ldapUnbindRequest    = berApplication | 2
+	ldapSearchRequest    = berApplication | berConstructed | 3
+	ldapSearchResultItem = berApplication | berConstructed | 4
+	ldapSearchResultDone = berApplication | berConstructed | 5
+
+	ldapSimpleAuth    = berContext | 0
+	ldapEqualityMatch = berContext | berConstructed | 3
+)
+
+// LDAP result codes used by [LDAPVerifier].
+const (
+	ldapSuccess            = 0
+	ldapInvalidCredentials = 49
+)
+
+// ldapDefaultTimeout is the default timeout for LDAP operations.
+const ldapDefaultTimeout = 10 * time.Second
+
+// LDAPConfig represents [LDAPVerifier] configuration.
+type LDAPConfig struct {
+	// URL of the LDAP server: `ldap://host:port` or `ldaps://host:port`.
+	URL string
+
+	// UserDNTemplate is used to construct the user's DN for the bind operation;
+	// `{user}` is replaced with the escaped username.
+	// For example, `uid={user},ou=users,dc=example,dc=com`.
+	UserDNTemplate string
+
+	// GroupBaseDN is the base DN for groups search.
+	// Groups are entries with `member` attribute set to the user's DN;
+	// their `cn` attributes are returned as group names.
+	// If empty, groups are not searched.
+	GroupBaseDN string
+
+	// GroupRoles maps group names to names of roles in the `admin` database.
+	// Groups without mapping are ignored.
+	GroupRoles map[string]string
+
+	// TLSConfig is used for `ldaps://` URLs.
+	TLSConfig *tls.Config
+
+	// Timeout for the whole verification; defaults to 10s.
+	Timeout time.Duration
+}
+
+// LDAPVerifier is a [Verifier] that uses LDAP simple bind.
+//
+// The user is bound with the DN constructed from the template and the password;
+// the same connection is then used to search the user's groups.
+// Groups are returned as names of roles they are mapped to.
+type LDAPVerifier struct {
+	config LDAPConfig
+	addr   string
+	tls    bool
+}
+
+// NewLDAPVerifier creates a new verifier with the given configuration.
+func NewLDAPVerifier(config *LDAPConfig) (*LDAPVerifier, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	v := &LDAPVerifier{
+		config: *config,
+		addr:   u.Host,
+	}
+
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			v.addr = net.JoinHostPort(u.Hostname(), "389")
+		}
+	case "ldaps":
+		v.tls = true
+		if u.Port() == "" {
+			v.addr = net.JoinHostPort(u.Hostname(), "636")
+		}
+	default:
+		return nil, lazyerrors.Errorf("unsupported LDAP URL scheme %q", u.Scheme)
+	}
+
+	if !strings.Contains(config.UserDNTemplate, "{user}") {
+		return nil, lazyerrors.New("LDAP user DN template should contain {user} placeholder")
+	}
+
+	if v.config.Timeout == 0 {
+		v.config.Timeout = ldapDefaultTimeout
+	}
+
+	return v, nil
+}
+
+// Verify implements [Verifier].
+func (v *LDAPVerifier) Verify(ctx context.Context, username, password string) ([]string, error) {
+	// empty password means unauthenticated bind that always succeeds, see RFC 4513 (section 5.1.2)
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, v.config.Timeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+
+	if v.tls {
+		d := &tls.Dialer{Config: v.config.TLSConfig}
+		conn, err = d.DialContext(ctx, "tcp", v.addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", v.addr)
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer conn.Close() //nolint:errcheck // we are only interested in results of operations below
+
+	deadline, _ := ctx.Deadline()
+	if err = conn.SetDeadline(deadline); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c := &ldapConn{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+	}
+
+	userDN := strings.ReplaceAll(v.config.UserDNTemplate, "{user}", escapeDN(username))
+
+	code, err := c.bind(userDN, password)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	switch code {
+	case ldapSuccess:
+		// nothing
+	case ldapInvalidCredentials:
+		return nil, ErrInvalidCredentials
+	default:
+		return nil, lazyerrors.Errorf("LDAP bind failed with result code %d", code)
+	}
+
+	var roles []string
+
+	if v.config.GroupBaseDN != "" {
+		var groups []string
+		if groups, err = c.searchGroups(v.config.GroupBaseDN, userDN); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// group names are controlled by the directory, not by FerretDB administrators,
+		// so only explicitly mapped groups are used
+		for _, g := range groups {
+			if role, ok := v.config.GroupRoles[g]; ok && !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	c.unbind()
+
+	return roles, nil
+}
+
+// ldapConn represents a single LDAP client connection.
+type ldapConn struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	msgID int
+}
+
+// send sends the LDAP message with the given protocol operation.
+func (c *ldapConn) send(op []byte) error {
+	c.msgID++
+
+	if _, err := c.conn.Write(berConcat(berSequence, berInt(berInteger, c.msgID), op)); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// recv receives the LDAP message and returns its protocol operation.
+func (c *ldapConn) recv() (*berElement, error) {
+	msg, err := berRead(c.r)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	children, err := msg.children()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if msg.tag != berSequence || len(children) < 2 {
+		return nil, lazyerrors.New("invalid LDAP message")
+	}
+
+	id, err := children[0].int()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if id != c.msgID {
+		return nil, lazyerrors.Errorf("unexpected LDAP message ID %d, expected %d", id, c.msgID)
+	}
+
+	return children[1], nil
+}
+
+// resultCode returns the result code of LDAPResult.
+func resultCode(op *berElement) (int, error) {
+	children, err := op.children()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	if len(children) < 3 {
+		return 0, lazyerrors.New("invalid LDAP result")
+	}
+
+	return children[0].int()
+}
+
+// bind performs the simple bind operation and returns the result code.
+func (c *ldapConn) bind(dn, password string) (int, error) {
+	err := c.send(berConcat(
+		ldapBindRequest,
+		berInt(berInteger, 3),
+		berString(berOctetString, dn),
+		berString(ldapSimpleAuth, password),
+	))
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	op, err := c.recv()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	if op.tag != ldapBindResponse {
+		return 0, lazyerrors.Errorf("unexpected LDAP operation 0x%02x", op.tag)
+	}
+
+	return resultCode(op)
+}
+
+// searchGroups returns `cn` attributes of entries under baseDN that have the given member.
+func (c *ldapConn) searchGroups(baseDN, memberDN string) ([]string, error) {
+	err := c.send(berConcat(
+		ldapSearchRequest,
+		berString(berOctetString, baseDN),
+		berInt(berEnumerated, 2), // wholeSubtree
+		berInt(berEnumerated, 0), // neverDerefAliases
+		berInt(berInteger, 0),    // no size limit
+		berInt(berInteger, 0),    // no time limit
+		berBool(false),           // typesOnly
+		berConcat(ldapEqualityMatch, berString(berOctetString, "member"), berString(berOctetString, memberDN)),
+		berConcat(berSequence, berString(berOctetString, "cn")),
+	))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res []string
+
+	for {
+		op, err := c.recv()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch op.tag {
+		case ldapSearchResultItem:
+			var names []string
+			if names, err = entryAttribute(op, "cn"); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			res = append(res, names...)
+
+		case ldapSearchResultDone:
+			var code int
+			if code, err = resultCode(op); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if code != ldapSuccess {
+				return nil, lazyerrors.Errorf("LDAP search failed with result code %d", code)
+			}
+
+			return res, nil
+
+		default:
+			// ignore references and other responses
+		}
+	}
+}
+
+// unbind sends the unbind request; the server closes the connection after that.
+func (c *ldapConn) unbind() {
+	_ = c.send(berEncode(ldapUnbindRequest, nil))
+}
+
+// entryAttribute returns values of the given attribute of SearchResultEntry.
+func entryAttribute(op *berElement, name string) ([]string, error) {
+	children, err := op.children()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(children) != 2 {
+		return nil, lazyerrors.New("invalid LDAP search result entry")
+	}
+
+	attrs, err := children[1].children()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res []string
+
+	for _, attr := range attrs {
+		var parts []*berElement
+		if parts, err = attr.children(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if len(parts) != 2 || !strings.EqualFold(string(parts[0].content), name) {
+			continue
+		}
+
+		var values []*berElement
+		if values, err = parts[1].children(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for _, v := range values {
+			res = append(res, string(v.content))
+		}
+	}
+
+	return res, nil
+}
+
+// escapeDN escapes the attribute value for use in DN, see RFC 4514 (section 2.4).
+func escapeDN(s string) string {
+	var b strings.Builder
+
+	for i, r := range s {
+		switch {
+		case strings.ContainsRune(`"+,;<>\`, r),
+			(r == ' ' || r == '#') && i == 0,
+			r == ' ' && i == len(s)-1:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+
+		case r == 0:
+			b.WriteString(`\00`)
+
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// String implements [fmt.Stringer].
+func (v *LDAPVerifier) String() string {
+	return fmt.Sprintf("LDAP %s", v.config.URL)
+}
+
+// check interfaces
+var (
+	_ Verifier     = (*LDAPVerifier)(nil)
+	_ fmt.Stringer = (*LDAPVerifier)(nil)
+)
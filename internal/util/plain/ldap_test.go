@@ -0,0 +1,284 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+// ldapStandIn is a minimal in-process LDAP server for tests.
+//
+// It supports simple bind, search by `member` equality filter, and unbind.
+type ldapStandIn struct {
+	// passwords by user DN
+	passwords map[string]string
+
+	// group DNs and `cn` attributes by member DN
+	groups map[string][][2]string
+
+	l net.Listener
+}
+
+// newLDAPStandIn starts a new server and returns its URL.
+func newLDAPStandIn(t testing.TB, passwords map[string]string, groups map[string][][2]string) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &ldapStandIn{
+		passwords: passwords,
+		groups:    groups,
+		l:         l,
+	}
+
+	t.Cleanup(func() {
+		require.NoError(t, l.Close())
+	})
+
+	go s.serve(t)
+
+	return "ldap://" + l.Addr().String()
+}
+
+// serve accepts connections until the listener is closed.
+func (s *ldapStandIn) serve(t testing.TB) {
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close() //nolint:errcheck // we are only interested in protocol errors
+
+			if err := s.handle(conn); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+}
+
+// handle handles a single connection.
+func (s *ldapStandIn) handle(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+
+	var boundDN string
+
+	for {
+		msg, err := berRead(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		children, err := msg.children()
+		if err != nil {
+			return err
+		}
+
+		id, err := children[0].int()
+		if err != nil {
+			return err
+		}
+
+		op := children[1]
+
+		reply := func(op []byte) error {
+			_, err := conn.Write(berConcat(berSequence, berInt(berInteger, id), op))
+			return err
+		}
+
+		result := func(tag byte, code int) []byte {
+			return berConcat(
+				tag,
+				berInt(berEnumerated, code),
+				berString(berOctetString, ""),
+				berString(berOctetString, ""),
+			)
+		}
+
+		opChildren, err := op.children()
+		if err != nil {
+			return err
+		}
+
+		switch op.tag {
+		case ldapBindRequest:
+			dn, password := string(opChildren[1].content), string(opChildren[2].content)
+
+			code := ldapInvalidCredentials
+			if expected, ok := s.passwords[dn]; ok && expected == password {
+				code = ldapSuccess
+				boundDN = dn
+			}
+
+			if err = reply(result(ldapBindResponse, code)); err != nil {
+				return err
+			}
+
+		case ldapSearchRequest:
+			if boundDN == "" {
+				// insufficientAccessRights
+				if err = reply(result(ldapSearchResultDone, 50)); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			filter, err := opChildren[6].children()
+			if err != nil {
+				return err
+			}
+
+			member := string(filter[1].content)
+
+			for _, g := range s.groups[member] {
+				entry := berConcat(
+					ldapSearchResultItem,
+					berString(berOctetString, g[0]),
+					berConcat(
+						berSequence,
+						berConcat(
+							berSequence,
+							berString(berOctetString, "cn"),
+							berConcat(berSet, berString(berOctetString, g[1])),
+						),
+					),
+				)
+
+				if err = reply(entry); err != nil {
+					return err
+				}
+			}
+
+			if err = reply(result(ldapSearchResultDone, ldapSuccess)); err != nil {
+				return err
+			}
+
+		case ldapUnbindRequest:
+			return nil
+
+		default:
+			return errors.New("unexpected LDAP operation")
+		}
+	}
+}
+
+func TestLDAPVerifier(t *testing.T) {
+	t.Parallel()
+
+	passwords := map[string]string{
+		`uid=alice,ou=users,dc=example,dc=com`:     "pencil",
+		`uid=bob\, jr.,ou=users,dc=example,dc=com`: "pen",
+	}
+
+	groups := map[string][][2]string{
+		`uid=alice,ou=users,dc=example,dc=com`: {
+			{"cn=readers,ou=groups,dc=example,dc=com", "readers"},
+			{"cn=writers,ou=groups,dc=example,dc=com", "writers"},
+		},
+	}
+
+	u := newLDAPStandIn(t, passwords, groups)
+
+	v, err := NewLDAPVerifier(&LDAPConfig{
+		URL:            u,
+		UserDNTemplate: "uid={user},ou=users,dc=example,dc=com",
+		GroupBaseDN:    "ou=groups,dc=example,dc=com",
+		GroupRoles:     map[string]string{"readers": "readAnyDatabase", "admins": "root"},
+	})
+	require.NoError(t, err)
+
+	ctx := testutil.Ctx(t)
+
+	actual, err := v.Verify(ctx, "alice", "pencil")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"readAnyDatabase"}, actual, "unmapped groups should be ignored")
+
+	actual, err = v.Verify(ctx, "bob, jr.", "pen")
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	for name, tc := range map[string]struct {
+		username string
+		password string
+	}{
+		"WrongPassword": {username: "alice", password: "pen"},
+		"EmptyPassword": {username: "alice", password: ""},
+		"UnknownUser":   {username: "carol", password: "pencil"},
+		"Injection":     {username: "alice,ou=users,dc=example,dc=com", password: "pencil"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := v.Verify(ctx, tc.username, tc.password)
+			require.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("NoGroups", func(t *testing.T) {
+		t.Parallel()
+
+		nv, err := NewLDAPVerifier(&LDAPConfig{
+			URL:            u,
+			UserDNTemplate: "uid={user},ou=users,dc=example,dc=com",
+		})
+		require.NoError(t, err)
+
+		actual, err := nv.Verify(ctx, "alice", "pencil")
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewLDAPVerifier(&LDAPConfig{URL: "http://127.0.0.1", UserDNTemplate: "uid={user}"})
+		require.Error(t, err)
+
+		_, err = NewLDAPVerifier(&LDAPConfig{URL: u, UserDNTemplate: "uid=alice"})
+		require.Error(t, err)
+	})
+}
+
+func TestEscapeDN(t *testing.T) {
+	t.Parallel()
+
+	for in, expected := range map[string]string{
+		"alice":       "alice",
+		"bob, jr.":    `bob\, jr.`,
+		"#hash":       `\#hash`,
+		" spaces ":    `\ spaces\ `,
+		`a+b=c;d<e>f`: `a\+b=c\;d\<e\>f`,
+		`back\slash`:  `back\\slash`,
+		"nul\x00":     `nul\00`,
+	} {
+		assert.Equal(t, expected, escapeDN(in), "%q", in)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plain provides SASL PLAIN authentication with pluggable credential verifiers.
+//
+// See RFC 4616.
+package plain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// Mechanism is the name of the SASL PLAIN mechanism.
+const Mechanism = "PLAIN"
+
+// ErrInvalidCredentials is returned by [Verifier] implementations
+// if the username or password is not valid.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Verifier verifies the username and password against some external source of identities,
+// such as a directory service.
+type Verifier interface {
+	// Verify returns groups of the user if the password is valid,
+	// or [ErrInvalidCredentials] if it is not.
+	// Other errors indicate problems with the source itself.
+	Verify(ctx context.Context, username, password string) ([]string, error)
+}
+
+// ParsePayload returns the username and the password from the client's SASL PLAIN message.
+//
+// The authorization identity, if present, should be the same as the username.
+func ParsePayload(payload []byte) (string, string, error) {
+	parts := bytes.Split(payload, []byte{0})
+	if len(parts) != 3 {
+		return "", "", lazyerrors.New("invalid PLAIN message")
+	}
+
+	authzid, username, password := string(parts[0]), string(parts[1]), string(parts[2])
+
+	if username == "" {
+		return "", "", lazyerrors.New("empty username in PLAIN message")
+	}
+
+	if authzid != "" && authzid != username {
+		return "", "", lazyerrors.New("authorization identity does not match username in PLAIN message")
+	}
+
+	return username, password, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestParsePayload(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		payload  string
+		username string
+		password string
+		err      bool
+	}{
+		"Valid":            {payload: "\x00user\x00pencil", username: "user", password: "pencil"},
+		"SameAuthzid":      {payload: "user\x00user\x00pencil", username: "user", password: "pencil"},
+		"EmptyPassword":    {payload: "\x00user\x00", username: "user", password: ""},
+		"DifferentAuthzid": {payload: "admin\x00user\x00pencil", err: true},
+		"EmptyUsername":    {payload: "\x00\x00pencil", err: true},
+		"TooFewParts":      {payload: "user\x00pencil", err: true},
+		"TooManyParts":     {payload: "\x00user\x00pen\x00cil", err: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			username, password, err := ParsePayload([]byte(tc.payload))
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.username, username)
+			assert.Equal(t, tc.password, password)
+		})
+	}
+}
+
+func TestFileVerifier(t *testing.T) {
+	t.Parallel()
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("pencil"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "users")
+	content := "# comment\n\n" +
+		"alice:" + string(bcryptHash) + ":readers, writers\n" +
+		"bob:{SHA}0vxRJJChUDZGC1SJQBQ51tpUB/o=\n" // SHA-1 of "pencil"
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	v, err := NewFileVerifier(path)
+	require.NoError(t, err)
+
+	ctx := testutil.Ctx(t)
+
+	groups, err := v.Verify(ctx, "alice", "pencil")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"readers", "writers"}, groups)
+
+	groups, err = v.Verify(ctx, "bob", "pencil")
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	for name, tc := range map[string]struct {
+		username string
+		password string
+	}{
+		"WrongBcrypt":   {username: "alice", password: "pen"},
+		"WrongSHA":      {username: "bob", password: "pen"},
+		"EmptyPassword": {username: "alice", password: ""},
+		"UnknownUser":   {username: "carol", password: "pencil"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := v.Verify(ctx, tc.username, tc.password)
+			require.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("Reload", func(t *testing.T) {
+		t.Parallel()
+
+		reloadPath := filepath.Join(t.TempDir(), "users")
+		require.NoError(t, os.WriteFile(reloadPath, []byte("bob:{SHA}0vxRJJChUDZGC1SJQBQ51tpUB/o=\n"), 0o600))
+
+		rv, err := NewFileVerifier(reloadPath)
+		require.NoError(t, err)
+
+		_, err = rv.Verify(ctx, "bob", "pencil")
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(reloadPath, []byte("# no users\n"), 0o600))
+
+		// make sure the modification time changes even on file systems with coarse timestamps
+		future := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(reloadPath, future, future))
+
+		_, err = rv.Verify(ctx, "bob", "pencil")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		t.Parallel()
+
+		invalidPath := filepath.Join(t.TempDir(), "users")
+		require.NoError(t, os.WriteFile(invalidPath, []byte("alice:plaintext\n"), 0o600))
+
+		_, err := NewFileVerifier(invalidPath)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,364 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redact provides masking of sensitive data in commands and wire protocol messages
+// before they are logged, stored, or returned to other clients.
+//
+// Secrets (passwords, SASL payloads, etc) are always masked.
+// All values could be masked too, keeping only field names;
+// that is similar to MongoDB's `redactClientLogData` mode.
+package redact
+
+import (
+	"slices"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Placeholder replaces masked values.
+const Placeholder = "###"
+
+// secretFields contains paths of fields with secrets, indexed by command name.
+// The same paths are used for both requests and responses.
+var secretFields = map[string][][]string{
+	"createAPIKey": {{"key"}},
+	"createUser":   {{"pwd"}},
+	"updateUser":   {{"pwd"}},
+	"saslStart":    {{"payload"}},
+	"saslContinue": {{"payload"}},
+	"hello":        {{"speculativeAuthenticate", "payload"}},
+	"isMaster":     {{"speculativeAuthenticate", "payload"}},
+	"ismaster":     {{"speculativeAuthenticate", "payload"}},
+}
+
+// Secrets returns a copy of the given command or response document with secret values masked.
+// If there are no secrets, the document itself is returned.
+//
+// For responses, command should be the name of the command of the corresponding request.
+func Secrets(command string, doc *wirebson.Document) *wirebson.Document {
+	if doc == nil {
+		return nil
+	}
+
+	res := doc
+
+	for _, path := range secretFields[command] {
+		res = maskPath(res, path)
+	}
+
+	return res
+}
+
+// maskPath returns a shallow copy of the document with values at the given path masked,
+// or the document itself if there are no such values.
+func maskPath(doc *wirebson.Document, path []string) *wirebson.Document {
+	res := wirebson.MakeDocument(doc.Len())
+
+	var masked bool
+
+	for name, v := range doc.All() {
+		if name == path[0] {
+			switch {
+			case len(path) == 1:
+				v = Placeholder
+				masked = true
+
+			default:
+				if nested := toDocument(v); nested != nil {
+					if m := maskPath(nested, path[1:]); m != nested {
+						v = m
+						masked = true
+					}
+				}
+			}
+		}
+
+		must.NoError(res.Add(name, v))
+	}
+
+	if !masked {
+		return doc
+	}
+
+	return res
+}
+
+// toDocument returns the given value as a document, or nil.
+func toDocument(v any) *wirebson.Document {
+	switch v := v.(type) {
+	case *wirebson.Document:
+		return v
+	case wirebson.RawDocument:
+		doc, err := v.Decode()
+		if err != nil {
+			return nil
+		}
+
+		return doc
+	default:
+		return nil
+	}
+}
+
+// All returns a deep copy of the given document with all values masked.
+// Only field names are kept.
+func All(doc wirebson.AnyDocument) *wirebson.Document {
+	res, _ := all(doc).(*wirebson.Document)
+	if res == nil {
+		res = wirebson.MustDocument()
+	}
+
+	return res
+}
+
+// all returns a deep copy of the given value with all values masked.
+func all(v any) any {
+	switch v := v.(type) {
+	case *wirebson.Document:
+		if v == nil {
+			return Placeholder
+		}
+
+		res := wirebson.MakeDocument(v.Len())
+		for name, fv := range v.All() {
+			must.NoError(res.Add(name, all(fv)))
+		}
+
+		return res
+
+	case wirebson.RawDocument:
+		doc, err := v.Decode()
+		if err != nil {
+			return Placeholder
+		}
+
+		return all(doc)
+
+	case *wirebson.Array:
+		if v == nil {
+			return Placeholder
+		}
+
+		res := wirebson.MakeArray(v.Len())
+		for _, ev := range v.All() {
+			must.NoError(res.Add(all(ev)))
+		}
+
+		return res
+
+	case wirebson.RawArray:
+		arr, err := v.Decode()
+		if err != nil {
+			return Placeholder
+		}
+
+		return all(arr)
+
+	default:
+		return Placeholder
+	}
+}
+
+// Command returns the command name of the given request message, or an empty string.
+func Command(body wire.MsgBody) string {
+	return document(body).Command()
+}
+
+// document returns the main document of the given message, or nil.
+func document(body wire.MsgBody) *wirebson.Document {
+	switch body := body.(type) {
+	case *wire.OpMsg:
+		if body == nil {
+			return nil
+		}
+
+		doc, _ := body.RawSection0().Decode()
+
+		return doc
+
+	case *wire.OpQuery:
+		if body == nil {
+			return nil
+		}
+
+		return body.Query()
+
+	case *wire.OpReply:
+		if body == nil {
+			return nil
+		}
+
+		doc, _ := body.Document()
+
+		return doc
+
+	default:
+		return nil
+	}
+}
+
+// Message returns a copy of the given message with secret values masked.
+// If there are no secrets, the message itself is returned.
+//
+// Only the main document (OP_MSG section of kind 0, OP_QUERY query, or OP_REPLY document) is checked.
+// The returned OP_MSG has no checksum; the returned OP_QUERY has no fields selector.
+//
+// For responses, command should be the name of the command of the corresponding request.
+func Message(body wire.MsgBody, command string) (wire.MsgBody, error) {
+	doc := document(body)
+	if doc == nil {
+		return body, nil
+	}
+
+	redacted := Secrets(command, doc)
+	if redacted == doc {
+		return body, nil
+	}
+
+	switch body := body.(type) {
+	case *wire.OpMsg:
+		msg, err := wire.NewOpMsg(redacted)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		sections := slices.Clone(body.Sections())
+		for i, s := range sections {
+			if s.Kind == 0 {
+				sections[i] = msg.Sections()[0]
+			}
+		}
+
+		if err = msg.SetSections(sections...); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		msg.Flags = body.Flags &^ wire.OpMsgFlags(wire.OpMsgChecksumPresent)
+
+		return msg, nil
+
+	case *wire.OpQuery:
+		query, err := wire.NewOpQuery(redacted)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		query.FullCollectionName = body.FullCollectionName
+		query.Flags = body.Flags
+		query.NumberToSkip = body.NumberToSkip
+		query.NumberToReturn = body.NumberToReturn
+
+		return query, nil
+
+	case *wire.OpReply:
+		reply, err := wire.NewOpReply(redacted)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		reply.CursorID = body.CursorID
+		reply.Flags = body.Flags
+		reply.StartingFrom = body.StartingFrom
+
+		return reply, nil
+
+	default:
+		panic("unreachable")
+	}
+}
+
+// LogMessage returns an indented string representation of the given message for logging.
+//
+// Secret values are always masked. If all is true, all values are masked; only field names are kept.
+//
+// For responses, command should be the name of the command of the corresponding request.
+func LogMessage(body wire.MsgBody, command string, all bool) string {
+	if all {
+		return wirebson.LogMessageIndent(allMessage(body))
+	}
+
+	msg, err := Message(body, command)
+	if err != nil {
+		return Placeholder
+	}
+
+	return msg.StringIndent()
+}
+
+// allMessage returns a representation of the given message for logging with all values masked.
+//
+// It follows the format of wire's StringIndent methods.
+func allMessage(body wire.MsgBody) *wirebson.Document {
+	switch body := body.(type) {
+	case *wire.OpMsg:
+		sections := wirebson.MakeArray(len(body.Sections()))
+
+		for _, s := range body.Sections() {
+			section := wirebson.MustDocument("Kind", int32(s.Kind))
+
+			switch s.Kind {
+			case 0:
+				must.NoError(section.Add("Document", All(s.Documents()[0])))
+
+			default:
+				docs := wirebson.MakeArray(len(s.Documents()))
+				for _, d := range s.Documents() {
+					must.NoError(docs.Add(All(d)))
+				}
+
+				must.NoError(section.Add("Identifier", s.Identifier))
+				must.NoError(section.Add("Documents", docs))
+			}
+
+			must.NoError(sections.Add(section))
+		}
+
+		return wirebson.MustDocument(
+			"FlagBits", body.Flags.String(),
+			"Sections", sections,
+		)
+
+	case *wire.OpQuery:
+		return wirebson.MustDocument(
+			"Flags", body.Flags.String(),
+			"FullCollectionName", body.FullCollectionName,
+			"NumberToSkip", body.NumberToSkip,
+			"NumberToReturn", body.NumberToReturn,
+			"Query", All(body.Query()),
+		)
+
+	case *wire.OpReply:
+		res := wirebson.MustDocument(
+			"ResponseFlags", body.Flags.String(),
+			"CursorID", body.CursorID,
+			"StartingFrom", body.StartingFrom,
+		)
+
+		if doc, _ := body.Document(); doc != nil {
+			must.NoError(res.Add("NumberReturned", int32(1)))
+			must.NoError(res.Add("Document", All(doc)))
+		} else {
+			must.NoError(res.Add("NumberReturned", int32(0)))
+		}
+
+		return res
+
+	default:
+		return wirebson.MustDocument()
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// makeOpMsg returns OP_MSG with the given document in the section of kind 0
+// and the given documents in the section of kind 1.
+func makeOpMsg(t *testing.T, doc *wirebson.Document, id string, docs ...*wirebson.Document) *wire.OpMsg {
+	t.Helper()
+
+	b := []byte{0, 0, 0, 0, 0}
+	b = append(b, must.NotFail(doc.Encode())...)
+
+	if id != "" {
+		section := append([]byte(id), 0)
+		for _, d := range docs {
+			section = append(section, must.NotFail(d.Encode())...)
+		}
+
+		b = append(b, 1)
+		b = binary.LittleEndian.AppendUint32(b, uint32(4+len(section)))
+		b = append(b, section...)
+	}
+
+	var msg wire.OpMsg
+	require.NoError(t, msg.UnmarshalBinaryNocopy(b))
+
+	return &msg
+}
+
+func TestSecrets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateUser", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument("createUser", "user", "pwd", "secret", "roles", wirebson.MakeArray(0))
+
+		actual := Secrets(doc.Command(), doc)
+		expected := wirebson.MustDocument("createUser", "user", "pwd", Placeholder, "roles", wirebson.MakeArray(0))
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, "secret", doc.Get("pwd"), "original document should not be modified")
+	})
+
+	t.Run("SpeculativeAuthenticate", func(t *testing.T) {
+		t.Parallel()
+
+		auth := wirebson.MustDocument("saslStart", int32(1), "payload", wirebson.Binary{B: []byte("n,,n=user")})
+		doc := wirebson.MustDocument("hello", int32(1), "speculativeAuthenticate", must.NotFail(auth.Encode()))
+
+		actual := Secrets(doc.Command(), doc)
+		expected := wirebson.MustDocument(
+			"hello", int32(1),
+			"speculativeAuthenticate", wirebson.MustDocument("saslStart", int32(1), "payload", Placeholder),
+		)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Response", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument("conversationId", int32(1), "done", false, "payload", wirebson.Binary{})
+		assert.Equal(t, Placeholder, Secrets("saslContinue", doc).Get("payload"))
+	})
+
+	t.Run("NoSecrets", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument("hello", int32(1))
+		assert.Same(t, doc, Secrets(doc.Command(), doc))
+
+		doc = wirebson.MustDocument("find", "pwd", "filter", wirebson.MustDocument("pwd", "value"))
+		assert.Same(t, doc, Secrets(doc.Command(), doc))
+
+		assert.Nil(t, Secrets("createUser", nil))
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	doc := wirebson.MustDocument(
+		"insert", "coll",
+		"v", int32(42),
+		"nested", must.NotFail(wirebson.MustDocument("a", "b").Encode()),
+		"arr", wirebson.MustArray("c", wirebson.MustDocument("d", 1.5)),
+	)
+
+	expected := wirebson.MustDocument(
+		"insert", Placeholder,
+		"v", Placeholder,
+		"nested", wirebson.MustDocument("a", Placeholder),
+		"arr", wirebson.MustArray(Placeholder, wirebson.MustDocument("d", Placeholder)),
+	)
+	assert.Equal(t, expected, All(doc))
+}
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OpMsg", func(t *testing.T) {
+		t.Parallel()
+
+		doc := wirebson.MustDocument("updateUser", "user", "pwd", "secret", "$db", "admin")
+		msg := makeOpMsg(t, doc, "documents", wirebson.MustDocument("v", "visible"))
+		command := Command(msg)
+		require.Equal(t, "updateUser", command)
+
+		actual, err := Message(msg, command)
+		require.NoError(t, err)
+
+		actualMsg := actual.(*wire.OpMsg)
+		require.Len(t, actualMsg.Sections(), 2)
+
+		actualDoc, err := actualMsg.RawSection0().Decode()
+		require.NoError(t, err)
+		assert.Equal(t, Placeholder, actualDoc.Get("pwd"))
+		assert.Equal(t, "admin", actualDoc.Get("$db"))
+
+		s := actualMsg.Sections()[1]
+		assert.Equal(t, "documents", s.Identifier)
+		require.Len(t, s.Documents(), 1)
+
+		s1, err := s.Documents()[0].Decode()
+		require.NoError(t, err)
+		assert.Equal(t, "visible", s1.Get("v"))
+
+		str := LogMessage(msg, command, false)
+		assert.NotContains(t, str, "secret")
+		assert.Contains(t, str, "visible")
+
+		str = LogMessage(msg, command, true)
+		assert.NotContains(t, str, "secret")
+		assert.NotContains(t, str, "visible")
+		assert.Contains(t, str, "`documents`")
+	})
+
+	t.Run("OpQuery", func(t *testing.T) {
+		t.Parallel()
+
+		auth := wirebson.MustDocument("saslStart", int32(1), "payload", wirebson.Binary{B: []byte("secret")})
+		query := must.NotFail(wire.NewOpQuery(wirebson.MustDocument("isMaster", int32(1), "speculativeAuthenticate", auth)))
+		query.FullCollectionName = "admin.$cmd"
+		query.NumberToReturn = -1
+
+		actual, err := Message(query, Command(query))
+		require.NoError(t, err)
+
+		actualQuery := actual.(*wire.OpQuery)
+		assert.Equal(t, "admin.$cmd", actualQuery.FullCollectionName)
+		assert.Equal(t, int32(-1), actualQuery.NumberToReturn)
+		assert.NotContains(t, actualQuery.StringIndent(), "secret")
+	})
+
+	t.Run("NoSecrets", func(t *testing.T) {
+		t.Parallel()
+
+		msg := wire.MustOpMsg("find", "coll", "$db", "test")
+
+		actual, err := Message(msg, Command(msg))
+		require.NoError(t, err)
+		assert.Same(t, msg, actual)
+		assert.Equal(t, msg.StringIndent(), LogMessage(msg, "find", false))
+	})
+}
@@ -31,23 +31,36 @@ import (
 // Conversation is not restartable. A new instance should be created for each conversation.
 type Conv struct {
 	// The order of fields is weird to make the struct smaller due to alignment.
-	// All fields except l are protected by rw.
+	// All fields except l and mechanism are protected by rw.
 
 	clientFirst *message
 	serverFirst *message
 	clientFinal *message
 	serverFinal *message
 	l           *slog.Logger
+	mechanism   string
 	rw          sync.RWMutex
 }
 
-// NewConv creates a server SCRAM conversation.
-func NewConv(l *slog.Logger) *Conv {
+// NewConv creates a server SCRAM conversation for the given mechanism ([SHA1] or [SHA256]).
+func NewConv(mechanism string, l *slog.Logger) *Conv {
+	must.BeTrue(mechanism == SHA1 || mechanism == SHA256)
+
 	return &Conv{
-		l: l,
+		l:         l,
+		mechanism: mechanism,
 	}
 }
 
+// Mechanism returns the conversation's mechanism.
+func (c *Conv) Mechanism() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.mechanism
+}
+
 // Succeed returns true if conversation was done successfully.
 func (c *Conv) Succeed() bool {
 	if c == nil {
@@ -99,7 +112,8 @@ func (c *Conv) ClientFirst(payload string) (string, error) {
 	return c.clientFirst.n, nil
 }
 
-// ServerFirst processes the ScramSha256GetSaltAndIterations's result and returns the server-first message.
+// ServerFirst processes the ScramSha256GetSaltAndIterations's result
+// (or [Credentials.SaltAndIterations] for SCRAM-SHA-1) and returns the server-first message.
 func (c *Conv) ServerFirst(res wirebson.RawDocument) (string, error) {
 	c.rw.Lock()
 	defer c.rw.Unlock()
@@ -173,7 +187,8 @@ func (c *Conv) ClientFinal(payload string) (string, string, error) {
 	return authMessage, p, nil
 }
 
-// ServerFinal processes the AuthenticateWithScramSha256's result and returns the server-final message.
+// ServerFinal processes the AuthenticateWithScramSha256's result
+// (or [Credentials.AuthenticateSHA1] for SCRAM-SHA-1) and returns the server-final message.
 func (c *Conv) ServerFinal(res wirebson.RawDocument) (string, error) {
 	c.rw.Lock()
 	defer c.rw.Unlock()
@@ -12,5 +12,5 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package scram provides an implementation of SCRAM-SHA-256 subset.
+// Package scram provides an implementation of SCRAM-SHA-1 and SCRAM-SHA-256 subset.
 package scram
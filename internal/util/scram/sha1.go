@@ -0,0 +1,173 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scram
+
+import (
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // required by SCRAM-SHA-1 as implemented by MongoDB
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // required by SCRAM-SHA-1
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/FerretDB/wire/wirebson"
+	xdgscram "github.com/xdg-go/scram"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Supported mechanisms.
+const (
+	SHA1   = "SCRAM-SHA-1"
+	SHA256 = "SCRAM-SHA-256"
+)
+
+const (
+	// sha1Iterations is the iteration count used by MongoDB for SCRAM-SHA-1 credentials.
+	sha1Iterations = 10000
+
+	// sha1SaltLen is the salt length used by MongoDB for SCRAM-SHA-1 credentials.
+	sha1SaltLen = 16
+)
+
+// Credentials represents stored SCRAM credentials of the user.
+//
+// DocumentDB stores only SCRAM-SHA-256 credentials and verifies proofs itself;
+// SCRAM-SHA-1 credentials are stored and verified by FerretDB.
+type Credentials struct {
+	Salt           string // base64-encoded
+	StoredKey      string // base64-encoded
+	ServerKey      string // base64-encoded
+	IterationCount int32
+}
+
+// NewSHA1Credentials returns new SCRAM-SHA-1 credentials for the given username and password.
+//
+// Like MongoDB, it uses the MD5 digest of the username and password instead of the password itself.
+func NewSHA1Credentials(username, password string) (*Credentials, error) {
+	salt := make([]byte, sha1SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	client, err := xdgscram.SHA1.NewClientUnprepped(username, sha1PasswordDigest(username, password), "")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	stored := client.GetStoredCredentials(xdgscram.KeyFactors{Salt: string(salt), Iters: sha1Iterations})
+
+	return &Credentials{
+		Salt:           base64.StdEncoding.EncodeToString(salt),
+		StoredKey:      base64.StdEncoding.EncodeToString(stored.StoredKey),
+		ServerKey:      base64.StdEncoding.EncodeToString(stored.ServerKey),
+		IterationCount: sha1Iterations,
+	}, nil
+}
+
+// sha1PasswordDigest returns MongoDB's password digest used by SCRAM-SHA-1 instead of the password.
+func sha1PasswordDigest(username, password string) string {
+	h := md5.Sum([]byte(username + ":mongo:" + password)) //nolint:gosec // required by MongoDB
+	return hex.EncodeToString(h[:])
+}
+
+// CredentialsFromDocument returns credentials stored in the document returned by [Credentials.Document].
+func CredentialsFromDocument(doc *wirebson.Document) (*Credentials, error) {
+	var res Credentials
+	var ok bool
+
+	if res.IterationCount, ok = doc.Get("iterationCount").(int32); !ok {
+		return nil, lazyerrors.Errorf("invalid credentials: %s", doc.LogMessage())
+	}
+
+	for f, dst := range map[string]*string{"salt": &res.Salt, "storedKey": &res.StoredKey, "serverKey": &res.ServerKey} {
+		if *dst, ok = doc.Get(f).(string); !ok {
+			return nil, lazyerrors.Errorf("invalid credentials: %s", doc.LogMessage())
+		}
+	}
+
+	return &res, nil
+}
+
+// Document returns credentials in the same format as MongoDB's `usersInfo` with `showCredentials`.
+func (c *Credentials) Document() *wirebson.Document {
+	return wirebson.MustDocument(
+		"iterationCount", c.IterationCount,
+		"salt", c.Salt,
+		"storedKey", c.StoredKey,
+		"serverKey", c.ServerKey,
+	)
+}
+
+// SaltAndIterations returns the salt and the iteration count
+// in the same format as DocumentDB's ScramSha256GetSaltAndIterations,
+// suitable for [Conv.ServerFirst].
+func (c *Credentials) SaltAndIterations() wirebson.RawDocument {
+	return must.NotFail(wirebson.MustDocument(
+		"ok", int32(1),
+		"iterations", c.IterationCount,
+		"salt", c.Salt,
+	).Encode())
+}
+
+// AuthenticateSHA1 verifies the SCRAM-SHA-1 client proof for the auth message
+// returned by [Conv.ClientFinal].
+// It returns the result in the same format as DocumentDB's AuthenticateWithScramSha256,
+// suitable for [Conv.ServerFinal].
+func (c *Credentials) AuthenticateSHA1(authMessage, clientProof string) wirebson.RawDocument {
+	failed := must.NotFail(wirebson.MustDocument("ok", int32(0)).Encode())
+
+	storedKey, err := base64.StdEncoding.DecodeString(c.StoredKey)
+	if err != nil {
+		return failed
+	}
+
+	serverKey, err := base64.StdEncoding.DecodeString(c.ServerKey)
+	if err != nil {
+		return failed
+	}
+
+	proof, err := base64.StdEncoding.DecodeString(clientProof)
+	if err != nil || len(proof) != sha1.Size {
+		return failed
+	}
+
+	// ClientKey = ClientProof XOR HMAC(StoredKey, AuthMessage)
+	clientKey := make([]byte, sha1.Size)
+	subtle.XORBytes(clientKey, proof, computeHMAC(storedKey, authMessage))
+
+	// StoredKey = H(ClientKey)
+	h := sha1.Sum(clientKey) //nolint:gosec // required by SCRAM-SHA-1
+	if !hmac.Equal(h[:], storedKey) {
+		return failed
+	}
+
+	serverSignature := computeHMAC(serverKey, authMessage)
+
+	return must.NotFail(wirebson.MustDocument(
+		"ok", int32(1),
+		"ServerSignature", base64.StdEncoding.EncodeToString(serverSignature),
+	).Encode())
+}
+
+// computeHMAC returns HMAC-SHA-1 of the message.
+func computeHMAC(key []byte, msg string) []byte {
+	mac := hmac.New(sha1.New, key)
+	must.NotFail(mac.Write([]byte(msg)))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xdgscram "github.com/xdg-go/scram"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestSHA1Conv(t *testing.T) {
+	t.Parallel()
+
+	creds, err := NewSHA1Credentials("user", "pencil")
+	require.NoError(t, err)
+
+	doc := creds.Document()
+	actual, err := CredentialsFromDocument(doc)
+	require.NoError(t, err)
+	assert.Equal(t, creds, actual)
+
+	for name, tc := range map[string]struct {
+		password string
+		ok       bool
+	}{
+		"Valid":         {password: "pencil", ok: true},
+		"WrongPassword": {password: "pen", ok: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// drivers use the password digest as the password
+			client, err := xdgscram.SHA1.NewClientUnprepped("user", sha1PasswordDigest("user", tc.password), "")
+			require.NoError(t, err)
+
+			cc := client.NewConversation()
+			conv := NewConv(SHA1, testutil.Logger(t))
+			assert.Equal(t, SHA1, conv.Mechanism())
+
+			clientFirst, err := cc.Step("")
+			require.NoError(t, err)
+
+			username, err := conv.ClientFirst(clientFirst)
+			require.NoError(t, err)
+			assert.Equal(t, "user", username)
+
+			serverFirst, err := conv.ServerFirst(creds.SaltAndIterations())
+			require.NoError(t, err)
+
+			clientFinal, err := cc.Step(serverFirst)
+			require.NoError(t, err)
+
+			authMsg, proof, err := conv.ClientFinal(clientFinal)
+			require.NoError(t, err)
+
+			serverFinal, err := conv.ServerFinal(creds.AuthenticateSHA1(authMsg, proof))
+			if !tc.ok {
+				require.Error(t, err)
+				assert.False(t, conv.Succeed())
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, conv.Succeed())
+
+			_, err = cc.Step(serverFinal)
+			require.NoError(t, err)
+			assert.True(t, cc.Valid())
+		})
+	}
+}
@@ -23,8 +23,9 @@ import (
 )
 
 // Config provides TLS configuration for the given certificate and key files.
-// If CA file is provided, full authentication is enabled.
-func Config(certFile, keyFile, caFile string) (*tls.Config, error) {
+// If CA file is provided, full authentication is enabled:
+// client certificates are verified if given, and required if requireClientCert is true.
+func Config(certFile, keyFile, caFile string, requireClientCert bool) (*tls.Config, error) {
 	if _, err := os.Stat(certFile); err != nil {
 		return nil, fmt.Errorf("TLS certificate file: %w", err)
 	}
@@ -57,7 +58,11 @@ func Config(certFile, keyFile, caFile string) (*tls.Config, error) {
 			return nil, fmt.Errorf("TLS CA file: failed to parse")
 		}
 
-		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
 		config.ClientCAs = ca
 		config.RootCAs = ca
 	}